@@ -3,7 +3,12 @@
 package main
 
 import (
+	"context"
+	"io"
 	"log"
+	"os"
+	"time"
+	_ "time/tzdata" // Base de fusos embutida: os fusos dos usuários não dependem do sistema
 
 	"amigos-terceira-idade/internal/config"
 	"amigos-terceira-idade/internal/domain"
@@ -11,6 +16,11 @@ import (
 	"amigos-terceira-idade/internal/repository"
 	"amigos-terceira-idade/internal/service"
 	"amigos-terceira-idade/pkg/database"
+	"amigos-terceira-idade/pkg/notify"
+	"amigos-terceira-idade/pkg/pubsub"
+	"amigos-terceira-idade/pkg/ratelimit"
+	"amigos-terceira-idade/pkg/storage"
+	"amigos-terceira-idade/pkg/webhook"
 
 	"github.com/gin-gonic/gin"
 )
@@ -45,6 +55,28 @@ func main() {
 		&domain.Institution{},
 		&domain.Connection{},
 		&domain.Appointment{},
+		&domain.AppointmentStatusHistory{},
+		&domain.AppointmentSeries{},
+		&domain.VolunteerAvailability{},
+		&domain.AvailabilityException{},
+		&domain.VisitWindow{},
+		&domain.CalendarFeed{},
+		&domain.AppointmentReminder{},
+		&domain.Notification{},
+		&domain.NotificationPreference{},
+		&domain.OutboxMessage{},
+		&domain.WebhookEndpoint{},
+		&domain.WebhookDelivery{},
+		&domain.AuditLog{},
+		&domain.VerificationDocument{},
+		&domain.VerificationStatusHistory{},
+		&domain.Resident{},
+		&domain.ProxyGrant{},
+		&domain.ProxyActionLog{},
+		&domain.Session{},
+		&domain.AccountToken{},
+		&domain.LoginCode{},
+		&domain.AccountLockout{},
 	)
 	if err != nil {
 		log.Fatalf("Erro ao executar migrations: %v", err)
@@ -56,6 +88,25 @@ func main() {
 	interestRepo := repository.NewInterestRepository(db)
 	connectionRepo := repository.NewConnectionRepository(db)
 	appointmentRepo := repository.NewAppointmentRepository(db)
+	availabilityRepo := repository.NewAvailabilityRepository(db)
+	calendarFeedRepo := repository.NewCalendarFeedRepository(db)
+	reminderRepo := repository.NewReminderRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	verificationRepo := repository.NewVerificationRepository(db)
+	residentRepo := repository.NewResidentRepository(db)
+	proxyRepo := repository.NewProxyRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	accountTokenRepo := repository.NewAccountTokenRepository(db)
+	loginCodeRepo := repository.NewLoginCodeRepository(db)
+
+	// Armazenamento dos documentos enviados
+	fileStorage, err := storage.NewLocalStorage(cfg.Storage.Dir)
+	if err != nil {
+		log.Fatalf("Erro ao preparar o armazenamento de arquivos: %v", err)
+	}
 
 	// Insere os interesses padrão
 	log.Println("Inserindo interesses padrão...")
@@ -64,18 +115,71 @@ func main() {
 	}
 
 	// Inicializa os serviços
-	authService := service.NewAuthService(userRepo, interestRepo, cfg.JWT)
+	mailer := newNotifier(cfg, cfg.Account.MailChannel, cfg.Account.MailOutboxPath)
+	authService := service.NewAuthService(userRepo, interestRepo, sessionRepo, loginCodeRepo,
+		service.LoginCodeSenders{
+			Email: mailer,
+			SMS:   newSMSNotifier(cfg.LoginCode.SMSChannel, cfg.LoginCode.SMSOutboxPath),
+		}, cfg.JWT, cfg.LoginCode, service.RunInBackground)
+	accountService := service.NewAccountService(userRepo, accountTokenRepo, sessionRepo, mailer, cfg.Account, cfg.JWT.SecretKey,
+		service.RunInBackground)
 	userService := service.NewUserService(userRepo, interestRepo)
 	interestService := service.NewInterestService(interestRepo)
-	matchingService := service.NewMatchingService(userRepo, connectionRepo)
-	appointmentService := service.NewAppointmentService(appointmentRepo, userRepo)
+	notificationService := service.NewNotificationService(notificationRepo)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, residentRepo, verificationRepo, notificationService,
+		service.MatchingOptions{RequireVerifiedVolunteers: cfg.Verification.RequireVerified})
+	appointmentService := service.NewAppointmentService(appointmentRepo, userRepo, residentRepo, availabilityRepo, notificationService)
+	availabilityService := service.NewAvailabilityService(availabilityRepo, appointmentRepo, userRepo)
+	calendarService := service.NewCalendarService(appointmentRepo, calendarFeedRepo)
+	adminService := service.NewAdminService(userRepo, connectionRepo, appointmentRepo, auditRepo, sessionRepo)
+	verificationService := service.NewVerificationService(verificationRepo, userRepo, auditRepo, fileStorage, notificationService,
+		service.VerificationOptions{MaxDocumentSize: cfg.Verification.MaxDocumentSize})
+	residentService := service.NewResidentService(residentRepo, userRepo, interestRepo)
+	proxyService := service.NewProxyService(proxyRepo, userRepo, connectionRepo, userService, appointmentService,
+		matchingService, notificationService)
+	webhookService := service.NewWebhookService(webhookRepo, userRepo,
+		webhook.NewClient(cfg.Webhook.Timeout, "AmigosTerceiraIdade-Webhooks/1.0"),
+		service.WebhookOptions{
+			MaxAttempts: cfg.Webhook.MaxAttempts,
+			BaseBackoff: cfg.Webhook.BaseBackoff,
+			MaxBackoff:  cfg.Webhook.MaxBackoff,
+		})
+
+	// Entrega os eventos gravados no outbox; o stream SSE é um dos handlers
+	eventHub := pubsub.NewHub(cfg.Events.HistorySize, cfg.Events.Retention)
+	outboxDispatcher := service.NewOutboxDispatcher(outboxRepo, service.OutboxOptions{
+		BatchSize:   cfg.Outbox.BatchSize,
+		MaxAttempts: cfg.Outbox.MaxAttempts,
+		BaseBackoff: cfg.Outbox.BaseBackoff,
+		MaxBackoff:  cfg.Outbox.MaxBackoff,
+	})
+	outboxDispatcher.Register("sse", service.NewHubPublisher(eventHub).Handle)
+	outboxDispatcher.Register("webhooks", webhookService.Handle, domain.EventTypes...)
+	go outboxDispatcher.Run(context.Background(), cfg.Outbox.Interval)
+	go webhookService.Run(context.Background(), cfg.Webhook.Interval)
+
+	// Inicia o envio de lembretes em segundo plano
+	if cfg.Reminder.Enabled {
+		reminderService := service.NewReminderService(appointmentRepo, reminderRepo, newNotifier(cfg, cfg.Reminder.Channel, cfg.Reminder.OutboxPath), notificationService, cfg.Reminder.Offsets)
+		go reminderService.Run(context.Background(), cfg.Reminder.Interval)
+		log.Printf("Lembretes ativos (canal %s, antecedências %v)", cfg.Reminder.Channel, cfg.Reminder.Offsets)
+	}
 
 	// Inicializa os handlers
-	authHandler := handler.NewAuthHandler(authService)
+	authHandler := handler.NewAuthHandler(authService, accountService)
 	userHandler := handler.NewUserHandler(userService)
 	interestHandler := handler.NewInterestHandler(interestService)
 	matchingHandler := handler.NewMatchingHandler(matchingService)
 	appointmentHandler := handler.NewAppointmentHandler(appointmentService)
+	availabilityHandler := handler.NewAvailabilityHandler(availabilityService)
+	calendarHandler := handler.NewCalendarHandler(calendarService, cfg.Server.PublicURL)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	eventHandler := handler.NewEventHandler(eventHub, authService, cfg.Events.Heartbeat)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	adminHandler := handler.NewAdminHandler(adminService)
+	verificationHandler := handler.NewVerificationHandler(verificationService)
+	residentHandler := handler.NewResidentHandler(residentService)
+	proxyHandler := handler.NewProxyHandler(proxyService)
 
 	// Configura o router
 	router := handler.NewRouter(
@@ -84,11 +188,29 @@ func main() {
 		interestHandler,
 		matchingHandler,
 		appointmentHandler,
+		availabilityHandler,
+		calendarHandler,
+		notificationHandler,
+		eventHandler,
+		webhookHandler,
+		adminHandler,
+		verificationHandler,
+		residentHandler,
+		proxyHandler,
 		authService,
+		handler.RouterOptions{
+			RequireVerifiedEmail: cfg.Account.RequireVerifiedEmail,
+			RateLimit:            newRateLimitOptions(cfg.RateLimit),
+		},
 	)
 
-	// Cria o engine do Gin
+	// Cria o engine do Gin. O IP do cliente (limites por IP e IP das sessões) só vem do
+	// X-Forwarded-For quando a requisição chega por um proxy confiável; sem proxies
+	// configurados vale o endereço da conexão.
 	engine := gin.Default()
+	if err := engine.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatalf("Erro ao configurar TRUSTED_PROXIES: %v", err)
+	}
 
 	// Configura as rotas
 	router.Setup(engine)
@@ -99,3 +221,57 @@ func main() {
 		log.Fatalf("Erro ao iniciar servidor: %v", err)
 	}
 }
+
+// newRateLimitOptions monta os limites de requisições por minuto com o store em memória.
+func newRateLimitOptions(cfg config.RateLimitConfig) handler.RateLimitOptions {
+	if !cfg.Enabled {
+		return handler.RateLimitOptions{}
+	}
+	perMinute := func(requests int) ratelimit.Limit {
+		return ratelimit.Limit{Requests: requests, Period: time.Minute}
+	}
+	return handler.RateLimitOptions{
+		Store:           ratelimit.NewMemoryStore(),
+		APIPerIP:        perMinute(cfg.APIPerIP),
+		AuthPerIP:       perMinute(cfg.AuthPerIP),
+		LoginPerAccount: perMinute(cfg.LoginPerAccount),
+		APIPerUser:      perMinute(cfg.APIPerUser),
+	}
+}
+
+// newNotifier cria o canal de envio de mensagens: e-mail via SMTP ou log gravado em
+// outboxPath (ou na saída padrão, se vazio).
+func newNotifier(cfg *config.Config, channel, outboxPath string) notify.Notifier {
+	if channel == "email" {
+		return notify.NewSMTPNotifier(notify.SMTPConfig{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.User,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+		})
+	}
+	return newLogNotifier(outboxPath)
+}
+
+// newSMSNotifier cria o notificador dos códigos por SMS. Ainda não há integração com
+// provedor de SMS: o único canal é log, que grava as mensagens para envio externo.
+func newSMSNotifier(channel, outboxPath string) notify.Notifier {
+	if channel != "log" {
+		log.Fatalf("Canal de SMS desconhecido: %s", channel)
+	}
+	return newLogNotifier(outboxPath)
+}
+
+// newLogNotifier grava as mensagens em JSON no arquivo informado ou na saída padrão.
+func newLogNotifier(outboxPath string) notify.Notifier {
+	var out io.Writer = os.Stdout
+	if outboxPath != "" {
+		file, err := os.OpenFile(outboxPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			log.Fatalf("Erro ao abrir o arquivo de saída %s: %v", outboxPath, err)
+		}
+		out = file
+	}
+	return notify.NewLogNotifier(out)
+}
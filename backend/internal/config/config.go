@@ -5,19 +5,33 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // Config representa todas as configurações da aplicação.
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
+	Server       ServerConfig
+	Database     DatabaseConfig
+	JWT          JWTConfig
+	Reminder     ReminderConfig
+	SMTP         SMTPConfig
+	Events       EventsConfig
+	Outbox       OutboxConfig
+	Webhook      WebhookConfig
+	Storage      StorageConfig
+	Verification VerificationConfig
+	Account      AccountConfig
+	LoginCode    LoginCodeConfig
+	RateLimit    RateLimitConfig
 }
 
 // ServerConfig contém as configurações do servidor HTTP.
 type ServerConfig struct {
-	Port string
-	Mode string // "debug", "release", "test"
+	Port           string
+	Mode           string   // "debug", "release", "test"
+	PublicURL      string   // Endereço público da API, usado nos links que ela mesma gera (ex.: feed de calendário)
+	TrustedProxies []string // IPs ou CIDRs dos proxies cujo X-Forwarded-For é aceito; vazio = nenhum
 }
 
 // DatabaseConfig contém as configurações de conexão com o SQL Server.
@@ -36,13 +50,101 @@ type JWTConfig struct {
 	RefreshTokenExpiry int // em dias
 }
 
+// ReminderConfig contém as configurações dos lembretes de agendamento.
+type ReminderConfig struct {
+	Enabled    bool
+	Offsets    []time.Duration // Antecedências dos lembretes (ex.: 24h e 15m)
+	Interval   time.Duration   // Intervalo entre as varreduras
+	Channel    string          // "email" ou "log"
+	OutboxPath string          // Arquivo do canal "log"; vazio grava na saída padrão
+}
+
+// SMTPConfig contém as configurações do servidor de e-mail.
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	From     string
+}
+
+// EventsConfig contém as configurações do stream de eventos em tempo real (SSE).
+type EventsConfig struct {
+	HistorySize int           // Eventos guardados por usuário para retomada via Last-Event-ID
+	Heartbeat   time.Duration // Intervalo dos comentários que mantêm a conexão aberta
+	Retention   time.Duration // Tempo que o histórico de um usuário sem conexões abertas fica disponível
+}
+
+// OutboxConfig contém as configurações do dispatcher do outbox de eventos.
+type OutboxConfig struct {
+	Interval    time.Duration // Intervalo entre as varreduras de mensagens pendentes
+	BatchSize   int
+	MaxAttempts int           // Tentativas antes da fila de mortos
+	BaseBackoff time.Duration // Espera após a primeira falha; dobra a cada nova falha
+	MaxBackoff  time.Duration
+}
+
+// WebhookConfig contém as configurações do envio de webhooks às instituições.
+type WebhookConfig struct {
+	Interval    time.Duration // Intervalo entre as varreduras de entregas pendentes
+	Timeout     time.Duration // Tempo limite de cada requisição
+	MaxAttempts int
+	BaseBackoff time.Duration // Espera após a primeira falha; dobra a cada nova falha
+	MaxBackoff  time.Duration
+}
+
+// StorageConfig contém as configurações do armazenamento de arquivos enviados.
+type StorageConfig struct {
+	Dir string // Diretório raiz do armazenamento local
+}
+
+// VerificationConfig contém as configurações da verificação de voluntários.
+type VerificationConfig struct {
+	RequireVerified bool  // Apenas voluntários verificados buscam sugestões e pedem conexões
+	MaxDocumentSize int64 // Tamanho máximo de cada documento, em bytes
+}
+
+// AccountConfig contém as configurações da redefinição de senha e da verificação de e-mail.
+type AccountConfig struct {
+	AppURL               string        // Endereço do aplicativo usado nos links enviados por e-mail
+	MailChannel          string        // log ou email
+	MailOutboxPath       string        // Arquivo do canal log (vazio = saída padrão)
+	PasswordResetTTL     time.Duration // Validade do link de redefinição de senha
+	EmailVerificationTTL time.Duration // Validade do link de verificação de e-mail
+	RequireVerifiedEmail bool          // Apenas contas com e-mail confirmado pedem conexões e agendam
+}
+
+// LoginCodeConfig contém as configurações do login sem senha por código de acesso e
+// do bloqueio da conta, que vale também para o login com senha.
+type LoginCodeConfig struct {
+	TTL                time.Duration // Validade de cada código
+	MaxAttempts        int           // Tentativas erradas antes de o código ser descartado
+	ResendInterval     time.Duration // Espera mínima entre dois pedidos de código
+	LockoutThreshold   int           // Falhas seguidas que bloqueiam a conta
+	LockoutDuration    time.Duration // Duração do primeiro bloqueio; dobra a cada novo bloqueio
+	LockoutMaxDuration time.Duration // Duração máxima do bloqueio
+	SMSChannel         string        // Canal dos códigos por SMS; apenas log por enquanto
+	SMSOutboxPath      string        // Arquivo do canal log (vazio = saída padrão)
+}
+
+// RateLimitConfig contém os limites de requisições por minuto (0 = sem limite).
+type RateLimitConfig struct {
+	Enabled         bool
+	AuthPerIP       int // Rotas públicas de autenticação, por IP
+	LoginPerAccount int // Tentativas de login e envio de códigos, por e-mail
+	APIPerUser      int // Rotas autenticadas, por usuário
+	APIPerIP        int // Todas as rotas da API, por IP
+}
+
 // Load carrega todas as configurações a partir de variáveis de ambiente.
 // Valores padrão são usados quando as variáveis não estão definidas.
 func Load() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "8080"),
-			Mode: getEnv("GIN_MODE", "debug"),
+			Port:           getEnv("SERVER_PORT", "8080"),
+			Mode:           getEnv("GIN_MODE", "debug"),
+			PublicURL:      getEnv("PUBLIC_URL", "http://localhost:8080"),
+			TrustedProxies: getEnvAsList("TRUSTED_PROXIES"),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -56,6 +158,71 @@ func Load() *Config {
 			AccessTokenExpiry:  getEnvAsInt("JWT_ACCESS_EXPIRY_HOURS", 24),
 			RefreshTokenExpiry: getEnvAsInt("JWT_REFRESH_EXPIRY_DAYS", 7),
 		},
+		Reminder: ReminderConfig{
+			Enabled:    getEnv("REMINDERS_ENABLED", "true") == "true",
+			Offsets:    getEnvAsDurations("REMINDER_OFFSETS", []time.Duration{24 * time.Hour, 15 * time.Minute}),
+			Interval:   time.Duration(getEnvAsInt("REMINDER_INTERVAL_SECONDS", 60)) * time.Second,
+			Channel:    getEnv("REMINDER_CHANNEL", "log"),
+			OutboxPath: getEnv("REMINDER_OUTBOX_PATH", ""),
+		},
+		SMTP: SMTPConfig{
+			Host:     getEnv("SMTP_HOST", "localhost"),
+			Port:     getEnv("SMTP_PORT", "25"),
+			User:     getEnv("SMTP_USER", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("SMTP_FROM", "Amigos da Terceira Idade <nao-responda@amigosterceiraidade.com.br>"),
+		},
+		Events: EventsConfig{
+			HistorySize: getEnvAsInt("EVENTS_HISTORY_SIZE", 100),
+			Heartbeat:   time.Duration(getEnvAsInt("EVENTS_HEARTBEAT_SECONDS", 25)) * time.Second,
+			Retention:   time.Duration(getEnvAsInt("EVENTS_RETENTION_MINUTES", 10)) * time.Minute,
+		},
+		Outbox: OutboxConfig{
+			Interval:    time.Duration(getEnvAsInt("OUTBOX_INTERVAL_MS", 1000)) * time.Millisecond,
+			BatchSize:   getEnvAsInt("OUTBOX_BATCH_SIZE", 100),
+			MaxAttempts: getEnvAsInt("OUTBOX_MAX_ATTEMPTS", 10),
+			BaseBackoff: time.Duration(getEnvAsInt("OUTBOX_BASE_BACKOFF_SECONDS", 5)) * time.Second,
+			MaxBackoff:  time.Duration(getEnvAsInt("OUTBOX_MAX_BACKOFF_SECONDS", 3600)) * time.Second,
+		},
+		Webhook: WebhookConfig{
+			Interval:    time.Duration(getEnvAsInt("WEBHOOK_INTERVAL_SECONDS", 5)) * time.Second,
+			Timeout:     time.Duration(getEnvAsInt("WEBHOOK_TIMEOUT_SECONDS", 10)) * time.Second,
+			MaxAttempts: getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			BaseBackoff: time.Duration(getEnvAsInt("WEBHOOK_BASE_BACKOFF_SECONDS", 30)) * time.Second,
+			MaxBackoff:  time.Duration(getEnvAsInt("WEBHOOK_MAX_BACKOFF_SECONDS", 21600)) * time.Second,
+		},
+		Storage: StorageConfig{
+			Dir: getEnv("STORAGE_DIR", "./data/uploads"),
+		},
+		Verification: VerificationConfig{
+			RequireVerified: getEnv("REQUIRE_VERIFIED_VOLUNTEERS", "false") == "true",
+			MaxDocumentSize: int64(getEnvAsInt("VERIFICATION_MAX_DOCUMENT_MB", 10)) << 20,
+		},
+		Account: AccountConfig{
+			AppURL:               getEnv("APP_URL", "http://localhost:3000"),
+			MailChannel:          getEnv("MAIL_CHANNEL", "log"),
+			MailOutboxPath:       getEnv("MAIL_OUTBOX_PATH", ""),
+			PasswordResetTTL:     time.Duration(getEnvAsInt("PASSWORD_RESET_TTL_MINUTES", 60)) * time.Minute,
+			EmailVerificationTTL: time.Duration(getEnvAsInt("EMAIL_VERIFICATION_TTL_HOURS", 48)) * time.Hour,
+			RequireVerifiedEmail: getEnv("REQUIRE_VERIFIED_EMAIL", "false") == "true",
+		},
+		LoginCode: LoginCodeConfig{
+			TTL:                time.Duration(getEnvAsInt("LOGIN_CODE_TTL_MINUTES", 10)) * time.Minute,
+			MaxAttempts:        getEnvAsInt("LOGIN_CODE_MAX_ATTEMPTS", 5),
+			ResendInterval:     time.Duration(getEnvAsInt("LOGIN_CODE_RESEND_SECONDS", 60)) * time.Second,
+			LockoutThreshold:   getEnvAsInt("LOGIN_LOCKOUT_THRESHOLD", 10),
+			LockoutDuration:    time.Duration(getEnvAsInt("LOGIN_LOCKOUT_MINUTES", 15)) * time.Minute,
+			LockoutMaxDuration: time.Duration(getEnvAsInt("LOGIN_LOCKOUT_MAX_MINUTES", 1440)) * time.Minute,
+			SMSChannel:         getEnv("SMS_CHANNEL", "log"),
+			SMSOutboxPath:      getEnv("SMS_OUTBOX_PATH", ""),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:         getEnv("RATE_LIMIT_ENABLED", "true") == "true",
+			AuthPerIP:       getEnvAsInt("RATE_LIMIT_AUTH_PER_IP", 20),
+			LoginPerAccount: getEnvAsInt("RATE_LIMIT_LOGIN_PER_ACCOUNT", 5),
+			APIPerUser:      getEnvAsInt("RATE_LIMIT_API_PER_USER", 300),
+			APIPerIP:        getEnvAsInt("RATE_LIMIT_API_PER_IP", 600),
+		},
 	}
 }
 
@@ -79,3 +246,32 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return value
 }
+
+// getEnvAsList lê uma lista de valores separados por vírgula, ignorando os vazios.
+func getEnvAsList(key string) []string {
+	var values []string
+	for _, part := range strings.Split(getEnv(key, ""), ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+// getEnvAsDurations lê uma lista de durações separadas por vírgula (ex.: "24h,15m")
+// ou retorna o valor padrão se a variável estiver vazia ou inválida.
+func getEnvAsDurations(key string, defaultValue []time.Duration) []time.Duration {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+	var values []time.Duration
+	for _, part := range strings.Split(valueStr, ",") {
+		value, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil || value <= 0 {
+			return defaultValue
+		}
+		values = append(values, value)
+	}
+	return values
+}
@@ -0,0 +1,52 @@
+// Package domain contém as entidades de negócio da aplicação.
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrAccountTokenNotFound indica que o token de conta não existe.
+var ErrAccountTokenNotFound = errors.New("token não encontrado")
+
+// AccountTokenPurpose define para que serve um token enviado por e-mail.
+type AccountTokenPurpose string
+
+const (
+	AccountTokenPasswordReset     AccountTokenPurpose = "PASSWORD_RESET"     // Redefinição de senha esquecida
+	AccountTokenEmailVerification AccountTokenPurpose = "EMAIL_VERIFICATION" // Confirmação de que o e-mail é do usuário
+)
+
+// AccountToken registra um token enviado por e-mail ao usuário. O token entregue
+// carrega o ID assinado com o segredo do servidor; o banco guarda apenas o ID, o
+// propósito, a validade e quando foi usado, pois cada token vale uma única vez.
+type AccountToken struct {
+	ID        uuid.UUID           `gorm:"type:uniqueidentifier;primaryKey" json:"id"`
+	UserID    uuid.UUID           `gorm:"type:uniqueidentifier;not null;index" json:"user_id"`
+	Purpose   AccountTokenPurpose `gorm:"size:30;not null" json:"purpose"`
+	Email     string              `gorm:"size:255;not null" json:"email"` // Endereço para o qual o token foi enviado
+	ExpiresAt time.Time           `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time          `json:"used_at,omitempty"`
+	CreatedAt time.Time           `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName define o nome da tabela no banco de dados.
+func (AccountToken) TableName() string {
+	return "account_tokens"
+}
+
+// BeforeCreate é executado antes de inserir um novo token.
+func (t *AccountToken) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsUsable indica se o token ainda não foi usado nem expirou.
+func (t *AccountToken) IsUsable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
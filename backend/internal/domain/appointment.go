@@ -2,6 +2,7 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,30 +13,62 @@ import (
 type AppointmentStatus string
 
 const (
-	AppointmentStatusPending   AppointmentStatus = "PENDING"   // Convite enviado, aguardando aceite
-	AppointmentStatusConfirmed AppointmentStatus = "CONFIRMED" // Agendamento confirmado
-	AppointmentStatusCancelled AppointmentStatus = "CANCELLED" // Agendamento cancelado
-	AppointmentStatusCompleted AppointmentStatus = "COMPLETED" // Conversa realizada
+	AppointmentStatusPending            AppointmentStatus = "PENDING"             // Convite enviado, aguardando aceite
+	AppointmentStatusConfirmed          AppointmentStatus = "CONFIRMED"           // Agendamento confirmado
+	AppointmentStatusRescheduleProposed AppointmentStatus = "RESCHEDULE_PROPOSED" // Nova data proposta, aguardando o outro participante
+	AppointmentStatusCancelled          AppointmentStatus = "CANCELLED"           // Agendamento cancelado
+	AppointmentStatusCompleted          AppointmentStatus = "COMPLETED"           // Conversa realizada
+	AppointmentStatusNoShow             AppointmentStatus = "NO_SHOW"             // Participante não compareceu
 )
 
+// Limites de duração de uma conversa, em minutos.
+const (
+	DefaultAppointmentDuration = 30
+	MaxAppointmentDuration     = 240
+)
+
+// ActiveAppointmentStatuses são os status que ocupam a agenda dos participantes.
+// Com uma remarcação proposta, a data original continua reservada até o aceite.
+var ActiveAppointmentStatuses = []AppointmentStatus{
+	AppointmentStatusPending,
+	AppointmentStatusConfirmed,
+	AppointmentStatusRescheduleProposed,
+}
+
+// ErrAppointmentNotFound indica que o agendamento não existe.
+var ErrAppointmentNotFound = errors.New("agendamento não encontrado")
+
 // Appointment representa um agendamento de conversa entre voluntário e idoso.
+// Visitas a um residente têm a instituição como destinatário e o residente em ResidentID.
 type Appointment struct {
-	ID              uuid.UUID         `gorm:"type:uniqueidentifier;primaryKey" json:"id"`
-	VolunteerID     uuid.UUID         `gorm:"type:uniqueidentifier;not null" json:"volunteer_id"`
-	TargetID        uuid.UUID         `gorm:"type:uniqueidentifier;not null" json:"target_id"`
-	TargetType      UserType          `gorm:"size:20;not null" json:"target_type"`
-	Date            time.Time         `gorm:"not null" json:"date"`
-	DurationMinutes int               `gorm:"default:30" json:"duration_minutes"`
-	Status          AppointmentStatus `gorm:"size:20;default:PENDING" json:"status"`
-	MeetingURL      string            `gorm:"size:500" json:"meeting_url,omitempty"` // Link do Google Meet
-	Notes           string            `gorm:"type:text" json:"notes,omitempty"`
-	Rating          int               `gorm:"" json:"rating,omitempty"` // Avaliação pós-conversa (1-5)
-	CreatedAt       time.Time         `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt       time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+	ID                 uuid.UUID         `gorm:"type:uniqueidentifier;primaryKey" json:"id"`
+	VolunteerID        uuid.UUID         `gorm:"type:uniqueidentifier;not null;index:idx_appointments_volunteer_date,priority:1" json:"volunteer_id"`
+	TargetID           uuid.UUID         `gorm:"type:uniqueidentifier;not null;index:idx_appointments_target_date,priority:1" json:"target_id"`
+	TargetType         UserType          `gorm:"size:20;not null" json:"target_type"`
+	Date               time.Time         `gorm:"not null;index:idx_appointments_volunteer_date,priority:2;index:idx_appointments_target_date,priority:2" json:"date"` // Sempre em UTC
+	Timezone           string            `gorm:"size:64" json:"timezone,omitempty"`                                                                                   // Fuso de quem criou o agendamento
+	DurationMinutes    int               `gorm:"default:30" json:"duration_minutes"`
+	Status             AppointmentStatus `gorm:"size:20;default:PENDING" json:"status"`
+	MeetingURL         string            `gorm:"size:500" json:"meeting_url,omitempty"` // Link do Google Meet
+	Notes              string            `gorm:"type:text" json:"notes,omitempty"`
+	Rating             int               `gorm:"" json:"rating,omitempty"`                                 // Avaliação pós-conversa (1-5)
+	SeriesID           *uuid.UUID        `gorm:"type:uniqueidentifier;index" json:"series_id,omitempty"`   // Série recorrente, se houver
+	ResidentID         *uuid.UUID        `gorm:"type:uniqueidentifier;index" json:"resident_id,omitempty"` // Residente visitado, se houver
+	ProposedDate       *time.Time        `gorm:"" json:"proposed_date,omitempty"`                          // Nova data proposta (RESCHEDULE_PROPOSED)
+	ProposedBy         *uuid.UUID        `gorm:"type:uniqueidentifier" json:"proposed_by,omitempty"`       // Participante que propôs a remarcação
+	ProposedFromStatus AppointmentStatus `gorm:"size:20" json:"-"`                                         // Status ao qual voltar se a proposta for recusada
+	CalendarSequence   int               `gorm:"default:0" json:"-"`                                       // Revisão do evento exportado (SEQUENCE do iCalendar)
+	CreatedAt          time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Horário local de quem consulta (não persistido; veja Localize)
+	LocalDate      *time.Time `gorm:"-" json:"local_date,omitempty"`
+	ViewerTimezone string     `gorm:"-" json:"viewer_timezone,omitempty"`
 
 	// Relacionamentos
-	Volunteer User `gorm:"foreignKey:VolunteerID" json:"volunteer,omitempty"`
-	Target    User `gorm:"foreignKey:TargetID" json:"target,omitempty"`
+	Volunteer User      `gorm:"foreignKey:VolunteerID" json:"volunteer,omitempty"`
+	Target    User      `gorm:"foreignKey:TargetID" json:"target,omitempty"`
+	Resident  *Resident `gorm:"foreignKey:ResidentID" json:"resident,omitempty"`
 }
 
 // TableName define o nome da tabela no banco de dados.
@@ -50,3 +83,37 @@ func (a *Appointment) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// Localize preenche o horário do agendamento no fuso de quem está consultando.
+func (a *Appointment) Localize(loc *time.Location) {
+	local := a.Date.In(loc)
+	a.LocalDate = &local
+	a.ViewerTimezone = loc.String()
+}
+
+// LocationOf retorna o fuso do participante informado, usando os relacionamentos
+// carregados; sem eles, usa o fuso de quem criou o agendamento.
+func (a *Appointment) LocationOf(userID uuid.UUID) *time.Location {
+	switch {
+	case userID == a.VolunteerID && a.Volunteer.Timezone != "":
+		return a.Volunteer.Location()
+	case userID == a.TargetID && a.Target.Timezone != "":
+		return a.Target.Location()
+	}
+	return LoadLocation(a.Timezone)
+}
+
+// EndsAt retorna o horário previsto de término da conversa.
+func (a *Appointment) EndsAt() time.Time {
+	return a.Date.Add(time.Duration(a.DurationMinutes) * time.Minute)
+}
+
+// IsParticipant verifica se o usuário é o voluntário ou o destinatário do agendamento.
+func (a *Appointment) IsParticipant(userID uuid.UUID) bool {
+	return a.VolunteerID == userID || a.TargetID == userID
+}
+
+// Overlaps verifica se o agendamento ocupa algum instante do intervalo [start, end).
+func (a *Appointment) Overlaps(start, end time.Time) bool {
+	return a.Date.Before(end) && a.EndsAt().After(start)
+}
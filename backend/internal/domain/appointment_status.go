@@ -0,0 +1,116 @@
+// Package domain contém as entidades de negócio da aplicação.
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidStatusTransition indica uma mudança de status não permitida.
+var ErrInvalidStatusTransition = errors.New("transição de status inválida")
+
+// appointmentTransitions define a máquina de estados dos agendamentos.
+// Estados sem transições de saída (concluído, cancelado, não compareceu) são finais.
+var appointmentTransitions = map[AppointmentStatus][]AppointmentStatus{
+	AppointmentStatusPending: {
+		AppointmentStatusConfirmed,
+		AppointmentStatusCancelled,
+		AppointmentStatusRescheduleProposed,
+	},
+	AppointmentStatusConfirmed: {
+		AppointmentStatusCompleted,
+		AppointmentStatusCancelled,
+		AppointmentStatusNoShow,
+		AppointmentStatusRescheduleProposed,
+	},
+	AppointmentStatusRescheduleProposed: {
+		AppointmentStatusConfirmed, // Proposta aceita: a nova data passa a valer
+		AppointmentStatusPending,   // Proposta recusada em um convite ainda pendente
+		AppointmentStatusCancelled,
+	},
+}
+
+// CanTransitionTo verifica se o status pode mudar para o próximo estado.
+func (s AppointmentStatus) CanTransitionTo(next AppointmentStatus) bool {
+	for _, allowed := range appointmentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValid verifica se o status é um dos estados conhecidos.
+func (s AppointmentStatus) IsValid() bool {
+	switch s {
+	case AppointmentStatusPending, AppointmentStatusConfirmed, AppointmentStatusRescheduleProposed,
+		AppointmentStatusCancelled, AppointmentStatusCompleted, AppointmentStatusNoShow:
+		return true
+	}
+	return false
+}
+
+// IsFinal verifica se o status não admite mais transições.
+func (s AppointmentStatus) IsFinal() bool {
+	return len(appointmentTransitions[s]) == 0
+}
+
+// ValidateTransition retorna erro se a mudança de status não for permitida.
+func (s AppointmentStatus) ValidateTransition(next AppointmentStatus) error {
+	if !s.CanTransitionTo(next) {
+		return fmt.Errorf("%w: %s → %s", ErrInvalidStatusTransition, s, next)
+	}
+	return nil
+}
+
+// AppointmentStatusHistory registra cada mudança de status de um agendamento:
+// quem mudou, de qual estado para qual, quando e por quê.
+type AppointmentStatusHistory struct {
+	ID            uuid.UUID         `gorm:"type:uniqueidentifier;primaryKey" json:"id"`
+	AppointmentID uuid.UUID         `gorm:"type:uniqueidentifier;not null;index" json:"appointment_id"`
+	FromStatus    AppointmentStatus `gorm:"size:20" json:"from_status,omitempty"` // Vazio na criação
+	ToStatus      AppointmentStatus `gorm:"size:20;not null" json:"to_status"`
+	ChangedBy     uuid.UUID         `gorm:"type:uniqueidentifier;not null" json:"changed_by"`
+	Reason        string            `gorm:"size:500" json:"reason,omitempty"`
+	FromDate      *time.Time        `gorm:"" json:"from_date,omitempty"` // Data anterior, em remarcações
+	ToDate        *time.Time        `gorm:"" json:"to_date,omitempty"`   // Nova data, em remarcações
+	CreatedAt     time.Time         `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName define o nome da tabela no banco de dados.
+func (AppointmentStatusHistory) TableName() string {
+	return "appointment_status_history"
+}
+
+// BeforeCreate é executado antes de inserir um novo registro de histórico.
+func (h *AppointmentStatusHistory) BeforeCreate(tx *gorm.DB) error {
+	if h.ID == uuid.Nil {
+		h.ID = uuid.New()
+	}
+	return nil
+}
+
+// NewStatusChange cria o registro de histórico para levar o agendamento ao próximo status.
+func NewStatusChange(appointment *Appointment, to AppointmentStatus, changedBy uuid.UUID, reason string) *AppointmentStatusHistory {
+	return &AppointmentStatusHistory{
+		AppointmentID: appointment.ID,
+		FromStatus:    appointment.Status,
+		ToStatus:      to,
+		ChangedBy:     changedBy,
+		Reason:        reason,
+	}
+}
+
+// NewReschedule cria o registro de histórico para mover o agendamento para uma nova data,
+// preservando a data original. O status pode permanecer o mesmo.
+func NewReschedule(appointment *Appointment, to AppointmentStatus, newDate time.Time, changedBy uuid.UUID, reason string) *AppointmentStatusHistory {
+	change := NewStatusChange(appointment, to, changedBy, reason)
+	fromDate := appointment.Date
+	change.FromDate = &fromDate
+	change.ToDate = &newDate
+	return change
+}
@@ -0,0 +1,47 @@
+// Package domain contém as entidades de negócio da aplicação.
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuditAction identifica uma ação administrativa registrada no log de auditoria.
+type AuditAction string
+
+const (
+	AuditUserDeactivated   AuditAction = "user.deactivated"
+	AuditUserReactivated   AuditAction = "user.reactivated"
+	AuditUserRoleChanged   AuditAction = "user.role_changed"
+	AuditUserPasswordReset AuditAction = "user.password_reset"
+	AuditUserMerged        AuditAction = "user.merged"
+
+	AuditVolunteerApproved AuditAction = "volunteer.verification_approved"
+	AuditVolunteerRejected AuditAction = "volunteer.verification_rejected"
+)
+
+// AuditLog registra uma ação administrativa: quem fez, o quê e sobre qual usuário.
+// Os registros nunca são alterados nem removidos pela aplicação.
+type AuditLog struct {
+	ID           uuid.UUID   `gorm:"type:uniqueidentifier;primaryKey" json:"id"`
+	ActorID      uuid.UUID   `gorm:"type:uniqueidentifier;not null;index" json:"actor_id"`
+	Action       AuditAction `gorm:"size:50;not null" json:"action"`
+	TargetUserID uuid.UUID   `gorm:"type:uniqueidentifier;not null;index" json:"target_user_id"`
+	Details      string      `gorm:"size:1000" json:"details,omitempty"`
+	CreatedAt    time.Time   `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+// TableName define o nome da tabela no banco de dados.
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// BeforeCreate é executado antes de inserir um novo registro.
+func (a *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Package domain contém as entidades de negócio da aplicação.
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DateLayout é o formato usado para datas sem horário (ex.: exceções de disponibilidade).
+const DateLayout = "2006-01-02"
+
+// VolunteerAvailability representa uma janela semanal recorrente em que o voluntário
+// está disponível (ex.: toda terça das 14:00 às 17:00).
+type VolunteerAvailability struct {
+	ID          uuid.UUID    `gorm:"type:uniqueidentifier;primaryKey" json:"id"`
+	VolunteerID uuid.UUID    `gorm:"type:uniqueidentifier;not null;index" json:"volunteer_id"`
+	Weekday     time.Weekday `gorm:"not null" json:"weekday"`           // 0 = domingo, 6 = sábado
+	StartTime   string       `gorm:"size:5;not null" json:"start_time"` // HH:MM
+	EndTime     string       `gorm:"size:5;not null" json:"end_time"`   // HH:MM
+	CreatedAt   time.Time    `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName define o nome da tabela no banco de dados.
+func (VolunteerAvailability) TableName() string {
+	return "volunteer_availability"
+}
+
+// BeforeCreate é executado antes de inserir uma nova janela de disponibilidade.
+func (a *VolunteerAvailability) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// AvailabilityException altera a disponibilidade semanal em uma data específica.
+// Com Available = false bloqueia o horário (ou o dia inteiro, se não houver horário);
+// com Available = true adiciona uma janela extra naquele dia.
+type AvailabilityException struct {
+	ID          uuid.UUID `gorm:"type:uniqueidentifier;primaryKey" json:"id"`
+	VolunteerID uuid.UUID `gorm:"type:uniqueidentifier;not null;index" json:"volunteer_id"`
+	Date        time.Time `gorm:"type:date;not null" json:"date"`
+	StartTime   string    `gorm:"size:5" json:"start_time,omitempty"` // Vazio = dia inteiro
+	EndTime     string    `gorm:"size:5" json:"end_time,omitempty"`
+	Available   bool      `gorm:"default:false" json:"available"`
+	Reason      string    `gorm:"size:255" json:"reason,omitempty"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName define o nome da tabela no banco de dados.
+func (AvailabilityException) TableName() string {
+	return "availability_exceptions"
+}
+
+// BeforeCreate é executado antes de inserir uma nova exceção.
+func (e *AvailabilityException) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsFullDay indica se a exceção vale para o dia inteiro.
+func (e *AvailabilityException) IsFullDay() bool {
+	return e.StartTime == "" && e.EndTime == ""
+}
+
+// ParseClock converte um horário HH:MM em minutos desde a meia-noite.
+// Aceita "24:00" como fim do dia.
+func ParseClock(value string) (int, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(value, "%d:%d", &hour, &minute); err != nil || len(value) != 5 {
+		return 0, fmt.Errorf("horário inválido: %q (use HH:MM)", value)
+	}
+	if hour < 0 || minute < 0 || minute > 59 || hour > 24 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("horário inválido: %q (use HH:MM)", value)
+	}
+	return hour*60 + minute, nil
+}
+
+// ValidateClockRange valida um intervalo HH:MM–HH:MM, exigindo início antes do fim.
+func ValidateClockRange(start, end string) error {
+	startMinute, err := ParseClock(start)
+	if err != nil {
+		return err
+	}
+	endMinute, err := ParseClock(end)
+	if err != nil {
+		return err
+	}
+	if startMinute >= endMinute {
+		return errors.New("o horário de início deve ser anterior ao de término")
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+// Package domain contém as entidades de negócio da aplicação.
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarFeed guarda o token secreto do feed de calendário (.ics) de um usuário.
+// Quem conhece o token consegue ler a agenda, por isso ele pode ser trocado a qualquer momento.
+type CalendarFeed struct {
+	UserID    uuid.UUID `gorm:"type:uniqueidentifier;primaryKey" json:"user_id"`
+	Token     string    `gorm:"size:64;not null;uniqueIndex" json:"token"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName define o nome da tabela no banco de dados.
+func (CalendarFeed) TableName() string {
+	return "calendar_feeds"
+}
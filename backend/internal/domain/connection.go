@@ -2,6 +2,7 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,20 +18,29 @@ const (
 	ConnectionStatusRejected ConnectionStatus = "REJECTED" // Conexão rejeitada
 )
 
+// ErrConnectionNotFound indica que a conexão não existe.
+var ErrConnectionNotFound = errors.New("conexão não encontrada")
+
+// ErrConnectionNotPending indica que a conexão já foi respondida.
+var ErrConnectionNotPending = errors.New("a conexão já foi respondida")
+
 // Connection representa uma conexão/pareamento entre voluntário e idoso/instituição.
+// Conexões com um residente têm a instituição como destinatário e o residente em ResidentID.
 type Connection struct {
 	ID               uuid.UUID        `gorm:"type:uniqueidentifier;primaryKey" json:"id"`
 	VolunteerID      uuid.UUID        `gorm:"type:uniqueidentifier;not null" json:"volunteer_id"`
 	TargetID         uuid.UUID        `gorm:"type:uniqueidentifier;not null" json:"target_id"`
 	TargetType       UserType         `gorm:"size:20;not null" json:"target_type"` // ELDERLY ou INSTITUTION
 	Status           ConnectionStatus `gorm:"size:20;default:PENDING" json:"status"`
-	MatchedInterests int              `gorm:"default:0" json:"matched_interests"` // Quantidade de interesses em comum
+	MatchedInterests int              `gorm:"default:0" json:"matched_interests"`                       // Quantidade de interesses em comum
+	ResidentID       *uuid.UUID       `gorm:"type:uniqueidentifier;index" json:"resident_id,omitempty"` // Residente da instituição, se houver
 	CreatedAt        time.Time        `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt        time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
 
 	// Relacionamentos para facilitar consultas
-	Volunteer User `gorm:"foreignKey:VolunteerID" json:"volunteer,omitempty"`
-	Target    User `gorm:"foreignKey:TargetID" json:"target,omitempty"`
+	Volunteer User      `gorm:"foreignKey:VolunteerID" json:"volunteer,omitempty"`
+	Target    User      `gorm:"foreignKey:TargetID" json:"target,omitempty"`
+	Resident  *Resident `gorm:"foreignKey:ResidentID" json:"resident,omitempty"`
 }
 
 // TableName define o nome da tabela no banco de dados.
@@ -0,0 +1,170 @@
+// Package domain contém as entidades de negócio da aplicação.
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType identifica um evento de domínio (ex.: "appointment.confirmed").
+type EventType string
+
+const (
+	EventAppointmentCreated            EventType = "appointment.created"
+	EventAppointmentConfirmed          EventType = "appointment.confirmed"
+	EventAppointmentCancelled          EventType = "appointment.cancelled"
+	EventAppointmentRescheduled        EventType = "appointment.rescheduled"
+	EventAppointmentRescheduleProposed EventType = "appointment.reschedule_proposed"
+	EventAppointmentRescheduleRejected EventType = "appointment.reschedule_rejected"
+	EventAppointmentCompleted          EventType = "appointment.completed"
+	EventAppointmentNoShow             EventType = "appointment.no_show"
+	EventAppointmentUpdated            EventType = "appointment.updated"
+	EventConnectionRequested           EventType = "connection.requested"
+	EventConnectionAccepted            EventType = "connection.accepted"
+	EventConnectionRejected            EventType = "connection.rejected"
+	EventWebhookTest                   EventType = "webhook.test"
+)
+
+// EventTypes lista os eventos de agendamentos e conexões que podem ser assinados.
+var EventTypes = []EventType{
+	EventAppointmentCreated,
+	EventAppointmentConfirmed,
+	EventAppointmentCancelled,
+	EventAppointmentRescheduled,
+	EventAppointmentRescheduleProposed,
+	EventAppointmentRescheduleRejected,
+	EventAppointmentCompleted,
+	EventAppointmentNoShow,
+	EventConnectionRequested,
+	EventConnectionAccepted,
+	EventConnectionRejected,
+}
+
+// IsValid verifica se o tipo de evento pode ser assinado.
+func (t EventType) IsValid() bool {
+	for _, valid := range EventTypes {
+		if t == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// Event descreve uma mudança em um agendamento ou conexão, entregue aos usuários envolvidos.
+type Event struct {
+	ID          uuid.UUID             `json:"id"` // Permite aos consumidores descartar entregas repetidas
+	Type        EventType             `json:"type"`
+	UserIDs     []uuid.UUID           `json:"user_ids"` // Usuários afetados pelo evento
+	ActorID     uuid.UUID             `json:"actor_id"` // Quem causou a mudança
+	OccurredAt  time.Time             `json:"occurred_at"`
+	Appointment *AppointmentEventData `json:"appointment,omitempty"`
+	Connection  *ConnectionEventData  `json:"connection,omitempty"`
+}
+
+// AppointmentEventData é o estado do agendamento após a mudança.
+type AppointmentEventData struct {
+	ID             uuid.UUID         `json:"id"`
+	VolunteerID    uuid.UUID         `json:"volunteer_id"`
+	TargetID       uuid.UUID         `json:"target_id"`
+	SeriesID       *uuid.UUID        `json:"series_id,omitempty"`
+	Status         AppointmentStatus `json:"status"`
+	PreviousStatus AppointmentStatus `json:"previous_status,omitempty"`
+	Date           time.Time         `json:"date"`
+	ProposedDate   *time.Time        `json:"proposed_date,omitempty"`
+}
+
+// ConnectionEventData é o estado da conexão após a mudança.
+type ConnectionEventData struct {
+	ID          uuid.UUID        `json:"id"`
+	VolunteerID uuid.UUID        `json:"volunteer_id"`
+	TargetID    uuid.UUID        `json:"target_id"`
+	Status      ConnectionStatus `json:"status"`
+}
+
+// statusChangeEventType define o evento publicado para uma mudança registrada no histórico.
+func statusChangeEventType(change *AppointmentStatusHistory) EventType {
+	switch {
+	case change.ToStatus == AppointmentStatusRescheduleProposed:
+		return EventAppointmentRescheduleProposed
+	case change.ToDate != nil:
+		return EventAppointmentRescheduled
+	case change.FromStatus == AppointmentStatusRescheduleProposed:
+		return EventAppointmentRescheduleRejected
+	}
+
+	switch change.ToStatus {
+	case AppointmentStatusConfirmed:
+		return EventAppointmentConfirmed
+	case AppointmentStatusCancelled:
+		return EventAppointmentCancelled
+	case AppointmentStatusCompleted:
+		return EventAppointmentCompleted
+	case AppointmentStatusNoShow:
+		return EventAppointmentNoShow
+	}
+	return EventAppointmentUpdated
+}
+
+// NewAppointmentEvent cria o evento da criação de um agendamento.
+func NewAppointmentEvent(eventType EventType, appointment *Appointment, actorID uuid.UUID) Event {
+	return Event{
+		ID:         uuid.New(),
+		Type:       eventType,
+		UserIDs:    []uuid.UUID{appointment.VolunteerID, appointment.TargetID},
+		ActorID:    actorID,
+		OccurredAt: time.Now().UTC(),
+		Appointment: &AppointmentEventData{
+			ID:           appointment.ID,
+			VolunteerID:  appointment.VolunteerID,
+			TargetID:     appointment.TargetID,
+			SeriesID:     appointment.SeriesID,
+			Status:       appointment.Status,
+			Date:         appointment.Date,
+			ProposedDate: appointment.ProposedDate,
+		},
+	}
+}
+
+// NewStatusChangeEvent cria o evento de uma mudança de status registrada no histórico.
+// O tipo do evento depende do status de destino; a data reflete a remarcação, se houver.
+func NewStatusChangeEvent(appointment *Appointment, change *AppointmentStatusHistory) Event {
+	event := NewAppointmentEvent(statusChangeEventType(change), appointment, change.ChangedBy)
+	event.Appointment.Status = change.ToStatus
+	event.Appointment.PreviousStatus = change.FromStatus
+	event.Appointment.ProposedDate = nil
+
+	switch {
+	case change.ToStatus == AppointmentStatusRescheduleProposed:
+		event.Appointment.ProposedDate = change.ToDate
+	case change.ToDate != nil:
+		event.Appointment.Date = *change.ToDate
+	}
+	return event
+}
+
+// NewConnectionStatusEvent cria o evento da resposta do destinatário a um pedido de conexão.
+func NewConnectionStatusEvent(connection *Connection) Event {
+	eventType := EventConnectionAccepted
+	if connection.Status == ConnectionStatusRejected {
+		eventType = EventConnectionRejected
+	}
+	return NewConnectionEvent(eventType, connection, connection.TargetID)
+}
+
+// NewConnectionEvent cria o evento de uma mudança na conexão.
+func NewConnectionEvent(eventType EventType, connection *Connection, actorID uuid.UUID) Event {
+	return Event{
+		ID:         uuid.New(),
+		Type:       eventType,
+		UserIDs:    []uuid.UUID{connection.VolunteerID, connection.TargetID},
+		ActorID:    actorID,
+		OccurredAt: time.Now().UTC(),
+		Connection: &ConnectionEventData{
+			ID:          connection.ID,
+			VolunteerID: connection.VolunteerID,
+			TargetID:    connection.TargetID,
+			Status:      connection.Status,
+		},
+	}
+}
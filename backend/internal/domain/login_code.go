@@ -0,0 +1,68 @@
+// Package domain contém as entidades de negócio da aplicação.
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrLoginCodeNotFound indica que o usuário não tem código de acesso válido.
+var ErrLoginCodeNotFound = errors.New("código de acesso não encontrado")
+
+// LoginCodeChannel define por onde o código de acesso é enviado.
+type LoginCodeChannel string
+
+const (
+	LoginCodeChannelEmail LoginCodeChannel = "email"
+	LoginCodeChannelSMS   LoginCodeChannel = "sms" // Para o telefone do perfil
+)
+
+// LoginCode é um código de 6 dígitos que substitui a senha em um login, pensado para
+// os idosos que têm dificuldade com senhas. O banco guarda apenas o hash do código;
+// cada pedido invalida os anteriores e cada código vale uma única vez.
+type LoginCode struct {
+	ID        uuid.UUID        `gorm:"type:uniqueidentifier;primaryKey" json:"id"`
+	UserID    uuid.UUID        `gorm:"type:uniqueidentifier;not null;index" json:"user_id"`
+	Channel   LoginCodeChannel `gorm:"size:10;not null" json:"channel"`
+	CodeHash  string           `gorm:"size:64;not null" json:"-"`
+	Attempts  int              `gorm:"not null;default:0" json:"attempts"` // Tentativas erradas com este código
+	ExpiresAt time.Time        `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time       `json:"used_at,omitempty"`
+	CreatedAt time.Time        `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName define o nome da tabela no banco de dados.
+func (LoginCode) TableName() string {
+	return "login_codes"
+}
+
+// BeforeCreate é executado antes de inserir um novo código.
+func (c *LoginCode) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+// AccountLockout guarda as falhas de login (por código ou senha) de uma conta e até quando ela está
+// bloqueada depois de falhas demais.
+type AccountLockout struct {
+	UserID      uuid.UUID  `gorm:"type:uniqueidentifier;primaryKey" json:"user_id"`
+	Failures    int        `gorm:"not null;default:0" json:"failures"` // Falhas desde o último sucesso ou bloqueio
+	Lockouts    int        `gorm:"not null;default:0" json:"lockouts"` // Bloqueios desde o último sucesso; aumentam a duração do próximo
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName define o nome da tabela no banco de dados.
+func (AccountLockout) TableName() string {
+	return "account_lockouts"
+}
+
+// IsLocked indica se a conta está bloqueada no momento.
+func (l *AccountLockout) IsLocked(now time.Time) bool {
+	return l.LockedUntil != nil && now.Before(*l.LockedUntil)
+}
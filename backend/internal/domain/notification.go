@@ -0,0 +1,94 @@
+// Package domain contém as entidades de negócio da aplicação.
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NotificationType define os eventos que geram notificações no aplicativo.
+type NotificationType string
+
+const (
+	NotificationInvitationReceived   NotificationType = "INVITATION_RECEIVED"   // Novo convite de conversa
+	NotificationInvitationAccepted   NotificationType = "INVITATION_ACCEPTED"   // Convite aceito pelo destinatário
+	NotificationInvitationDeclined   NotificationType = "INVITATION_DECLINED"   // Convite recusado pelo destinatário
+	NotificationAppointmentCancelled NotificationType = "APPOINTMENT_CANCELLED" // Conversa cancelada pelo outro participante
+	NotificationRescheduleProposed   NotificationType = "RESCHEDULE_PROPOSED"   // Nova data proposta pelo outro participante
+	NotificationConnectionRequested  NotificationType = "CONNECTION_REQUESTED"  // Pedido de conexão recebido
+	NotificationConnectionAccepted   NotificationType = "CONNECTION_ACCEPTED"   // Pedido de conexão aceito
+	NotificationAppointmentReminder  NotificationType = "APPOINTMENT_REMINDER"  // Conversa se aproximando
+	NotificationVerificationApproved NotificationType = "VERIFICATION_APPROVED" // Verificação do voluntário aprovada
+	NotificationVerificationRejected NotificationType = "VERIFICATION_REJECTED" // Verificação recusada ou revogada
+	NotificationProxyGranted         NotificationType = "PROXY_GRANTED"         // Delegação recebida de um idoso
+	NotificationProxyRevoked         NotificationType = "PROXY_REVOKED"         // Delegação revogada
+)
+
+// NotificationTypes lista todos os tipos de notificação, na ordem exibida nas preferências.
+var NotificationTypes = []NotificationType{
+	NotificationInvitationReceived,
+	NotificationInvitationAccepted,
+	NotificationInvitationDeclined,
+	NotificationAppointmentCancelled,
+	NotificationRescheduleProposed,
+	NotificationConnectionRequested,
+	NotificationConnectionAccepted,
+	NotificationAppointmentReminder,
+	NotificationVerificationApproved,
+	NotificationVerificationRejected,
+	NotificationProxyGranted,
+	NotificationProxyRevoked,
+}
+
+// IsValid verifica se o tipo de notificação existe.
+func (t NotificationType) IsValid() bool {
+	for _, known := range NotificationTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification representa uma notificação na caixa de entrada de um usuário.
+type Notification struct {
+	ID            uuid.UUID        `gorm:"type:uniqueidentifier;primaryKey" json:"id"`
+	UserID        uuid.UUID        `gorm:"type:uniqueidentifier;not null;index:idx_notifications_user_created,priority:1" json:"user_id"`
+	Type          NotificationType `gorm:"size:40;not null" json:"type"`
+	Title         string           `gorm:"size:200;not null" json:"title"`
+	Body          string           `gorm:"size:1000" json:"body,omitempty"`
+	ActorID       *uuid.UUID       `gorm:"type:uniqueidentifier" json:"actor_id,omitempty"`       // Quem causou o evento
+	AppointmentID *uuid.UUID       `gorm:"type:uniqueidentifier" json:"appointment_id,omitempty"` // Agendamento relacionado
+	ConnectionID  *uuid.UUID       `gorm:"type:uniqueidentifier" json:"connection_id,omitempty"`  // Conexão relacionada
+	ReadAt        *time.Time       `gorm:"" json:"read_at,omitempty"`                             // Vazio enquanto não lida
+	CreatedAt     time.Time        `gorm:"autoCreateTime;index:idx_notifications_user_created,priority:2" json:"created_at"`
+}
+
+// TableName define o nome da tabela no banco de dados.
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// BeforeCreate é executado antes de inserir uma nova notificação.
+func (n *Notification) BeforeCreate(tx *gorm.DB) error {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	return nil
+}
+
+// NotificationPreference indica se o usuário quer receber um tipo de notificação.
+// Tipos sem preferência registrada estão ativos.
+type NotificationPreference struct {
+	UserID    uuid.UUID        `gorm:"type:uniqueidentifier;primaryKey" json:"-"`
+	Type      NotificationType `gorm:"size:40;primaryKey" json:"type"`
+	Enabled   bool             `gorm:"not null" json:"enabled"`
+	UpdatedAt time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName define o nome da tabela no banco de dados.
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
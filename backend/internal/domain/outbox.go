@@ -0,0 +1,91 @@
+// Package domain contém as entidades de negócio da aplicação.
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OutboxStatus define os possíveis estados de uma mensagem do outbox.
+type OutboxStatus string
+
+const (
+	OutboxStatusPending   OutboxStatus = "PENDING"   // Aguardando entrega (ou nova tentativa)
+	OutboxStatusDelivered OutboxStatus = "DELIVERED" // Entregue a todos os handlers
+	OutboxStatusDead      OutboxStatus = "DEAD"      // Tentativas esgotadas; exige intervenção
+)
+
+// OutboxMessage é um evento de domínio gravado na mesma transação da mudança de estado
+// que o originou, e entregue depois pelo dispatcher. Assim, uma queda entre a gravação
+// e o envio não perde o evento.
+type OutboxMessage struct {
+	ID            uuid.UUID    `gorm:"type:uniqueidentifier;primaryKey" json:"id"`
+	EventType     EventType    `gorm:"size:64;not null" json:"event_type"`
+	Payload       string       `gorm:"type:nvarchar(max);not null" json:"payload"` // Evento serializado em JSON
+	Status        OutboxStatus `gorm:"size:20;not null;default:PENDING;index:idx_outbox_due,priority:1" json:"status"`
+	Attempts      int          `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time    `gorm:"not null;index:idx_outbox_due,priority:2" json:"next_attempt_at"`
+	DeliveredTo   string       `gorm:"size:500" json:"delivered_to,omitempty"` // Handlers que já processaram, separados por vírgula
+	LastError     string       `gorm:"size:1000" json:"last_error,omitempty"`
+	CreatedAt     time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	ProcessedAt   *time.Time   `json:"processed_at,omitempty"` // Entrega concluída ou envio para a fila de mortos
+}
+
+// TableName define o nome da tabela no banco de dados.
+func (OutboxMessage) TableName() string {
+	return "outbox"
+}
+
+// BeforeCreate é executado antes de inserir uma nova mensagem.
+func (m *OutboxMessage) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
+
+// NewOutboxMessage serializa o evento em uma mensagem pronta para entrega.
+func NewOutboxMessage(event Event) (*OutboxMessage, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return &OutboxMessage{
+		EventType:     event.Type,
+		Payload:       string(payload),
+		Status:        OutboxStatusPending,
+		NextAttemptAt: event.OccurredAt,
+	}, nil
+}
+
+// Event desserializa o evento da mensagem.
+func (m *OutboxMessage) Event() (Event, error) {
+	var event Event
+	err := json.Unmarshal([]byte(m.Payload), &event)
+	return event, err
+}
+
+// DeliveredToHandler indica se o handler já processou a mensagem em uma tentativa anterior.
+func (m *OutboxMessage) DeliveredToHandler(name string) bool {
+	for _, delivered := range strings.Split(m.DeliveredTo, ",") {
+		if delivered == name {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkDeliveredTo registra que o handler processou a mensagem.
+func (m *OutboxMessage) MarkDeliveredTo(name string) {
+	if m.DeliveredToHandler(name) {
+		return
+	}
+	if m.DeliveredTo != "" {
+		m.DeliveredTo += ","
+	}
+	m.DeliveredTo += name
+}
@@ -0,0 +1,152 @@
+// Package domain contém as entidades de negócio da aplicação.
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrProxyGrantNotFound indica que a delegação não existe ou já foi revogada.
+var ErrProxyGrantNotFound = errors.New("delegação não encontrada")
+
+// ProxyPermission define o que um familiar pode fazer em nome de um idoso.
+type ProxyPermission string
+
+const (
+	ProxyPermissionView               ProxyPermission = "view"                // Ver perfil, agendamentos, convites e conexões
+	ProxyPermissionRespondInvitations ProxyPermission = "invitations:respond" // Aceitar e recusar convites e pedidos de conexão
+	ProxyPermissionEditProfile        ProxyPermission = "profile:edit"        // Alterar o perfil do idoso
+)
+
+// ProxyPermissions lista as permissões que podem ser concedidas.
+var ProxyPermissions = []ProxyPermission{
+	ProxyPermissionView,
+	ProxyPermissionRespondInvitations,
+	ProxyPermissionEditProfile,
+}
+
+// IsValid verifica se a permissão é uma das conhecidas.
+func (p ProxyPermission) IsValid() bool {
+	for _, known := range ProxyPermissions {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ProxyGrant é a delegação de um idoso para a conta de um familiar (procurador).
+// O idoso concede e revoga; o procurador também pode abrir mão da delegação.
+// Delegações revogadas são mantidas para que o log de ações continue legível.
+type ProxyGrant struct {
+	ID             uuid.UUID         `gorm:"type:uniqueidentifier;primaryKey" json:"id"`
+	ElderlyID      uuid.UUID         `gorm:"type:uniqueidentifier;not null;index" json:"elderly_id"`
+	ProxyID        uuid.UUID         `gorm:"type:uniqueidentifier;not null;index" json:"proxy_id"`
+	PermissionList string            `gorm:"column:permissions;size:255;not null" json:"-"` // Permissões separadas por vírgula
+	Permissions    []ProxyPermission `gorm:"-" json:"permissions"`
+	Relationship   string            `gorm:"size:100" json:"relationship,omitempty"` // Ex.: filha, sobrinho
+	RevokedAt      *time.Time        `json:"revoked_at,omitempty"`
+	RevokedBy      *uuid.UUID        `gorm:"type:uniqueidentifier" json:"revoked_by,omitempty"`
+	CreatedAt      time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relacionamentos
+	Elderly User `gorm:"foreignKey:ElderlyID" json:"elderly,omitempty"`
+	Proxy   User `gorm:"foreignKey:ProxyID" json:"proxy,omitempty"`
+}
+
+// TableName define o nome da tabela no banco de dados.
+func (ProxyGrant) TableName() string {
+	return "proxy_grants"
+}
+
+// BeforeCreate é executado antes de inserir uma nova delegação.
+func (g *ProxyGrant) BeforeCreate(tx *gorm.DB) error {
+	if g.ID == uuid.Nil {
+		g.ID = uuid.New()
+	}
+	return nil
+}
+
+// BeforeSave grava a lista de permissões na coluna permissions.
+func (g *ProxyGrant) BeforeSave(tx *gorm.DB) error {
+	permissions := make([]string, 0, len(g.Permissions))
+	for _, permission := range g.Permissions {
+		permissions = append(permissions, string(permission))
+	}
+	g.PermissionList = strings.Join(permissions, ",")
+	return nil
+}
+
+// AfterFind preenche a lista de permissões a partir da coluna permissions.
+func (g *ProxyGrant) AfterFind(tx *gorm.DB) error {
+	g.Permissions = nil
+	for _, permission := range strings.Split(g.PermissionList, ",") {
+		if permission != "" {
+			g.Permissions = append(g.Permissions, ProxyPermission(permission))
+		}
+	}
+	return nil
+}
+
+// IsActive indica se a delegação ainda vale.
+func (g *ProxyGrant) IsActive() bool {
+	return g.RevokedAt == nil
+}
+
+// Allows indica se a delegação ativa concede a permissão.
+func (g *ProxyGrant) Allows(permission ProxyPermission) bool {
+	if !g.IsActive() {
+		return false
+	}
+	for _, granted := range g.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// ProxyAction identifica uma ação registrada no log das delegações.
+type ProxyAction string
+
+const (
+	ProxyActionGrantCreated       ProxyAction = "grant.created"
+	ProxyActionGrantUpdated       ProxyAction = "grant.updated"
+	ProxyActionGrantRevoked       ProxyAction = "grant.revoked"
+	ProxyActionProfileUpdated     ProxyAction = "profile.updated"
+	ProxyActionInvitationAccepted ProxyAction = "invitation.accepted"
+	ProxyActionInvitationDeclined ProxyAction = "invitation.declined"
+	ProxyActionConnectionAccepted ProxyAction = "connection.accepted"
+	ProxyActionConnectionRejected ProxyAction = "connection.rejected"
+)
+
+// ProxyActionLog registra cada ação sobre a conta de um idoso feita por um procurador,
+// ou sobre a própria delegação: quem agiu (ActorID), em nome de quem e sobre o quê.
+type ProxyActionLog struct {
+	ID         uuid.UUID   `gorm:"type:uniqueidentifier;primaryKey" json:"id"`
+	GrantID    uuid.UUID   `gorm:"type:uniqueidentifier;not null;index" json:"grant_id"`
+	ElderlyID  uuid.UUID   `gorm:"type:uniqueidentifier;not null;index" json:"elderly_id"`
+	ActorID    uuid.UUID   `gorm:"type:uniqueidentifier;not null" json:"actor_id"`
+	Action     ProxyAction `gorm:"size:50;not null" json:"action"`
+	ResourceID *uuid.UUID  `gorm:"type:uniqueidentifier" json:"resource_id,omitempty"` // Agendamento ou conexão afetados
+	Details    string      `gorm:"size:500" json:"details,omitempty"`
+	CreatedAt  time.Time   `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+// TableName define o nome da tabela no banco de dados.
+func (ProxyActionLog) TableName() string {
+	return "proxy_action_logs"
+}
+
+// BeforeCreate é executado antes de inserir um novo registro.
+func (l *ProxyActionLog) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+// Package domain contém as entidades de negócio da aplicação.
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RecurrenceFrequency define a frequência suportada nas regras de recorrência.
+type RecurrenceFrequency string
+
+const (
+	RecurrenceWeekly  RecurrenceFrequency = "WEEKLY"  // Semanal (INTERVAL=2 para quinzenal)
+	RecurrenceMonthly RecurrenceFrequency = "MONTHLY" // Mensal, no mesmo dia do mês
+)
+
+// MaxOccurrences limita quantos agendamentos uma série pode gerar.
+const MaxOccurrences = 52
+
+// RecurrenceRule é o subconjunto suportado de uma RRULE (RFC 5545):
+// FREQ=WEEKLY|MONTHLY, INTERVAL (1 ou 2 para semanal, 1 para mensal) e COUNT ou UNTIL.
+// O dia da semana/mês e o horário vêm da data da primeira ocorrência.
+type RecurrenceRule struct {
+	Frequency RecurrenceFrequency
+	Interval  int
+	Count     int
+	Until     time.Time
+}
+
+// ParseRecurrenceRule interpreta uma RRULE, com ou sem o prefixo "RRULE:".
+// Ex.: "FREQ=WEEKLY;COUNT=8", "FREQ=WEEKLY;INTERVAL=2;UNTIL=20251231".
+func ParseRecurrenceRule(value string) (*RecurrenceRule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return nil, errors.New("regra de recorrência vazia")
+	}
+
+	rule := &RecurrenceRule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("parte inválida na regra de recorrência: %q", part)
+		}
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Frequency = RecurrenceFrequency(strings.ToUpper(val))
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("INTERVAL inválido: %q", val)
+			}
+			rule.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(val)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("COUNT inválido: %q", val)
+			}
+			rule.Count = count
+		case "UNTIL":
+			until, err := parseRecurrenceUntil(val)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = until
+		default:
+			return nil, fmt.Errorf("parâmetro de recorrência não suportado: %s", key)
+		}
+	}
+
+	switch rule.Frequency {
+	case RecurrenceWeekly:
+		if rule.Interval > 2 {
+			return nil, errors.New("recorrência semanal aceita apenas INTERVAL=1 ou 2")
+		}
+	case RecurrenceMonthly:
+		if rule.Interval != 1 {
+			return nil, errors.New("recorrência mensal aceita apenas INTERVAL=1")
+		}
+	default:
+		return nil, errors.New("FREQ deve ser WEEKLY ou MONTHLY")
+	}
+
+	if (rule.Count == 0) == rule.Until.IsZero() {
+		return nil, errors.New("informe COUNT ou UNTIL (apenas um deles)")
+	}
+	if rule.Count > MaxOccurrences {
+		return nil, fmt.Errorf("uma série pode ter no máximo %d ocorrências", MaxOccurrences)
+	}
+
+	return rule, nil
+}
+
+// parseRecurrenceUntil aceita UNTIL como data-hora UTC (AAAAMMDDTHHMMSSZ) ou data (AAAAMMDD).
+// Uma data sem horário inclui o dia inteiro.
+func parseRecurrenceUntil(value string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("20060102", value); err == nil {
+		return t.Add(24*time.Hour - time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("UNTIL inválido: %q", value)
+}
+
+// String retorna a regra no formato RRULE canônico.
+func (r *RecurrenceRule) String() string {
+	parts := []string{"FREQ=" + string(r.Frequency)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	} else {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Occurrences gera as datas da série a partir da primeira ocorrência, mantendo o
+// horário local de start. Meses sem o dia de start (ex.: 31) são pulados, como na RFC 5545.
+func (r *RecurrenceRule) Occurrences(start time.Time) []time.Time {
+	var dates []time.Time
+	for i := 0; len(dates) < MaxOccurrences; i++ {
+		var next time.Time
+		if r.Frequency == RecurrenceMonthly {
+			next = start.AddDate(0, i*r.Interval, 0)
+			if next.Day() != start.Day() {
+				continue
+			}
+		} else {
+			next = start.AddDate(0, 0, 7*i*r.Interval)
+		}
+
+		if !r.Until.IsZero() && next.After(r.Until) {
+			break
+		}
+		dates = append(dates, next)
+		if r.Count > 0 && len(dates) == r.Count {
+			break
+		}
+	}
+	return dates
+}
+
+// AppointmentSeries agrupa os agendamentos gerados por uma regra de recorrência
+// (ex.: "toda terça às 15h").
+type AppointmentSeries struct {
+	ID              uuid.UUID `gorm:"type:uniqueidentifier;primaryKey" json:"id"`
+	VolunteerID     uuid.UUID `gorm:"type:uniqueidentifier;not null;index" json:"volunteer_id"`
+	TargetID        uuid.UUID `gorm:"type:uniqueidentifier;not null;index" json:"target_id"`
+	RRule           string    `gorm:"size:255;not null" json:"rrule"`
+	DurationMinutes int       `gorm:"default:30" json:"duration_minutes"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// Ocorrências materializadas da série
+	Appointments []Appointment `gorm:"foreignKey:SeriesID" json:"appointments,omitempty"`
+}
+
+// TableName define o nome da tabela no banco de dados.
+func (AppointmentSeries) TableName() string {
+	return "appointment_series"
+}
+
+// BeforeCreate é executado antes de inserir uma nova série.
+func (s *AppointmentSeries) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
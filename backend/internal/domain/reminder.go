@@ -0,0 +1,37 @@
+// Package domain contém as entidades de negócio da aplicação.
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AppointmentReminder registra um lembrete de agendamento enviado (ou em envio) a um participante.
+// A chave única (agendamento, participante, antecedência, data) garante que o mesmo lembrete
+// não seja enviado duas vezes, mesmo após reinícios; se o agendamento for remarcado,
+// a nova data gera novos lembretes.
+type AppointmentReminder struct {
+	ID              uuid.UUID  `gorm:"type:uniqueidentifier;primaryKey" json:"id"`
+	AppointmentID   uuid.UUID  `gorm:"type:uniqueidentifier;not null;uniqueIndex:idx_reminders_unique,priority:1" json:"appointment_id"`
+	UserID          uuid.UUID  `gorm:"type:uniqueidentifier;not null;uniqueIndex:idx_reminders_unique,priority:2" json:"user_id"`
+	OffsetMinutes   int        `gorm:"not null;uniqueIndex:idx_reminders_unique,priority:3" json:"offset_minutes"` // Antecedência do lembrete
+	AppointmentDate time.Time  `gorm:"not null;uniqueIndex:idx_reminders_unique,priority:4" json:"appointment_date"`
+	Channel         string     `gorm:"size:20;not null" json:"channel"` // Canal usado (email, log)
+	SentAt          *time.Time `gorm:"" json:"sent_at,omitempty"`       // Vazio enquanto o envio está em andamento
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName define o nome da tabela no banco de dados.
+func (AppointmentReminder) TableName() string {
+	return "appointment_reminders"
+}
+
+// BeforeCreate é executado antes de inserir um novo lembrete.
+func (r *AppointmentReminder) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+// Package domain contém as entidades de negócio da aplicação.
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrResidentNotFound indica que o residente não existe.
+var ErrResidentNotFound = errors.New("residente não encontrado")
+
+// Resident é o perfil de um idoso que mora em uma instituição e não usa o aplicativo.
+// A instituição cria e mantém o perfil e responde, em nome do residente, aos pedidos de
+// conexão e aos convites: nas conexões e agendamentos o destinatário (TargetID) é a
+// instituição e ResidentID identifica o residente.
+type Resident struct {
+	ID              uuid.UUID `gorm:"type:uniqueidentifier;primaryKey" json:"id"`
+	InstitutionID   uuid.UUID `gorm:"type:uniqueidentifier;not null;index" json:"institution_id"`
+	Name            string    `gorm:"size:255;not null" json:"name"`
+	Age             int       `gorm:"" json:"age,omitempty"`
+	Bio             string    `gorm:"type:text" json:"bio,omitempty"`
+	AssistanceNeeds string    `gorm:"size:1000" json:"assistance_needs,omitempty"` // Ex.: baixa audição, usa cadeira de rodas
+	PhotoURL        string    `gorm:"size:500" json:"photo_url,omitempty"`
+	IsActive        bool      `gorm:"default:true" json:"is_active"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relacionamentos
+	Interests   []Interest `gorm:"many2many:resident_interests;" json:"interests,omitempty"`
+	Institution User       `gorm:"foreignKey:InstitutionID" json:"institution,omitempty"`
+}
+
+// TableName define o nome da tabela no banco de dados.
+func (Resident) TableName() string {
+	return "residents"
+}
+
+// BeforeCreate é executado antes de inserir um novo residente.
+func (r *Resident) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
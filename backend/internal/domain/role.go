@@ -0,0 +1,89 @@
+// Package domain contém as entidades de negócio da aplicação.
+package domain
+
+import "errors"
+
+// Role define o papel administrativo de um usuário, independente do UserType.
+// Usuários comuns não têm papel (string vazia).
+type Role string
+
+const (
+	RoleAdmin            Role = "ADMIN"             // Administração completa da plataforma
+	RoleModerator        Role = "MODERATOR"         // Moderação de usuários e agendamentos
+	RoleInstitutionStaff Role = "INSTITUTION_STAFF" // Equipe de uma instituição
+)
+
+// Permission define uma ação administrativa verificada pelo middleware RequirePermission.
+type Permission string
+
+const (
+	PermissionUsersRead           Permission = "users:read"            // Listar e consultar usuários
+	PermissionUsersDeactivate     Permission = "users:deactivate"      // Desativar e reativar contas
+	PermissionUsersManage         Permission = "users:manage"          // Redefinir senhas e mesclar contas
+	PermissionRolesManage         Permission = "roles:manage"          // Atribuir papéis
+	PermissionAppointmentsReadAll Permission = "appointments:read_all" // Ver todos os agendamentos
+	PermissionAuditRead           Permission = "audit:read"            // Consultar o log de auditoria
+	PermissionVolunteersVerify    Permission = "volunteers:verify"     // Analisar a verificação de voluntários
+	PermissionInstitutionManage   Permission = "institution:manage"    // Gerir webhooks, residentes e janelas de visita da instituição vinculada
+)
+
+// ErrInvalidRole indica um papel desconhecido.
+var ErrInvalidRole = errors.New("papel inválido: use ADMIN, MODERATOR ou INSTITUTION_STAFF")
+
+// rolePermissions define as permissões de cada papel. O administrador tem todas.
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		PermissionUsersRead,
+		PermissionUsersDeactivate,
+		PermissionUsersManage,
+		PermissionRolesManage,
+		PermissionAppointmentsReadAll,
+		PermissionAuditRead,
+		PermissionVolunteersVerify,
+		PermissionInstitutionManage,
+	},
+	RoleModerator: {
+		PermissionUsersRead,
+		PermissionUsersDeactivate,
+		PermissionAppointmentsReadAll,
+		PermissionVolunteersVerify,
+	},
+	RoleInstitutionStaff: {
+		PermissionInstitutionManage,
+	},
+}
+
+// ParseRole valida o papel informado; vazio remove o papel do usuário.
+func ParseRole(value string) (Role, error) {
+	role := Role(value)
+	if role == "" {
+		return role, nil
+	}
+	if _, ok := rolePermissions[role]; !ok {
+		return "", ErrInvalidRole
+	}
+	return role, nil
+}
+
+// Can verifica se o papel concede a permissão.
+func (r Role) Can(permission Permission) bool {
+	for _, granted := range rolePermissions[r] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// roleRank ordena os papéis para as ações de um usuário sobre outro; quem não tem
+// papel fica abaixo de todos.
+var roleRank = map[Role]int{
+	RoleInstitutionStaff: 1,
+	RoleModerator:        2,
+	RoleAdmin:            3,
+}
+
+// Outranks verifica se o papel está acima de outro na hierarquia.
+func (r Role) Outranks(other Role) bool {
+	return roleRank[r] > roleRank[other]
+}
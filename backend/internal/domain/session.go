@@ -0,0 +1,69 @@
+// Package domain contém as entidades de negócio da aplicação.
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrSessionNotFound indica que a sessão não existe.
+var ErrSessionNotFound = errors.New("sessão não encontrada")
+
+// SessionRevokeReason explica por que uma sessão foi encerrada.
+type SessionRevokeReason string
+
+const (
+	SessionRevokedLogout    SessionRevokeReason = "LOGOUT"     // O usuário saiu desta sessão
+	SessionRevokedLogoutAll SessionRevokeReason = "LOGOUT_ALL" // O usuário saiu de todas as sessões
+	SessionRevokedReuse     SessionRevokeReason = "REUSE"      // Um refresh token já trocado foi reapresentado
+	SessionRevokedByUser    SessionRevokeReason = "REVOKED"    // O usuário encerrou a sessão pela lista de dispositivos
+	SessionRevokedPassword  SessionRevokeReason = "PASSWORD"   // A senha foi redefinida
+)
+
+// Session é um login de um usuário. O refresh token em uso é identificado por
+// RefreshTokenID (o jti do JWT) e troca a cada renovação; apresentar um refresh
+// token anterior indica que ele vazou e encerra a sessão inteira.
+// Os access tokens carregam o ID da sessão e deixam de valer quando ela é encerrada.
+// O dispositivo e o IP permitem ao usuário reconhecer e encerrar logins esquecidos,
+// como o de um tablet compartilhado na instituição.
+type Session struct {
+	ID             uuid.UUID           `gorm:"type:uniqueidentifier;primaryKey" json:"id"`
+	UserID         uuid.UUID           `gorm:"type:uniqueidentifier;not null;index" json:"user_id"`
+	RefreshTokenID uuid.UUID           `gorm:"type:uniqueidentifier;not null" json:"-"`
+	DeviceName     string              `gorm:"size:255" json:"device_name"` // Ex.: android 2.4.0, a partir de X-Platform e X-App-Version
+	UserAgent      string              `gorm:"size:500" json:"user_agent,omitempty"`
+	IPAddress      string              `gorm:"size:45" json:"ip_address"`
+	LastSeenAt     time.Time           `gorm:"not null" json:"last_seen_at"`
+	ExpiresAt      time.Time           `gorm:"not null" json:"expires_at"`
+	RevokedAt      *time.Time          `json:"revoked_at,omitempty"`
+	RevokedReason  SessionRevokeReason `gorm:"size:20" json:"revoked_reason,omitempty"`
+	CreatedAt      time.Time           `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Current indica, na listagem, a sessão usada na própria requisição
+	Current bool `gorm:"-" json:"current"`
+
+	// Relacionamentos
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// TableName define o nome da tabela no banco de dados.
+func (Session) TableName() string {
+	return "auth_sessions"
+}
+
+// BeforeCreate é executado antes de inserir uma nova sessão.
+func (s *Session) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsActive indica se a sessão não foi encerrada nem expirou.
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
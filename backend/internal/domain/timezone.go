@@ -0,0 +1,35 @@
+// Package domain contém as entidades de negócio da aplicação.
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultTimezone é o fuso usado quando o usuário não informou o seu (horário de Brasília).
+const DefaultTimezone = "America/Sao_Paulo"
+
+// LoadLocation carrega um fuso IANA (ex.: "America/Manaus"), usando o fuso padrão
+// quando o nome está vazio ou é desconhecido.
+func LoadLocation(name string) *time.Location {
+	if name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	if loc, err := time.LoadLocation(DefaultTimezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// ValidateTimezone verifica se o nome é um fuso IANA válido.
+func ValidateTimezone(name string) error {
+	if name == "" || name == "Local" {
+		return fmt.Errorf("fuso horário inválido: %q (use um nome IANA, ex.: America/Sao_Paulo)", name)
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return fmt.Errorf("fuso horário inválido: %q (use um nome IANA, ex.: America/Sao_Paulo)", name)
+	}
+	return nil
+}
@@ -3,12 +3,20 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// Erros da mesclagem de contas: dados da conta duplicada que não são transferidos
+// sem revisão da equipe.
+var (
+	ErrMergeVerifiedDuplicate = errors.New("a conta duplicada tem a verificação aprovada; mantenha-a ou revise a verificação antes de mesclar")
+	ErrMergeProxyGrants       = errors.New("a conta duplicada tem delegações ativas; revogue-as antes de mesclar")
+)
+
 // UserType define os tipos de usuário no sistema.
 type UserType string
 
@@ -30,9 +38,18 @@ type User struct {
 	Phone        string    `gorm:"type:text" json:"phone,omitempty"`
 	PhotoURL     string    `gorm:"size:500" json:"photo_url,omitempty"`
 	UserType     UserType  `gorm:"size:20;not null" json:"user_type"`
+	Timezone     string    `gorm:"size:64;default:America/Sao_Paulo" json:"timezone"` // Fuso IANA
 	IsActive     bool      `gorm:"default:true" json:"is_active"`
-	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	Role         Role      `gorm:"size:20" json:"role,omitempty"` // Papel administrativo, além do UserType
+
+	// Momento em que o usuário confirmou o e-mail pelo link enviado; nulo se não confirmou
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+
+	// Instituição da equipe, para usuários com RoleInstitutionStaff
+	StaffInstitutionID *uuid.UUID `gorm:"type:uniqueidentifier" json:"staff_institution_id,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 
 	// Relacionamentos
 	Interests []Interest `gorm:"many2many:user_interests;" json:"interests,omitempty"`
@@ -52,14 +69,26 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// IsEmailVerified indica se o usuário já confirmou o e-mail.
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// Location retorna o fuso horário do usuário.
+func (u *User) Location() *time.Location {
+	return LoadLocation(u.Timezone)
+}
+
 // Volunteer representa dados adicionais de um voluntário.
 type Volunteer struct {
 	UserID         uuid.UUID `gorm:"type:uniqueidentifier;primaryKey" json:"user_id"`
 	DedicatedHours float64   `gorm:"default:0" json:"dedicated_hours"`
-	IsVerified     bool      `gorm:"default:false" json:"is_verified"`
+	IsVerified     bool      `gorm:"default:false" json:"is_verified"` // Verdadeiro apenas com a verificação aprovada
 	RatingAvg      float64   `gorm:"default:0" json:"rating_avg"`
 	RatingCount    int       `gorm:"default:0" json:"rating_count"`
 
+	VerificationStatus VerificationStatus `gorm:"size:20;not null;default:NOT_SUBMITTED" json:"verification_status"`
+
 	// Relacionamento com User
 	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
 }
@@ -88,13 +117,12 @@ func (Elderly) TableName() string {
 type Institution struct {
 	UserID          uuid.UUID `gorm:"type:uniqueidentifier;primaryKey" json:"user_id"`
 	InstitutionType string    `gorm:"size:100" json:"institution_type"`
-	VisitDays       string    `gorm:"size:255" json:"visit_days,omitempty"`
-	VisitTime       string    `gorm:"size:50" json:"visit_time,omitempty"`
-	VisitDuration   string    `gorm:"size:50" json:"visit_duration,omitempty"`
 	ResponsibleName string    `gorm:"size:255" json:"responsible_name,omitempty"`
 
 	// Relacionamento com User
 	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	// Janelas de visita estruturadas (substituem os antigos campos de texto livre)
+	VisitWindows []VisitWindow `gorm:"foreignKey:InstitutionID;references:UserID" json:"visit_windows,omitempty"`
 }
 
 // TableName define o nome da tabela no banco de dados.
@@ -0,0 +1,124 @@
+// Package domain contém as entidades de negócio da aplicação.
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// VerificationStatus define os estados da verificação de antecedentes de um voluntário.
+type VerificationStatus string
+
+const (
+	VerificationNotSubmitted VerificationStatus = "NOT_SUBMITTED" // Nenhum pedido enviado
+	VerificationPending      VerificationStatus = "PENDING"       // Aguardando análise da equipe
+	VerificationApproved     VerificationStatus = "APPROVED"      // Documentos aprovados: voluntário verificado
+	VerificationRejected     VerificationStatus = "REJECTED"      // Recusado; o voluntário pode reenviar
+)
+
+// verificationTransitions define a máquina de estados da verificação.
+// Uma aprovação pode ser revogada (APPROVED → REJECTED), por exemplo após uma denúncia.
+var verificationTransitions = map[VerificationStatus][]VerificationStatus{
+	VerificationNotSubmitted: {VerificationPending},
+	VerificationPending:      {VerificationApproved, VerificationRejected},
+	VerificationApproved:     {VerificationRejected},
+	VerificationRejected:     {VerificationPending},
+}
+
+// IsValid verifica se o status é um dos estados conhecidos.
+func (s VerificationStatus) IsValid() bool {
+	_, ok := verificationTransitions[s]
+	return ok
+}
+
+// ValidateTransition retorna erro se a mudança de status não for permitida.
+func (s VerificationStatus) ValidateTransition(next VerificationStatus) error {
+	for _, allowed := range verificationTransitions[s] {
+		if allowed == next {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s → %s", ErrInvalidStatusTransition, s, next)
+}
+
+// CurrentVerification retorna o status da verificação do voluntário.
+// Registros criados antes da verificação existir não têm status e contam como não enviados.
+func (v *Volunteer) CurrentVerification() VerificationStatus {
+	if v.VerificationStatus == "" {
+		return VerificationNotSubmitted
+	}
+	return v.VerificationStatus
+}
+
+// DocumentKind define os tipos de documento aceitos na verificação.
+type DocumentKind string
+
+const (
+	DocumentIdentity       DocumentKind = "IDENTITY"        // Documento de identidade com foto
+	DocumentCriminalRecord DocumentKind = "CRIMINAL_RECORD" // Certidão de antecedentes criminais
+	DocumentProofOfAddress DocumentKind = "PROOF_OF_ADDRESS"
+	DocumentOther          DocumentKind = "OTHER"
+)
+
+// IsValid verifica se o tipo de documento é aceito.
+func (k DocumentKind) IsValid() bool {
+	switch k {
+	case DocumentIdentity, DocumentCriminalRecord, DocumentProofOfAddress, DocumentOther:
+		return true
+	}
+	return false
+}
+
+// VerificationDocument é um documento enviado pelo voluntário para a verificação.
+// O arquivo fica no armazenamento configurado; aqui ficam apenas os metadados e a chave.
+type VerificationDocument struct {
+	ID          uuid.UUID    `gorm:"type:uniqueidentifier;primaryKey" json:"id"`
+	VolunteerID uuid.UUID    `gorm:"type:uniqueidentifier;not null;index" json:"volunteer_id"`
+	Kind        DocumentKind `gorm:"size:30;not null" json:"kind"`
+	FileName    string       `gorm:"size:255;not null" json:"file_name"` // Nome original, apenas para exibição
+	ContentType string       `gorm:"size:100;not null" json:"content_type"`
+	Size        int64        `gorm:"not null" json:"size"`
+	StorageKey  string       `gorm:"size:500;not null" json:"-"`
+	CreatedAt   time.Time    `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName define o nome da tabela no banco de dados.
+func (VerificationDocument) TableName() string {
+	return "verification_documents"
+}
+
+// BeforeCreate é executado antes de inserir um novo documento.
+func (d *VerificationDocument) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
+
+// VerificationStatusHistory registra cada mudança da verificação de um voluntário:
+// quem mudou, de qual estado para qual, quando e por quê.
+type VerificationStatusHistory struct {
+	ID          uuid.UUID          `gorm:"type:uniqueidentifier;primaryKey" json:"id"`
+	VolunteerID uuid.UUID          `gorm:"type:uniqueidentifier;not null;index" json:"volunteer_id"`
+	FromStatus  VerificationStatus `gorm:"size:20;not null" json:"from_status"`
+	ToStatus    VerificationStatus `gorm:"size:20;not null" json:"to_status"`
+	ChangedBy   uuid.UUID          `gorm:"type:uniqueidentifier;not null" json:"changed_by"`
+	Reason      string             `gorm:"size:500" json:"reason,omitempty"` // Obrigatório nas recusas
+	CreatedAt   time.Time          `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName define o nome da tabela no banco de dados.
+func (VerificationStatusHistory) TableName() string {
+	return "verification_status_history"
+}
+
+// BeforeCreate é executado antes de inserir um novo registro de histórico.
+func (h *VerificationStatusHistory) BeforeCreate(tx *gorm.DB) error {
+	if h.ID == uuid.Nil {
+		h.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// Package domain contém as entidades de negócio da aplicação.
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// VisitWindow representa uma janela semanal de visitas de uma instituição
+// (ex.: toda quarta das 14:00 às 16:00, conversas de até 45 minutos, 3 visitantes por vez).
+type VisitWindow struct {
+	ID                 uuid.UUID    `gorm:"type:uniqueidentifier;primaryKey" json:"id"`
+	InstitutionID      uuid.UUID    `gorm:"type:uniqueidentifier;not null;index" json:"institution_id"`
+	Weekday            time.Weekday `gorm:"not null" json:"weekday"`               // 0 = domingo, 6 = sábado
+	StartTime          string       `gorm:"size:5;not null" json:"start_time"`     // HH:MM
+	EndTime            string       `gorm:"size:5;not null" json:"end_time"`       // HH:MM
+	MaxDurationMinutes int          `gorm:"default:0" json:"max_duration_minutes"` // 0 = sem limite
+	MaxVisitors        int          `gorm:"default:0" json:"max_visitors"`         // Visitantes simultâneos; 0 = sem limite
+	CreatedAt          time.Time    `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName define o nome da tabela no banco de dados.
+func (VisitWindow) TableName() string {
+	return "institution_visit_windows"
+}
+
+// BeforeCreate é executado antes de inserir uma nova janela de visitas.
+func (w *VisitWindow) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	return nil
+}
+
+// Contains verifica se o intervalo [start, end) cabe inteiro na janela,
+// considerando o horário no fuso informado.
+func (w *VisitWindow) Contains(start, end time.Time, loc *time.Location) bool {
+	start = start.In(loc)
+	if start.Weekday() != w.Weekday {
+		return false
+	}
+
+	from, err := ParseClock(w.StartTime)
+	if err != nil {
+		return false
+	}
+	to, err := ParseClock(w.EndTime)
+	if err != nil {
+		return false
+	}
+
+	// Como o fim da janela não passa de 24:00, o intervalo não atravessa a meia-noite
+	startMinute := start.Hour()*60 + start.Minute()
+	endMinute := startMinute + int(end.Sub(start)/time.Minute)
+	return startMinute >= from && endMinute <= to
+}
+
+// AllowsDuration verifica se a duração respeita o limite da janela.
+func (w *VisitWindow) AllowsDuration(minutes int) bool {
+	return w.MaxDurationMinutes <= 0 || minutes <= w.MaxDurationMinutes
+}
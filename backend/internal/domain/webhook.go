@@ -0,0 +1,110 @@
+// Package domain contém as entidades de negócio da aplicação.
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WebhookEndpoint é um endereço de uma instituição parceira que recebe os eventos assinados.
+// As entregas são assinadas com HMAC-SHA256 usando o segredo do endpoint.
+type WebhookEndpoint struct {
+	ID            uuid.UUID   `gorm:"type:uniqueidentifier;primaryKey" json:"id"`
+	InstitutionID uuid.UUID   `gorm:"type:uniqueidentifier;not null;index" json:"institution_id"`
+	URL           string      `gorm:"size:500;not null" json:"url"`
+	Secret        string      `gorm:"size:100;not null" json:"secret,omitempty"` // Exibido apenas na criação
+	EventList     string      `gorm:"column:events;size:1000;not null" json:"-"` // Tipos separados por vírgula
+	Events        []EventType `gorm:"-" json:"events"`
+	IsActive      bool        `gorm:"default:true" json:"is_active"`
+	CreatedAt     time.Time   `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time   `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName define o nome da tabela no banco de dados.
+func (WebhookEndpoint) TableName() string {
+	return "webhook_endpoints"
+}
+
+// BeforeCreate é executado antes de inserir um novo endpoint.
+func (w *WebhookEndpoint) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	return nil
+}
+
+// BeforeSave grava a lista de eventos na coluna events.
+func (w *WebhookEndpoint) BeforeSave(tx *gorm.DB) error {
+	types := make([]string, 0, len(w.Events))
+	for _, eventType := range w.Events {
+		types = append(types, string(eventType))
+	}
+	w.EventList = strings.Join(types, ",")
+	return nil
+}
+
+// AfterFind preenche a lista de eventos a partir da coluna events.
+func (w *WebhookEndpoint) AfterFind(tx *gorm.DB) error {
+	w.Events = nil
+	for _, eventType := range strings.Split(w.EventList, ",") {
+		if eventType != "" {
+			w.Events = append(w.Events, EventType(eventType))
+		}
+	}
+	return nil
+}
+
+// Subscribes indica se o endpoint assina o tipo de evento.
+func (w *WebhookEndpoint) Subscribes(eventType EventType) bool {
+	for _, subscribed := range w.Events {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus define os possíveis estados de uma entrega de webhook.
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"   // Aguardando envio (ou nova tentativa)
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "SUCCEEDED" // O receptor respondeu 2xx
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "FAILED"    // Tentativas esgotadas
+)
+
+// WebhookDelivery registra a entrega de um evento a um endpoint e o resultado da última tentativa.
+// O índice único (endpoint, evento) impede que o mesmo evento seja enfileirado duas vezes.
+type WebhookDelivery struct {
+	ID             uuid.UUID             `gorm:"type:uniqueidentifier;primaryKey" json:"id"`
+	EndpointID     uuid.UUID             `gorm:"type:uniqueidentifier;not null;uniqueIndex:idx_webhook_deliveries_event,priority:1" json:"endpoint_id"`
+	EventID        uuid.UUID             `gorm:"type:uniqueidentifier;not null;uniqueIndex:idx_webhook_deliveries_event,priority:2" json:"event_id"`
+	EventType      EventType             `gorm:"size:64;not null" json:"event_type"`
+	Payload        string                `gorm:"type:nvarchar(max);not null" json:"payload"`
+	Status         WebhookDeliveryStatus `gorm:"size:20;not null;default:PENDING;index:idx_webhook_deliveries_due,priority:1" json:"status"`
+	Attempts       int                   `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time             `gorm:"not null;index:idx_webhook_deliveries_due,priority:2" json:"next_attempt_at"`
+	ResponseStatus int                   `json:"response_status,omitempty"`        // Código HTTP da última tentativa
+	Error          string                `gorm:"size:1000" json:"error,omitempty"` // Falha de rede ou resposta não 2xx
+	DurationMs     int64                 `json:"duration_ms"`                      // Duração da última tentativa
+	CreatedAt      time.Time             `gorm:"autoCreateTime;index" json:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+
+	Endpoint WebhookEndpoint `gorm:"foreignKey:EndpointID" json:"-"`
+}
+
+// TableName define o nome da tabela no banco de dados.
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// BeforeCreate é executado antes de inserir uma nova entrega.
+func (d *WebhookDelivery) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
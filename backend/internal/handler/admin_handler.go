@@ -0,0 +1,408 @@
+// Package handler contém os handlers HTTP da aplicação.
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+	"amigos-terceira-idade/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AdminHandler gerencia os endpoints administrativos.
+// O acesso a cada rota é controlado por middleware.RequirePermission no router.
+type AdminHandler struct {
+	adminService *service.AdminService
+}
+
+// NewAdminHandler cria uma nova instância do handler administrativo.
+func NewAdminHandler(adminService *service.AdminService) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+	}
+}
+
+// SearchUsers godoc
+// @Summary Busca usuários
+// @Description Busca usuários por nome/email, tipo e situação, inclusive desativados (requer users:read)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Trecho do nome ou do email"
+// @Param type query string false "VOLUNTEER, ELDERLY ou INSTITUTION"
+// @Param active query bool false "Apenas ativos (true) ou desativados (false)"
+// @Param page query int false "Página (padrão 1)"
+// @Param per_page query int false "Itens por página (padrão 20, máximo 100)"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Router /admin/users [get]
+func (h *AdminHandler) SearchUsers(c *gin.Context) {
+	search := service.UserSearch{
+		Query:    c.Query("q"),
+		UserType: domain.UserType(c.Query("type")),
+	}
+	search.Page, _ = strconv.Atoi(c.Query("page"))
+	search.PerPage, _ = strconv.Atoi(c.Query("per_page"))
+	if value := c.Query("active"); value != "" {
+		active, err := strconv.ParseBool(value)
+		if err != nil {
+			ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "O parâmetro active deve ser true ou false")
+			return
+		}
+		search.IsActive = &active
+	}
+
+	result, err := h.adminService.SearchUsers(search)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "FETCH_ERROR", err.Error())
+		return
+	}
+
+	totalPages := int((result.Total + int64(result.PerPage) - 1) / int64(result.PerPage))
+	SuccessResponseWithMeta(c, http.StatusOK, result.Users, &MetaInfo{
+		Page:       result.Page,
+		PerPage:    result.PerPage,
+		Total:      int(result.Total),
+		TotalPages: totalPages,
+	})
+}
+
+// GetUser godoc
+// @Summary Detalhes de um usuário
+// @Description Retorna um usuário, ativo ou não (requer users:read)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Router /admin/users/{id} [get]
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	id, ok := parseAdminUserID(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.adminService.GetUser(id)
+	if err != nil {
+		ErrorResponse(c, http.StatusNotFound, "USER_NOT_FOUND", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, user)
+}
+
+// GetUserConnections godoc
+// @Summary Conexões de um usuário
+// @Description Retorna as conexões do usuário (requer users:read)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Router /admin/users/{id}/connections [get]
+func (h *AdminHandler) GetUserConnections(c *gin.Context) {
+	id, ok := parseAdminUserID(c)
+	if !ok {
+		return
+	}
+
+	connections, err := h.adminService.GetUserConnections(id)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "FETCH_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, connections)
+}
+
+// GetUserAppointments godoc
+// @Summary Agendamentos de um usuário
+// @Description Retorna os agendamentos do usuário (requer users:read)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Router /admin/users/{id}/appointments [get]
+func (h *AdminHandler) GetUserAppointments(c *gin.Context) {
+	id, ok := parseAdminUserID(c)
+	if !ok {
+		return
+	}
+
+	appointments, err := h.adminService.GetUserAppointments(id)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "FETCH_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, appointments)
+}
+
+// DeactivateUser godoc
+// @Summary Desativa a conta de um usuário
+// @Description Impede novos logins do usuário (requer users:deactivate)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Router /admin/users/{id}/deactivate [post]
+func (h *AdminHandler) DeactivateUser(c *gin.Context) {
+	actorID := c.MustGet("user_id").(uuid.UUID)
+	id, ok := parseAdminUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.adminService.DeactivateUser(actorID, id); err != nil {
+		adminErrorResponse(c, "DEACTIVATE_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Conta desativada com sucesso"})
+}
+
+// ReactivateUser godoc
+// @Summary Reativa a conta de um usuário
+// @Description Permite que um usuário desativado volte a entrar (requer users:deactivate)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Router /admin/users/{id}/reactivate [post]
+func (h *AdminHandler) ReactivateUser(c *gin.Context) {
+	actorID := c.MustGet("user_id").(uuid.UUID)
+	id, ok := parseAdminUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.adminService.ReactivateUser(actorID, id); err != nil {
+		adminErrorResponse(c, "REACTIVATE_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Conta reativada com sucesso"})
+}
+
+// ResetPassword godoc
+// @Summary Redefine a senha de um usuário
+// @Description Gera uma senha temporária, exibida somente nesta resposta (requer users:manage)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Router /admin/users/{id}/reset-password [post]
+func (h *AdminHandler) ResetPassword(c *gin.Context) {
+	actorID := c.MustGet("user_id").(uuid.UUID)
+	id, ok := parseAdminUserID(c)
+	if !ok {
+		return
+	}
+
+	password, err := h.adminService.ResetPassword(actorID, id)
+	if err != nil {
+		adminErrorResponse(c, "RESET_PASSWORD_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"temporary_password": password})
+}
+
+// MergeUsers godoc
+// @Summary Mescla contas duplicadas
+// @Description Transfere conexões, agendamentos, notificações e interesses da conta duplicada
+// @Description para a conta informada na URL e desativa a duplicada (requer users:manage).
+// @Description Duplicadas com verificação aprovada ou delegações ativas são recusadas (409)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da conta mantida"
+// @Param request body service.MergeUsersRequest true "Conta duplicada"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Failure 409 {object} Response
+// @Router /admin/users/{id}/merge [post]
+func (h *AdminHandler) MergeUsers(c *gin.Context) {
+	actorID := c.MustGet("user_id").(uuid.UUID)
+	id, ok := parseAdminUserID(c)
+	if !ok {
+		return
+	}
+
+	var req service.MergeUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos: "+err.Error())
+		return
+	}
+
+	user, err := h.adminService.MergeUsers(actorID, id, req)
+	if errors.Is(err, domain.ErrMergeVerifiedDuplicate) || errors.Is(err, domain.ErrMergeProxyGrants) {
+		ErrorResponse(c, http.StatusConflict, "MERGE_CONFLICT", err.Error())
+		return
+	}
+	if err != nil {
+		adminErrorResponse(c, "MERGE_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, user)
+}
+
+// SetRole godoc
+// @Summary Atribui um papel administrativo
+// @Description Define o papel (ADMIN, MODERATOR, INSTITUTION_STAFF ou vazio) de um usuário (requer roles:manage)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Param request body service.SetRoleRequest true "Papel e instituição da equipe"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Router /admin/users/{id}/role [put]
+func (h *AdminHandler) SetRole(c *gin.Context) {
+	actorID := c.MustGet("user_id").(uuid.UUID)
+	id, ok := parseAdminUserID(c)
+	if !ok {
+		return
+	}
+
+	var req service.SetRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos: "+err.Error())
+		return
+	}
+
+	user, err := h.adminService.SetRole(actorID, id, req)
+	if err != nil {
+		adminErrorResponse(c, "ROLE_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, user)
+}
+
+// ListAppointments godoc
+// @Summary Lista todos os agendamentos
+// @Description Retorna os agendamentos de toda a plataforma (requer appointments:read_all)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filtra por status (PENDING, CONFIRMED, ...)"
+// @Param page query int false "Página (padrão 1)"
+// @Param per_page query int false "Itens por página (padrão 20, máximo 100)"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Router /admin/appointments [get]
+func (h *AdminHandler) ListAppointments(c *gin.Context) {
+	status := domain.AppointmentStatus(c.Query("status"))
+	page, _ := strconv.Atoi(c.Query("page"))
+	perPage, _ := strconv.Atoi(c.Query("per_page"))
+
+	result, err := h.adminService.ListAppointments(status, page, perPage)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "FETCH_ERROR", err.Error())
+		return
+	}
+
+	totalPages := int((result.Total + int64(result.PerPage) - 1) / int64(result.PerPage))
+	SuccessResponseWithMeta(c, http.StatusOK, result.Appointments, &MetaInfo{
+		Page:       result.Page,
+		PerPage:    result.PerPage,
+		Total:      int(result.Total),
+		TotalPages: totalPages,
+	})
+}
+
+// ListAuditLog godoc
+// @Summary Log de auditoria
+// @Description Retorna as ações administrativas, das mais recentes para as mais antigas (requer audit:read)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param actor_id query string false "Administrador que executou a ação"
+// @Param user_id query string false "Usuário afetado"
+// @Param action query string false "Ação (user.deactivated, user.merged, ...)"
+// @Param page query int false "Página (padrão 1)"
+// @Param per_page query int false "Itens por página (padrão 20, máximo 100)"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Router /admin/audit-log [get]
+func (h *AdminHandler) ListAuditLog(c *gin.Context) {
+	filter := repository.AuditFilter{Action: domain.AuditAction(c.Query("action"))}
+	for param, target := range map[string]**uuid.UUID{"actor_id": &filter.ActorID, "user_id": &filter.TargetUserID} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			ErrorResponse(c, http.StatusBadRequest, "INVALID_ID", "ID inválido em "+param)
+			return
+		}
+		*target = &id
+	}
+	page, _ := strconv.Atoi(c.Query("page"))
+	perPage, _ := strconv.Atoi(c.Query("per_page"))
+
+	result, err := h.adminService.ListAuditLog(filter, page, perPage)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "FETCH_ERROR", err.Error())
+		return
+	}
+
+	totalPages := int((result.Total + int64(result.PerPage) - 1) / int64(result.PerPage))
+	SuccessResponseWithMeta(c, http.StatusOK, result.Entries, &MetaInfo{
+		Page:       result.Page,
+		PerPage:    result.PerPage,
+		Total:      int(result.Total),
+		TotalPages: totalPages,
+	})
+}
+
+// parseAdminUserID lê o ID do usuário da URL.
+// Retorna false se a resposta de erro já foi enviada.
+func parseAdminUserID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// adminErrorResponse responde 403 para operações sobre a própria conta ou sobre contas
+// com papel igual ou superior ao do administrador, e 400 para os demais erros.
+func adminErrorResponse(c *gin.Context, code string, err error) {
+	if errors.Is(err, service.ErrSelfAdministration) || errors.Is(err, service.ErrRoleHierarchy) {
+		ErrorResponse(c, http.StatusForbidden, "FORBIDDEN", err.Error())
+		return
+	}
+	ErrorResponse(c, http.StatusBadRequest, code, err.Error())
+}
@@ -2,8 +2,11 @@
 package handler
 
 import (
+	"errors"
+	"io"
 	"net/http"
 
+	"amigos-terceira-idade/internal/domain"
 	"amigos-terceira-idade/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -24,7 +27,7 @@ func NewAppointmentHandler(appointmentService *service.AppointmentService) *Appo
 
 // Create godoc
 // @Summary Cria um agendamento
-// @Description Voluntário envia convite para conversa
+// @Description Voluntário envia convite para conversa; com "recurrence" (RRULE), cria uma série
 // @Tags Appointments
 // @Accept json
 // @Produce json
@@ -32,6 +35,7 @@ func NewAppointmentHandler(appointmentService *service.AppointmentService) *Appo
 // @Param request body service.CreateAppointmentRequest true "Dados do agendamento"
 // @Success 201 {object} Response
 // @Failure 400 {object} Response
+// @Failure 409 {object} Response
 // @Router /appointments [post]
 func (h *AppointmentHandler) Create(c *gin.Context) {
 	userID := c.MustGet("user_id").(uuid.UUID)
@@ -42,9 +46,19 @@ func (h *AppointmentHandler) Create(c *gin.Context) {
 		return
 	}
 
+	if req.Recurrence != "" {
+		series, err := h.appointmentService.CreateSeries(userID, req)
+		if err != nil {
+			scheduleErrorResponse(c, "CREATE_ERROR", err)
+			return
+		}
+		SuccessResponse(c, http.StatusCreated, series)
+		return
+	}
+
 	appointment, err := h.appointmentService.Create(userID, req)
 	if err != nil {
-		ErrorResponse(c, http.StatusBadRequest, "CREATE_ERROR", err.Error())
+		scheduleErrorResponse(c, "CREATE_ERROR", err)
 		return
 	}
 
@@ -99,9 +113,11 @@ func (h *AppointmentHandler) GetUpcoming(c *gin.Context) {
 // @Security BearerAuth
 // @Param id path string true "ID do agendamento"
 // @Success 200 {object} Response
+// @Failure 403 {object} Response
 // @Failure 404 {object} Response
 // @Router /appointments/{id} [get]
 func (h *AppointmentHandler) GetByID(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
@@ -109,9 +125,9 @@ func (h *AppointmentHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	appointment, err := h.appointmentService.GetByID(id)
+	appointment, err := h.appointmentService.GetByID(id, userID)
 	if err != nil {
-		ErrorResponse(c, http.StatusNotFound, "APPOINTMENT_NOT_FOUND", err.Error())
+		accessErrorResponse(c, "FETCH_ERROR", err)
 		return
 	}
 
@@ -165,6 +181,7 @@ func (h *AppointmentHandler) GetSentInvitations(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "ID do agendamento"
+// @Param scope query string false "Escopo em séries: this, following ou all"
 // @Success 200 {object} Response
 // @Failure 400 {object} Response
 // @Router /appointments/{id}/accept [post]
@@ -177,8 +194,13 @@ func (h *AppointmentHandler) Accept(c *gin.Context) {
 		return
 	}
 
-	if err := h.appointmentService.Accept(id, userID); err != nil {
-		ErrorResponse(c, http.StatusBadRequest, "ACCEPT_ERROR", err.Error())
+	scope, ok := bindScope(c)
+	if !ok {
+		return
+	}
+
+	if err := h.appointmentService.Accept(id, userID, scope); err != nil {
+		accessErrorResponse(c, "ACCEPT_ERROR", err)
 		return
 	}
 
@@ -192,6 +214,8 @@ func (h *AppointmentHandler) Accept(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "ID do agendamento"
+// @Param scope query string false "Escopo em séries: this, following ou all"
+// @Param request body StatusChangeRequest false "Motivo da recusa"
 // @Success 200 {object} Response
 // @Failure 400 {object} Response
 // @Router /appointments/{id}/decline [post]
@@ -204,8 +228,18 @@ func (h *AppointmentHandler) Decline(c *gin.Context) {
 		return
 	}
 
-	if err := h.appointmentService.Decline(id, userID); err != nil {
-		ErrorResponse(c, http.StatusBadRequest, "DECLINE_ERROR", err.Error())
+	reason, ok := bindReason(c)
+	if !ok {
+		return
+	}
+
+	scope, ok := bindScope(c)
+	if !ok {
+		return
+	}
+
+	if err := h.appointmentService.Decline(id, userID, reason, scope); err != nil {
+		accessErrorResponse(c, "DECLINE_ERROR", err)
 		return
 	}
 
@@ -219,6 +253,8 @@ func (h *AppointmentHandler) Decline(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "ID do agendamento"
+// @Param scope query string false "Escopo em séries: this, following ou all"
+// @Param request body StatusChangeRequest false "Motivo do cancelamento"
 // @Success 200 {object} Response
 // @Failure 400 {object} Response
 // @Router /appointments/{id} [delete]
@@ -231,10 +267,351 @@ func (h *AppointmentHandler) Cancel(c *gin.Context) {
 		return
 	}
 
-	if err := h.appointmentService.Cancel(id, userID); err != nil {
-		ErrorResponse(c, http.StatusBadRequest, "CANCEL_ERROR", err.Error())
+	reason, ok := bindReason(c)
+	if !ok {
+		return
+	}
+
+	scope, ok := bindScope(c)
+	if !ok {
+		return
+	}
+
+	if err := h.appointmentService.Cancel(id, userID, reason, scope); err != nil {
+		accessErrorResponse(c, "CANCEL_ERROR", err)
 		return
 	}
 
 	SuccessResponse(c, http.StatusOK, gin.H{"message": "Agendamento cancelado"})
 }
+
+// Complete godoc
+// @Summary Conclui um agendamento
+// @Description Participante marca a conversa confirmada como realizada após o término
+// @Tags Appointments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do agendamento"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /appointments/{id}/complete [post]
+func (h *AppointmentHandler) Complete(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	if err := h.appointmentService.Complete(id, userID); err != nil {
+		accessErrorResponse(c, "COMPLETE_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Agendamento concluído"})
+}
+
+// RateRequest contém a avaliação de uma conversa concluída.
+type RateRequest struct {
+	Rating int `json:"rating" binding:"required,min=1,max=5"`
+}
+
+// Rate godoc
+// @Summary Avalia um agendamento
+// @Description Idoso/instituição avalia o voluntário após a conversa concluída
+// @Tags Appointments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do agendamento"
+// @Param request body RateRequest true "Avaliação (1-5)"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /appointments/{id}/rating [post]
+func (h *AppointmentHandler) Rate(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	var req RateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos: "+err.Error())
+		return
+	}
+
+	if err := h.appointmentService.Rate(id, userID, req.Rating); err != nil {
+		accessErrorResponse(c, "RATING_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Avaliação registrada"})
+}
+
+// MarkNoShow godoc
+// @Summary Registra ausência
+// @Description Participante registra que a conversa confirmada não aconteceu
+// @Tags Appointments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do agendamento"
+// @Param request body StatusChangeRequest false "Motivo"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /appointments/{id}/no-show [post]
+func (h *AppointmentHandler) MarkNoShow(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	reason, ok := bindReason(c)
+	if !ok {
+		return
+	}
+
+	if err := h.appointmentService.MarkNoShow(id, userID, reason); err != nil {
+		accessErrorResponse(c, "NO_SHOW_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Ausência registrada"})
+}
+
+// GetHistory godoc
+// @Summary Histórico de status do agendamento
+// @Description Retorna quem alterou o status do agendamento, quando e por quê
+// @Tags Appointments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do agendamento"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /appointments/{id}/history [get]
+func (h *AppointmentHandler) GetHistory(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	history, err := h.appointmentService.GetHistory(id, userID)
+	if err != nil {
+		accessErrorResponse(c, "FETCH_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, history)
+}
+
+// ProposeReschedule godoc
+// @Summary Propõe uma nova data
+// @Description Qualquer participante propõe remarcar; a data original vale até o outro participante aceitar
+// @Tags Appointments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do agendamento"
+// @Param scope query string false "Escopo em séries: this, following ou all"
+// @Param request body service.RescheduleRequest true "Data proposta"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 409 {object} Response
+// @Router /appointments/{id}/reschedule [post]
+// @Router /appointments/{id} [patch]
+func (h *AppointmentHandler) ProposeReschedule(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	var req service.RescheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos: "+err.Error())
+		return
+	}
+
+	scope, ok := bindScope(c)
+	if !ok {
+		return
+	}
+
+	if err := h.appointmentService.ProposeReschedule(id, userID, req, scope); err != nil {
+		scheduleErrorResponse(c, "RESCHEDULE_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Remarcação proposta"})
+}
+
+// AcceptReschedule godoc
+// @Summary Aceita a nova data proposta
+// @Description O outro participante aceita a remarcação; o agendamento passa para a nova data confirmado
+// @Tags Appointments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do agendamento"
+// @Param scope query string false "Escopo em séries: this, following ou all"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 409 {object} Response
+// @Router /appointments/{id}/reschedule/accept [post]
+func (h *AppointmentHandler) AcceptReschedule(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	scope, ok := bindScope(c)
+	if !ok {
+		return
+	}
+
+	if err := h.appointmentService.AcceptReschedule(id, userID, scope); err != nil {
+		scheduleErrorResponse(c, "RESCHEDULE_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Remarcação aceita"})
+}
+
+// RejectReschedule godoc
+// @Summary Recusa a nova data proposta
+// @Description Recusa (ou retira) a proposta; o agendamento volta ao status anterior na data original
+// @Tags Appointments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do agendamento"
+// @Param scope query string false "Escopo em séries: this, following ou all"
+// @Param request body StatusChangeRequest false "Motivo"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /appointments/{id}/reschedule/reject [post]
+func (h *AppointmentHandler) RejectReschedule(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	reason, ok := bindReason(c)
+	if !ok {
+		return
+	}
+
+	scope, ok := bindScope(c)
+	if !ok {
+		return
+	}
+
+	if err := h.appointmentService.RejectReschedule(id, userID, reason, scope); err != nil {
+		accessErrorResponse(c, "RESCHEDULE_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Remarcação recusada"})
+}
+
+// GetSeries godoc
+// @Summary Série recorrente do agendamento
+// @Description Retorna a regra de recorrência e todas as ocorrências da série
+// @Tags Appointments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do agendamento"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Router /appointments/{id}/series [get]
+func (h *AppointmentHandler) GetSeries(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	series, err := h.appointmentService.GetSeries(id, userID)
+	if err != nil {
+		accessErrorResponse(c, "FETCH_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, series)
+}
+
+// StatusChangeRequest contém o motivo opcional de uma mudança de status.
+type StatusChangeRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// bindReason lê o motivo opcional do corpo da requisição.
+// Um corpo vazio é aceito; retorna false se a resposta de erro já foi enviada.
+func bindReason(c *gin.Context) (string, bool) {
+	var req StatusChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos: "+err.Error())
+		return "", false
+	}
+	return req.Reason, true
+}
+
+// bindScope lê o escopo opcional (?scope=) das operações em séries.
+// Retorna false se a resposta de erro já foi enviada.
+func bindScope(c *gin.Context) (service.SeriesScope, bool) {
+	scope, err := service.ParseSeriesScope(c.Query("scope"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_SCOPE", err.Error())
+		return "", false
+	}
+	return scope, true
+}
+
+// accessErrorResponse responde 404 para agendamentos inexistentes, 403 para quem não
+// participa do agendamento ou 400 para os demais erros.
+func accessErrorResponse(c *gin.Context, code string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrAppointmentNotFound):
+		ErrorResponse(c, http.StatusNotFound, "APPOINTMENT_NOT_FOUND", err.Error())
+	case errors.Is(err, service.ErrNotAppointmentParticipant):
+		ErrorResponse(c, http.StatusForbidden, "FORBIDDEN", err.Error())
+	default:
+		ErrorResponse(c, http.StatusBadRequest, code, err.Error())
+	}
+}
+
+// scheduleErrorResponse responde 409 com os conflitos de agenda (ou lotação da
+// instituição); os demais erros seguem accessErrorResponse.
+func scheduleErrorResponse(c *gin.Context, code string, err error) {
+	var conflictErr *service.ConflictError
+	if errors.As(err, &conflictErr) {
+		ErrorResponseWithDetails(c, http.StatusConflict, "SCHEDULE_CONFLICT", err.Error(), conflictErr.Conflicts)
+		return
+	}
+	if errors.Is(err, service.ErrVisitCapacityReached) {
+		ErrorResponse(c, http.StatusConflict, "VISIT_CAPACITY_REACHED", err.Error())
+		return
+	}
+	accessErrorResponse(c, code, err)
+}
@@ -2,28 +2,38 @@
 package handler
 
 import (
+	"errors"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/middleware"
 	"amigos-terceira-idade/internal/service"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // AuthHandler gerencia os endpoints de autenticação.
 type AuthHandler struct {
-	authService *service.AuthService
+	authService    *service.AuthService
+	accountService *service.AccountService
 }
 
 // NewAuthHandler cria uma nova instância do handler de autenticação.
-func NewAuthHandler(authService *service.AuthService) *AuthHandler {
+func NewAuthHandler(authService *service.AuthService, accountService *service.AccountService) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
+		authService:    authService,
+		accountService: accountService,
 	}
 }
 
 // Register godoc
 // @Summary Cadastra um novo usuário
-// @Description Cria uma nova conta de voluntário, idoso ou instituição
+// @Description Cria uma nova conta de voluntário, idoso ou instituição e envia o link de confirmação do e-mail
 // @Tags Auth
 // @Accept json
 // @Produce json
@@ -45,24 +55,30 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	result, err := h.authService.Register(req)
+	result, err := h.authService.Register(req, middleware.ClientInfo(c))
 	if err != nil {
 		ErrorResponse(c, http.StatusBadRequest, "REGISTER_ERROR", err.Error())
 		return
 	}
 
+	// A conta já está criada; sem o e-mail, o usuário pode pedir um novo link depois
+	if err := h.accountService.SendEmailVerification(result.User.ID); err != nil {
+		log.Printf("Erro ao enviar a verificação de e-mail do usuário %s: %v", result.User.ID, err)
+	}
+
 	SuccessResponse(c, http.StatusCreated, result)
 }
 
 // Login godoc
 // @Summary Realiza login
-// @Description Autentica um usuário e retorna os tokens
+// @Description Autentica um usuário, abre uma sessão para o dispositivo (headers X-Platform e X-App-Version) e retorna os tokens
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param request body service.LoginRequest true "Credenciais"
 // @Success 200 {object} Response
 // @Failure 401 {object} Response
+// @Failure 429 {object} Response
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req service.LoginRequest
@@ -71,9 +87,65 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	result, err := h.authService.Login(req)
+	result, err := h.authService.Login(req, middleware.ClientInfo(c))
 	if err != nil {
-		ErrorResponse(c, http.StatusUnauthorized, "LOGIN_ERROR", err.Error())
+		if !accountLockedResponse(c, err) {
+			ErrorResponse(c, http.StatusUnauthorized, "LOGIN_ERROR", err.Error())
+		}
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, result)
+}
+
+// RequestLoginCode godoc
+// @Summary Pede um código de acesso
+// @Description Envia por e-mail ou SMS um código de 6 dígitos para entrar sem senha; os códigos anteriores deixam de valer.
+// @Description A resposta é a mesma quando o código não é enviado, para não revelar quais e-mails têm conta
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body service.RequestLoginCodeRequest true "E-mail da conta e canal (email ou sms)"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 429 {object} Response
+// @Router /auth/login-code [post]
+func (h *AuthHandler) RequestLoginCode(c *gin.Context) {
+	var req service.RequestLoginCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos: "+err.Error())
+		return
+	}
+
+	if err := h.authService.RequestLoginCode(req); err != nil {
+		loginCodeErrorResponse(c, "LOGIN_CODE_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Se a conta existir, enviaremos o código de acesso"})
+}
+
+// LoginWithCode godoc
+// @Summary Realiza login com código
+// @Description Troca o código de acesso recebido pelos tokens, como no login com senha
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body service.LoginWithCodeRequest true "E-mail e código"
+// @Success 200 {object} Response
+// @Failure 401 {object} Response
+// @Failure 429 {object} Response
+// @Router /auth/login-code/verify [post]
+func (h *AuthHandler) LoginWithCode(c *gin.Context) {
+	var req service.LoginWithCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos: "+err.Error())
+		return
+	}
+
+	result, err := h.authService.LoginWithCode(req, middleware.ClientInfo(c))
+	if err != nil {
+		loginCodeErrorResponse(c, "LOGIN_ERROR", err)
 		return
 	}
 
@@ -104,9 +176,240 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 
 	result, err := h.authService.RefreshToken(req.RefreshToken)
 	if err != nil {
-		ErrorResponse(c, http.StatusUnauthorized, "REFRESH_ERROR", err.Error())
+		code := "REFRESH_ERROR"
+		if errors.Is(err, service.ErrRefreshTokenReused) {
+			code = "REFRESH_TOKEN_REUSED"
+		}
+		ErrorResponse(c, http.StatusUnauthorized, code, err.Error())
 		return
 	}
 
 	SuccessResponse(c, http.StatusOK, result)
 }
+
+// Logout godoc
+// @Summary Encerra a sessão atual
+// @Description Revoga a sessão do token usado; o access token e o refresh token dela deixam de valer
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	sessionID := c.MustGet("session_id").(uuid.UUID)
+
+	if err := h.authService.Logout(sessionID); err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "LOGOUT_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Sessão encerrada"})
+}
+
+// LogoutAll godoc
+// @Summary Encerra todas as sessões
+// @Description Revoga todas as sessões do usuário, em todos os dispositivos
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Router /auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.authService.LogoutAll(userID); err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "LOGOUT_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Todas as sessões foram encerradas"})
+}
+
+// ListSessions godoc
+// @Summary Lista as sessões ativas
+// @Description Lista os dispositivos com sessão aberta, marcando a sessão atual
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Router /auth/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	sessionID := c.MustGet("session_id").(uuid.UUID)
+
+	sessions, err := h.authService.ListSessions(userID, sessionID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "FETCH_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, sessions)
+}
+
+// RevokeSession godoc
+// @Summary Encerra uma sessão
+// @Description Encerra a sessão de um dispositivo, como um tablet compartilhado esquecido logado
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da sessão"
+// @Success 200 {object} Response
+// @Failure 404 {object} Response
+// @Router /auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	if err := h.authService.RevokeSession(userID, sessionID); err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			ErrorResponse(c, http.StatusNotFound, "SESSION_NOT_FOUND", err.Error())
+			return
+		}
+		ErrorResponse(c, http.StatusBadRequest, "REVOKE_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Sessão encerrada"})
+}
+
+// ForgotPassword godoc
+// @Summary Pede a redefinição de senha
+// @Description Envia um link de redefinição para o e-mail, se houver conta ativa com ele; a resposta é a mesma em qualquer caso
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body service.ForgotPasswordRequest true "E-mail da conta"
+// @Success 200 {object} Response
+// @Router /auth/password/forgot [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req service.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos: "+err.Error())
+		return
+	}
+
+	if err := h.accountService.ForgotPassword(req); err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "FORGOT_PASSWORD_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Se o e-mail estiver cadastrado, enviaremos as instruções para redefinir a senha"})
+}
+
+// ResetPassword godoc
+// @Summary Redefine a senha
+// @Description Troca a senha com o token recebido por e-mail e encerra todas as sessões da conta
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body service.ResetPasswordRequest true "Token e nova senha"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req service.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos: "+err.Error())
+		return
+	}
+
+	if err := h.accountService.ResetPassword(req); err != nil {
+		accountErrorResponse(c, "RESET_PASSWORD_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Senha redefinida. Faça login com a nova senha"})
+}
+
+// VerifyEmail godoc
+// @Summary Confirma o e-mail
+// @Description Confirma o e-mail com o token recebido; os tokens seguintes (login ou refresh) refletem a confirmação
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body service.VerifyEmailRequest true "Token"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /auth/email/verify [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req service.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos: "+err.Error())
+		return
+	}
+
+	user, err := h.accountService.VerifyEmail(req)
+	if err != nil {
+		accountErrorResponse(c, "VERIFY_EMAIL_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, user)
+}
+
+// ResendEmailVerification godoc
+// @Summary Reenvia a confirmação do e-mail
+// @Description Envia um novo link de confirmação; os links anteriores deixam de valer
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Failure 409 {object} Response
+// @Router /auth/email/verify/resend [post]
+func (h *AuthHandler) ResendEmailVerification(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.accountService.SendEmailVerification(userID); err != nil {
+		accountErrorResponse(c, "VERIFY_EMAIL_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Enviamos um novo link de confirmação para o seu e-mail"})
+}
+
+// accountErrorResponse responde 400 para tokens inválidos, 409 para e-mails já
+// confirmados ou 400 para os demais erros.
+func accountErrorResponse(c *gin.Context, code string, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidAccountToken):
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_TOKEN", err.Error())
+	case errors.Is(err, service.ErrEmailAlreadyVerified):
+		ErrorResponse(c, http.StatusConflict, "EMAIL_ALREADY_VERIFIED", err.Error())
+	default:
+		ErrorResponse(c, http.StatusBadRequest, code, err.Error())
+	}
+}
+
+// loginCodeErrorResponse responde 429 com Retry-After para contas bloqueadas, 401 para
+// códigos inválidos ou contas desativadas e 400 para os demais erros.
+func loginCodeErrorResponse(c *gin.Context, code string, err error) {
+	if accountLockedResponse(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, service.ErrInvalidLoginCode):
+		ErrorResponse(c, http.StatusUnauthorized, "INVALID_LOGIN_CODE", err.Error())
+	case errors.Is(err, service.ErrUserInactive):
+		ErrorResponse(c, http.StatusUnauthorized, "USER_INACTIVE", err.Error())
+	default:
+		ErrorResponse(c, http.StatusBadRequest, code, err.Error())
+	}
+}
+
+// accountLockedResponse responde 429 com Retry-After se a conta está bloqueada por
+// falhas de login e indica se respondeu.
+func accountLockedResponse(c *gin.Context, err error) bool {
+	var lockedErr *service.AccountLockedError
+	if !errors.As(err, &lockedErr) {
+		return false
+	}
+	seconds := int(math.Ceil(lockedErr.RetryAfter(time.Now()).Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	ErrorResponse(c, http.StatusTooManyRequests, "ACCOUNT_LOCKED", err.Error())
+	return true
+}
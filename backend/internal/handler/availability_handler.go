@@ -0,0 +1,333 @@
+// Package handler contém os handlers HTTP da aplicação.
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/middleware"
+	"amigos-terceira-idade/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AvailabilityHandler gerencia os endpoints de disponibilidade dos voluntários
+// e das janelas de visita das instituições.
+type AvailabilityHandler struct {
+	availabilityService *service.AvailabilityService
+}
+
+// NewAvailabilityHandler cria uma nova instância do handler de disponibilidade.
+func NewAvailabilityHandler(availabilityService *service.AvailabilityService) *AvailabilityHandler {
+	return &AvailabilityHandler{
+		availabilityService: availabilityService,
+	}
+}
+
+// GetMine godoc
+// @Summary Minha disponibilidade semanal
+// @Description Retorna as janelas semanais do voluntário autenticado
+// @Tags Availability
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Router /users/me/availability [get]
+func (h *AvailabilityHandler) GetMine(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	windows, err := h.availabilityService.GetWeekly(userID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "FETCH_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, windows)
+}
+
+// SetMine godoc
+// @Summary Define a disponibilidade semanal
+// @Description Substitui as janelas semanais do voluntário autenticado
+// @Tags Availability
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.SetAvailabilityRequest true "Janelas semanais"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /users/me/availability [put]
+func (h *AvailabilityHandler) SetMine(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	var req service.SetAvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos: "+err.Error())
+		return
+	}
+
+	windows, err := h.availabilityService.SetWeekly(userID, req)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "AVAILABILITY_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, windows)
+}
+
+// GetMyVisitWindows godoc
+// @Summary Minhas janelas de visita
+// @Description Retorna as janelas de visita da instituição autenticada (ou da instituição da equipe)
+// @Tags Availability
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Router /users/me/visit-windows [get]
+func (h *AvailabilityHandler) GetMyVisitWindows(c *gin.Context) {
+	institutionID := middleware.ActingInstitutionID(c)
+
+	windows, err := h.availabilityService.GetVisitWindows(institutionID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "FETCH_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, windows)
+}
+
+// SetMyVisitWindows godoc
+// @Summary Define as janelas de visita
+// @Description Substitui as janelas de visita (dias, horários, duração máxima e visitantes simultâneos) da instituição autenticada (ou da instituição da equipe)
+// @Tags Availability
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.SetVisitWindowsRequest true "Janelas de visita"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /users/me/visit-windows [put]
+func (h *AvailabilityHandler) SetMyVisitWindows(c *gin.Context) {
+	institutionID := middleware.ActingInstitutionID(c)
+
+	var req service.SetVisitWindowsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos: "+err.Error())
+		return
+	}
+
+	windows, err := h.availabilityService.SetVisitWindows(institutionID, req)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "VISIT_WINDOWS_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, windows)
+}
+
+// GetVisitWindows godoc
+// @Summary Janelas de visita de uma instituição
+// @Description Retorna quando a instituição recebe visitas, para o voluntário escolher o horário
+// @Tags Availability
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da instituição"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /users/{id}/visit-windows [get]
+func (h *AvailabilityHandler) GetVisitWindows(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	windows, err := h.availabilityService.GetVisitWindows(id)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "FETCH_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, windows)
+}
+
+// GetExceptions godoc
+// @Summary Lista exceções de disponibilidade
+// @Description Retorna bloqueios e janelas extras do voluntário autenticado
+// @Tags Availability
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Data inicial (AAAA-MM-DD ou RFC3339), padrão hoje"
+// @Param to query string false "Data final (AAAA-MM-DD ou RFC3339), padrão 31 dias depois"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /users/me/availability/exceptions [get]
+func (h *AvailabilityHandler) GetExceptions(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	loc, err := h.availabilityService.UserLocation(userID)
+	if err != nil {
+		ErrorResponse(c, http.StatusNotFound, "USER_NOT_FOUND", err.Error())
+		return
+	}
+
+	from, to, ok := parseRange(c, service.MaxSlotRange, loc)
+	if !ok {
+		return
+	}
+
+	exceptions, err := h.availabilityService.GetExceptions(userID, from, to)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "FETCH_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, exceptions)
+}
+
+// AddException godoc
+// @Summary Adiciona exceção de disponibilidade
+// @Description Bloqueia um horário/dia ou adiciona uma janela extra em uma data
+// @Tags Availability
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.AvailabilityExceptionRequest true "Exceção"
+// @Success 201 {object} Response
+// @Failure 400 {object} Response
+// @Router /users/me/availability/exceptions [post]
+func (h *AvailabilityHandler) AddException(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	var req service.AvailabilityExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos: "+err.Error())
+		return
+	}
+
+	exception, err := h.availabilityService.AddException(userID, req)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "AVAILABILITY_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusCreated, exception)
+}
+
+// RemoveException godoc
+// @Summary Remove exceção de disponibilidade
+// @Description Remove um bloqueio ou janela extra do voluntário autenticado
+// @Tags Availability
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da exceção"
+// @Success 200 {object} Response
+// @Failure 404 {object} Response
+// @Router /users/me/availability/exceptions/{id} [delete]
+func (h *AvailabilityHandler) RemoveException(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	if err := h.availabilityService.RemoveException(userID, id); err != nil {
+		ErrorResponse(c, http.StatusNotFound, "EXCEPTION_NOT_FOUND", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Exceção removida"})
+}
+
+// GetSlots godoc
+// @Summary Horários livres de um voluntário
+// @Description Calcula os horários livres a partir da disponibilidade menos os agendamentos existentes
+// @Tags Availability
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do voluntário"
+// @Param from query string false "Início (AAAA-MM-DD ou RFC3339), padrão agora"
+// @Param to query string false "Fim (AAAA-MM-DD ou RFC3339), padrão 7 dias depois"
+// @Param duration query int false "Duração de cada horário em minutos (padrão 30)"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /users/{id}/availability/slots [get]
+func (h *AvailabilityHandler) GetSlots(c *gin.Context) {
+	idParam := c.Param("id")
+	volunteerID, err := uuid.Parse(idParam)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	// Datas sem horário e os horários retornados seguem o fuso de quem consulta
+	loc, err := h.availabilityService.UserLocation(c.MustGet("user_id").(uuid.UUID))
+	if err != nil {
+		ErrorResponse(c, http.StatusNotFound, "USER_NOT_FOUND", err.Error())
+		return
+	}
+
+	from, to, ok := parseRange(c, 7*24*time.Hour, loc)
+	if !ok {
+		return
+	}
+
+	duration := 0
+	if value := c.Query("duration"); value != "" {
+		duration, err = strconv.Atoi(value)
+		if err != nil {
+			ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Duração inválida")
+			return
+		}
+	}
+
+	slots, err := h.availabilityService.GetSlots(volunteerID, from, to, duration)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "SLOTS_ERROR", err.Error())
+		return
+	}
+
+	for i := range slots {
+		slots[i] = slots[i].In(loc)
+	}
+
+	SuccessResponse(c, http.StatusOK, slots)
+}
+
+// parseRange lê os parâmetros from/to da query string, aceitando datas (AAAA-MM-DD,
+// meia-noite no fuso informado) ou RFC3339. Sem from, usa o momento atual; sem to,
+// soma o intervalo padrão a from. Retorna false se a resposta de erro já foi enviada.
+func parseRange(c *gin.Context, defaultSpan time.Duration, loc *time.Location) (time.Time, time.Time, bool) {
+	from := time.Now()
+	if value := c.Query("from"); value != "" {
+		parsed, err := parseQueryTime(value, loc)
+		if err != nil {
+			ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Parâmetro from inválido")
+			return time.Time{}, time.Time{}, false
+		}
+		from = parsed
+	}
+
+	to := from.Add(defaultSpan)
+	if value := c.Query("to"); value != "" {
+		parsed, err := parseQueryTime(value, loc)
+		if err != nil {
+			ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Parâmetro to inválido")
+			return time.Time{}, time.Time{}, false
+		}
+		to = parsed
+	}
+
+	return from, to, true
+}
+
+// parseQueryTime interpreta um instante em RFC3339 ou uma data AAAA-MM-DD (meia-noite no fuso informado).
+func parseQueryTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(domain.DateLayout, value, loc)
+}
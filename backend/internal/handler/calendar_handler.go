@@ -0,0 +1,139 @@
+// Package handler contém os handlers HTTP da aplicação.
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"amigos-terceira-idade/internal/service"
+	"amigos-terceira-idade/pkg/ical"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CalendarHandler gerencia a exportação de agendamentos para calendários (.ics).
+type CalendarHandler struct {
+	calendarService *service.CalendarService
+	publicURL       string
+}
+
+// NewCalendarHandler cria uma nova instância do handler de calendário. publicURL é o
+// endereço público da API, base dos endereços de feed.
+func NewCalendarHandler(calendarService *service.CalendarService, publicURL string) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: calendarService,
+		publicURL:       strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+// CalendarFeedResponse contém o endereço do feed de calendário do usuário.
+type CalendarFeedResponse struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+// AppointmentICS godoc
+// @Summary Exporta um agendamento (.ics)
+// @Description Baixa o agendamento como evento iCalendar; cancelados saem com METHOD:CANCEL
+// @Tags Calendar
+// @Produce text/calendar
+// @Security BearerAuth
+// @Param id path string true "ID do agendamento"
+// @Success 200 {string} string "Arquivo .ics"
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Router /appointments/{id}/ics [get]
+func (h *CalendarHandler) AppointmentICS(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	calendar, err := h.calendarService.AppointmentICS(id, userID)
+	if err != nil {
+		accessErrorResponse(c, "CALENDAR_ERROR", err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="conversa-%s.ics"`, id))
+	writeCalendar(c, calendar)
+}
+
+// GetMyFeed godoc
+// @Summary Endereço do meu calendário
+// @Description Retorna o endereço secreto do feed .ics para assinar no aplicativo de calendário
+// @Tags Calendar
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Router /users/me/calendar [get]
+func (h *CalendarHandler) GetMyFeed(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	feed, err := h.calendarService.GetFeed(userID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "CALENDAR_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, CalendarFeedResponse{Token: feed.Token, URL: h.feedURL(feed.Token)})
+}
+
+// RotateMyFeed godoc
+// @Summary Gera um novo endereço de calendário
+// @Description Troca o token do feed; o endereço anterior deixa de funcionar
+// @Tags Calendar
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Router /users/me/calendar/rotate [post]
+func (h *CalendarHandler) RotateMyFeed(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	feed, err := h.calendarService.RotateFeed(userID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "CALENDAR_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, CalendarFeedResponse{Token: feed.Token, URL: h.feedURL(feed.Token)})
+}
+
+// Feed godoc
+// @Summary Feed de calendário (.ics)
+// @Description Feed de assinatura com os próximos agendamentos do dono do token (sem autenticação JWT)
+// @Tags Calendar
+// @Produce text/calendar
+// @Param token path string true "Token secreto seguido de .ics"
+// @Success 200 {string} string "Feed .ics"
+// @Failure 404 {object} Response
+// @Router /calendar/{token}.ics [get]
+func (h *CalendarHandler) Feed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	calendar, err := h.calendarService.FeedICS(token)
+	if err != nil {
+		ErrorResponse(c, http.StatusNotFound, "CALENDAR_NOT_FOUND", err.Error())
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=900")
+	writeCalendar(c, calendar)
+}
+
+// writeCalendar escreve o calendário com o tipo de conteúdo do iCalendar.
+func writeCalendar(c *gin.Context, calendar *ical.Calendar) {
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(calendar.String()))
+}
+
+// feedURL monta o endereço absoluto do feed a partir do endereço público configurado,
+// e não dos headers da requisição, que o cliente controla.
+func (h *CalendarHandler) feedURL(token string) string {
+	return fmt.Sprintf("%s/api/v1/calendar/%s.ics", h.publicURL, token)
+}
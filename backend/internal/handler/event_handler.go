@@ -0,0 +1,108 @@
+// Package handler contém os handlers HTTP da aplicação.
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"amigos-terceira-idade/internal/service"
+	"amigos-terceira-idade/pkg/pubsub"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// sseRetryMillis é o intervalo sugerido ao cliente para reconectar;
+// defaultHeartbeat é usado quando nenhum intervalo válido é configurado.
+const (
+	sseRetryMillis   = 5000
+	defaultHeartbeat = 25 * time.Second
+)
+
+// EventHandler gerencia o stream de eventos em tempo real (Server-Sent Events).
+type EventHandler struct {
+	hub         *pubsub.Hub
+	authService *service.AuthService
+	heartbeat   time.Duration
+}
+
+// NewEventHandler cria uma nova instância do handler de eventos.
+func NewEventHandler(hub *pubsub.Hub, authService *service.AuthService, heartbeat time.Duration) *EventHandler {
+	if heartbeat <= 0 {
+		heartbeat = defaultHeartbeat
+	}
+	return &EventHandler{
+		hub:         hub,
+		authService: authService,
+		heartbeat:   heartbeat,
+	}
+}
+
+// Stream godoc
+// @Summary Stream de eventos
+// @Description Mantém uma conexão SSE que envia os eventos das conexões e agendamentos do usuário
+// @Description (ex.: appointment.confirmed, connection.accepted). Com o header Last-Event-ID, reenvia
+// @Description os eventos perdidos; se não for possível, envia antes um evento "resync" indicando
+// @Description que o cliente deve recarregar os dados. Comentários periódicos mantêm a conexão aberta;
+// @Description a cada um a sessão é conferida e o stream termina se ela tiver sido encerrada.
+// @Tags Events
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Param Last-Event-ID header string false "ID do último evento recebido"
+// @Success 200 {string} string "Stream de eventos"
+// @Router /events/stream [get]
+func (h *EventHandler) Stream(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	sessionID := c.MustGet("session_id").(uuid.UUID)
+	lastEventID := c.GetHeader("Last-Event-ID")
+
+	subscription, missed, complete := h.hub.Subscribe(userID.String(), lastEventID)
+	defer subscription.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Desativa o buffer de proxies como o nginx
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetryMillis)
+	if !complete {
+		fmt.Fprint(w, "event: resync\ndata: {}\n\n")
+	}
+	for _, message := range missed {
+		writeEvent(w, message)
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case message, ok := <-subscription.C():
+			if !ok {
+				// O hub encerrou a assinatura; o cliente reconecta com o último ID
+				return
+			}
+			writeEvent(w, message)
+			w.Flush()
+		case <-heartbeat.C:
+			// O token só é validado na abertura; sessões encerradas depois (logout,
+			// troca de senha, desativação) não podem continuar recebendo eventos
+			if err := h.authService.CheckSession(sessionID); err != nil {
+				return
+			}
+			fmt.Fprint(w, ": heartbeat\n\n")
+			w.Flush()
+		}
+	}
+}
+
+// writeEvent escreve uma mensagem no formato SSE. Os dados são JSON em uma única linha.
+func writeEvent(w io.Writer, message pubsub.Message) {
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", message.ID, message.Type, message.Data)
+}
@@ -2,8 +2,10 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
+	"amigos-terceira-idade/internal/domain"
 	"amigos-terceira-idade/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -27,9 +29,10 @@ func NewMatchingHandler(matchingService *service.MatchingService) *MatchingHandl
 // @Tags Matching
 // @Produce json
 // @Security BearerAuth
-// @Param type query string false "Filtrar por tipo: elderly ou institution"
+// @Param type query string false "Filtrar por tipo: elderly, institution ou resident"
 // @Success 200 {object} Response
 // @Failure 401 {object} Response
+// @Failure 403 {object} Response
 // @Router /matching/suggestions [get]
 func (h *MatchingHandler) GetSuggestions(c *gin.Context) {
 	userID := c.MustGet("user_id").(uuid.UUID)
@@ -37,7 +40,7 @@ func (h *MatchingHandler) GetSuggestions(c *gin.Context) {
 
 	suggestions, err := h.matchingService.GetSuggestions(userID, filterType)
 	if err != nil {
-		ErrorResponse(c, http.StatusBadRequest, "SUGGESTIONS_ERROR", err.Error())
+		matchingErrorResponse(c, "SUGGESTIONS_ERROR", err)
 		return
 	}
 
@@ -45,20 +48,23 @@ func (h *MatchingHandler) GetSuggestions(c *gin.Context) {
 }
 
 // ConnectRequest contém os dados para criar uma conexão.
+// Informe target_id para um idoso/instituição ou resident_id para um residente.
 type ConnectRequest struct {
-	TargetID uuid.UUID `json:"target_id" binding:"required"`
+	TargetID   uuid.UUID  `json:"target_id" binding:"required_without=ResidentID"`
+	ResidentID *uuid.UUID `json:"resident_id"`
 }
 
 // Connect godoc
 // @Summary Cria uma conexão
-// @Description Voluntário solicita conexão com idoso/instituição
+// @Description Voluntário solicita conexão com idoso/instituição ou com um residente de instituição
 // @Tags Matching
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param request body ConnectRequest true "ID do alvo"
+// @Param request body ConnectRequest true "ID do alvo ou do residente"
 // @Success 201 {object} Response
 // @Failure 400 {object} Response
+// @Failure 403 {object} Response
 // @Router /matching/connect [post]
 func (h *MatchingHandler) Connect(c *gin.Context) {
 	userID := c.MustGet("user_id").(uuid.UUID)
@@ -69,9 +75,15 @@ func (h *MatchingHandler) Connect(c *gin.Context) {
 		return
 	}
 
-	connection, err := h.matchingService.Connect(userID, req.TargetID)
+	var connection *domain.Connection
+	var err error
+	if req.ResidentID != nil {
+		connection, err = h.matchingService.ConnectResident(userID, *req.ResidentID)
+	} else {
+		connection, err = h.matchingService.Connect(userID, req.TargetID)
+	}
 	if err != nil {
-		ErrorResponse(c, http.StatusBadRequest, "CONNECT_ERROR", err.Error())
+		matchingErrorResponse(c, "CONNECT_ERROR", err)
 		return
 	}
 
@@ -107,8 +119,12 @@ func (h *MatchingHandler) GetConnections(c *gin.Context) {
 // @Param id path string true "ID da conexão"
 // @Success 200 {object} Response
 // @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
 // @Router /matching/connections/{id}/accept [post]
 func (h *MatchingHandler) AcceptConnection(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
@@ -116,8 +132,8 @@ func (h *MatchingHandler) AcceptConnection(c *gin.Context) {
 		return
 	}
 
-	if err := h.matchingService.AcceptConnection(id); err != nil {
-		ErrorResponse(c, http.StatusBadRequest, "ACCEPT_ERROR", err.Error())
+	if err := h.matchingService.AcceptConnection(id, userID); err != nil {
+		connectionErrorResponse(c, "ACCEPT_ERROR", err)
 		return
 	}
 
@@ -133,8 +149,12 @@ func (h *MatchingHandler) AcceptConnection(c *gin.Context) {
 // @Param id path string true "ID da conexão"
 // @Success 200 {object} Response
 // @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
 // @Router /matching/connections/{id}/reject [post]
 func (h *MatchingHandler) RejectConnection(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
@@ -142,10 +162,38 @@ func (h *MatchingHandler) RejectConnection(c *gin.Context) {
 		return
 	}
 
-	if err := h.matchingService.RejectConnection(id); err != nil {
-		ErrorResponse(c, http.StatusBadRequest, "REJECT_ERROR", err.Error())
+	if err := h.matchingService.RejectConnection(id, userID); err != nil {
+		connectionErrorResponse(c, "REJECT_ERROR", err)
 		return
 	}
 
 	SuccessResponse(c, http.StatusOK, gin.H{"message": "Conexão rejeitada"})
 }
+
+// connectionErrorResponse responde 404 para conexões inexistentes, 403 para quem não é
+// o destinatário, 409 para conexões já respondidas ou 400 para os demais erros.
+func connectionErrorResponse(c *gin.Context, code string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrConnectionNotFound):
+		ErrorResponse(c, http.StatusNotFound, "CONNECTION_NOT_FOUND", err.Error())
+	case errors.Is(err, service.ErrNotConnectionTarget):
+		ErrorResponse(c, http.StatusForbidden, "FORBIDDEN", err.Error())
+	case errors.Is(err, domain.ErrConnectionNotPending):
+		ErrorResponse(c, http.StatusConflict, "CONNECTION_ALREADY_ANSWERED", err.Error())
+	default:
+		ErrorResponse(c, http.StatusBadRequest, code, err.Error())
+	}
+}
+
+// matchingErrorResponse responde 403 para voluntários sem a verificação exigida,
+// 404 para residentes inexistentes ou 400 para os demais erros.
+func matchingErrorResponse(c *gin.Context, code string, err error) {
+	switch {
+	case errors.Is(err, service.ErrVolunteerNotVerified):
+		ErrorResponse(c, http.StatusForbidden, "VOLUNTEER_NOT_VERIFIED", err.Error())
+	case errors.Is(err, domain.ErrResidentNotFound):
+		ErrorResponse(c, http.StatusNotFound, "RESIDENT_NOT_FOUND", err.Error())
+	default:
+		ErrorResponse(c, http.StatusBadRequest, code, err.Error())
+	}
+}
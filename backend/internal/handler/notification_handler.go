@@ -0,0 +1,169 @@
+// Package handler contém os handlers HTTP da aplicação.
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"amigos-terceira-idade/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// NotificationHandler gerencia os endpoints da caixa de entrada de notificações.
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+}
+
+// NewNotificationHandler cria uma nova instância do handler de notificações.
+func NewNotificationHandler(notificationService *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// List godoc
+// @Summary Lista minhas notificações
+// @Description Retorna as notificações do usuário autenticado, das mais recentes para as mais antigas
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Param unread query bool false "Apenas não lidas"
+// @Param page query int false "Página (padrão 1)"
+// @Param per_page query int false "Itens por página (padrão 20, máximo 100)"
+// @Success 200 {object} Response
+// @Router /notifications [get]
+func (h *NotificationHandler) List(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	unreadOnly := c.Query("unread") == "true"
+	page, _ := strconv.Atoi(c.Query("page"))
+	perPage, _ := strconv.Atoi(c.Query("per_page"))
+
+	result, err := h.notificationService.List(userID, unreadOnly, page, perPage)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "FETCH_ERROR", err.Error())
+		return
+	}
+
+	totalPages := int((result.Total + int64(result.PerPage) - 1) / int64(result.PerPage))
+	SuccessResponseWithMeta(c, http.StatusOK, result.Notifications, &MetaInfo{
+		Page:       result.Page,
+		PerPage:    result.PerPage,
+		Total:      int(result.Total),
+		TotalPages: totalPages,
+	})
+}
+
+// UnreadCount godoc
+// @Summary Quantidade de notificações não lidas
+// @Description Retorna o total de não lidas e a quantidade por tipo
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Router /notifications/unread-count [get]
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	count, err := h.notificationService.GetUnreadCount(userID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "FETCH_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, count)
+}
+
+// MarkRead godoc
+// @Summary Marca uma notificação como lida
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da notificação"
+// @Success 200 {object} Response
+// @Failure 404 {object} Response
+// @Router /notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	if err := h.notificationService.MarkRead(userID, id); err != nil {
+		ErrorResponse(c, http.StatusNotFound, "NOTIFICATION_NOT_FOUND", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Notificação marcada como lida"})
+}
+
+// MarkAllRead godoc
+// @Summary Marca todas as notificações como lidas
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Router /notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	updated, err := h.notificationService.MarkAllRead(userID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "UPDATE_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"updated": updated})
+}
+
+// GetPreferences godoc
+// @Summary Preferências de notificação
+// @Description Indica, para cada tipo, se o usuário recebe a notificação
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Router /notifications/preferences [get]
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	preferences, err := h.notificationService.GetPreferences(userID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "FETCH_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, preferences)
+}
+
+// UpdatePreferences godoc
+// @Summary Atualiza preferências de notificação
+// @Description Ativa ou desativa tipos de notificação (ex.: {"preferences": {"APPOINTMENT_REMINDER": false}})
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.UpdatePreferencesRequest true "Preferências"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /notifications/preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	var req service.UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos: "+err.Error())
+		return
+	}
+
+	preferences, err := h.notificationService.UpdatePreferences(userID, req)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "PREFERENCES_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, preferences)
+}
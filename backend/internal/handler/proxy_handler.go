@@ -0,0 +1,524 @@
+// Package handler contém os handlers HTTP da aplicação.
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ProxyHandler gerencia os endpoints das delegações a familiares: a concessão e a
+// revogação pelo idoso (/users/me/proxies) e as ações do familiar em nome dele (/proxies).
+type ProxyHandler struct {
+	proxyService *service.ProxyService
+}
+
+// NewProxyHandler cria uma nova instância do handler de delegações.
+func NewProxyHandler(proxyService *service.ProxyService) *ProxyHandler {
+	return &ProxyHandler{
+		proxyService: proxyService,
+	}
+}
+
+// ListGrants godoc
+// @Summary Familiares com acesso
+// @Description Lista as delegações concedidas pelo idoso, inclusive as revogadas
+// @Tags Proxies
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Router /users/me/proxies [get]
+func (h *ProxyHandler) ListGrants(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	grants, err := h.proxyService.ListGrants(userID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "FETCH_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, grants)
+}
+
+// Grant godoc
+// @Summary Dá acesso a um familiar
+// @Description O idoso concede a uma conta existente acesso à sua conta, com as permissões
+// @Description view, invitations:respond e profile:edit (view é sempre incluída)
+// @Tags Proxies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.GrantProxyRequest true "E-mail do familiar e permissões"
+// @Success 201 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Failure 409 {object} Response
+// @Router /users/me/proxies [post]
+func (h *ProxyHandler) Grant(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	var req service.GrantProxyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos: "+err.Error())
+		return
+	}
+
+	grant, err := h.proxyService.Grant(userID, req)
+	if err != nil {
+		proxyErrorResponse(c, "GRANT_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusCreated, grant)
+}
+
+// UpdateGrant godoc
+// @Summary Altera as permissões de um familiar
+// @Tags Proxies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da delegação"
+// @Param request body service.UpdateProxyRequest true "Novas permissões"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /users/me/proxies/{id} [put]
+func (h *ProxyHandler) UpdateGrant(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	id, ok := parseProxyParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req service.UpdateProxyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos: "+err.Error())
+		return
+	}
+
+	grant, err := h.proxyService.UpdatePermissions(userID, id, req)
+	if err != nil {
+		proxyErrorResponse(c, "UPDATE_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, grant)
+}
+
+// RevokeGrant godoc
+// @Summary Revoga o acesso de um familiar
+// @Tags Proxies
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da delegação"
+// @Success 200 {object} Response
+// @Failure 404 {object} Response
+// @Router /users/me/proxies/{id} [delete]
+func (h *ProxyHandler) RevokeGrant(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	id, ok := parseProxyParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.proxyService.Revoke(userID, id); err != nil {
+		proxyErrorResponse(c, "REVOKE_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Acesso revogado"})
+}
+
+// ListActions godoc
+// @Summary Ações dos familiares
+// @Description Lista o que cada familiar fez em nome do idoso, e quando
+// @Tags Proxies
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Página (padrão 1)"
+// @Param per_page query int false "Itens por página (padrão 20, máximo 100)"
+// @Success 200 {object} Response
+// @Router /users/me/proxies/actions [get]
+func (h *ProxyHandler) ListActions(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	page, _ := strconv.Atoi(c.Query("page"))
+	perPage, _ := strconv.Atoi(c.Query("per_page"))
+
+	result, err := h.proxyService.ListActions(userID, page, perPage)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "FETCH_ERROR", err.Error())
+		return
+	}
+
+	totalPages := int((result.Total + int64(result.PerPage) - 1) / int64(result.PerPage))
+	SuccessResponseWithMeta(c, http.StatusOK, result.Entries, &MetaInfo{
+		Page:       result.Page,
+		PerPage:    result.PerPage,
+		Total:      int(result.Total),
+		TotalPages: totalPages,
+	})
+}
+
+// ListManaged godoc
+// @Summary Contas que gerencio
+// @Description Lista as delegações ativas recebidas pelo familiar
+// @Tags Proxies
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Router /proxies [get]
+func (h *ProxyHandler) ListManaged(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	grants, err := h.proxyService.ListManaged(userID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "FETCH_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, grants)
+}
+
+// Relinquish godoc
+// @Summary Abre mão do acesso
+// @Description O familiar encerra a delegação recebida do idoso
+// @Tags Proxies
+// @Produce json
+// @Security BearerAuth
+// @Param elderlyId path string true "ID do idoso"
+// @Success 200 {object} Response
+// @Failure 404 {object} Response
+// @Router /proxies/{elderlyId} [delete]
+func (h *ProxyHandler) Relinquish(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	elderlyID, ok := parseProxyParam(c, "elderlyId")
+	if !ok {
+		return
+	}
+
+	if err := h.proxyService.Relinquish(userID, elderlyID); err != nil {
+		proxyErrorResponse(c, "REVOKE_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Acesso encerrado"})
+}
+
+// GetProfile godoc
+// @Summary Perfil do idoso
+// @Description Retorna o perfil do idoso para o familiar (requer view)
+// @Tags Proxies
+// @Produce json
+// @Security BearerAuth
+// @Param elderlyId path string true "ID do idoso"
+// @Success 200 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Router /proxies/{elderlyId}/profile [get]
+func (h *ProxyHandler) GetProfile(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	elderlyID, ok := parseProxyParam(c, "elderlyId")
+	if !ok {
+		return
+	}
+
+	user, err := h.proxyService.GetProfile(userID, elderlyID)
+	if err != nil {
+		proxyErrorResponse(c, "FETCH_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, user)
+}
+
+// UpdateProfile godoc
+// @Summary Atualiza o perfil do idoso
+// @Description Altera o perfil em nome do idoso (requer profile:edit)
+// @Tags Proxies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param elderlyId path string true "ID do idoso"
+// @Param request body service.UpdateProfileRequest true "Dados do perfil"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Router /proxies/{elderlyId}/profile [put]
+func (h *ProxyHandler) UpdateProfile(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	elderlyID, ok := parseProxyParam(c, "elderlyId")
+	if !ok {
+		return
+	}
+
+	var req service.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos: "+err.Error())
+		return
+	}
+
+	user, err := h.proxyService.UpdateProfile(userID, elderlyID, req)
+	if err != nil {
+		proxyErrorResponse(c, "UPDATE_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, user)
+}
+
+// GetAppointments godoc
+// @Summary Agendamentos do idoso
+// @Tags Proxies
+// @Produce json
+// @Security BearerAuth
+// @Param elderlyId path string true "ID do idoso"
+// @Success 200 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Router /proxies/{elderlyId}/appointments [get]
+func (h *ProxyHandler) GetAppointments(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	elderlyID, ok := parseProxyParam(c, "elderlyId")
+	if !ok {
+		return
+	}
+
+	appointments, err := h.proxyService.GetAppointments(userID, elderlyID)
+	if err != nil {
+		proxyErrorResponse(c, "FETCH_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, appointments)
+}
+
+// GetInvitations godoc
+// @Summary Convites pendentes do idoso
+// @Tags Proxies
+// @Produce json
+// @Security BearerAuth
+// @Param elderlyId path string true "ID do idoso"
+// @Success 200 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Router /proxies/{elderlyId}/invitations [get]
+func (h *ProxyHandler) GetInvitations(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	elderlyID, ok := parseProxyParam(c, "elderlyId")
+	if !ok {
+		return
+	}
+
+	invitations, err := h.proxyService.GetInvitations(userID, elderlyID)
+	if err != nil {
+		proxyErrorResponse(c, "FETCH_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, invitations)
+}
+
+// GetConnections godoc
+// @Summary Conexões do idoso
+// @Tags Proxies
+// @Produce json
+// @Security BearerAuth
+// @Param elderlyId path string true "ID do idoso"
+// @Success 200 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Router /proxies/{elderlyId}/connections [get]
+func (h *ProxyHandler) GetConnections(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	elderlyID, ok := parseProxyParam(c, "elderlyId")
+	if !ok {
+		return
+	}
+
+	connections, err := h.proxyService.GetConnections(userID, elderlyID)
+	if err != nil {
+		proxyErrorResponse(c, "FETCH_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, connections)
+}
+
+// AcceptInvitation godoc
+// @Summary Aceita um convite pelo idoso
+// @Description Aceita o convite em nome do idoso (requer invitations:respond)
+// @Tags Proxies
+// @Produce json
+// @Security BearerAuth
+// @Param elderlyId path string true "ID do idoso"
+// @Param id path string true "ID do agendamento"
+// @Param scope query string false "Escopo em séries: this, following ou all"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Router /proxies/{elderlyId}/appointments/{id}/accept [post]
+func (h *ProxyHandler) AcceptInvitation(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	elderlyID, id, ok := parseProxyResource(c)
+	if !ok {
+		return
+	}
+	scope, ok := bindScope(c)
+	if !ok {
+		return
+	}
+
+	if err := h.proxyService.AcceptInvitation(userID, elderlyID, id, scope); err != nil {
+		proxyErrorResponse(c, "ACCEPT_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Convite aceito com sucesso"})
+}
+
+// DeclineInvitation godoc
+// @Summary Recusa um convite pelo idoso
+// @Description Recusa o convite em nome do idoso (requer invitations:respond)
+// @Tags Proxies
+// @Produce json
+// @Security BearerAuth
+// @Param elderlyId path string true "ID do idoso"
+// @Param id path string true "ID do agendamento"
+// @Param scope query string false "Escopo em séries: this, following ou all"
+// @Param request body StatusChangeRequest false "Motivo da recusa"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Router /proxies/{elderlyId}/appointments/{id}/decline [post]
+func (h *ProxyHandler) DeclineInvitation(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	elderlyID, id, ok := parseProxyResource(c)
+	if !ok {
+		return
+	}
+	reason, ok := bindReason(c)
+	if !ok {
+		return
+	}
+	scope, ok := bindScope(c)
+	if !ok {
+		return
+	}
+
+	if err := h.proxyService.DeclineInvitation(userID, elderlyID, id, reason, scope); err != nil {
+		proxyErrorResponse(c, "DECLINE_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Convite recusado"})
+}
+
+// AcceptConnection godoc
+// @Summary Aceita uma conexão pelo idoso
+// @Description Aceita o pedido de conexão em nome do idoso (requer invitations:respond)
+// @Tags Proxies
+// @Produce json
+// @Security BearerAuth
+// @Param elderlyId path string true "ID do idoso"
+// @Param id path string true "ID da conexão"
+// @Success 200 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /proxies/{elderlyId}/connections/{id}/accept [post]
+func (h *ProxyHandler) AcceptConnection(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	elderlyID, id, ok := parseProxyResource(c)
+	if !ok {
+		return
+	}
+
+	if err := h.proxyService.AcceptConnection(userID, elderlyID, id); err != nil {
+		proxyErrorResponse(c, "ACCEPT_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Conexão aceita com sucesso"})
+}
+
+// RejectConnection godoc
+// @Summary Rejeita uma conexão pelo idoso
+// @Description Rejeita o pedido de conexão em nome do idoso (requer invitations:respond)
+// @Tags Proxies
+// @Produce json
+// @Security BearerAuth
+// @Param elderlyId path string true "ID do idoso"
+// @Param id path string true "ID da conexão"
+// @Success 200 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /proxies/{elderlyId}/connections/{id}/reject [post]
+func (h *ProxyHandler) RejectConnection(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	elderlyID, id, ok := parseProxyResource(c)
+	if !ok {
+		return
+	}
+
+	if err := h.proxyService.RejectConnection(userID, elderlyID, id); err != nil {
+		proxyErrorResponse(c, "REJECT_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Conexão rejeitada"})
+}
+
+// parseProxyParam lê um ID da rota, respondendo 400 se for inválido.
+func parseProxyParam(c *gin.Context, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// parseProxyResource lê o ID do idoso e o do agendamento ou conexão da rota.
+func parseProxyResource(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	elderlyID, ok := parseProxyParam(c, "elderlyId")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	id, ok := parseProxyParam(c, "id")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	return elderlyID, id, true
+}
+
+// proxyErrorResponse responde 404 para delegações, agendamentos ou conexões inexistentes,
+// 403 quando a delegação não permite a ação, 409 para delegações repetidas ou pedidos já
+// respondidos, ou 400 para os demais erros.
+func proxyErrorResponse(c *gin.Context, code string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrProxyGrantNotFound):
+		ErrorResponse(c, http.StatusNotFound, "PROXY_GRANT_NOT_FOUND", err.Error())
+	case errors.Is(err, domain.ErrAppointmentNotFound):
+		ErrorResponse(c, http.StatusNotFound, "APPOINTMENT_NOT_FOUND", err.Error())
+	case errors.Is(err, domain.ErrConnectionNotFound):
+		ErrorResponse(c, http.StatusNotFound, "CONNECTION_NOT_FOUND", err.Error())
+	case errors.Is(err, service.ErrProxyPermissionDenied), errors.Is(err, service.ErrElderlyOnly),
+		errors.Is(err, service.ErrProxyOwnInvitation), errors.Is(err, service.ErrNotAppointmentParticipant),
+		errors.Is(err, service.ErrNotConnectionTarget):
+		ErrorResponse(c, http.StatusForbidden, "FORBIDDEN", err.Error())
+	case errors.Is(err, service.ErrProxyAlreadyGranted):
+		ErrorResponse(c, http.StatusConflict, "PROXY_ALREADY_GRANTED", err.Error())
+	case errors.Is(err, domain.ErrConnectionNotPending):
+		ErrorResponse(c, http.StatusConflict, "CONNECTION_ALREADY_ANSWERED", err.Error())
+	default:
+		ErrorResponse(c, http.StatusBadRequest, code, err.Error())
+	}
+}
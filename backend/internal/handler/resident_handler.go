@@ -0,0 +1,209 @@
+// Package handler contém os handlers HTTP da aplicação.
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/middleware"
+	"amigos-terceira-idade/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ResidentHandler gerencia os endpoints dos residentes das instituições.
+type ResidentHandler struct {
+	residentService *service.ResidentService
+}
+
+// NewResidentHandler cria uma nova instância do handler de residentes.
+func NewResidentHandler(residentService *service.ResidentService) *ResidentHandler {
+	return &ResidentHandler{
+		residentService: residentService,
+	}
+}
+
+// Create godoc
+// @Summary Cadastra um residente
+// @Description Cadastra o perfil de um idoso que mora na instituição (apenas instituições)
+// @Tags Residents
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.ResidentRequest true "Dados do residente"
+// @Success 201 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Router /residents [post]
+func (h *ResidentHandler) Create(c *gin.Context) {
+	institutionID := middleware.ActingInstitutionID(c)
+
+	var req service.ResidentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos: "+err.Error())
+		return
+	}
+
+	resident, err := h.residentService.Create(institutionID, req)
+	if err != nil {
+		residentErrorResponse(c, "CREATE_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusCreated, resident)
+}
+
+// List godoc
+// @Summary Lista meus residentes
+// @Description Lista os residentes da instituição em ordem alfabética
+// @Tags Residents
+// @Produce json
+// @Security BearerAuth
+// @Param include_inactive query bool false "Incluir residentes desativados"
+// @Success 200 {object} Response
+// @Failure 403 {object} Response
+// @Router /residents [get]
+func (h *ResidentHandler) List(c *gin.Context) {
+	institutionID := middleware.ActingInstitutionID(c)
+	includeInactive := c.Query("include_inactive") == "true"
+
+	residents, err := h.residentService.List(institutionID, includeInactive)
+	if err != nil {
+		residentErrorResponse(c, "FETCH_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, residents)
+}
+
+// GetByID godoc
+// @Summary Busca um residente
+// @Description Retorna o perfil de um residente ativo (a instituição vê também os desativados)
+// @Tags Residents
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do residente"
+// @Success 200 {object} Response
+// @Failure 404 {object} Response
+// @Router /residents/{id} [get]
+func (h *ResidentHandler) GetByID(c *gin.Context) {
+	institutionID := middleware.ActingInstitutionID(c)
+	id, ok := parseResidentID(c)
+	if !ok {
+		return
+	}
+
+	resident, err := h.residentService.GetByID(id, institutionID)
+	if err != nil {
+		residentErrorResponse(c, "FETCH_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, resident)
+}
+
+// Update godoc
+// @Summary Atualiza um residente
+// @Description Altera o perfil, os interesses e as necessidades de assistência do residente
+// @Tags Residents
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do residente"
+// @Param request body service.ResidentRequest true "Dados do residente"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Router /residents/{id} [put]
+func (h *ResidentHandler) Update(c *gin.Context) {
+	institutionID := middleware.ActingInstitutionID(c)
+	id, ok := parseResidentID(c)
+	if !ok {
+		return
+	}
+
+	var req service.ResidentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos: "+err.Error())
+		return
+	}
+
+	resident, err := h.residentService.Update(institutionID, id, req)
+	if err != nil {
+		residentErrorResponse(c, "UPDATE_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, resident)
+}
+
+// Deactivate godoc
+// @Summary Desativa um residente
+// @Description Remove o residente do pareamento e dos novos convites; o histórico é mantido
+// @Tags Residents
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do residente"
+// @Success 200 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Router /residents/{id} [delete]
+func (h *ResidentHandler) Deactivate(c *gin.Context) {
+	h.setActive(c, false)
+}
+
+// Reactivate godoc
+// @Summary Reativa um residente
+// @Tags Residents
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do residente"
+// @Success 200 {object} Response
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Router /residents/{id}/reactivate [post]
+func (h *ResidentHandler) Reactivate(c *gin.Context) {
+	h.setActive(c, true)
+}
+
+// setActive desativa ou reativa o residente indicado na rota.
+func (h *ResidentHandler) setActive(c *gin.Context, active bool) {
+	institutionID := middleware.ActingInstitutionID(c)
+	id, ok := parseResidentID(c)
+	if !ok {
+		return
+	}
+
+	resident, err := h.residentService.SetActive(institutionID, id, active)
+	if err != nil {
+		residentErrorResponse(c, "UPDATE_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, resident)
+}
+
+// parseResidentID lê o ID do residente da rota, respondendo 400 se for inválido.
+func parseResidentID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// residentErrorResponse responde 404 para residentes inexistentes, 403 para quem não é
+// a instituição responsável ou 400 para os demais erros.
+func residentErrorResponse(c *gin.Context, code string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrResidentNotFound):
+		ErrorResponse(c, http.StatusNotFound, "RESIDENT_NOT_FOUND", err.Error())
+	case errors.Is(err, service.ErrInstitutionOnlyResidents), errors.Is(err, service.ErrNotResidentInstitution):
+		ErrorResponse(c, http.StatusForbidden, "FORBIDDEN", err.Error())
+	default:
+		ErrorResponse(c, http.StatusBadRequest, code, err.Error())
+	}
+}
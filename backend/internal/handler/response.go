@@ -17,8 +17,9 @@ type Response struct {
 
 // ErrorInfo contém detalhes sobre um erro.
 type ErrorInfo struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
 }
 
 // MetaInfo contém informações adicionais como paginação.
@@ -56,3 +57,16 @@ func ErrorResponse(c *gin.Context, statusCode int, code, message string) {
 		},
 	})
 }
+
+// ErrorResponseWithDetails retorna uma resposta de erro com dados adicionais,
+// como a lista de agendamentos em conflito.
+func ErrorResponseWithDetails(c *gin.Context, statusCode int, code, message string, details interface{}) {
+	c.JSON(statusCode, Response{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    code,
+			Message: message,
+			Details: details,
+		},
+	})
+}
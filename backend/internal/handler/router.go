@@ -2,19 +2,49 @@
 package handler
 
 import (
+	"amigos-terceira-idade/internal/domain"
 	"amigos-terceira-idade/internal/middleware"
 	"amigos-terceira-idade/internal/service"
+	"amigos-terceira-idade/pkg/ratelimit"
 	"github.com/gin-gonic/gin"
 )
 
+// RouterOptions configura regras de acesso aplicadas nas rotas.
+type RouterOptions struct {
+	// RequireVerifiedEmail exige e-mail confirmado para pedir conexões e agendar visitas.
+	RequireVerifiedEmail bool
+	// RateLimit limita as requisições por IP, conta e usuário.
+	RateLimit RateLimitOptions
+}
+
+// RateLimitOptions define os limites de requisições de cada grupo de rotas. Sem Store,
+// ou com limites zerados, as rotas não são limitadas.
+type RateLimitOptions struct {
+	Store           ratelimit.Store
+	APIPerIP        ratelimit.Limit // Todas as rotas da API, por IP
+	AuthPerIP       ratelimit.Limit // Rotas públicas de autenticação, por IP
+	LoginPerAccount ratelimit.Limit // Login, códigos de acesso e recuperação de senha, por e-mail
+	APIPerUser      ratelimit.Limit // Rotas autenticadas, por usuário
+}
+
 // Router configura todas as rotas da API.
 type Router struct {
-	authHandler        *AuthHandler
-	userHandler        *UserHandler
-	interestHandler    *InterestHandler
-	matchingHandler    *MatchingHandler
-	appointmentHandler *AppointmentHandler
-	authService        *service.AuthService
+	authHandler         *AuthHandler
+	userHandler         *UserHandler
+	interestHandler     *InterestHandler
+	matchingHandler     *MatchingHandler
+	appointmentHandler  *AppointmentHandler
+	availabilityHandler *AvailabilityHandler
+	calendarHandler     *CalendarHandler
+	notificationHandler *NotificationHandler
+	eventHandler        *EventHandler
+	webhookHandler      *WebhookHandler
+	adminHandler        *AdminHandler
+	verificationHandler *VerificationHandler
+	residentHandler     *ResidentHandler
+	proxyHandler        *ProxyHandler
+	authService         *service.AuthService
+	options             RouterOptions
 }
 
 // NewRouter cria uma nova instância do router.
@@ -24,15 +54,35 @@ func NewRouter(
 	interestHandler *InterestHandler,
 	matchingHandler *MatchingHandler,
 	appointmentHandler *AppointmentHandler,
+	availabilityHandler *AvailabilityHandler,
+	calendarHandler *CalendarHandler,
+	notificationHandler *NotificationHandler,
+	eventHandler *EventHandler,
+	webhookHandler *WebhookHandler,
+	adminHandler *AdminHandler,
+	verificationHandler *VerificationHandler,
+	residentHandler *ResidentHandler,
+	proxyHandler *ProxyHandler,
 	authService *service.AuthService,
+	options RouterOptions,
 ) *Router {
 	return &Router{
-		authHandler:        authHandler,
-		userHandler:        userHandler,
-		interestHandler:    interestHandler,
-		matchingHandler:    matchingHandler,
-		appointmentHandler: appointmentHandler,
-		authService:        authService,
+		authHandler:         authHandler,
+		userHandler:         userHandler,
+		interestHandler:     interestHandler,
+		matchingHandler:     matchingHandler,
+		appointmentHandler:  appointmentHandler,
+		availabilityHandler: availabilityHandler,
+		calendarHandler:     calendarHandler,
+		notificationHandler: notificationHandler,
+		eventHandler:        eventHandler,
+		webhookHandler:      webhookHandler,
+		adminHandler:        adminHandler,
+		verificationHandler: verificationHandler,
+		residentHandler:     residentHandler,
+		proxyHandler:        proxyHandler,
+		authService:         authService,
+		options:             options,
 	}
 }
 
@@ -43,6 +93,7 @@ func (r *Router) Setup(engine *gin.Engine) {
 
 	// Grupo base da API
 	api := engine.Group("/api/v1")
+	api.Use(r.rateLimit("api-ip", r.options.RateLimit.APIPerIP, middleware.ByIP))
 
 	// Rotas públicas (sem autenticação)
 	r.setupPublicRoutes(api)
@@ -50,9 +101,19 @@ func (r *Router) Setup(engine *gin.Engine) {
 	// Rotas protegidas (com autenticação)
 	protected := api.Group("")
 	protected.Use(middleware.AuthMiddleware(r.authService))
+	protected.Use(r.rateLimit("api-user", r.options.RateLimit.APIPerUser, middleware.ByUser))
 	r.setupProtectedRoutes(protected)
 }
 
+// rateLimit cria o middleware de limite de requisições, ou um que apenas segue adiante
+// se os limites estiverem desativados.
+func (r *Router) rateLimit(name string, limit ratelimit.Limit, key middleware.RateLimitKey) gin.HandlerFunc {
+	if r.options.RateLimit.Store == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return middleware.RateLimit(r.options.RateLimit.Store, name, limit, key)
+}
+
 // setupPublicRoutes configura as rotas que não precisam de autenticação.
 func (r *Router) setupPublicRoutes(api *gin.RouterGroup) {
 	// Health check
@@ -61,13 +122,25 @@ func (r *Router) setupPublicRoutes(api *gin.RouterGroup) {
 	})
 
 	// Autenticação
+	// Limitada por IP e, nas tentativas de login, também pelo e-mail informado
 	auth := api.Group("/auth")
+	auth.Use(r.rateLimit("auth-ip", r.options.RateLimit.AuthPerIP, middleware.ByIP))
+	perAccount := r.rateLimit("login-account", r.options.RateLimit.LoginPerAccount, middleware.ByJSONField("email"))
 	{
 		auth.POST("/register", r.authHandler.Register)
-		auth.POST("/login", r.authHandler.Login)
+		auth.POST("/login", perAccount, r.authHandler.Login)
+		auth.POST("/login-code", perAccount, r.authHandler.RequestLoginCode)
+		auth.POST("/login-code/verify", perAccount, r.authHandler.LoginWithCode)
 		auth.POST("/refresh", r.authHandler.RefreshToken)
+		auth.POST("/password/forgot", perAccount, r.authHandler.ForgotPassword)
+		auth.POST("/password/reset", r.authHandler.ResetPassword)
+		auth.POST("/email/verify", r.authHandler.VerifyEmail)
 	}
 
+	// Feed de calendário (.ics): autenticado pelo token secreto na URL,
+	// pois aplicativos de calendário não enviam o JWT
+	api.GET("/calendar/:token", r.calendarHandler.Feed)
+
 	// Interesses (público para mostrar no cadastro)
 	api.GET("/interests", r.interestHandler.GetAll)
 	api.GET("/interests/:id", r.interestHandler.GetByID)
@@ -75,6 +148,22 @@ func (r *Router) setupPublicRoutes(api *gin.RouterGroup) {
 
 // setupProtectedRoutes configura as rotas que precisam de autenticação.
 func (r *Router) setupProtectedRoutes(api *gin.RouterGroup) {
+	// Sessões
+	auth := api.Group("/auth")
+	{
+		auth.POST("/logout", r.authHandler.Logout)
+		auth.POST("/logout-all", r.authHandler.LogoutAll)
+		auth.GET("/sessions", r.authHandler.ListSessions)
+		auth.DELETE("/sessions/:id", r.authHandler.RevokeSession)
+		auth.POST("/email/verify/resend", r.authHandler.ResendEmailVerification)
+	}
+
+	// Pedir conexões e agendar exigem e-mail confirmado, se configurado
+	verified := func(c *gin.Context) { c.Next() }
+	if r.options.RequireVerifiedEmail {
+		verified = middleware.RequireVerifiedEmail()
+	}
+
 	// Usuários
 	users := api.Group("/users")
 	{
@@ -82,27 +171,91 @@ func (r *Router) setupProtectedRoutes(api *gin.RouterGroup) {
 		users.PUT("/me", r.userHandler.UpdateMe)
 		users.DELETE("/me", r.userHandler.Deactivate)
 		users.GET("/:id", r.userHandler.GetByID)
+
+		// Disponibilidade do voluntário
+		users.GET("/me/availability", r.availabilityHandler.GetMine)
+		users.PUT("/me/availability", r.availabilityHandler.SetMine)
+		users.GET("/me/availability/exceptions", r.availabilityHandler.GetExceptions)
+		users.POST("/me/availability/exceptions", r.availabilityHandler.AddException)
+		users.DELETE("/me/availability/exceptions/:id", r.availabilityHandler.RemoveException)
+		users.GET("/:id/availability/slots", r.availabilityHandler.GetSlots)
+		users.GET("/me/visit-windows", r.availabilityHandler.GetMyVisitWindows)
+		users.PUT("/me/visit-windows", r.availabilityHandler.SetMyVisitWindows)
+		users.GET("/:id/visit-windows", r.availabilityHandler.GetVisitWindows)
+
+		// Calendário
+		users.GET("/me/calendar", r.calendarHandler.GetMyFeed)
+		users.POST("/me/calendar/rotate", r.calendarHandler.RotateMyFeed)
+
+		// Verificação do voluntário
+		users.GET("/me/verification", r.verificationHandler.GetMine)
+		users.POST("/me/verification/documents", r.verificationHandler.UploadDocument)
+		users.POST("/me/verification/submit", r.verificationHandler.Submit)
+
+		// Familiares com acesso à conta do idoso
+		users.GET("/me/proxies", r.proxyHandler.ListGrants)
+		users.POST("/me/proxies", r.proxyHandler.Grant)
+		users.GET("/me/proxies/actions", r.proxyHandler.ListActions)
+		users.PUT("/me/proxies/:id", r.proxyHandler.UpdateGrant)
+		users.DELETE("/me/proxies/:id", r.proxyHandler.RevokeGrant)
+	}
+
+	// Ações do familiar em nome do idoso, conforme as permissões da delegação
+	proxies := api.Group("/proxies")
+	{
+		proxies.GET("", r.proxyHandler.ListManaged)
+		proxies.DELETE("/:elderlyId", r.proxyHandler.Relinquish)
+		proxies.GET("/:elderlyId/profile", r.proxyHandler.GetProfile)
+		proxies.PUT("/:elderlyId/profile", r.proxyHandler.UpdateProfile)
+		proxies.GET("/:elderlyId/appointments", r.proxyHandler.GetAppointments)
+		proxies.GET("/:elderlyId/invitations", r.proxyHandler.GetInvitations)
+		proxies.GET("/:elderlyId/connections", r.proxyHandler.GetConnections)
+		proxies.POST("/:elderlyId/appointments/:id/accept", r.proxyHandler.AcceptInvitation)
+		proxies.POST("/:elderlyId/appointments/:id/decline", r.proxyHandler.DeclineInvitation)
+		proxies.POST("/:elderlyId/connections/:id/accept", r.proxyHandler.AcceptConnection)
+		proxies.POST("/:elderlyId/connections/:id/reject", r.proxyHandler.RejectConnection)
 	}
 
 	// Pareamento
 	matching := api.Group("/matching")
 	{
 		matching.GET("/suggestions", r.matchingHandler.GetSuggestions)
-		matching.POST("/connect", r.matchingHandler.Connect)
+		matching.POST("/connect", verified, r.matchingHandler.Connect)
 		matching.GET("/connections", r.matchingHandler.GetConnections)
 		matching.POST("/connections/:id/accept", r.matchingHandler.AcceptConnection)
 		matching.POST("/connections/:id/reject", r.matchingHandler.RejectConnection)
 	}
 
+	// Residentes das instituições
+	residents := api.Group("/residents")
+	{
+		residents.POST("", r.residentHandler.Create)
+		residents.GET("", r.residentHandler.List)
+		residents.GET("/:id", r.residentHandler.GetByID)
+		residents.PUT("/:id", r.residentHandler.Update)
+		residents.DELETE("/:id", r.residentHandler.Deactivate)
+		residents.POST("/:id/reactivate", r.residentHandler.Reactivate)
+	}
+
 	// Agendamentos
 	appointments := api.Group("/appointments")
 	{
-		appointments.POST("", r.appointmentHandler.Create)
+		appointments.POST("", verified, r.appointmentHandler.Create)
 		appointments.GET("", r.appointmentHandler.GetMy)
 		appointments.GET("/upcoming", r.appointmentHandler.GetUpcoming)
 		appointments.GET("/:id", r.appointmentHandler.GetByID)
 		appointments.POST("/:id/accept", r.appointmentHandler.Accept)
 		appointments.POST("/:id/decline", r.appointmentHandler.Decline)
+		appointments.POST("/:id/complete", r.appointmentHandler.Complete)
+		appointments.POST("/:id/rating", r.appointmentHandler.Rate)
+		appointments.POST("/:id/no-show", r.appointmentHandler.MarkNoShow)
+		appointments.GET("/:id/history", r.appointmentHandler.GetHistory)
+		appointments.GET("/:id/series", r.appointmentHandler.GetSeries)
+		appointments.GET("/:id/ics", r.calendarHandler.AppointmentICS)
+		appointments.PATCH("/:id", r.appointmentHandler.ProposeReschedule)
+		appointments.POST("/:id/reschedule", r.appointmentHandler.ProposeReschedule)
+		appointments.POST("/:id/reschedule/accept", r.appointmentHandler.AcceptReschedule)
+		appointments.POST("/:id/reschedule/reject", r.appointmentHandler.RejectReschedule)
 		appointments.DELETE("/:id", r.appointmentHandler.Cancel)
 	}
 
@@ -112,4 +265,61 @@ func (r *Router) setupProtectedRoutes(api *gin.RouterGroup) {
 		invitations.GET("/received", r.appointmentHandler.GetReceivedInvitations)
 		invitations.GET("/sent", r.appointmentHandler.GetSentInvitations)
 	}
+
+	// Notificações
+	notifications := api.Group("/notifications")
+	{
+		notifications.GET("", r.notificationHandler.List)
+		notifications.GET("/unread-count", r.notificationHandler.UnreadCount)
+		notifications.POST("/read-all", r.notificationHandler.MarkAllRead)
+		notifications.POST("/:id/read", r.notificationHandler.MarkRead)
+		notifications.GET("/preferences", r.notificationHandler.GetPreferences)
+		notifications.PUT("/preferences", r.notificationHandler.UpdatePreferences)
+	}
+
+	// Rotas de eventos em tempo real
+	events := api.Group("/events")
+	{
+		events.GET("/stream", r.eventHandler.Stream)
+	}
+
+	// Rotas de webhooks (instituições)
+	webhooks := api.Group("/webhooks")
+	{
+		webhooks.POST("", r.webhookHandler.Create)
+		webhooks.GET("", r.webhookHandler.List)
+		webhooks.GET("/:id", r.webhookHandler.GetByID)
+		webhooks.PUT("/:id", r.webhookHandler.Update)
+		webhooks.DELETE("/:id", r.webhookHandler.Delete)
+		webhooks.POST("/:id/test", r.webhookHandler.SendTest)
+		webhooks.GET("/:id/deliveries", r.webhookHandler.ListDeliveries)
+	}
+
+	// Administração (papéis ADMIN e MODERATOR, conforme a permissão de cada rota)
+	admin := api.Group("/admin")
+	admin.Use(middleware.RequireRole(domain.RoleAdmin, domain.RoleModerator))
+	{
+		canRead := middleware.RequirePermission(domain.PermissionUsersRead)
+		canDeactivate := middleware.RequirePermission(domain.PermissionUsersDeactivate)
+		canManage := middleware.RequirePermission(domain.PermissionUsersManage)
+
+		admin.GET("/users", canRead, r.adminHandler.SearchUsers)
+		admin.GET("/users/:id", canRead, r.adminHandler.GetUser)
+		admin.GET("/users/:id/connections", canRead, r.adminHandler.GetUserConnections)
+		admin.GET("/users/:id/appointments", canRead, r.adminHandler.GetUserAppointments)
+		admin.POST("/users/:id/deactivate", canDeactivate, r.adminHandler.DeactivateUser)
+		admin.POST("/users/:id/reactivate", canDeactivate, r.adminHandler.ReactivateUser)
+		admin.POST("/users/:id/reset-password", canManage, r.adminHandler.ResetPassword)
+		admin.POST("/users/:id/merge", canManage, r.adminHandler.MergeUsers)
+		admin.PUT("/users/:id/role", middleware.RequirePermission(domain.PermissionRolesManage), r.adminHandler.SetRole)
+		admin.GET("/appointments", middleware.RequirePermission(domain.PermissionAppointmentsReadAll), r.adminHandler.ListAppointments)
+		admin.GET("/audit-log", middleware.RequirePermission(domain.PermissionAuditRead), r.adminHandler.ListAuditLog)
+
+		canVerify := middleware.RequirePermission(domain.PermissionVolunteersVerify)
+		admin.GET("/verifications", canVerify, r.verificationHandler.List)
+		admin.GET("/verifications/:id", canVerify, r.verificationHandler.GetByVolunteer)
+		admin.GET("/verifications/:id/documents/:documentId", canVerify, r.verificationHandler.DownloadDocument)
+		admin.POST("/verifications/:id/approve", canVerify, r.verificationHandler.Approve)
+		admin.POST("/verifications/:id/reject", canVerify, r.verificationHandler.Reject)
+	}
 }
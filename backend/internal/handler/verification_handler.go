@@ -0,0 +1,284 @@
+// Package handler contém os handlers HTTP da aplicação.
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// VerificationHandler gerencia os endpoints da verificação de voluntários: o envio dos
+// documentos pelo voluntário e a análise pela equipe, nas rotas /admin.
+type VerificationHandler struct {
+	verificationService *service.VerificationService
+}
+
+// NewVerificationHandler cria uma nova instância do handler de verificação.
+func NewVerificationHandler(verificationService *service.VerificationService) *VerificationHandler {
+	return &VerificationHandler{
+		verificationService: verificationService,
+	}
+}
+
+// GetMine godoc
+// @Summary Minha verificação
+// @Description Retorna o status, os documentos enviados e o histórico da verificação do voluntário
+// @Tags Verification
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Failure 403 {object} Response
+// @Router /users/me/verification [get]
+func (h *VerificationHandler) GetMine(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	details, err := h.verificationService.GetDetails(userID)
+	if err != nil {
+		verificationErrorResponse(c, "FETCH_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, details)
+}
+
+// UploadDocument godoc
+// @Summary Envia um documento
+// @Description Envia um documento (PDF, JPEG ou PNG) para a verificação de antecedentes
+// @Tags Verification
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param kind formData string true "IDENTITY, CRIMINAL_RECORD, PROOF_OF_ADDRESS ou OTHER"
+// @Param file formData file true "Arquivo do documento"
+// @Success 201 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Router /users/me/verification/documents [post]
+func (h *VerificationHandler) UploadDocument(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Envie o arquivo no campo file")
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Não foi possível ler o arquivo")
+		return
+	}
+	defer file.Close()
+
+	document, err := h.verificationService.UploadDocument(userID, service.DocumentUpload{
+		Kind:     domain.DocumentKind(c.PostForm("kind")),
+		FileName: header.Filename,
+		Size:     header.Size,
+		Content:  file,
+	})
+	if err != nil {
+		verificationErrorResponse(c, "UPLOAD_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusCreated, document)
+}
+
+// Submit godoc
+// @Summary Pede a verificação
+// @Description Envia os documentos para análise da equipe (também após uma recusa)
+// @Tags Verification
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Failure 409 {object} Response
+// @Router /users/me/verification/submit [post]
+func (h *VerificationHandler) Submit(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	details, err := h.verificationService.Submit(userID)
+	if err != nil {
+		verificationErrorResponse(c, "SUBMIT_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, details)
+}
+
+// List godoc
+// @Summary Fila de verificações
+// @Description Lista os voluntários por status de verificação, por padrão os pendentes (requer volunteers:verify)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "NOT_SUBMITTED, PENDING (padrão), APPROVED ou REJECTED"
+// @Param page query int false "Página (padrão 1)"
+// @Param per_page query int false "Itens por página (padrão 20, máximo 100)"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Router /admin/verifications [get]
+func (h *VerificationHandler) List(c *gin.Context) {
+	status := domain.VerificationStatus(c.Query("status"))
+	page, _ := strconv.Atoi(c.Query("page"))
+	perPage, _ := strconv.Atoi(c.Query("per_page"))
+
+	result, err := h.verificationService.ListByStatus(status, page, perPage)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "FETCH_ERROR", err.Error())
+		return
+	}
+
+	totalPages := int((result.Total + int64(result.PerPage) - 1) / int64(result.PerPage))
+	SuccessResponseWithMeta(c, http.StatusOK, result.Volunteers, &MetaInfo{
+		Page:       result.Page,
+		PerPage:    result.PerPage,
+		Total:      int(result.Total),
+		TotalPages: totalPages,
+	})
+}
+
+// GetByVolunteer godoc
+// @Summary Verificação de um voluntário
+// @Description Retorna o status, os documentos e o histórico da verificação (requer volunteers:verify)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do voluntário"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Router /admin/verifications/{id} [get]
+func (h *VerificationHandler) GetByVolunteer(c *gin.Context) {
+	id, ok := parseAdminUserID(c)
+	if !ok {
+		return
+	}
+
+	details, err := h.verificationService.GetDetails(id)
+	if err != nil {
+		verificationErrorResponse(c, "FETCH_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, details)
+}
+
+// DownloadDocument godoc
+// @Summary Baixa um documento
+// @Description Retorna o arquivo enviado pelo voluntário (requer volunteers:verify)
+// @Tags Admin
+// @Produce application/octet-stream
+// @Security BearerAuth
+// @Param id path string true "ID do voluntário"
+// @Param documentId path string true "ID do documento"
+// @Success 200 {file} file
+// @Failure 403 {object} Response
+// @Failure 404 {object} Response
+// @Router /admin/verifications/{id}/documents/{documentId} [get]
+func (h *VerificationHandler) DownloadDocument(c *gin.Context) {
+	id, ok := parseAdminUserID(c)
+	if !ok {
+		return
+	}
+	documentID, err := uuid.Parse(c.Param("documentId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return
+	}
+
+	document, content, err := h.verificationService.OpenDocument(id, documentID)
+	if err != nil {
+		ErrorResponse(c, http.StatusNotFound, "DOCUMENT_NOT_FOUND", err.Error())
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, document.Size, document.ContentType, content, map[string]string{
+		"Content-Disposition":    fmt.Sprintf("attachment; filename=%q", document.FileName),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// Approve godoc
+// @Summary Aprova a verificação
+// @Description Marca o voluntário como verificado (requer volunteers:verify)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do voluntário"
+// @Success 200 {object} Response
+// @Failure 403 {object} Response
+// @Failure 409 {object} Response
+// @Router /admin/verifications/{id}/approve [post]
+func (h *VerificationHandler) Approve(c *gin.Context) {
+	actorID := c.MustGet("user_id").(uuid.UUID)
+	id, ok := parseAdminUserID(c)
+	if !ok {
+		return
+	}
+
+	details, err := h.verificationService.Approve(actorID, id)
+	if err != nil {
+		verificationErrorResponse(c, "APPROVE_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, details)
+}
+
+// Reject godoc
+// @Summary Recusa a verificação
+// @Description Recusa a verificação pendente ou revoga uma aprovação, com o motivo (requer volunteers:verify)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do voluntário"
+// @Param request body service.RejectVerificationRequest true "Motivo da recusa"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Failure 409 {object} Response
+// @Router /admin/verifications/{id}/reject [post]
+func (h *VerificationHandler) Reject(c *gin.Context) {
+	actorID := c.MustGet("user_id").(uuid.UUID)
+	id, ok := parseAdminUserID(c)
+	if !ok {
+		return
+	}
+
+	var req service.RejectVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos: "+err.Error())
+		return
+	}
+
+	details, err := h.verificationService.Reject(actorID, id, req)
+	if err != nil {
+		verificationErrorResponse(c, "REJECT_ERROR", err)
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, details)
+}
+
+// verificationErrorResponse responde 403 para quem não é voluntário ou para a análise da
+// própria conta, 409 para mudanças de status inválidas ou 400 para os demais erros.
+func verificationErrorResponse(c *gin.Context, code string, err error) {
+	switch {
+	case errors.Is(err, service.ErrVolunteerOnly), errors.Is(err, service.ErrSelfAdministration):
+		ErrorResponse(c, http.StatusForbidden, "FORBIDDEN", err.Error())
+	case errors.Is(err, domain.ErrInvalidStatusTransition):
+		ErrorResponse(c, http.StatusConflict, "INVALID_STATUS_TRANSITION", err.Error())
+	default:
+		ErrorResponse(c, http.StatusBadRequest, code, err.Error())
+	}
+}
@@ -0,0 +1,234 @@
+// Package handler contém os handlers HTTP da aplicação.
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"amigos-terceira-idade/internal/middleware"
+	"amigos-terceira-idade/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WebhookHandler gerencia os endpoints de webhook das instituições.
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+// NewWebhookHandler cria uma nova instância do handler de webhooks.
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// Create godoc
+// @Summary Cadastra um webhook
+// @Description Cadastra um endereço que receberá os eventos assinados (apenas instituições).
+// @Description O segredo das assinaturas HMAC-SHA256 é exibido somente nesta resposta.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.CreateWebhookRequest true "Endereço e eventos"
+// @Success 201 {object} Response
+// @Failure 400 {object} Response
+// @Failure 403 {object} Response
+// @Router /webhooks [post]
+func (h *WebhookHandler) Create(c *gin.Context) {
+	institutionID := middleware.ActingInstitutionID(c)
+
+	var req service.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos: "+err.Error())
+		return
+	}
+
+	endpoint, err := h.webhookService.CreateEndpoint(institutionID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrInstitutionOnly) {
+			ErrorResponse(c, http.StatusForbidden, "FORBIDDEN", err.Error())
+			return
+		}
+		ErrorResponse(c, http.StatusBadRequest, "WEBHOOK_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusCreated, endpoint)
+}
+
+// List godoc
+// @Summary Lista meus webhooks
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Response
+// @Router /webhooks [get]
+func (h *WebhookHandler) List(c *gin.Context) {
+	institutionID := middleware.ActingInstitutionID(c)
+
+	endpoints, err := h.webhookService.ListEndpoints(institutionID)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "FETCH_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, endpoints)
+}
+
+// GetByID godoc
+// @Summary Busca um webhook
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do webhook"
+// @Success 200 {object} Response
+// @Failure 404 {object} Response
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) GetByID(c *gin.Context) {
+	institutionID := middleware.ActingInstitutionID(c)
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	endpoint, err := h.webhookService.GetEndpoint(institutionID, id)
+	if err != nil {
+		ErrorResponse(c, http.StatusNotFound, "WEBHOOK_NOT_FOUND", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, endpoint)
+}
+
+// Update godoc
+// @Summary Atualiza um webhook
+// @Description Altera o endereço, os eventos assinados ou ativa/desativa o webhook
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do webhook"
+// @Param request body service.UpdateWebhookRequest true "Campos alterados"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /webhooks/{id} [put]
+func (h *WebhookHandler) Update(c *gin.Context) {
+	institutionID := middleware.ActingInstitutionID(c)
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	var req service.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "Dados inválidos: "+err.Error())
+		return
+	}
+
+	endpoint, err := h.webhookService.UpdateEndpoint(institutionID, id, req)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "WEBHOOK_ERROR", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, endpoint)
+}
+
+// Delete godoc
+// @Summary Remove um webhook
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do webhook"
+// @Success 200 {object} Response
+// @Failure 404 {object} Response
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	institutionID := middleware.ActingInstitutionID(c)
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.DeleteEndpoint(institutionID, id); err != nil {
+		ErrorResponse(c, http.StatusNotFound, "WEBHOOK_NOT_FOUND", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, gin.H{"message": "Webhook removido"})
+}
+
+// SendTest godoc
+// @Summary Envia um evento de teste
+// @Description Envia imediatamente um evento webhook.test assinado e retorna o resultado da entrega
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do webhook"
+// @Success 200 {object} Response
+// @Failure 404 {object} Response
+// @Router /webhooks/{id}/test [post]
+func (h *WebhookHandler) SendTest(c *gin.Context) {
+	institutionID := middleware.ActingInstitutionID(c)
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.SendTest(c.Request.Context(), institutionID, id)
+	if err != nil {
+		ErrorResponse(c, http.StatusNotFound, "WEBHOOK_NOT_FOUND", err.Error())
+		return
+	}
+
+	SuccessResponse(c, http.StatusOK, delivery)
+}
+
+// ListDeliveries godoc
+// @Summary Registro de entregas
+// @Description Retorna as entregas do webhook, das mais recentes para as mais antigas, com o resultado da última tentativa
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do webhook"
+// @Param page query int false "Página (padrão 1)"
+// @Param per_page query int false "Itens por página (padrão 20, máximo 100)"
+// @Success 200 {object} Response
+// @Failure 404 {object} Response
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	institutionID := middleware.ActingInstitutionID(c)
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.Query("page"))
+	perPage, _ := strconv.Atoi(c.Query("per_page"))
+
+	result, err := h.webhookService.ListDeliveries(institutionID, id, page, perPage)
+	if err != nil {
+		ErrorResponse(c, http.StatusNotFound, "WEBHOOK_NOT_FOUND", err.Error())
+		return
+	}
+
+	totalPages := int((result.Total + int64(result.PerPage) - 1) / int64(result.PerPage))
+	SuccessResponseWithMeta(c, http.StatusOK, result.Deliveries, &MetaInfo{
+		Page:       result.Page,
+		PerPage:    result.PerPage,
+		Total:      int(result.Total),
+		TotalPages: totalPages,
+	})
+}
+
+// parseWebhookID lê o ID do webhook da rota, respondendo 400 se for inválido.
+func parseWebhookID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "INVALID_ID", "ID inválido")
+		return uuid.Nil, false
+	}
+	return id, true
+}
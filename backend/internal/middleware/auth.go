@@ -3,6 +3,7 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 	"amigos-terceira-idade/internal/service"
@@ -43,14 +44,21 @@ func AuthMiddleware(authService *service.AuthService) gin.HandlerFunc {
 
 		tokenString := parts[1]
 
-		// Valida o token
-		claims, err := authService.ValidateToken(tokenString)
+		// Valida o token e a sessão, que pode ter sido encerrada em outro dispositivo
+		claims, err := authService.Authenticate(tokenString, ClientInfo(c))
 		if err != nil {
+			code, message := "INVALID_TOKEN", "Token inválido ou expirado"
+			switch {
+			case errors.Is(err, service.ErrSessionRevoked):
+				code, message = "SESSION_REVOKED", err.Error()
+			case errors.Is(err, service.ErrUserInactive):
+				code, message = "USER_INACTIVE", err.Error()
+			}
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"success": false,
 				"error": gin.H{
-					"code":    "INVALID_TOKEN",
-					"message": "Token inválido ou expirado",
+					"code":    code,
+					"message": message,
 				},
 			})
 			c.Abort()
@@ -61,6 +69,12 @@ func AuthMiddleware(authService *service.AuthService) gin.HandlerFunc {
 		c.Set("user_id", claims.UserID)
 		c.Set("user_email", claims.Email)
 		c.Set("user_type", claims.UserType)
+		c.Set("user_role", claims.Role)
+		c.Set("session_id", claims.SessionID)
+		c.Set("email_verified", claims.EmailVerified)
+		if claims.StaffInstitutionID != nil {
+			c.Set("staff_institution_id", *claims.StaffInstitutionID)
+		}
 
 		c.Next()
 	}
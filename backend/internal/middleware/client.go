@@ -0,0 +1,19 @@
+// Package middleware contém os middlewares HTTP da aplicação.
+package middleware
+
+import (
+	"amigos-terceira-idade/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClientInfo extrai da requisição os dados do dispositivo usados nas sessões: os headers
+// X-Platform e X-App-Version enviados pelo aplicativo, o User-Agent e o IP do cliente.
+func ClientInfo(c *gin.Context) service.ClientInfo {
+	return service.ClientInfo{
+		Platform:   c.GetHeader("X-Platform"),
+		AppVersion: c.GetHeader("X-App-Version"),
+		UserAgent:  c.Request.UserAgent(),
+		IPAddress:  c.ClientIP(),
+	}
+}
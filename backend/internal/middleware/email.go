@@ -0,0 +1,29 @@
+// Package middleware contém os middlewares HTTP da aplicação.
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireVerifiedEmail permite a requisição apenas para usuários que já confirmaram o
+// e-mail. Deve ser usado depois de AuthMiddleware, que coloca a confirmação do token no
+// contexto; quem acabou de confirmar precisa renovar os tokens.
+func RequireVerifiedEmail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		verified, _ := c.Get("email_verified")
+		if value, _ := verified.(bool); value {
+			c.Next()
+			return
+		}
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "EMAIL_NOT_VERIFIED",
+				"message": "Confirme o seu e-mail pelo link enviado antes de continuar",
+			},
+		})
+		c.Abort()
+	}
+}
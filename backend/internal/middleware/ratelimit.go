@@ -0,0 +1,130 @@
+// Package middleware contém os middlewares HTTP da aplicação.
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"amigos-terceira-idade/pkg/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RateLimitKey extrai da requisição a chave do limite; chaves vazias não são limitadas.
+// A função pode abortar a requisição, e então o limite não é aplicado.
+type RateLimitKey func(c *gin.Context) string
+
+// ByIP limita pelo IP do cliente.
+func ByIP(c *gin.Context) string {
+	return c.ClientIP()
+}
+
+// ByUser limita pelo usuário autenticado. Deve ser usado depois de AuthMiddleware.
+func ByUser(c *gin.Context) string {
+	if userID, ok := c.Get("user_id"); ok {
+		if id, ok := userID.(uuid.UUID); ok {
+			return id.String()
+		}
+	}
+	return ""
+}
+
+// maxKeyBody é o maior corpo que ByJSONField lê para extrair a chave.
+const maxKeyBody = 1 << 20
+
+// ByJSONField limita pelo valor de um campo do corpo JSON, como o e-mail nas rotas de
+// login, para que trocar de IP não permita mais tentativas contra a mesma conta. O
+// corpo é restaurado para o handler. Corpos acima de 1 MiB são recusados com 413, em
+// vez de truncados: o handler receberia um JSON diferente do usado para a chave.
+func ByJSONField(field string) RateLimitKey {
+	return func(c *gin.Context) string {
+		if c.Request.Body == nil {
+			return ""
+		}
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxKeyBody+1))
+		if err != nil {
+			return ""
+		}
+		if len(body) > maxKeyBody {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "PAYLOAD_TOO_LARGE",
+					"message": "O corpo da requisição é grande demais",
+				},
+			})
+			return ""
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		var fields map[string]any
+		if json.Unmarshal(body, &fields) != nil {
+			return ""
+		}
+		value, _ := fields[field].(string)
+		return strings.ToLower(strings.TrimSpace(value))
+	}
+}
+
+// RateLimit limita as requisições por chave com token bucket. name separa os buckets
+// de limites diferentes que usam a mesma chave. Responde com os headers RateLimit-Limit,
+// RateLimit-Remaining e RateLimit-Reset e, ao negar, 429 com Retry-After. Com mais de
+// um limite na rota, os headers mostram o mais próximo de se esgotar.
+func RateLimit(store ratelimit.Store, name string, limit ratelimit.Limit, key RateLimitKey) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !limit.Enabled() {
+			c.Next()
+			return
+		}
+		value := key(c)
+		if c.IsAborted() {
+			return
+		}
+		if value == "" {
+			c.Next()
+			return
+		}
+
+		result := store.Take(name+":"+value, limit, time.Now())
+		setRateLimitHeaders(c, result)
+		if result.Allowed {
+			c.Next()
+			return
+		}
+
+		c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "RATE_LIMITED",
+				"message": "Muitas requisições; tente novamente em instantes",
+			},
+		})
+		c.Abort()
+	}
+}
+
+// setRateLimitHeaders grava os headers do limite, a menos que outro limite da mesma
+// requisição tenha menos requisições restantes.
+func setRateLimitHeaders(c *gin.Context, result ratelimit.Result) {
+	if current := c.Writer.Header().Get("RateLimit-Remaining"); current != "" {
+		if remaining, err := strconv.Atoi(current); err == nil && remaining <= result.Remaining {
+			return
+		}
+	}
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+}
+
+// ceilSeconds arredonda a duração para cima em segundos inteiros.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,73 @@
+// Package middleware contém os middlewares HTTP da aplicação.
+package middleware
+
+import (
+	"net/http"
+
+	"amigos-terceira-idade/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequireRole permite a requisição apenas para usuários com um dos papéis informados.
+// Deve ser usado depois de AuthMiddleware, que coloca o papel do usuário no contexto.
+func RequireRole(roles ...domain.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := roleFromContext(c)
+		for _, allowed := range roles {
+			if role != "" && role == allowed {
+				c.Next()
+				return
+			}
+		}
+		forbidden(c)
+	}
+}
+
+// RequirePermission permite a requisição apenas se o papel do usuário conceder
+// todas as permissões informadas. Deve ser usado depois de AuthMiddleware.
+func RequirePermission(permissions ...domain.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := roleFromContext(c)
+		for _, permission := range permissions {
+			if !role.Can(permission) {
+				forbidden(c)
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// ActingInstitutionID retorna a instituição em nome da qual o usuário autenticado atua
+// nas rotas de instituição: a instituição vinculada, para a equipe com permissão
+// institution:manage, ou a própria conta.
+func ActingInstitutionID(c *gin.Context) uuid.UUID {
+	if roleFromContext(c).Can(domain.PermissionInstitutionManage) {
+		if institutionID, ok := c.Get("staff_institution_id"); ok {
+			if id, ok := institutionID.(uuid.UUID); ok {
+				return id
+			}
+		}
+	}
+	return c.MustGet("user_id").(uuid.UUID)
+}
+
+// roleFromContext retorna o papel do usuário autenticado (vazio para usuários comuns).
+func roleFromContext(c *gin.Context) domain.Role {
+	role, _ := c.Get("user_role")
+	value, _ := role.(domain.Role)
+	return value
+}
+
+// forbidden interrompe a requisição com 403.
+func forbidden(c *gin.Context) {
+	c.JSON(http.StatusForbidden, gin.H{
+		"success": false,
+		"error": gin.H{
+			"code":    "FORBIDDEN",
+			"message": "Você não tem permissão para acessar este recurso",
+		},
+	})
+	c.Abort()
+}
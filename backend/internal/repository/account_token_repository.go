@@ -0,0 +1,57 @@
+// Package repository contém as implementações de acesso a dados.
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AccountTokenRepository gerencia os tokens de redefinição de senha e de verificação de e-mail.
+type AccountTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewAccountTokenRepository cria uma nova instância do repositório de tokens de conta.
+func NewAccountTokenRepository(db *gorm.DB) *AccountTokenRepository {
+	return &AccountTokenRepository{db: db}
+}
+
+// Create insere um novo token.
+func (r *AccountTokenRepository) Create(token *domain.AccountToken) error {
+	return r.db.Create(token).Error
+}
+
+// FindByID busca um token pelo ID.
+func (r *AccountTokenRepository) FindByID(id uuid.UUID) (*domain.AccountToken, error) {
+	var token domain.AccountToken
+	err := r.db.First(&token, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrAccountTokenNotFound
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Consume marca o token como usado. Retorna false se ele já tinha sido usado, o que
+// impede que duas requisições simultâneas aproveitem o mesmo token.
+func (r *AccountTokenRepository) Consume(id uuid.UUID) (bool, error) {
+	result := r.db.Model(&domain.AccountToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// InvalidateByUser marca como usados os tokens pendentes do usuário com o propósito
+// informado, para que apenas o último enviado continue valendo.
+func (r *AccountTokenRepository) InvalidateByUser(userID uuid.UUID, purpose domain.AccountTokenPurpose) error {
+	return r.db.Model(&domain.AccountToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
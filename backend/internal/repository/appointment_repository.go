@@ -8,6 +8,7 @@ import (
 	"amigos-terceira-idade/internal/domain"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // AppointmentRepository gerencia as operações de banco de dados para agendamentos.
@@ -21,18 +22,47 @@ func NewAppointmentRepository(db *gorm.DB) *AppointmentRepository {
 }
 
 // Create insere um novo agendamento no banco de dados.
-func (r *AppointmentRepository) Create(appointment *domain.Appointment) error {
-	return r.db.Create(appointment).Error
+// Registra a criação como primeira entrada do histórico de status e grava o evento no outbox.
+// Antes, na mesma transação, lê com UPDLOCK/HOLDLOCK os agendamentos que se sobrepõem ao
+// horário e os passa a check, que pode recusar a inserção: os intervalos ficam travados até
+// o fim, então dois convites simultâneos não passam ambos pela checagem de conflitos.
+func (r *AppointmentRepository) Create(appointment *domain.Appointment, check func(overlapping []domain.Appointment) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		overlapping, err := lockOverlapping(tx, appointment)
+		if err != nil {
+			return err
+		}
+		if err := check(overlapping); err != nil {
+			return err
+		}
+		return insertAppointment(tx, appointment)
+	})
+}
+
+// insertAppointment insere o agendamento, a primeira entrada do histórico e o evento de criação.
+func insertAppointment(tx *gorm.DB, appointment *domain.Appointment) error {
+	if err := tx.Omit(clause.Associations).Create(appointment).Error; err != nil {
+		return err
+	}
+	err := tx.Create(&domain.AppointmentStatusHistory{
+		AppointmentID: appointment.ID,
+		ToStatus:      appointment.Status,
+		ChangedBy:     appointment.VolunteerID,
+	}).Error
+	if err != nil {
+		return err
+	}
+	return enqueueEvents(tx, domain.NewAppointmentEvent(domain.EventAppointmentCreated, appointment, appointment.VolunteerID))
 }
 
 // FindByID busca um agendamento pelo ID.
 func (r *AppointmentRepository) FindByID(id uuid.UUID) (*domain.Appointment, error) {
 	var appointment domain.Appointment
-	err := r.db.Preload("Volunteer").Preload("Target").
+	err := r.db.Preload("Volunteer").Preload("Target").Preload("Resident").
 		First(&appointment, "id = ?", id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("agendamento não encontrado")
+			return nil, domain.ErrAppointmentNotFound
 		}
 		return nil, err
 	}
@@ -42,7 +72,7 @@ func (r *AppointmentRepository) FindByID(id uuid.UUID) (*domain.Appointment, err
 // FindByVolunteerID busca todos os agendamentos de um voluntário.
 func (r *AppointmentRepository) FindByVolunteerID(volunteerID uuid.UUID) ([]domain.Appointment, error) {
 	var appointments []domain.Appointment
-	err := r.db.Preload("Target").
+	err := r.db.Preload("Target").Preload("Resident").
 		Where("volunteer_id = ?", volunteerID).
 		Order("date ASC").
 		Find(&appointments).Error
@@ -55,7 +85,7 @@ func (r *AppointmentRepository) FindByVolunteerID(volunteerID uuid.UUID) ([]doma
 // FindByTargetID busca todos os agendamentos de um idoso/instituição.
 func (r *AppointmentRepository) FindByTargetID(targetID uuid.UUID) ([]domain.Appointment, error) {
 	var appointments []domain.Appointment
-	err := r.db.Preload("Volunteer").
+	err := r.db.Preload("Volunteer").Preload("Resident").
 		Where("target_id = ?", targetID).
 		Order("date ASC").
 		Find(&appointments).Error
@@ -66,13 +96,79 @@ func (r *AppointmentRepository) FindByTargetID(targetID uuid.UUID) ([]domain.App
 }
 
 // FindUpcoming busca os próximos agendamentos de um usuário.
-// Retorna agendamentos confirmados com data futura.
+// Retorna agendamentos confirmados (inclusive com remarcação proposta) com data futura.
 func (r *AppointmentRepository) FindUpcoming(userID uuid.UUID) ([]domain.Appointment, error) {
 	var appointments []domain.Appointment
-	now := time.Now()
-	err := r.db.Preload("Volunteer").Preload("Target").
+	now := time.Now().UTC()
+	err := r.db.Preload("Volunteer").Preload("Target").Preload("Resident").
+		Where("(volunteer_id = ? OR target_id = ?) AND date > ?", userID, userID, now).
+		Where("status = ? OR (status = ? AND proposed_from_status = ?)",
+			domain.AppointmentStatusConfirmed,
+			domain.AppointmentStatusRescheduleProposed, domain.AppointmentStatusConfirmed).
+		Order("date ASC").
+		Find(&appointments).Error
+	if err != nil {
+		return nil, err
+	}
+	return appointments, nil
+}
+
+// FindOverlapping busca agendamentos pendentes ou confirmados de qualquer um dos
+// usuários (como voluntário ou destinatário) que se sobrepõem ao intervalo [start, end).
+// O filtro por data usa a duração máxima permitida para aproveitar os índices por data.
+func (r *AppointmentRepository) FindOverlapping(userIDs []uuid.UUID, start, end time.Time) ([]domain.Appointment, error) {
+	return findOverlapping(r.db, userIDs, start, end)
+}
+
+// lockOverlapping busca, como FindOverlapping, os agendamentos dos participantes que se
+// sobrepõem ao agendamento, travando-os (e o intervalo, contra novas inserções) até o
+// fim da transação.
+func lockOverlapping(tx *gorm.DB, appointment *domain.Appointment) ([]domain.Appointment, error) {
+	userIDs := []uuid.UUID{appointment.VolunteerID, appointment.TargetID}
+	return findOverlapping(tx.Table("appointments WITH (UPDLOCK, HOLDLOCK)"), userIDs, appointment.Date, appointment.EndsAt())
+}
+
+// findOverlapping executa a busca de FindOverlapping na conexão ou transação informada.
+func findOverlapping(db *gorm.DB, userIDs []uuid.UUID, start, end time.Time) ([]domain.Appointment, error) {
+	var appointments []domain.Appointment
+	earliest := start.Add(-domain.MaxAppointmentDuration * time.Minute)
+	err := db.
+		Where("(volunteer_id IN ? OR target_id IN ?)", userIDs, userIDs).
+		Where("status IN ?", domain.ActiveAppointmentStatuses).
+		Where("date > ? AND date < ?", earliest, end).
+		Where("DATEADD(minute, duration_minutes, date) > ?", start).
+		Order("date ASC").
+		Find(&appointments).Error
+	if err != nil {
+		return nil, err
+	}
+	return appointments, nil
+}
+
+// FindUpcomingCancelled busca os agendamentos futuros de um usuário que foram cancelados,
+// para que os calendários assinados removam os eventos.
+func (r *AppointmentRepository) FindUpcomingCancelled(userID uuid.UUID) ([]domain.Appointment, error) {
+	var appointments []domain.Appointment
+	err := r.db.Preload("Volunteer").Preload("Target").Preload("Resident").
 		Where("(volunteer_id = ? OR target_id = ?) AND date > ? AND status = ?",
-			userID, userID, now, domain.AppointmentStatusConfirmed).
+			userID, userID, time.Now().UTC(), domain.AppointmentStatusCancelled).
+		Order("date ASC").
+		Find(&appointments).Error
+	if err != nil {
+		return nil, err
+	}
+	return appointments, nil
+}
+
+// FindConfirmedBetween busca os agendamentos confirmados (inclusive com remarcação
+// proposta) de todos os usuários com data em (from, to], para o envio de lembretes.
+func (r *AppointmentRepository) FindConfirmedBetween(from, to time.Time) ([]domain.Appointment, error) {
+	var appointments []domain.Appointment
+	err := r.db.Preload("Volunteer").Preload("Target").Preload("Resident").
+		Where("date > ? AND date <= ?", from.UTC(), to.UTC()).
+		Where("status = ? OR (status = ? AND proposed_from_status = ?)",
+			domain.AppointmentStatusConfirmed,
+			domain.AppointmentStatusRescheduleProposed, domain.AppointmentStatusConfirmed).
 		Order("date ASC").
 		Find(&appointments).Error
 	if err != nil {
@@ -84,7 +180,7 @@ func (r *AppointmentRepository) FindUpcoming(userID uuid.UUID) ([]domain.Appoint
 // FindPendingInvitations busca convites pendentes recebidos por um usuário.
 func (r *AppointmentRepository) FindPendingInvitations(targetID uuid.UUID) ([]domain.Appointment, error) {
 	var appointments []domain.Appointment
-	err := r.db.Preload("Volunteer").
+	err := r.db.Preload("Volunteer").Preload("Target").Preload("Resident").
 		Where("target_id = ? AND status = ?", targetID, domain.AppointmentStatusPending).
 		Order("date ASC").
 		Find(&appointments).Error
@@ -97,7 +193,7 @@ func (r *AppointmentRepository) FindPendingInvitations(targetID uuid.UUID) ([]do
 // FindSentInvitations busca convites enviados por um voluntário.
 func (r *AppointmentRepository) FindSentInvitations(volunteerID uuid.UUID) ([]domain.Appointment, error) {
 	var appointments []domain.Appointment
-	err := r.db.Preload("Target").
+	err := r.db.Preload("Volunteer").Preload("Target").Preload("Resident").
 		Where("volunteer_id = ? AND status = ?", volunteerID, domain.AppointmentStatusPending).
 		Order("date ASC").
 		Find(&appointments).Error
@@ -107,16 +203,208 @@ func (r *AppointmentRepository) FindSentInvitations(volunteerID uuid.UUID) ([]do
 	return appointments, nil
 }
 
+// FindPage busca uma página de todos os agendamentos, opcionalmente filtrados por status,
+// dos mais recentes para os mais antigos, junto com o total.
+func (r *AppointmentRepository) FindPage(status domain.AppointmentStatus, limit, offset int) ([]domain.Appointment, int64, error) {
+	query := r.db.Model(&domain.Appointment{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var appointments []domain.Appointment
+	err := query.Preload("Volunteer").Preload("Target").Preload("Resident").
+		Order("date DESC").Limit(limit).Offset(offset).
+		Find(&appointments).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return appointments, total, nil
+}
+
 // Update atualiza os dados de um agendamento.
 func (r *AppointmentRepository) Update(appointment *domain.Appointment) error {
 	return r.db.Save(appointment).Error
 }
 
-// UpdateStatus atualiza apenas o status de um agendamento.
-func (r *AppointmentRepository) UpdateStatus(id uuid.UUID, status domain.AppointmentStatus) error {
-	return r.db.Model(&domain.Appointment{}).
-		Where("id = ?", id).
-		Update("status", status).Error
+// ChangeStatus aplica mudanças de status (e de data, em remarcações) e grava o
+// histórico e os eventos no outbox na mesma transação. Cada atualização só ocorre se o agendamento ainda
+// estiver no status de origem, evitando que operações concorrentes se sobrescrevam.
+// Recebe várias mudanças para alterar ocorrências de uma série de uma só vez.
+func (r *AppointmentRepository) ChangeStatus(changes ...*domain.AppointmentStatusHistory) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, change := range changes {
+			if err := applyStatusChange(tx, change); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CreateSeries insere uma série recorrente e todas as suas ocorrências na mesma transação.
+// Como em Create, os agendamentos que se sobrepõem a cada ocorrência são travados e passados
+// a check (overlapping[i] corresponde a appointments[i]), que pode recusar a série inteira.
+func (r *AppointmentRepository) CreateSeries(
+	series *domain.AppointmentSeries,
+	appointments []domain.Appointment,
+	check func(overlapping [][]domain.Appointment) error,
+) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		overlapping := make([][]domain.Appointment, len(appointments))
+		for i := range appointments {
+			found, err := lockOverlapping(tx, &appointments[i])
+			if err != nil {
+				return err
+			}
+			overlapping[i] = found
+		}
+		if err := check(overlapping); err != nil {
+			return err
+		}
+
+		if err := tx.Omit("Appointments").Create(series).Error; err != nil {
+			return err
+		}
+		for i := range appointments {
+			appointments[i].SeriesID = &series.ID
+			if err := insertAppointment(tx, &appointments[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindSeries busca uma série recorrente com suas ocorrências em ordem de data.
+func (r *AppointmentRepository) FindSeries(id uuid.UUID) (*domain.AppointmentSeries, error) {
+	var series domain.AppointmentSeries
+	err := r.db.Preload("Appointments", func(db *gorm.DB) *gorm.DB {
+		return db.Order("date ASC")
+	}).Preload("Appointments.Volunteer").Preload("Appointments.Target").Preload("Appointments.Resident").
+		First(&series, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("série não encontrada")
+		}
+		return nil, err
+	}
+	return &series, nil
+}
+
+// Complete marca o agendamento como concluído e soma a duração da conversa às
+// horas dedicadas do voluntário, na mesma transação.
+func (r *AppointmentRepository) Complete(appointment *domain.Appointment, change *domain.AppointmentStatusHistory) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := applyStatusChange(tx, change); err != nil {
+			return err
+		}
+
+		if err := ensureVolunteer(tx, appointment.VolunteerID); err != nil {
+			return err
+		}
+
+		hours := float64(appointment.DurationMinutes) / 60
+		return tx.Model(&domain.Volunteer{}).
+			Where("user_id = ?", appointment.VolunteerID).
+			Update("dedicated_hours", gorm.Expr("dedicated_hours + ?", hours)).Error
+	})
+}
+
+// FindHistory busca o histórico de status de um agendamento, do mais antigo ao mais recente.
+func (r *AppointmentRepository) FindHistory(appointmentID uuid.UUID) ([]domain.AppointmentStatusHistory, error) {
+	var history []domain.AppointmentStatusHistory
+	err := r.db.Where("appointment_id = ?", appointmentID).
+		Order("created_at ASC").
+		Find(&history).Error
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// applyStatusChange atualiza o status (e a data, se informada) condicionado ao status
+// de origem e grava o histórico. Em uma proposta de remarcação, a nova data fica em
+// proposed_date e a data original só muda quando a proposta é aceita.
+func applyStatusChange(tx *gorm.DB, change *domain.AppointmentStatusHistory) error {
+	// Toda mudança gera uma nova revisão do evento nos calendários exportados
+	updates := map[string]interface{}{
+		"status":            change.ToStatus,
+		"calendar_sequence": gorm.Expr("COALESCE(calendar_sequence, 0) + 1"),
+	}
+	switch {
+	case change.ToStatus == domain.AppointmentStatusRescheduleProposed:
+		updates["proposed_date"] = change.ToDate
+		updates["proposed_by"] = change.ChangedBy
+		updates["proposed_from_status"] = change.FromStatus
+	case change.ToDate != nil:
+		updates["date"] = *change.ToDate
+	}
+	if change.FromStatus == domain.AppointmentStatusRescheduleProposed {
+		updates["proposed_date"] = nil
+		updates["proposed_by"] = nil
+		updates["proposed_from_status"] = ""
+	}
+	result := tx.Model(&domain.Appointment{}).
+		Where("id = ? AND status = ?", change.AppointmentID, change.FromStatus).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("o status do agendamento foi alterado por outra operação")
+	}
+	if err := tx.Create(change).Error; err != nil {
+		return err
+	}
+
+	// O evento leva o estado já atualizado, lido na própria transação
+	var appointment domain.Appointment
+	if err := tx.First(&appointment, "id = ?", change.AppointmentID).Error; err != nil {
+		return err
+	}
+	return enqueueEvents(tx, domain.NewStatusChangeEvent(&appointment, change))
+}
+
+// Rate registra a avaliação de um agendamento concluído e recalcula a média
+// do voluntário, na mesma transação.
+func (r *AppointmentRepository) Rate(appointment *domain.Appointment, rating int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Só avalia agendamentos concluídos e ainda não avaliados
+		result := tx.Model(&domain.Appointment{}).
+			Where("id = ? AND status = ? AND (rating IS NULL OR rating = 0)",
+				appointment.ID, domain.AppointmentStatusCompleted).
+			Update("rating", rating)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("agendamento já avaliado ou não concluído")
+		}
+
+		if err := ensureVolunteer(tx, appointment.VolunteerID); err != nil {
+			return err
+		}
+
+		return tx.Model(&domain.Volunteer{}).
+			Where("user_id = ?", appointment.VolunteerID).
+			Updates(map[string]interface{}{
+				"rating_avg":   gorm.Expr("(rating_avg * rating_count + ?) / (rating_count + 1)", rating),
+				"rating_count": gorm.Expr("rating_count + 1"),
+			}).Error
+	})
+}
+
+// ensureVolunteer garante que exista o registro de dados do voluntário.
+// O cadastro cria apenas o usuário, então o registro é criado sob demanda.
+func ensureVolunteer(tx *gorm.DB, volunteerID uuid.UUID) error {
+	var volunteer domain.Volunteer
+	return tx.Where(domain.Volunteer{UserID: volunteerID}).
+		FirstOrCreate(&volunteer).Error
 }
 
 // Delete remove um agendamento.
@@ -0,0 +1,58 @@
+// Package repository contém as implementações de acesso a dados.
+package repository
+
+import (
+	"amigos-terceira-idade/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuditFilter restringe a consulta do log de auditoria; campos nulos não filtram.
+type AuditFilter struct {
+	ActorID      *uuid.UUID
+	TargetUserID *uuid.UUID
+	Action       domain.AuditAction
+}
+
+// AuditRepository gerencia o log de auditoria das ações administrativas.
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository cria uma nova instância do repositório de auditoria.
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Create grava um registro de auditoria.
+func (r *AuditRepository) Create(entry *domain.AuditLog) error {
+	return r.db.Create(entry).Error
+}
+
+// FindPage busca uma página do log, dos registros mais recentes para os mais antigos,
+// junto com o total.
+func (r *AuditRepository) FindPage(filter AuditFilter, limit, offset int) ([]domain.AuditLog, int64, error) {
+	query := r.db.Model(&domain.AuditLog{})
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.TargetUserID != nil {
+		query = query.Where("target_user_id = ?", *filter.TargetUserID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []domain.AuditLog
+	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
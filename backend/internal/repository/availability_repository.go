@@ -0,0 +1,113 @@
+// Package repository contém as implementações de acesso a dados.
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AvailabilityRepository gerencia as operações de banco de dados para a
+// disponibilidade dos voluntários.
+type AvailabilityRepository struct {
+	db *gorm.DB
+}
+
+// NewAvailabilityRepository cria uma nova instância do repositório de disponibilidade.
+func NewAvailabilityRepository(db *gorm.DB) *AvailabilityRepository {
+	return &AvailabilityRepository{db: db}
+}
+
+// FindWeekly busca as janelas semanais de um voluntário.
+func (r *AvailabilityRepository) FindWeekly(volunteerID uuid.UUID) ([]domain.VolunteerAvailability, error) {
+	var windows []domain.VolunteerAvailability
+	err := r.db.Where("volunteer_id = ?", volunteerID).
+		Order("weekday ASC, start_time ASC").
+		Find(&windows).Error
+	if err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+// ReplaceWeekly substitui todas as janelas semanais de um voluntário.
+func (r *AvailabilityRepository) ReplaceWeekly(volunteerID uuid.UUID, windows []domain.VolunteerAvailability) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("volunteer_id = ?", volunteerID).
+			Delete(&domain.VolunteerAvailability{}).Error; err != nil {
+			return err
+		}
+		if len(windows) == 0 {
+			return nil
+		}
+		return tx.Create(&windows).Error
+	})
+}
+
+// FindExceptions busca as exceções de um voluntário com data no intervalo [from, to].
+func (r *AvailabilityRepository) FindExceptions(volunteerID uuid.UUID, from, to time.Time) ([]domain.AvailabilityException, error) {
+	var exceptions []domain.AvailabilityException
+	err := r.db.Where("volunteer_id = ? AND date >= ? AND date <= ?",
+		volunteerID, from.Format(domain.DateLayout), to.Format(domain.DateLayout)).
+		Order("date ASC, start_time ASC").
+		Find(&exceptions).Error
+	if err != nil {
+		return nil, err
+	}
+	return exceptions, nil
+}
+
+// CreateException insere uma nova exceção de disponibilidade.
+func (r *AvailabilityRepository) CreateException(exception *domain.AvailabilityException) error {
+	return r.db.Create(exception).Error
+}
+
+// DeleteException remove uma exceção, desde que pertença ao voluntário.
+func (r *AvailabilityRepository) DeleteException(volunteerID, id uuid.UUID) error {
+	result := r.db.Where("id = ? AND volunteer_id = ?", id, volunteerID).
+		Delete(&domain.AvailabilityException{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("exceção não encontrada")
+	}
+	return nil
+}
+
+// FindVisitWindows busca as janelas de visita de uma instituição.
+func (r *AvailabilityRepository) FindVisitWindows(institutionID uuid.UUID) ([]domain.VisitWindow, error) {
+	var windows []domain.VisitWindow
+	err := r.db.Where("institution_id = ?", institutionID).
+		Order("weekday ASC, start_time ASC").
+		Find(&windows).Error
+	if err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+// ReplaceVisitWindows substitui todas as janelas de visita de uma instituição,
+// criando o registro da instituição se ainda não existir.
+func (r *AvailabilityRepository) ReplaceVisitWindows(institutionID uuid.UUID, windows []domain.VisitWindow) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// O cadastro cria apenas o usuário, então o registro da instituição é criado sob demanda
+		var institution domain.Institution
+		if err := tx.Where(domain.Institution{UserID: institutionID}).
+			FirstOrCreate(&institution).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("institution_id = ?", institutionID).
+			Delete(&domain.VisitWindow{}).Error; err != nil {
+			return err
+		}
+		if len(windows) == 0 {
+			return nil
+		}
+		return tx.Create(&windows).Error
+	})
+}
@@ -0,0 +1,60 @@
+// Package repository contém as implementações de acesso a dados.
+package repository
+
+import (
+	"errors"
+
+	"amigos-terceira-idade/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CalendarFeedRepository gerencia os tokens dos feeds de calendário.
+type CalendarFeedRepository struct {
+	db *gorm.DB
+}
+
+// NewCalendarFeedRepository cria uma nova instância do repositório de feeds de calendário.
+func NewCalendarFeedRepository(db *gorm.DB) *CalendarFeedRepository {
+	return &CalendarFeedRepository{db: db}
+}
+
+// FindOrCreate busca o feed do usuário, criando-o com o token informado se não existir.
+func (r *CalendarFeedRepository) FindOrCreate(userID uuid.UUID, token string) (*domain.CalendarFeed, error) {
+	var feed domain.CalendarFeed
+	err := r.db.Where(domain.CalendarFeed{UserID: userID}).
+		Attrs(domain.CalendarFeed{Token: token}).
+		FirstOrCreate(&feed).Error
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// FindByToken busca o feed pelo token secreto.
+func (r *CalendarFeedRepository) FindByToken(token string) (*domain.CalendarFeed, error) {
+	var feed domain.CalendarFeed
+	err := r.db.First(&feed, "token = ?", token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("calendário não encontrado")
+		}
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// UpdateToken troca o token do feed do usuário, invalidando o anterior.
+func (r *CalendarFeedRepository) UpdateToken(userID uuid.UUID, token string) error {
+	result := r.db.Model(&domain.CalendarFeed{}).
+		Where("user_id = ?", userID).
+		Update("token", token)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("calendário não encontrado")
+	}
+	return nil
+}
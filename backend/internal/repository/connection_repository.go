@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ConnectionRepository gerencia as operações de banco de dados para conexões/pareamentos.
@@ -20,19 +21,24 @@ func NewConnectionRepository(db *gorm.DB) *ConnectionRepository {
 	return &ConnectionRepository{db: db}
 }
 
-// Create insere uma nova conexão no banco de dados.
+// Create insere uma nova conexão no banco de dados e grava o pedido no outbox.
 func (r *ConnectionRepository) Create(connection *domain.Connection) error {
-	return r.db.Create(connection).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(connection).Error; err != nil {
+			return err
+		}
+		return enqueueEvents(tx, domain.NewConnectionEvent(domain.EventConnectionRequested, connection, connection.VolunteerID))
+	})
 }
 
 // FindByID busca uma conexão pelo ID.
 func (r *ConnectionRepository) FindByID(id uuid.UUID) (*domain.Connection, error) {
 	var connection domain.Connection
-	err := r.db.Preload("Volunteer").Preload("Target").
+	err := r.db.Preload("Volunteer").Preload("Target").Preload("Resident").
 		First(&connection, "id = ?", id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("conexão não encontrada")
+			return nil, domain.ErrConnectionNotFound
 		}
 		return nil, err
 	}
@@ -42,7 +48,7 @@ func (r *ConnectionRepository) FindByID(id uuid.UUID) (*domain.Connection, error
 // FindByVolunteerID busca todas as conexões de um voluntário.
 func (r *ConnectionRepository) FindByVolunteerID(volunteerID uuid.UUID) ([]domain.Connection, error) {
 	var connections []domain.Connection
-	err := r.db.Preload("Target").
+	err := r.db.Preload("Target").Preload("Resident").
 		Where("volunteer_id = ?", volunteerID).
 		Find(&connections).Error
 	if err != nil {
@@ -54,7 +60,7 @@ func (r *ConnectionRepository) FindByVolunteerID(volunteerID uuid.UUID) ([]domai
 // FindByTargetID busca todas as conexões de um idoso/instituição.
 func (r *ConnectionRepository) FindByTargetID(targetID uuid.UUID) ([]domain.Connection, error) {
 	var connections []domain.Connection
-	err := r.db.Preload("Volunteer").
+	err := r.db.Preload("Volunteer").Preload("Resident").
 		Where("target_id = ?", targetID).
 		Find(&connections).Error
 	if err != nil {
@@ -66,7 +72,7 @@ func (r *ConnectionRepository) FindByTargetID(targetID uuid.UUID) ([]domain.Conn
 // FindAcceptedByVolunteer busca conexões aceitas de um voluntário.
 func (r *ConnectionRepository) FindAcceptedByVolunteer(volunteerID uuid.UUID) ([]domain.Connection, error) {
 	var connections []domain.Connection
-	err := r.db.Preload("Target").
+	err := r.db.Preload("Target").Preload("Resident").
 		Where("volunteer_id = ? AND status = ?", volunteerID, domain.ConnectionStatusAccepted).
 		Find(&connections).Error
 	if err != nil {
@@ -75,11 +81,24 @@ func (r *ConnectionRepository) FindAcceptedByVolunteer(volunteerID uuid.UUID) ([
 	return connections, nil
 }
 
-// Exists verifica se já existe uma conexão entre o voluntário e o alvo.
+// Exists verifica se já existe uma conexão direta entre o voluntário e o alvo.
+// Conexões com residentes de uma instituição não contam como conexão com ela.
 func (r *ConnectionRepository) Exists(volunteerID, targetID uuid.UUID) (bool, error) {
 	var count int64
 	err := r.db.Model(&domain.Connection{}).
-		Where("volunteer_id = ? AND target_id = ?", volunteerID, targetID).
+		Where("volunteer_id = ? AND target_id = ? AND resident_id IS NULL", volunteerID, targetID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ExistsForResident verifica se já existe uma conexão entre o voluntário e o residente.
+func (r *ConnectionRepository) ExistsForResident(volunteerID, residentID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.Connection{}).
+		Where("volunteer_id = ? AND resident_id = ?", volunteerID, residentID).
 		Count(&count).Error
 	if err != nil {
 		return false, err
@@ -92,11 +111,34 @@ func (r *ConnectionRepository) Update(connection *domain.Connection) error {
 	return r.db.Save(connection).Error
 }
 
-// UpdateStatus atualiza apenas o status de uma conexão.
+// UpdateStatus responde a uma conexão pendente e grava a resposta no outbox, na mesma
+// transação. Retorna domain.ErrConnectionNotPending se a conexão já foi respondida.
 func (r *ConnectionRepository) UpdateStatus(id uuid.UUID, status domain.ConnectionStatus) error {
-	return r.db.Model(&domain.Connection{}).
-		Where("id = ?", id).
-		Update("status", status).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Só conexões pendentes podem ser respondidas; a condição evita duas respostas concorrentes
+		result := tx.Model(&domain.Connection{}).
+			Where("id = ? AND status = ?", id, domain.ConnectionStatusPending).
+			Update("status", status)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			var count int64
+			if err := tx.Model(&domain.Connection{}).Where("id = ?", id).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return domain.ErrConnectionNotFound
+			}
+			return domain.ErrConnectionNotPending
+		}
+
+		var connection domain.Connection
+		if err := tx.First(&connection, "id = ?", id).Error; err != nil {
+			return err
+		}
+		return enqueueEvents(tx, domain.NewConnectionStatusEvent(&connection))
+	})
 }
 
 // Delete remove uma conexão.
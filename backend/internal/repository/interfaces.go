@@ -3,6 +3,8 @@
 package repository
 
 import (
+	"time"
+
 	"amigos-terceira-idade/internal/domain"
 	"github.com/google/uuid"
 )
@@ -15,8 +17,11 @@ type UserRepositoryInterface interface {
 	FindByEmail(email string) (*domain.User, error)
 	ExistsByEmail(email string) (bool, error)
 	Update(user *domain.User) error
+	UpdateAudited(user *domain.User, entry *domain.AuditLog) error
 	Delete(id uuid.UUID) error
 	FindByType(userType domain.UserType) ([]domain.User, error)
+	Search(filter UserFilter, limit, offset int) ([]domain.User, int64, error)
+	Merge(targetID, sourceID uuid.UUID, entry *domain.AuditLog) error
 	AddInterests(userID uuid.UUID, interests []domain.Interest) error
 	RemoveInterest(userID uuid.UUID, interestID uuid.UUID) error
 	UpdateInterests(userID uuid.UUID, interests []domain.Interest) error
@@ -40,6 +45,7 @@ type ConnectionRepositoryInterface interface {
 	FindByTargetID(targetID uuid.UUID) ([]domain.Connection, error)
 	FindAcceptedByVolunteer(volunteerID uuid.UUID) ([]domain.Connection, error)
 	Exists(volunteerID, targetID uuid.UUID) (bool, error)
+	ExistsForResident(volunteerID, residentID uuid.UUID) (bool, error)
 	Update(connection *domain.Connection) error
 	UpdateStatus(id uuid.UUID, status domain.ConnectionStatus) error
 	Delete(id uuid.UUID) error
@@ -47,20 +53,168 @@ type ConnectionRepositoryInterface interface {
 
 // AppointmentRepositoryInterface define as operações do repositório de agendamentos.
 type AppointmentRepositoryInterface interface {
-	Create(appointment *domain.Appointment) error
+	Create(appointment *domain.Appointment, check func(overlapping []domain.Appointment) error) error
 	FindByID(id uuid.UUID) (*domain.Appointment, error)
 	FindByVolunteerID(volunteerID uuid.UUID) ([]domain.Appointment, error)
 	FindByTargetID(targetID uuid.UUID) ([]domain.Appointment, error)
 	FindUpcoming(userID uuid.UUID) ([]domain.Appointment, error)
+	FindUpcomingCancelled(userID uuid.UUID) ([]domain.Appointment, error)
+	FindConfirmedBetween(from, to time.Time) ([]domain.Appointment, error)
 	FindPendingInvitations(targetID uuid.UUID) ([]domain.Appointment, error)
+	FindOverlapping(userIDs []uuid.UUID, start, end time.Time) ([]domain.Appointment, error)
 	FindSentInvitations(volunteerID uuid.UUID) ([]domain.Appointment, error)
+	FindPage(status domain.AppointmentStatus, limit, offset int) ([]domain.Appointment, int64, error)
 	Update(appointment *domain.Appointment) error
-	UpdateStatus(id uuid.UUID, status domain.AppointmentStatus) error
+	ChangeStatus(changes ...*domain.AppointmentStatusHistory) error
+	Complete(appointment *domain.Appointment, change *domain.AppointmentStatusHistory) error
+	FindHistory(appointmentID uuid.UUID) ([]domain.AppointmentStatusHistory, error)
+	CreateSeries(series *domain.AppointmentSeries, appointments []domain.Appointment, check func(overlapping [][]domain.Appointment) error) error
+	FindSeries(id uuid.UUID) (*domain.AppointmentSeries, error)
+	Rate(appointment *domain.Appointment, rating int) error
 	Delete(id uuid.UUID) error
 }
 
+// AvailabilityRepositoryInterface define as operações do repositório de disponibilidade.
+type AvailabilityRepositoryInterface interface {
+	FindWeekly(volunteerID uuid.UUID) ([]domain.VolunteerAvailability, error)
+	ReplaceWeekly(volunteerID uuid.UUID, windows []domain.VolunteerAvailability) error
+	FindExceptions(volunteerID uuid.UUID, from, to time.Time) ([]domain.AvailabilityException, error)
+	CreateException(exception *domain.AvailabilityException) error
+	DeleteException(volunteerID, id uuid.UUID) error
+	FindVisitWindows(institutionID uuid.UUID) ([]domain.VisitWindow, error)
+	ReplaceVisitWindows(institutionID uuid.UUID, windows []domain.VisitWindow) error
+}
+
+// CalendarFeedRepositoryInterface define as operações do repositório de feeds de calendário.
+type CalendarFeedRepositoryInterface interface {
+	FindOrCreate(userID uuid.UUID, token string) (*domain.CalendarFeed, error)
+	FindByToken(token string) (*domain.CalendarFeed, error)
+	UpdateToken(userID uuid.UUID, token string) error
+}
+
+// ReminderRepositoryInterface define as operações do repositório de lembretes.
+type ReminderRepositoryInterface interface {
+	Claim(reminder *domain.AppointmentReminder) (bool, error)
+	MarkSent(id uuid.UUID, sentAt time.Time) error
+	Release(id uuid.UUID) error
+}
+
+// NotificationRepositoryInterface define as operações do repositório de notificações.
+type NotificationRepositoryInterface interface {
+	Create(notification *domain.Notification) error
+	FindByUser(userID uuid.UUID, unreadOnly bool, limit, offset int) ([]domain.Notification, int64, error)
+	CountUnread(userID uuid.UUID) (map[domain.NotificationType]int64, error)
+	MarkRead(userID, id uuid.UUID, readAt time.Time) error
+	MarkAllRead(userID uuid.UUID, readAt time.Time) (int64, error)
+	FindPreferences(userID uuid.UUID) ([]domain.NotificationPreference, error)
+	SavePreferences(preferences []domain.NotificationPreference) error
+}
+
+// OutboxRepositoryInterface define as operações do repositório do outbox.
+type OutboxRepositoryInterface interface {
+	FindDue(now time.Time, limit int) ([]domain.OutboxMessage, error)
+	Lease(message *domain.OutboxMessage, until time.Time) (bool, error)
+	SaveAttempt(message *domain.OutboxMessage) error
+}
+
+// WebhookRepositoryInterface define as operações do repositório de webhooks.
+type WebhookRepositoryInterface interface {
+	CreateEndpoint(endpoint *domain.WebhookEndpoint) error
+	FindEndpoint(institutionID, id uuid.UUID) (*domain.WebhookEndpoint, error)
+	FindEndpointsByInstitution(institutionID uuid.UUID) ([]domain.WebhookEndpoint, error)
+	FindActiveEndpoints(institutionIDs []uuid.UUID) ([]domain.WebhookEndpoint, error)
+	UpdateEndpoint(endpoint *domain.WebhookEndpoint) error
+	DeleteEndpoint(institutionID, id uuid.UUID) error
+	EnqueueDelivery(delivery *domain.WebhookDelivery) (bool, error)
+	FindDueDeliveries(now time.Time, limit int) ([]domain.WebhookDelivery, error)
+	LeaseDelivery(delivery *domain.WebhookDelivery, until time.Time) (bool, error)
+	SaveDeliveryAttempt(delivery *domain.WebhookDelivery) error
+	FindDeliveries(endpointID uuid.UUID, limit, offset int) ([]domain.WebhookDelivery, int64, error)
+}
+
+// AuditRepositoryInterface define as operações do log de auditoria.
+type AuditRepositoryInterface interface {
+	Create(entry *domain.AuditLog) error
+	FindPage(filter AuditFilter, limit, offset int) ([]domain.AuditLog, int64, error)
+}
+
+// VerificationRepositoryInterface define as operações da verificação de voluntários.
+type VerificationRepositoryInterface interface {
+	FindVolunteer(userID uuid.UUID) (*domain.Volunteer, error)
+	FindByStatus(status domain.VerificationStatus, limit, offset int) ([]domain.Volunteer, int64, error)
+	CreateDocument(document *domain.VerificationDocument) error
+	FindDocuments(volunteerID uuid.UUID) ([]domain.VerificationDocument, error)
+	FindDocument(volunteerID, id uuid.UUID) (*domain.VerificationDocument, error)
+	ChangeStatus(change *domain.VerificationStatusHistory) error
+	FindHistory(volunteerID uuid.UUID) ([]domain.VerificationStatusHistory, error)
+}
+
+// ResidentRepositoryInterface define as operações do repositório de residentes.
+type ResidentRepositoryInterface interface {
+	Create(resident *domain.Resident) error
+	FindByID(id uuid.UUID) (*domain.Resident, error)
+	FindByInstitution(institutionID uuid.UUID, includeInactive bool) ([]domain.Resident, error)
+	FindActive() ([]domain.Resident, error)
+	Update(resident *domain.Resident) error
+}
+
+// ProxyRepositoryInterface define as operações das delegações para familiares.
+type ProxyRepositoryInterface interface {
+	Create(grant *domain.ProxyGrant, entry *domain.ProxyActionLog) error
+	Update(grant *domain.ProxyGrant, entry *domain.ProxyActionLog) error
+	FindByID(id uuid.UUID) (*domain.ProxyGrant, error)
+	FindActive(elderlyID, proxyID uuid.UUID) (*domain.ProxyGrant, error)
+	FindByElderly(elderlyID uuid.UUID) ([]domain.ProxyGrant, error)
+	FindActiveByProxy(proxyID uuid.UUID) ([]domain.ProxyGrant, error)
+	CreateAction(entry *domain.ProxyActionLog) error
+	DeleteAction(id uuid.UUID) error
+	FindActions(elderlyID uuid.UUID, limit, offset int) ([]domain.ProxyActionLog, int64, error)
+}
+
+// SessionRepositoryInterface define as operações das sessões de login.
+type SessionRepositoryInterface interface {
+	Create(session *domain.Session) error
+	FindByID(id uuid.UUID) (*domain.Session, error)
+	FindActiveByUser(userID uuid.UUID) ([]domain.Session, error)
+	Touch(id uuid.UUID, ipAddress string, seenAt time.Time) error
+	Rotate(id, currentTokenID, nextTokenID uuid.UUID, expiresAt time.Time) (bool, error)
+	Revoke(id uuid.UUID, reason domain.SessionRevokeReason) error
+	RevokeAllByUser(userID uuid.UUID, reason domain.SessionRevokeReason) error
+}
+
+// AccountTokenRepositoryInterface define as operações dos tokens de conta enviados por e-mail.
+type AccountTokenRepositoryInterface interface {
+	Create(token *domain.AccountToken) error
+	FindByID(id uuid.UUID) (*domain.AccountToken, error)
+	Consume(id uuid.UUID) (bool, error)
+	InvalidateByUser(userID uuid.UUID, purpose domain.AccountTokenPurpose) error
+}
+
+// LoginCodeRepositoryInterface define as operações dos códigos de acesso e dos bloqueios de conta.
+type LoginCodeRepositoryInterface interface {
+	Create(code *domain.LoginCode) error
+	FindLatest(userID uuid.UUID) (*domain.LoginCode, error)
+	ClaimAttempt(id uuid.UUID, maxAttempts int) (bool, error)
+	Consume(id uuid.UUID) (bool, error)
+	FindLockout(userID uuid.UUID) (*domain.AccountLockout, error)
+	UpdateLockout(userID uuid.UUID, change func(lockout *domain.AccountLockout)) error
+}
+
 // Garante que as implementações satisfazem as interfaces
 var _ UserRepositoryInterface = (*UserRepository)(nil)
 var _ InterestRepositoryInterface = (*InterestRepository)(nil)
 var _ ConnectionRepositoryInterface = (*ConnectionRepository)(nil)
 var _ AppointmentRepositoryInterface = (*AppointmentRepository)(nil)
+var _ AvailabilityRepositoryInterface = (*AvailabilityRepository)(nil)
+var _ CalendarFeedRepositoryInterface = (*CalendarFeedRepository)(nil)
+var _ ReminderRepositoryInterface = (*ReminderRepository)(nil)
+var _ NotificationRepositoryInterface = (*NotificationRepository)(nil)
+var _ OutboxRepositoryInterface = (*OutboxRepository)(nil)
+var _ WebhookRepositoryInterface = (*WebhookRepository)(nil)
+var _ AuditRepositoryInterface = (*AuditRepository)(nil)
+var _ VerificationRepositoryInterface = (*VerificationRepository)(nil)
+var _ ResidentRepositoryInterface = (*ResidentRepository)(nil)
+var _ ProxyRepositoryInterface = (*ProxyRepository)(nil)
+var _ SessionRepositoryInterface = (*SessionRepository)(nil)
+var _ AccountTokenRepositoryInterface = (*AccountTokenRepository)(nil)
+var _ LoginCodeRepositoryInterface = (*LoginCodeRepository)(nil)
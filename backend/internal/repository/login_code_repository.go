@@ -0,0 +1,99 @@
+// Package repository contém as implementações de acesso a dados.
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LoginCodeRepository gerencia os códigos de acesso sem senha e os bloqueios de conta.
+type LoginCodeRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginCodeRepository cria uma nova instância do repositório de códigos de acesso.
+func NewLoginCodeRepository(db *gorm.DB) *LoginCodeRepository {
+	return &LoginCodeRepository{db: db}
+}
+
+// Create invalida os códigos pendentes do usuário e insere o novo, em uma transação.
+func (r *LoginCodeRepository) Create(code *domain.LoginCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&domain.LoginCode{}).
+			Where("user_id = ? AND used_at IS NULL", code.UserID).
+			Update("used_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(code).Error
+	})
+}
+
+// FindLatest busca o código mais recente do usuário, usado ou não.
+func (r *LoginCodeRepository) FindLatest(userID uuid.UUID) (*domain.LoginCode, error) {
+	var code domain.LoginCode
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").First(&code).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrLoginCodeNotFound
+		}
+		return nil, err
+	}
+	return &code, nil
+}
+
+// ClaimAttempt reserva uma tentativa do código, em um único UPDATE condicionado ao
+// limite. Retorna false se o código já foi usado ou esgotou as maxAttempts tentativas,
+// mesmo com várias tentativas simultâneas.
+func (r *LoginCodeRepository) ClaimAttempt(id uuid.UUID, maxAttempts int) (bool, error) {
+	result := r.db.Model(&domain.LoginCode{}).
+		Where("id = ? AND attempts < ? AND used_at IS NULL", id, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	return result.RowsAffected > 0, result.Error
+}
+
+// Consume marca o código como usado. Retorna false se ele já tinha sido usado.
+func (r *LoginCodeRepository) Consume(id uuid.UUID) (bool, error) {
+	result := r.db.Model(&domain.LoginCode{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindLockout busca as falhas e o bloqueio da conta; contas sem falhas recebem um
+// registro zerado, ainda não gravado.
+func (r *LoginCodeRepository) FindLockout(userID uuid.UUID) (*domain.AccountLockout, error) {
+	var lockout domain.AccountLockout
+	err := r.db.First(&lockout, "user_id = ?", userID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &domain.AccountLockout{UserID: userID}, nil
+		}
+		return nil, err
+	}
+	return &lockout, nil
+}
+
+// UpdateLockout aplica change ao registro de bloqueio da conta e o grava, em uma
+// transação que trava a linha (ou a ausência dela) até o fim. Assim falhas simultâneas
+// não se perdem nem decidem o bloqueio a partir de uma contagem já desatualizada.
+func (r *LoginCodeRepository) UpdateLockout(userID uuid.UUID, change func(lockout *domain.AccountLockout)) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var lockout domain.AccountLockout
+		result := tx.Raw("SELECT * FROM account_lockouts WITH (UPDLOCK, HOLDLOCK) WHERE user_id = ?", userID).
+			Scan(&lockout)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			lockout = domain.AccountLockout{UserID: userID}
+		}
+		change(&lockout)
+		return tx.Save(&lockout).Error
+	})
+}
@@ -0,0 +1,122 @@
+// Package repository contém as implementações de acesso a dados.
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationRepository gerencia as notificações e as preferências de notificação.
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository cria uma nova instância do repositório de notificações.
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Create insere uma nova notificação.
+func (r *NotificationRepository) Create(notification *domain.Notification) error {
+	return r.db.Create(notification).Error
+}
+
+// FindByUser busca as notificações do usuário, das mais recentes para as mais antigas,
+// e retorna também o total (para a paginação).
+func (r *NotificationRepository) FindByUser(userID uuid.UUID, unreadOnly bool, limit, offset int) ([]domain.Notification, int64, error) {
+	query := r.db.Model(&domain.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var notifications []domain.Notification
+	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&notifications).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
+
+// CountUnread conta as notificações não lidas do usuário por tipo.
+func (r *NotificationRepository) CountUnread(userID uuid.UUID) (map[domain.NotificationType]int64, error) {
+	var rows []struct {
+		Type  domain.NotificationType
+		Count int64
+	}
+	err := r.db.Model(&domain.Notification{}).
+		Select("type, COUNT(*) AS count").
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Group("type").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[domain.NotificationType]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Type] = row.Count
+	}
+	return counts, nil
+}
+
+// MarkRead marca uma notificação do usuário como lida.
+func (r *NotificationRepository) MarkRead(userID, id uuid.UUID, readAt time.Time) error {
+	var notification domain.Notification
+	err := r.db.First(&notification, "id = ? AND user_id = ?", id, userID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("notificação não encontrada")
+		}
+		return err
+	}
+	if notification.ReadAt != nil {
+		return nil
+	}
+	return r.db.Model(&notification).Update("read_at", readAt).Error
+}
+
+// MarkAllRead marca todas as notificações não lidas do usuário como lidas
+// e retorna quantas foram alteradas.
+func (r *NotificationRepository) MarkAllRead(userID uuid.UUID, readAt time.Time) (int64, error) {
+	result := r.db.Model(&domain.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", readAt)
+	return result.RowsAffected, result.Error
+}
+
+// FindPreferences busca as preferências registradas pelo usuário.
+func (r *NotificationRepository) FindPreferences(userID uuid.UUID) ([]domain.NotificationPreference, error) {
+	var preferences []domain.NotificationPreference
+	err := r.db.Where("user_id = ?", userID).Find(&preferences).Error
+	if err != nil {
+		return nil, err
+	}
+	return preferences, nil
+}
+
+// SavePreferences grava (ou atualiza) as preferências informadas em uma única transação.
+func (r *NotificationRepository) SavePreferences(preferences []domain.NotificationPreference) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range preferences {
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+				DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+			}).Create(&preferences[i]).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
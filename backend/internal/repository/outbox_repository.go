@@ -0,0 +1,80 @@
+// Package repository contém as implementações de acesso a dados.
+package repository
+
+import (
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// OutboxRepository gerencia as mensagens do outbox de eventos de domínio.
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository cria uma nova instância do repositório do outbox.
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// enqueueEvents grava os eventos no outbox dentro da transação da mudança de estado.
+func enqueueEvents(tx *gorm.DB, events ...domain.Event) error {
+	for _, event := range events {
+		message, err := domain.NewOutboxMessage(event)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(message).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindDue busca as mensagens pendentes cuja próxima tentativa já chegou, das mais antigas
+// para as mais recentes.
+func (r *OutboxRepository) FindDue(now time.Time, limit int) ([]domain.OutboxMessage, error) {
+	var messages []domain.OutboxMessage
+	err := r.db.Where("status = ? AND next_attempt_at <= ?", domain.OutboxStatusPending, now).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// Lease reserva a mensagem até o instante informado, adiando a próxima tentativa.
+// Retorna false se outra instância já a reservou; se o processo cair durante a entrega,
+// a mensagem volta a ser elegível quando a reserva expirar.
+func (r *OutboxRepository) Lease(message *domain.OutboxMessage, until time.Time) (bool, error) {
+	result := r.db.Model(&domain.OutboxMessage{}).
+		Where("id = ? AND status = ? AND next_attempt_at = ?", message.ID, domain.OutboxStatusPending, message.NextAttemptAt).
+		Update("next_attempt_at", until)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	message.NextAttemptAt = until
+	return true, nil
+}
+
+// SaveAttempt grava o resultado de uma tentativa de entrega: status, tentativas,
+// handlers já atendidos, último erro e próxima tentativa.
+func (r *OutboxRepository) SaveAttempt(message *domain.OutboxMessage) error {
+	return r.db.Model(&domain.OutboxMessage{}).
+		Where("id = ?", message.ID).
+		Updates(map[string]interface{}{
+			"status":          message.Status,
+			"attempts":        message.Attempts,
+			"next_attempt_at": message.NextAttemptAt,
+			"delivered_to":    message.DeliveredTo,
+			"last_error":      message.LastError,
+			"processed_at":    message.ProcessedAt,
+		}).Error
+}
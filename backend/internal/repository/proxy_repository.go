@@ -0,0 +1,119 @@
+// Package repository contém as implementações de acesso a dados.
+package repository
+
+import (
+	"errors"
+
+	"amigos-terceira-idade/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProxyRepository gerencia as delegações de idosos para familiares e o log das ações.
+type ProxyRepository struct {
+	db *gorm.DB
+}
+
+// NewProxyRepository cria uma nova instância do repositório de delegações.
+func NewProxyRepository(db *gorm.DB) *ProxyRepository {
+	return &ProxyRepository{db: db}
+}
+
+// Create insere uma nova delegação junto com o registro da concessão.
+func (r *ProxyRepository) Create(grant *domain.ProxyGrant, entry *domain.ProxyActionLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(grant).Error; err != nil {
+			return err
+		}
+		entry.GrantID = grant.ID
+		return tx.Create(entry).Error
+	})
+}
+
+// Update salva a delegação (permissões ou revogação) junto com o registro da mudança.
+func (r *ProxyRepository) Update(grant *domain.ProxyGrant, entry *domain.ProxyActionLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(grant).Error; err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+}
+
+// FindByID busca uma delegação com o idoso e o procurador.
+func (r *ProxyRepository) FindByID(id uuid.UUID) (*domain.ProxyGrant, error) {
+	var grant domain.ProxyGrant
+	err := r.db.Preload("Elderly").Preload("Proxy").First(&grant, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrProxyGrantNotFound
+		}
+		return nil, err
+	}
+	return &grant, nil
+}
+
+// FindActive busca a delegação ativa do idoso para o procurador.
+func (r *ProxyRepository) FindActive(elderlyID, proxyID uuid.UUID) (*domain.ProxyGrant, error) {
+	var grant domain.ProxyGrant
+	err := r.db.Where("elderly_id = ? AND proxy_id = ? AND revoked_at IS NULL", elderlyID, proxyID).
+		First(&grant).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrProxyGrantNotFound
+		}
+		return nil, err
+	}
+	return &grant, nil
+}
+
+// FindByElderly busca as delegações concedidas pelo idoso, inclusive as revogadas,
+// das mais recentes para as mais antigas.
+func (r *ProxyRepository) FindByElderly(elderlyID uuid.UUID) ([]domain.ProxyGrant, error) {
+	var grants []domain.ProxyGrant
+	err := r.db.Preload("Proxy").
+		Where("elderly_id = ?", elderlyID).
+		Order("created_at DESC").
+		Find(&grants).Error
+	return grants, err
+}
+
+// FindActiveByProxy busca as delegações ativas recebidas pelo procurador.
+func (r *ProxyRepository) FindActiveByProxy(proxyID uuid.UUID) ([]domain.ProxyGrant, error) {
+	var grants []domain.ProxyGrant
+	err := r.db.Preload("Elderly").
+		Where("proxy_id = ? AND revoked_at IS NULL", proxyID).
+		Order("created_at").
+		Find(&grants).Error
+	return grants, err
+}
+
+// CreateAction grava uma ação feita pelo procurador.
+func (r *ProxyRepository) CreateAction(entry *domain.ProxyActionLog) error {
+	return r.db.Create(entry).Error
+}
+
+// DeleteAction remove o registro de uma ação que não chegou a ser concluída.
+func (r *ProxyRepository) DeleteAction(id uuid.UUID) error {
+	return r.db.Delete(&domain.ProxyActionLog{}, "id = ?", id).Error
+}
+
+// FindActions busca uma página do log de ações sobre a conta do idoso, das mais
+// recentes para as mais antigas, junto com o total.
+func (r *ProxyRepository) FindActions(elderlyID uuid.UUID, limit, offset int) ([]domain.ProxyActionLog, int64, error) {
+	query := r.db.Model(&domain.ProxyActionLog{}).Where("elderly_id = ?", elderlyID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []domain.ProxyActionLog
+	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
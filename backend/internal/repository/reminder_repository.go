@@ -0,0 +1,57 @@
+// Package repository contém as implementações de acesso a dados.
+package repository
+
+import (
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReminderRepository gerencia o registro dos lembretes de agendamento.
+type ReminderRepository struct {
+	db *gorm.DB
+}
+
+// NewReminderRepository cria uma nova instância do repositório de lembretes.
+func NewReminderRepository(db *gorm.DB) *ReminderRepository {
+	return &ReminderRepository{db: db}
+}
+
+// Claim reserva o envio do lembrete, inserindo o registro se ainda não existir.
+// Retorna false se o lembrete já foi reservado (enviado ou em envio por outra instância);
+// o índice único protege contra duas instâncias reservando ao mesmo tempo.
+func (r *ReminderRepository) Claim(reminder *domain.AppointmentReminder) (bool, error) {
+	claimed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Model(&domain.AppointmentReminder{}).
+			Where("appointment_id = ? AND user_id = ? AND offset_minutes = ? AND appointment_date = ?",
+				reminder.AppointmentID, reminder.UserID, reminder.OffsetMinutes, reminder.AppointmentDate).
+			Count(&count).Error
+		if err != nil || count > 0 {
+			return err
+		}
+		if err := tx.Create(reminder).Error; err != nil {
+			return err
+		}
+		claimed = true
+		return nil
+	})
+	return claimed, err
+}
+
+// MarkSent registra o momento em que o lembrete foi entregue.
+func (r *ReminderRepository) MarkSent(id uuid.UUID, sentAt time.Time) error {
+	return r.db.Model(&domain.AppointmentReminder{}).
+		Where("id = ?", id).
+		Update("sent_at", sentAt).Error
+}
+
+// Release desfaz a reserva de um lembrete que não pôde ser entregue,
+// permitindo uma nova tentativa na próxima varredura.
+func (r *ReminderRepository) Release(id uuid.UUID) error {
+	return r.db.Delete(&domain.AppointmentReminder{}, "id = ?", id).Error
+}
@@ -0,0 +1,81 @@
+// Package repository contém as implementações de acesso a dados.
+package repository
+
+import (
+	"errors"
+
+	"amigos-terceira-idade/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ResidentRepository gerencia os perfis de residentes das instituições.
+type ResidentRepository struct {
+	db *gorm.DB
+}
+
+// NewResidentRepository cria uma nova instância do repositório de residentes.
+func NewResidentRepository(db *gorm.DB) *ResidentRepository {
+	return &ResidentRepository{db: db}
+}
+
+// Create insere um novo residente com seus interesses.
+func (r *ResidentRepository) Create(resident *domain.Resident) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Interests.*", "Institution").Create(resident).Error; err != nil {
+			return err
+		}
+		if len(resident.Interests) > 0 {
+			return tx.Model(resident).Association("Interests").Replace(resident.Interests)
+		}
+		return nil
+	})
+}
+
+// FindByID busca um residente com seus interesses e a instituição.
+func (r *ResidentRepository) FindByID(id uuid.UUID) (*domain.Resident, error) {
+	var resident domain.Resident
+	err := r.db.Preload("Interests").Preload("Institution").
+		First(&resident, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrResidentNotFound
+		}
+		return nil, err
+	}
+	return &resident, nil
+}
+
+// FindByInstitution busca os residentes de uma instituição em ordem alfabética,
+// incluindo os desativados se includeInactive for verdadeiro.
+func (r *ResidentRepository) FindByInstitution(institutionID uuid.UUID, includeInactive bool) ([]domain.Resident, error) {
+	query := r.db.Preload("Interests").Where("institution_id = ?", institutionID)
+	if !includeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var residents []domain.Resident
+	err := query.Order("name").Find(&residents).Error
+	return residents, err
+}
+
+// FindActive busca os residentes ativos de instituições ativas, usados no pareamento.
+func (r *ResidentRepository) FindActive() ([]domain.Resident, error) {
+	var residents []domain.Resident
+	err := r.db.Preload("Interests").Preload("Institution").
+		Joins("JOIN users ON users.id = residents.institution_id AND users.is_active = ?", true).
+		Where("residents.is_active = ?", true).
+		Find(&residents).Error
+	return residents, err
+}
+
+// Update salva os dados do residente e substitui seus interesses.
+func (r *ResidentRepository) Update(resident *domain.Resident) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Interests.*", "Institution").Save(resident).Error; err != nil {
+			return err
+		}
+		return tx.Model(resident).Association("Interests").Replace(resident.Interests)
+	})
+}
@@ -0,0 +1,86 @@
+// Package repository contém as implementações de acesso a dados.
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SessionRepository gerencia as sessões de login e seus refresh tokens.
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository cria uma nova instância do repositório de sessões.
+func NewSessionRepository(db *gorm.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// Create insere uma nova sessão.
+func (r *SessionRepository) Create(session *domain.Session) error {
+	return r.db.Omit(clause.Associations).Create(session).Error
+}
+
+// FindByID busca uma sessão junto com o usuário dono.
+func (r *SessionRepository) FindByID(id uuid.UUID) (*domain.Session, error) {
+	var session domain.Session
+	err := r.db.Preload("User").First(&session, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindActiveByUser busca as sessões ativas de um usuário, das usadas mais recentemente
+// para as mais antigas.
+func (r *SessionRepository) FindActiveByUser(userID uuid.UUID) ([]domain.Session, error) {
+	var sessions []domain.Session
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// Touch registra o último uso da sessão e o IP de onde veio.
+func (r *SessionRepository) Touch(id uuid.UUID, ipAddress string, seenAt time.Time) error {
+	return r.db.Model(&domain.Session{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"ip_address": ipAddress, "last_seen_at": seenAt}).Error
+}
+
+// Rotate troca o refresh token da sessão de currentTokenID para nextTokenID, prorroga a
+// expiração e registra o uso. Retorna false se a sessão já foi encerrada ou se o token
+// atual já tinha sido trocado, o que evita que duas renovações simultâneas usem o mesmo token.
+func (r *SessionRepository) Rotate(id, currentTokenID, nextTokenID uuid.UUID, expiresAt time.Time) (bool, error) {
+	result := r.db.Model(&domain.Session{}).
+		Where("id = ? AND refresh_token_id = ? AND revoked_at IS NULL", id, currentTokenID).
+		Updates(map[string]interface{}{
+			"refresh_token_id": nextTokenID,
+			"expires_at":       expiresAt,
+			"last_seen_at":     time.Now(),
+			"updated_at":       time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Revoke encerra uma sessão, se ainda estiver ativa.
+func (r *SessionRepository) Revoke(id uuid.UUID, reason domain.SessionRevokeReason) error {
+	return r.db.Model(&domain.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "revoked_reason": reason}).Error
+}
+
+// RevokeAllByUser encerra todas as sessões ativas de um usuário.
+func (r *SessionRepository) RevokeAllByUser(userID uuid.UUID, reason domain.SessionRevokeReason) error {
+	return r.db.Model(&domain.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "revoked_reason": reason}).Error
+}
@@ -5,6 +5,7 @@ package repository
 import (
 	"amigos-terceira-idade/internal/domain"
 	"errors"
+	"strings"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -93,6 +94,16 @@ func (r *UserRepository) Update(user *domain.User) error {
 	return r.db.Save(user).Error
 }
 
+// UpdateAudited atualiza o usuário e grava o registro de auditoria na mesma transação.
+func (r *UserRepository) UpdateAudited(user *domain.User, entry *domain.AuditLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(user).Error; err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+}
+
 // Delete remove um usuário do banco de dados (soft delete recomendado).
 func (r *UserRepository) Delete(id uuid.UUID) error {
 	return r.db.Delete(&domain.User{}, "id = ?", id).Error
@@ -122,6 +133,211 @@ func (r *UserRepository) FindByType(userType domain.UserType) ([]domain.User, er
 	return users, nil
 }
 
+// UserFilter restringe a busca de usuários; campos vazios ou nulos não filtram.
+type UserFilter struct {
+	Query    string // Trecho do nome ou do email
+	UserType domain.UserType
+	IsActive *bool
+}
+
+// Search busca uma página dos usuários que atendem ao filtro, ativos ou não, dos mais
+// recentes para os mais antigos, junto com o total.
+func (r *UserRepository) Search(filter UserFilter, limit, offset int) ([]domain.User, int64, error) {
+	query := r.db.Model(&domain.User{})
+	if filter.Query != "" {
+		like := "%" + escapeLike(filter.Query) + "%"
+		query = query.Where("name LIKE ? ESCAPE '\\' OR email LIKE ? ESCAPE '\\'", like, like)
+	}
+	if filter.UserType != "" {
+		query = query.Where("user_type = ?", filter.UserType)
+	}
+	if filter.IsActive != nil {
+		query = query.Where("is_active = ?", *filter.IsActive)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []domain.User
+	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// Merge transfere para targetID os vínculos da conta duplicada sourceID (conexões,
+// agendamentos, séries, histórico, notificações, lembretes, residentes e interesses) e desativa a
+// duplicada, tudo em uma transação. Conexões com alguém a quem a conta mantida já
+// está conectada são descartadas. As horas dedicadas e a avaliação dos voluntários são
+// recalculadas a partir dos agendamentos, e o perfil de idoso da conta mantida recebe
+// os dados que não tiver preenchido. Configurações da conta (disponibilidade, janelas de
+// visita, calendário, preferências e webhooks) permanecem na conta desativada.
+// Duplicadas com verificação aprovada ou delegações ativas são recusadas com
+// ErrMergeVerifiedDuplicate ou ErrMergeProxyGrants. O registro de auditoria entry é
+// gravado na mesma transação.
+func (r *UserRepository) Merge(targetID, sourceID uuid.UUID, entry *domain.AuditLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var approved, grants int64
+		err := tx.Model(&domain.Volunteer{}).
+			Where("user_id = ? AND verification_status = ?", sourceID, domain.VerificationApproved).
+			Count(&approved).Error
+		if err != nil {
+			return err
+		}
+		if approved > 0 {
+			return domain.ErrMergeVerifiedDuplicate
+		}
+		err = tx.Model(&domain.ProxyGrant{}).
+			Where("(elderly_id = ? OR proxy_id = ?) AND revoked_at IS NULL", sourceID, sourceID).
+			Count(&grants).Error
+		if err != nil {
+			return err
+		}
+		if grants > 0 {
+			return domain.ErrMergeProxyGrants
+		}
+
+		// Descarta conexões que ficariam duplicadas
+		for _, side := range [][2]string{{"volunteer_id", "target_id"}, {"target_id", "volunteer_id"}} {
+			existing := tx.Model(&domain.Connection{}).Select(side[1]).Where(side[0]+" = ?", targetID)
+			err := tx.Where(side[0]+" = ? AND "+side[1]+" IN (?)", sourceID, existing).
+				Delete(&domain.Connection{}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		moves := []struct {
+			model  interface{}
+			column string
+		}{
+			{&domain.Connection{}, "volunteer_id"},
+			{&domain.Connection{}, "target_id"},
+			{&domain.Appointment{}, "volunteer_id"},
+			{&domain.Appointment{}, "target_id"},
+			{&domain.Appointment{}, "proposed_by"},
+			{&domain.AppointmentSeries{}, "volunteer_id"},
+			{&domain.AppointmentSeries{}, "target_id"},
+			{&domain.AppointmentStatusHistory{}, "changed_by"},
+			{&domain.Notification{}, "user_id"},
+			{&domain.Notification{}, "actor_id"},
+			{&domain.AppointmentReminder{}, "user_id"},
+			{&domain.Resident{}, "institution_id"},
+		}
+		for _, move := range moves {
+			err := tx.Model(move.model).Where(move.column+" = ?", sourceID).
+				Update(move.column, targetID).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		// Interesses: a conta mantida fica com a união dos dois conjuntos
+		var target, source domain.User
+		if err := tx.Preload("Interests").First(&target, "id = ?", targetID).Error; err != nil {
+			return err
+		}
+		if err := tx.Preload("Interests").First(&source, "id = ?", sourceID).Error; err != nil {
+			return err
+		}
+		owned := make(map[uuid.UUID]bool, len(target.Interests))
+		for _, interest := range target.Interests {
+			owned[interest.ID] = true
+		}
+		var missing []domain.Interest
+		for _, interest := range source.Interests {
+			if !owned[interest.ID] {
+				missing = append(missing, interest)
+			}
+		}
+		if len(missing) > 0 {
+			if err := tx.Model(&target).Association("Interests").Append(missing); err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&source).Association("Interests").Clear(); err != nil {
+			return err
+		}
+
+		switch target.UserType {
+		case domain.UserTypeVolunteer:
+			// Os agendamentos concluídos e avaliados mudaram de dono
+			for _, id := range []uuid.UUID{targetID, sourceID} {
+				if err := recountVolunteer(tx, id); err != nil {
+					return err
+				}
+			}
+		case domain.UserTypeElderly:
+			if err := mergeElderlyProfile(tx, targetID, sourceID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&domain.User{}).Where("id = ?", sourceID).Update("is_active", false).Error; err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+}
+
+// recountVolunteer recalcula as horas dedicadas e a média de avaliações do voluntário
+// a partir dos seus agendamentos concluídos.
+func recountVolunteer(tx *gorm.DB, volunteerID uuid.UUID) error {
+	var stats struct {
+		Hours       float64
+		RatingAvg   float64
+		RatingCount int
+	}
+	err := tx.Model(&domain.Appointment{}).
+		Select("COALESCE(SUM(duration_minutes), 0) / 60.0 AS hours, "+
+			"COALESCE(AVG(CAST(NULLIF(rating, 0) AS FLOAT)), 0) AS rating_avg, "+
+			"COUNT(NULLIF(rating, 0)) AS rating_count").
+		Where("volunteer_id = ? AND status = ?", volunteerID, domain.AppointmentStatusCompleted).
+		Scan(&stats).Error
+	if err != nil {
+		return err
+	}
+
+	if err := ensureVolunteer(tx, volunteerID); err != nil {
+		return err
+	}
+	return tx.Model(&domain.Volunteer{}).
+		Where("user_id = ?", volunteerID).
+		Updates(map[string]interface{}{
+			"dedicated_hours": stats.Hours,
+			"rating_avg":      stats.RatingAvg,
+			"rating_count":    stats.RatingCount,
+		}).Error
+}
+
+// mergeElderlyProfile completa o perfil de idoso da conta mantida com os dados da
+// duplicada: o contato de emergência, se não houver, e a necessidade de assistência.
+func mergeElderlyProfile(tx *gorm.DB, targetID, sourceID uuid.UUID) error {
+	var source domain.Elderly
+	result := tx.Where("user_id = ?", sourceID).Limit(1).Find(&source)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+
+	var target domain.Elderly
+	if err := tx.Where(domain.Elderly{UserID: targetID}).FirstOrCreate(&target).Error; err != nil {
+		return err
+	}
+	if target.EmergencyContact == "" {
+		target.EmergencyContact = source.EmergencyContact
+	}
+	target.NeedsAssistance = target.NeedsAssistance || source.NeedsAssistance
+	return tx.Model(&target).Select("emergency_contact", "needs_assistance").Updates(&target).Error
+}
+
+// escapeLike escapa os curingas do LIKE para buscar o texto literalmente.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`, "[", `\[`).Replace(value)
+}
+
 // AddInterests adiciona interesses a um usuário.
 func (r *UserRepository) AddInterests(userID uuid.UUID, interests []domain.Interest) error {
 	user, err := r.FindByID(userID)
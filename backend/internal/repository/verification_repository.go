@@ -0,0 +1,113 @@
+// Package repository contém as implementações de acesso a dados.
+package repository
+
+import (
+	"errors"
+
+	"amigos-terceira-idade/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// VerificationRepository gerencia a verificação dos voluntários: documentos,
+// status e histórico.
+type VerificationRepository struct {
+	db *gorm.DB
+}
+
+// NewVerificationRepository cria uma nova instância do repositório de verificações.
+func NewVerificationRepository(db *gorm.DB) *VerificationRepository {
+	return &VerificationRepository{db: db}
+}
+
+// FindVolunteer busca os dados do voluntário. Como o cadastro cria apenas o usuário,
+// um voluntário sem registro é devolvido com os valores padrão (não verificado).
+func (r *VerificationRepository) FindVolunteer(userID uuid.UUID) (*domain.Volunteer, error) {
+	var volunteer domain.Volunteer
+	err := r.db.Where(domain.Volunteer{UserID: userID}).FirstOrInit(&volunteer).Error
+	if err != nil {
+		return nil, err
+	}
+	return &volunteer, nil
+}
+
+// FindByStatus busca uma página dos voluntários com o status de verificação informado,
+// dos registros mais antigos para os mais recentes, junto com o total.
+func (r *VerificationRepository) FindByStatus(status domain.VerificationStatus, limit, offset int) ([]domain.Volunteer, int64, error) {
+	query := r.db.Model(&domain.Volunteer{}).Where("verification_status = ?", status)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var volunteers []domain.Volunteer
+	err := query.Preload("User").Order("user_id").Limit(limit).Offset(offset).Find(&volunteers).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return volunteers, total, nil
+}
+
+// CreateDocument registra um documento enviado.
+func (r *VerificationRepository) CreateDocument(document *domain.VerificationDocument) error {
+	return r.db.Create(document).Error
+}
+
+// FindDocuments busca os documentos do voluntário, dos mais antigos para os mais recentes.
+func (r *VerificationRepository) FindDocuments(volunteerID uuid.UUID) ([]domain.VerificationDocument, error) {
+	var documents []domain.VerificationDocument
+	err := r.db.Where("volunteer_id = ?", volunteerID).Order("created_at").Find(&documents).Error
+	return documents, err
+}
+
+// FindDocument busca um documento do voluntário.
+func (r *VerificationRepository) FindDocument(volunteerID, id uuid.UUID) (*domain.VerificationDocument, error) {
+	var document domain.VerificationDocument
+	err := r.db.First(&document, "id = ? AND volunteer_id = ?", id, volunteerID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("documento não encontrado")
+		}
+		return nil, err
+	}
+	return &document, nil
+}
+
+// ChangeStatus aplica a mudança de status da verificação e grava o histórico na mesma
+// transação. A atualização só ocorre se o voluntário ainda estiver no status de origem,
+// evitando que duas análises simultâneas se sobrescrevam. IsVerified acompanha o status.
+func (r *VerificationRepository) ChangeStatus(change *domain.VerificationStatusHistory) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureVolunteer(tx, change.VolunteerID); err != nil {
+			return err
+		}
+
+		// Registros anteriores à verificação podem estar sem status
+		from := []domain.VerificationStatus{change.FromStatus}
+		if change.FromStatus == domain.VerificationNotSubmitted {
+			from = append(from, "")
+		}
+		result := tx.Model(&domain.Volunteer{}).
+			Where("user_id = ? AND verification_status IN ?", change.VolunteerID, from).
+			Updates(map[string]interface{}{
+				"verification_status": change.ToStatus,
+				"is_verified":         change.ToStatus == domain.VerificationApproved,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("a verificação foi alterada por outra operação")
+		}
+		return tx.Create(change).Error
+	})
+}
+
+// FindHistory busca o histórico da verificação do voluntário em ordem cronológica.
+func (r *VerificationRepository) FindHistory(volunteerID uuid.UUID) ([]domain.VerificationStatusHistory, error) {
+	var history []domain.VerificationStatusHistory
+	err := r.db.Where("volunteer_id = ?", volunteerID).Order("created_at").Find(&history).Error
+	return history, err
+}
@@ -0,0 +1,165 @@
+// Package repository contém as implementações de acesso a dados.
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WebhookRepository gerencia os endpoints de webhook e o registro das entregas.
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository cria uma nova instância do repositório de webhooks.
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// CreateEndpoint insere um novo endpoint.
+func (r *WebhookRepository) CreateEndpoint(endpoint *domain.WebhookEndpoint) error {
+	return r.db.Create(endpoint).Error
+}
+
+// FindEndpoint busca um endpoint da instituição pelo ID.
+func (r *WebhookRepository) FindEndpoint(institutionID, id uuid.UUID) (*domain.WebhookEndpoint, error) {
+	var endpoint domain.WebhookEndpoint
+	err := r.db.First(&endpoint, "id = ? AND institution_id = ?", id, institutionID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("webhook não encontrado")
+		}
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+// FindEndpointsByInstitution busca os endpoints cadastrados pela instituição.
+func (r *WebhookRepository) FindEndpointsByInstitution(institutionID uuid.UUID) ([]domain.WebhookEndpoint, error) {
+	var endpoints []domain.WebhookEndpoint
+	err := r.db.Where("institution_id = ?", institutionID).
+		Order("created_at ASC").
+		Find(&endpoints).Error
+	if err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// FindActiveEndpoints busca os endpoints ativos das instituições informadas.
+func (r *WebhookRepository) FindActiveEndpoints(institutionIDs []uuid.UUID) ([]domain.WebhookEndpoint, error) {
+	var endpoints []domain.WebhookEndpoint
+	err := r.db.Where("institution_id IN ? AND is_active = ?", institutionIDs, true).
+		Find(&endpoints).Error
+	if err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// UpdateEndpoint grava as alterações de um endpoint.
+func (r *WebhookRepository) UpdateEndpoint(endpoint *domain.WebhookEndpoint) error {
+	return r.db.Save(endpoint).Error
+}
+
+// DeleteEndpoint remove o endpoint da instituição e o seu registro de entregas.
+func (r *WebhookRepository) DeleteEndpoint(institutionID, id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&domain.WebhookEndpoint{}, "id = ? AND institution_id = ?", id, institutionID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("webhook não encontrado")
+		}
+		return tx.Delete(&domain.WebhookDelivery{}, "endpoint_id = ?", id).Error
+	})
+}
+
+// EnqueueDelivery insere a entrega se o evento ainda não foi enfileirado para o endpoint.
+// Retorna false se já existia, o que torna seguro reprocessar o mesmo evento.
+func (r *WebhookRepository) EnqueueDelivery(delivery *domain.WebhookDelivery) (bool, error) {
+	enqueued := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Model(&domain.WebhookDelivery{}).
+			Where("endpoint_id = ? AND event_id = ?", delivery.EndpointID, delivery.EventID).
+			Count(&count).Error
+		if err != nil || count > 0 {
+			return err
+		}
+		if err := tx.Omit("Endpoint").Create(delivery).Error; err != nil {
+			return err
+		}
+		enqueued = true
+		return nil
+	})
+	return enqueued, err
+}
+
+// FindDueDeliveries busca as entregas pendentes cuja próxima tentativa já chegou, com o endpoint.
+func (r *WebhookRepository) FindDueDeliveries(now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	err := r.db.Preload("Endpoint").
+		Where("status = ? AND next_attempt_at <= ?", domain.WebhookDeliveryPending, now).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// LeaseDelivery reserva a entrega até o instante informado, como em OutboxRepository.Lease.
+func (r *WebhookRepository) LeaseDelivery(delivery *domain.WebhookDelivery, until time.Time) (bool, error) {
+	result := r.db.Model(&domain.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at = ?", delivery.ID, domain.WebhookDeliveryPending, delivery.NextAttemptAt).
+		Update("next_attempt_at", until)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	delivery.NextAttemptAt = until
+	return true, nil
+}
+
+// SaveDeliveryAttempt grava o resultado de uma tentativa de entrega.
+func (r *WebhookRepository) SaveDeliveryAttempt(delivery *domain.WebhookDelivery) error {
+	return r.db.Model(&domain.WebhookDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"response_status": delivery.ResponseStatus,
+			"error":           delivery.Error,
+			"duration_ms":     delivery.DurationMs,
+			"delivered_at":    delivery.DeliveredAt,
+		}).Error
+}
+
+// FindDeliveries busca as entregas do endpoint, das mais recentes para as mais antigas,
+// e retorna também o total (para a paginação).
+func (r *WebhookRepository) FindDeliveries(endpointID uuid.UUID, limit, offset int) ([]domain.WebhookDelivery, int64, error) {
+	query := r.db.Model(&domain.WebhookDelivery{}).Where("endpoint_id = ?", endpointID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []domain.WebhookDelivery
+	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&deliveries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
@@ -0,0 +1,282 @@
+// Package service contém a lógica de negócio da aplicação.
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"amigos-terceira-idade/internal/config"
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+	"amigos-terceira-idade/pkg/notify"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Erros da redefinição de senha e da verificação de e-mail.
+var (
+	ErrInvalidAccountToken  = errors.New("link inválido, expirado ou já utilizado")
+	ErrEmailAlreadyVerified = errors.New("o e-mail já foi confirmado")
+)
+
+// AccountService cuida da recuperação de senha e da confirmação do e-mail, por meio de
+// tokens assinados, de uso único e com validade, enviados pelo mailer configurado.
+type AccountService struct {
+	userRepo    repository.UserRepositoryInterface
+	tokenRepo   repository.AccountTokenRepositoryInterface
+	sessionRepo repository.SessionRepositoryInterface
+	mailer      notify.Notifier
+	config      config.AccountConfig
+	secret      []byte
+	background  Background // Envia o link de redefinição fora da requisição
+}
+
+// NewAccountService cria uma nova instância do serviço de conta. secret assina os
+// tokens enviados por e-mail e background executa os envios que não podem atrasar a resposta.
+func NewAccountService(
+	userRepo repository.UserRepositoryInterface,
+	tokenRepo repository.AccountTokenRepositoryInterface,
+	sessionRepo repository.SessionRepositoryInterface,
+	mailer notify.Notifier,
+	cfg config.AccountConfig,
+	secret string,
+	background Background,
+) *AccountService {
+	return &AccountService{
+		userRepo:    userRepo,
+		tokenRepo:   tokenRepo,
+		sessionRepo: sessionRepo,
+		mailer:      mailer,
+		config:      cfg,
+		secret:      []byte(secret),
+		background:  background,
+	}
+}
+
+// ForgotPasswordRequest contém o e-mail da conta que esqueceu a senha.
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest contém o token recebido por e-mail e a nova senha.
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// VerifyEmailRequest contém o token recebido por e-mail.
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ForgotPassword agenda o envio do link de redefinição de senha e responde sem esperar.
+// A busca da conta, a emissão do token e o envio acontecem em segundo plano, para que
+// nem a resposta nem o tempo dela revelem quais e-mails têm conta; e-mails desconhecidos,
+// contas desativadas e falhas no envio ficam apenas no log.
+func (s *AccountService) ForgotPassword(req ForgotPasswordRequest) error {
+	s.background(func() {
+		if err := s.sendPasswordReset(req.Email); err != nil {
+			log.Printf("Link de redefinição de senha não enviado: %v", err)
+		}
+	})
+	return nil
+}
+
+// sendPasswordReset emite um novo link de redefinição para a conta do e-mail e o envia.
+func (s *AccountService) sendPasswordReset(email string) error {
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil || !user.IsActive {
+		return nil
+	}
+
+	// Apenas o link mais recente vale
+	if err := s.tokenRepo.InvalidateByUser(user.ID, domain.AccountTokenPasswordReset); err != nil {
+		return err
+	}
+	token, err := s.issue(user, domain.AccountTokenPasswordReset, s.config.PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	s.send(user, "Redefinição de senha", fmt.Sprintf(
+		"Olá, %s!\n\nRecebemos um pedido para redefinir a sua senha. Para escolher uma nova senha, acesse:\n\n%s\n\n"+
+			"O link vale por %s e pode ser usado uma única vez. Se não foi você, ignore este e-mail: a sua senha continua a mesma.",
+		user.Name, s.link("/reset-password", token), formatTTL(s.config.PasswordResetTTL),
+	))
+	return nil
+}
+
+// ResetPassword troca a senha usando o token recebido por e-mail e encerra todas as
+// sessões da conta, já que quem pede a redefinição pode ter perdido o controle dela.
+func (s *AccountService) ResetPassword(req ResetPasswordRequest) error {
+	record, user, err := s.redeem(req.Token, domain.AccountTokenPasswordReset)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.New("erro ao processar senha")
+	}
+	user.PasswordHash = string(hashedPassword)
+	// Receber o link no e-mail também comprova que ele pertence ao usuário
+	if !user.IsEmailVerified() && strings.EqualFold(record.Email, user.Email) {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+
+	return s.sessionRepo.RevokeAllByUser(user.ID, domain.SessionRevokedPassword)
+}
+
+// SendEmailVerification envia ao usuário o link para confirmar o e-mail.
+func (s *AccountService) SendEmailVerification(userID uuid.UUID) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.IsEmailVerified() {
+		return ErrEmailAlreadyVerified
+	}
+
+	if err := s.tokenRepo.InvalidateByUser(user.ID, domain.AccountTokenEmailVerification); err != nil {
+		return err
+	}
+	token, err := s.issue(user, domain.AccountTokenEmailVerification, s.config.EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	s.send(user, "Confirme o seu e-mail", fmt.Sprintf(
+		"Olá, %s!\n\nBoas-vindas ao Amigos da Terceira Idade. Para confirmar que este e-mail é seu, acesse:\n\n%s\n\n"+
+			"O link vale por %s.",
+		user.Name, s.link("/verify-email", token), formatTTL(s.config.EmailVerificationTTL),
+	))
+	return nil
+}
+
+// VerifyEmail confirma o e-mail do usuário com o token recebido. O token só vale para
+// o endereço ao qual foi enviado.
+func (s *AccountService) VerifyEmail(req VerifyEmailRequest) (*domain.User, error) {
+	record, user, err := s.redeem(req.Token, domain.AccountTokenEmailVerification)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(record.Email, user.Email) {
+		return nil, ErrInvalidAccountToken
+	}
+	if user.IsEmailVerified() {
+		return user, nil
+	}
+
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// issue registra um novo token para o usuário e retorna o texto a ser enviado.
+func (s *AccountService) issue(user *domain.User, purpose domain.AccountTokenPurpose, ttl time.Duration) (string, error) {
+	record := &domain.AccountToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Purpose:   purpose,
+		Email:     user.Email,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.tokenRepo.Create(record); err != nil {
+		return "", err
+	}
+	return record.ID.String() + "." + s.sign(record.ID, purpose), nil
+}
+
+// redeem confere a assinatura, o propósito e a validade do token, marca-o como usado
+// e retorna o registro e o usuário dono.
+func (s *AccountService) redeem(token string, purpose domain.AccountTokenPurpose) (*domain.AccountToken, *domain.User, error) {
+	idPart, signature, found := strings.Cut(token, ".")
+	if !found {
+		return nil, nil, ErrInvalidAccountToken
+	}
+	id, err := uuid.Parse(idPart)
+	if err != nil || !hmac.Equal([]byte(signature), []byte(s.sign(id, purpose))) {
+		return nil, nil, ErrInvalidAccountToken
+	}
+
+	record, err := s.tokenRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, domain.ErrAccountTokenNotFound) {
+			return nil, nil, ErrInvalidAccountToken
+		}
+		return nil, nil, err
+	}
+	if record.Purpose != purpose || !record.IsUsable(time.Now()) {
+		return nil, nil, ErrInvalidAccountToken
+	}
+
+	consumed, err := s.tokenRepo.Consume(record.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !consumed {
+		return nil, nil, ErrInvalidAccountToken
+	}
+
+	user, err := s.userRepo.FindByID(record.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !user.IsActive {
+		return nil, nil, ErrUserInactive
+	}
+	return record, user, nil
+}
+
+// sign calcula a assinatura HMAC-SHA256 do ID e do propósito do token.
+func (s *AccountService) sign(id uuid.UUID, purpose domain.AccountTokenPurpose) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(id.String() + "." + string(purpose)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// link monta o endereço do aplicativo que recebe o token.
+func (s *AccountService) link(path, token string) string {
+	return strings.TrimRight(s.config.AppURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
+
+// send entrega o e-mail ao usuário; falhas ficam no log para não revelar a existência da conta.
+func (s *AccountService) send(user *domain.User, subject, body string) {
+	err := s.mailer.Send(context.Background(), notify.Message{
+		To:      user.Email,
+		Name:    user.Name,
+		Subject: subject,
+		Body:    body,
+	})
+	if err != nil {
+		log.Printf("Erro ao enviar e-mail %q para o usuário %s: %v", subject, user.ID, err)
+	}
+}
+
+// formatTTL descreve a validade de um link em horas ou minutos.
+func formatTTL(ttl time.Duration) string {
+	if ttl >= time.Hour && ttl%time.Hour == 0 {
+		hours := int(ttl / time.Hour)
+		if hours == 1 {
+			return "1 hora"
+		}
+		return fmt.Sprintf("%d horas", hours)
+	}
+	return fmt.Sprintf("%d minutos", int(ttl/time.Minute))
+}
@@ -0,0 +1,392 @@
+// Package service contém a lógica de negócio da aplicação.
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// DefaultAdminPageSize e MaxAdminPageSize controlam a paginação das listagens administrativas.
+const (
+	DefaultAdminPageSize = 20
+	MaxAdminPageSize     = 100
+)
+
+// temporaryPasswordLength é o tamanho da senha gerada em ResetPassword.
+const temporaryPasswordLength = 12
+
+// temporaryPasswordAlphabet evita caracteres fáceis de confundir (0/O, 1/l/I).
+const temporaryPasswordAlphabet = "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// Erros das operações administrativas sobre outras contas.
+var (
+	ErrSelfAdministration = errors.New("não é possível aplicar esta operação à própria conta")
+	ErrRoleHierarchy      = errors.New("não é possível aplicar esta operação a um usuário com papel igual ou superior ao seu")
+)
+
+// AdminService reúne as operações de moderação da plataforma. Toda ação que altera
+// uma conta é gravada no log de auditoria com o administrador que a executou, na
+// mesma transação da alteração, e só vale para contas com papel abaixo do seu.
+// As permissões de cada operação são verificadas pelo middleware RequirePermission.
+type AdminService struct {
+	userRepo        repository.UserRepositoryInterface
+	connectionRepo  repository.ConnectionRepositoryInterface
+	appointmentRepo repository.AppointmentRepositoryInterface
+	auditRepo       repository.AuditRepositoryInterface
+	sessionRepo     repository.SessionRepositoryInterface
+}
+
+// NewAdminService cria uma nova instância do serviço administrativo.
+func NewAdminService(
+	userRepo repository.UserRepositoryInterface,
+	connectionRepo repository.ConnectionRepositoryInterface,
+	appointmentRepo repository.AppointmentRepositoryInterface,
+	auditRepo repository.AuditRepositoryInterface,
+	sessionRepo repository.SessionRepositoryInterface,
+) *AdminService {
+	return &AdminService{
+		userRepo:        userRepo,
+		connectionRepo:  connectionRepo,
+		appointmentRepo: appointmentRepo,
+		auditRepo:       auditRepo,
+		sessionRepo:     sessionRepo,
+	}
+}
+
+// UserSearch contém os filtros da busca de usuários.
+type UserSearch struct {
+	Query    string // Trecho do nome ou do email
+	UserType domain.UserType
+	IsActive *bool
+	Page     int
+	PerPage  int
+}
+
+// UserPage é uma página da listagem de usuários.
+type UserPage struct {
+	Users   []domain.User
+	Page    int
+	PerPage int
+	Total   int64
+}
+
+// AppointmentPage é uma página da listagem de agendamentos.
+type AppointmentPage struct {
+	Appointments []domain.Appointment
+	Page         int
+	PerPage      int
+	Total        int64
+}
+
+// AuditPage é uma página do log de auditoria.
+type AuditPage struct {
+	Entries []domain.AuditLog
+	Page    int
+	PerPage int
+	Total   int64
+}
+
+// SetRoleRequest contém o papel a atribuir; vazio remove o papel.
+// InstitutionID é obrigatório para INSTITUTION_STAFF.
+type SetRoleRequest struct {
+	Role          domain.Role `json:"role"`
+	InstitutionID *uuid.UUID  `json:"institution_id"`
+}
+
+// MergeUsersRequest identifica a conta duplicada que será incorporada.
+type MergeUsersRequest struct {
+	DuplicateID uuid.UUID `json:"duplicate_id" binding:"required"`
+}
+
+// SearchUsers retorna uma página dos usuários que atendem aos filtros, inclusive os desativados.
+func (s *AdminService) SearchUsers(search UserSearch) (*UserPage, error) {
+	if search.UserType != "" && !isKnownUserType(search.UserType) {
+		return nil, errors.New("tipo de usuário inválido")
+	}
+	page, perPage := adminPage(search.Page, search.PerPage)
+
+	filter := repository.UserFilter{Query: search.Query, UserType: search.UserType, IsActive: search.IsActive}
+	users, total, err := s.userRepo.Search(filter, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+	return &UserPage{Users: users, Page: page, PerPage: perPage, Total: total}, nil
+}
+
+// GetUser retorna um usuário, ativo ou não.
+func (s *AdminService) GetUser(userID uuid.UUID) (*domain.User, error) {
+	return s.userRepo.FindByID(userID)
+}
+
+// GetUserConnections retorna as conexões de um usuário, como ele mesmo as veria.
+func (s *AdminService) GetUserConnections(userID uuid.UUID) ([]domain.Connection, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.UserType == domain.UserTypeVolunteer {
+		return s.connectionRepo.FindByVolunteerID(userID)
+	}
+	return s.connectionRepo.FindByTargetID(userID)
+}
+
+// GetUserAppointments retorna os agendamentos de um usuário, como ele mesmo os veria.
+func (s *AdminService) GetUserAppointments(userID uuid.UUID) ([]domain.Appointment, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.UserType == domain.UserTypeVolunteer {
+		return s.appointmentRepo.FindByVolunteerID(userID)
+	}
+	return s.appointmentRepo.FindByTargetID(userID)
+}
+
+// DeactivateUser desativa a conta de outro usuário, impedindo novos logins.
+func (s *AdminService) DeactivateUser(actorID, userID uuid.UUID) error {
+	return s.setActive(actorID, userID, false)
+}
+
+// ReactivateUser reativa uma conta desativada.
+func (s *AdminService) ReactivateUser(actorID, userID uuid.UUID) error {
+	return s.setActive(actorID, userID, true)
+}
+
+// setActive ativa ou desativa a conta e registra a ação.
+func (s *AdminService) setActive(actorID, userID uuid.UUID, active bool) error {
+	if actorID == userID {
+		return ErrSelfAdministration
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkHierarchy(actorID, user); err != nil {
+		return err
+	}
+	if user.IsActive == active {
+		if active {
+			return errors.New("o usuário já está ativo")
+		}
+		return errors.New("o usuário já está desativado")
+	}
+
+	action := domain.AuditUserDeactivated
+	if active {
+		action = domain.AuditUserReactivated
+	}
+	user.IsActive = active
+	return s.userRepo.UpdateAudited(user, auditEntry(actorID, action, userID, ""))
+}
+
+// SetRole atribui ou remove o papel administrativo de outro usuário.
+func (s *AdminService) SetRole(actorID, userID uuid.UUID, req SetRoleRequest) (*domain.User, error) {
+	role, err := domain.ParseRole(string(req.Role))
+	if err != nil {
+		return nil, err
+	}
+	if actorID == userID {
+		return nil, ErrSelfAdministration
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkHierarchy(actorID, user); err != nil {
+		return nil, err
+	}
+
+	previous := user.Role
+	user.Role = role
+	user.StaffInstitutionID = nil
+	details := fmt.Sprintf("papel: %q → %q", previous, role)
+	if role == domain.RoleInstitutionStaff {
+		if req.InstitutionID == nil {
+			return nil, errors.New("informe a instituição da equipe")
+		}
+		institution, err := s.userRepo.FindByID(*req.InstitutionID)
+		if err != nil {
+			return nil, err
+		}
+		if institution.UserType != domain.UserTypeInstitution {
+			return nil, errors.New("o usuário informado não é uma instituição")
+		}
+		user.StaffInstitutionID = &institution.ID
+		details += fmt.Sprintf(" (instituição %s)", institution.ID)
+	}
+
+	if err := s.userRepo.UpdateAudited(user, auditEntry(actorID, domain.AuditUserRoleChanged, userID, details)); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ResetPassword troca a senha de outro usuário por uma senha temporária, que é
+// devolvida uma única vez para ser repassada ao usuário. As sessões abertas com a
+// senha anterior são encerradas.
+func (s *AdminService) ResetPassword(actorID, userID uuid.UUID) (string, error) {
+	if actorID == userID {
+		return "", ErrSelfAdministration
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return "", err
+	}
+	if err := s.checkHierarchy(actorID, user); err != nil {
+		return "", err
+	}
+
+	password, err := temporaryPassword()
+	if err != nil {
+		return "", err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	// As sessões são encerradas antes da troca: se a troca falhar, a conta fica sem
+	// sessões, mas nunca com a senha nova e as sessões antigas ainda válidas
+	if err := s.sessionRepo.RevokeAllByUser(user.ID, domain.SessionRevokedPassword); err != nil {
+		return "", err
+	}
+	user.PasswordHash = string(hash)
+	if err := s.userRepo.UpdateAudited(user, auditEntry(actorID, domain.AuditUserPasswordReset, userID, "")); err != nil {
+		return "", err
+	}
+	return password, nil
+}
+
+// MergeUsers incorpora a conta duplicada à conta mantida (userID): conexões,
+// agendamentos, notificações e interesses passam para a conta mantida e a duplicada
+// é desativada. As duas contas devem ser do mesmo tipo, e a duplicada não pode ter
+// verificação aprovada nem delegações ativas, que não são transferidas.
+func (s *AdminService) MergeUsers(actorID, userID uuid.UUID, req MergeUsersRequest) (*domain.User, error) {
+	if userID == req.DuplicateID {
+		return nil, errors.New("informe duas contas diferentes")
+	}
+
+	kept, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	duplicate, err := s.userRepo.FindByID(req.DuplicateID)
+	if err != nil {
+		return nil, err
+	}
+	if kept.UserType != duplicate.UserType {
+		return nil, errors.New("apenas contas do mesmo tipo podem ser mescladas")
+	}
+	if !kept.IsActive {
+		return nil, errors.New("a conta mantida está desativada")
+	}
+	if err := s.checkHierarchy(actorID, kept, duplicate); err != nil {
+		return nil, err
+	}
+
+	details := fmt.Sprintf("conta %s (%s) incorporada", duplicate.ID, duplicate.Email)
+	if err := s.userRepo.Merge(kept.ID, duplicate.ID, auditEntry(actorID, domain.AuditUserMerged, kept.ID, details)); err != nil {
+		return nil, err
+	}
+	return s.userRepo.FindByID(kept.ID)
+}
+
+// ListAppointments retorna uma página de todos os agendamentos, opcionalmente por status.
+func (s *AdminService) ListAppointments(status domain.AppointmentStatus, page, perPage int) (*AppointmentPage, error) {
+	if status != "" && !status.IsValid() {
+		return nil, errors.New("status inválido")
+	}
+	page, perPage = adminPage(page, perPage)
+
+	appointments, total, err := s.appointmentRepo.FindPage(status, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+	return &AppointmentPage{Appointments: appointments, Page: page, PerPage: perPage, Total: total}, nil
+}
+
+// ListAuditLog retorna uma página do log de auditoria.
+func (s *AdminService) ListAuditLog(filter repository.AuditFilter, page, perPage int) (*AuditPage, error) {
+	page, perPage = adminPage(page, perPage)
+
+	entries, total, err := s.auditRepo.FindPage(filter, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditPage{Entries: entries, Page: page, PerPage: perPage, Total: total}, nil
+}
+
+// checkHierarchy recusa a operação se alguma das contas tiver papel igual ou superior
+// ao de quem a executa.
+func (s *AdminService) checkHierarchy(actorID uuid.UUID, targets ...*domain.User) error {
+	actor, err := s.userRepo.FindByID(actorID)
+	if err != nil {
+		return err
+	}
+	for _, target := range targets {
+		if !actor.Role.Outranks(target.Role) {
+			return ErrRoleHierarchy
+		}
+	}
+	return nil
+}
+
+// auditEntry monta o registro de uma ação administrativa, gravado junto com a alteração.
+func auditEntry(actorID uuid.UUID, action domain.AuditAction, targetID uuid.UUID, details string) *domain.AuditLog {
+	return &domain.AuditLog{
+		ActorID:      actorID,
+		Action:       action,
+		TargetUserID: targetID,
+		Details:      details,
+	}
+}
+
+// adminPage normaliza os parâmetros de paginação das listagens administrativas.
+func adminPage(page, perPage int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = DefaultAdminPageSize
+	}
+	if perPage > MaxAdminPageSize {
+		perPage = MaxAdminPageSize
+	}
+	return page, perPage
+}
+
+// isKnownUserType verifica se o tipo de usuário existe.
+func isKnownUserType(userType domain.UserType) bool {
+	switch userType {
+	case domain.UserTypeVolunteer, domain.UserTypeElderly, domain.UserTypeInstitution:
+		return true
+	}
+	return false
+}
+
+// temporaryPassword gera uma senha aleatória com temporaryPasswordLength caracteres.
+func temporaryPassword() (string, error) {
+	max := big.NewInt(int64(len(temporaryPasswordAlphabet)))
+	password := make([]byte, temporaryPasswordLength)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		password[i] = temporaryPasswordAlphabet[n.Int64()]
+	}
+	return string(password), nil
+}
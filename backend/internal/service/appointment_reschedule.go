@@ -0,0 +1,228 @@
+// Package service contém a lógica de negócio da aplicação.
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ProposeReschedule registra a proposta de uma nova data feita por um dos participantes.
+// O agendamento fica em RESCHEDULE_PROPOSED, mantendo a data original, até que o outro
+// participante aceite ou recuse. O novo horário passa pelas mesmas checagens de Create.
+// Em séries, as ocorrências do escopo que admitem a proposta são deslocadas pelo mesmo
+// intervalo; se alguma conflitar, nenhuma proposta é registrada.
+func (s *AppointmentService) ProposeReschedule(appointmentID uuid.UUID, userID uuid.UUID, req RescheduleRequest, scope SeriesScope) error {
+	appointment, err := s.appointmentRepo.FindByID(appointmentID)
+	if err != nil {
+		return err
+	}
+
+	if !appointment.IsParticipant(userID) {
+		return notParticipantError("você não pode remarcar este agendamento")
+	}
+	if err := appointment.Status.ValidateTransition(domain.AppointmentStatusRescheduleProposed); err != nil {
+		return err
+	}
+
+	date := req.Date.UTC()
+	if date.Before(time.Now()) {
+		return errors.New("a data deve ser futura")
+	}
+	if date.Equal(appointment.Date) {
+		return errors.New("a nova data deve ser diferente da atual")
+	}
+
+	occurrences, err := s.occurrencesInScope(appointment, scope)
+	if err != nil {
+		return err
+	}
+	ignore := occurrenceIDs(occurrences)
+	delta := date.Sub(appointment.Date)
+
+	var changes []*domain.AppointmentStatusHistory
+	var conflicts []ScheduleConflict
+	for i := range occurrences {
+		occurrence := &occurrences[i]
+		if occurrence.ID != appointment.ID && !occurrence.Status.CanTransitionTo(domain.AppointmentStatusRescheduleProposed) {
+			continue
+		}
+
+		proposed := occurrence.Date.Add(delta)
+		if proposed.Before(time.Now()) {
+			return errors.New("a data deve ser futura")
+		}
+		if err := s.checkSlot(occurrence, proposed, ignore...); err != nil {
+			var conflictErr *ConflictError
+			if !errors.As(err, &conflictErr) {
+				return inOccurrence(occurrence, proposed, userID, err)
+			}
+			conflicts = append(conflicts, conflictErr.Conflicts...)
+			continue
+		}
+
+		changes = append(changes,
+			domain.NewReschedule(occurrence, domain.AppointmentStatusRescheduleProposed, proposed, userID, req.Reason))
+	}
+
+	if len(conflicts) > 0 {
+		return &ConflictError{Conflicts: conflicts}
+	}
+
+	if err := s.appointmentRepo.ChangeStatus(changes...); err != nil {
+		return err
+	}
+
+	s.notifyOther(appointment, userID, domain.NotificationRescheduleProposed,
+		"Nova data proposta", "%s propôs remarcar a conversa de %s.")
+	return nil
+}
+
+// AcceptReschedule aceita a data proposta pelo outro participante; o agendamento passa
+// para a nova data e fica confirmado. A data original permanece no histórico. Em séries,
+// aceita também as propostas da mesma pessoa nas ocorrências do escopo.
+func (s *AppointmentService) AcceptReschedule(appointmentID uuid.UUID, userID uuid.UUID, scope SeriesScope) error {
+	appointment, err := s.findProposal(appointmentID, userID)
+	if err != nil {
+		return err
+	}
+
+	if *appointment.ProposedBy == userID {
+		return errors.New("a proposta deve ser aceita pelo outro participante")
+	}
+	if appointment.ProposedDate.Before(time.Now()) {
+		return errors.New("a data proposta já passou")
+	}
+
+	occurrences, err := s.proposalsInScope(appointment, scope)
+	if err != nil {
+		return err
+	}
+	ignore := occurrenceIDs(occurrences)
+
+	var changes []*domain.AppointmentStatusHistory
+	var conflicts []ScheduleConflict
+	for i := range occurrences {
+		occurrence := &occurrences[i]
+		if occurrence.ID != appointment.ID && occurrence.ProposedDate.Before(time.Now()) {
+			continue
+		}
+
+		// A agenda pode ter mudado desde a proposta
+		if err := s.checkSlot(occurrence, *occurrence.ProposedDate, ignore...); err != nil {
+			var conflictErr *ConflictError
+			if !errors.As(err, &conflictErr) {
+				return inOccurrence(occurrence, *occurrence.ProposedDate, userID, err)
+			}
+			conflicts = append(conflicts, conflictErr.Conflicts...)
+			continue
+		}
+
+		changes = append(changes,
+			domain.NewReschedule(occurrence, domain.AppointmentStatusConfirmed, *occurrence.ProposedDate, userID, ""))
+	}
+
+	if len(conflicts) > 0 {
+		return &ConflictError{Conflicts: conflicts}
+	}
+	return s.appointmentRepo.ChangeStatus(changes...)
+}
+
+// RejectReschedule recusa a proposta (ou a retira, se feita pelo próprio usuário),
+// devolvendo o agendamento ao status anterior na data original. Em séries, recusa
+// também as propostas da mesma pessoa nas ocorrências do escopo.
+func (s *AppointmentService) RejectReschedule(appointmentID uuid.UUID, userID uuid.UUID, reason string, scope SeriesScope) error {
+	appointment, err := s.findProposal(appointmentID, userID)
+	if err != nil {
+		return err
+	}
+
+	occurrences, err := s.proposalsInScope(appointment, scope)
+	if err != nil {
+		return err
+	}
+
+	changes := make([]*domain.AppointmentStatusHistory, 0, len(occurrences))
+	for i := range occurrences {
+		occurrence := &occurrences[i]
+		previous := occurrence.ProposedFromStatus
+		if previous == "" {
+			previous = domain.AppointmentStatusConfirmed
+		}
+		changes = append(changes, domain.NewStatusChange(occurrence, previous, userID, reason))
+	}
+	return s.appointmentRepo.ChangeStatus(changes...)
+}
+
+// findProposal busca o agendamento e verifica se há uma proposta de remarcação
+// em aberto da qual o usuário participa.
+func (s *AppointmentService) findProposal(appointmentID uuid.UUID, userID uuid.UUID) (*domain.Appointment, error) {
+	appointment, err := s.appointmentRepo.FindByID(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !appointment.IsParticipant(userID) {
+		return nil, notParticipantError("você não pode responder a esta proposta")
+	}
+	if !hasProposal(appointment) {
+		return nil, errors.New("não há proposta de remarcação pendente")
+	}
+	return appointment, nil
+}
+
+// proposalsInScope devolve as ocorrências do escopo com proposta em aberto feita pela
+// mesma pessoa que propôs a remarcação do agendamento escolhido, que vem sempre primeiro.
+func (s *AppointmentService) proposalsInScope(appointment *domain.Appointment, scope SeriesScope) ([]domain.Appointment, error) {
+	occurrences, err := s.occurrencesInScope(appointment, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	proposals := occurrences[:0]
+	for _, occurrence := range occurrences {
+		if occurrence.ID == appointment.ID ||
+			(hasProposal(&occurrence) && *occurrence.ProposedBy == *appointment.ProposedBy) {
+			proposals = append(proposals, occurrence)
+		}
+	}
+	return proposals, nil
+}
+
+// hasProposal indica se o agendamento tem uma proposta de remarcação em aberto.
+func hasProposal(appointment *domain.Appointment) bool {
+	return appointment.Status == domain.AppointmentStatusRescheduleProposed &&
+		appointment.ProposedDate != nil && appointment.ProposedBy != nil
+}
+
+// checkSlot verifica disponibilidade e conflitos do agendamento movido para a data
+// informada, desconsiderando os agendamentos em ignore.
+func (s *AppointmentService) checkSlot(appointment *domain.Appointment, date time.Time, ignore ...uuid.UUID) error {
+	moved := *appointment
+	moved.Date = date
+
+	if err := s.checkAvailability(&moved); err != nil {
+		return err
+	}
+	return s.checkConflicts(&moved, ignore...)
+}
+
+// inOccurrence identifica, no fuso de quem agiu, a ocorrência cuja nova data foi recusada.
+func inOccurrence(occurrence *domain.Appointment, date time.Time, userID uuid.UUID, err error) error {
+	if occurrence.SeriesID == nil {
+		return err
+	}
+	return fmt.Errorf("ocorrência de %s: %w", date.In(occurrence.LocationOf(userID)).Format("02/01/2006 15:04"), err)
+}
+
+// occurrenceIDs lista os IDs das ocorrências, que não conflitam entre si ao serem movidas juntas.
+func occurrenceIDs(occurrences []domain.Appointment) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		ids = append(ids, occurrence.ID)
+	}
+	return ids
+}
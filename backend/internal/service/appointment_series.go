@@ -0,0 +1,168 @@
+// Package service contém a lógica de negócio da aplicação.
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"github.com/google/uuid"
+)
+
+// SeriesScope define quais ocorrências de uma série recorrente uma operação afeta.
+type SeriesScope string
+
+const (
+	SeriesScopeThis      SeriesScope = "this"      // Apenas a ocorrência escolhida
+	SeriesScopeFollowing SeriesScope = "following" // A ocorrência escolhida e as seguintes
+	SeriesScopeAll       SeriesScope = "all"       // Todas as ocorrências futuras da série
+)
+
+// ParseSeriesScope valida o escopo informado; vazio equivale a SeriesScopeThis.
+func ParseSeriesScope(value string) (SeriesScope, error) {
+	switch SeriesScope(value) {
+	case "", SeriesScopeThis:
+		return SeriesScopeThis, nil
+	case SeriesScopeFollowing, SeriesScopeAll:
+		return SeriesScope(value), nil
+	}
+	return "", errors.New("escopo inválido: use this, following ou all")
+}
+
+// RescheduleRequest contém a nova data de um agendamento.
+type RescheduleRequest struct {
+	Date   time.Time `json:"date" binding:"required"`
+	Reason string    `json:"reason" binding:"max=500"`
+}
+
+// CreateSeries cria uma série recorrente de agendamentos a partir de req.Recurrence.
+// Todas as ocorrências passam pelas mesmas validações de Create, com os conflitos
+// verificados na transação que insere a série; se alguma conflitar, nenhuma é criada e
+// o *ConflictError reúne os conflitos de todas elas.
+func (s *AppointmentService) CreateSeries(volunteerID uuid.UUID, req CreateAppointmentRequest) (*domain.AppointmentSeries, error) {
+	rule, err := domain.ParseRecurrenceRule(req.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+
+	first, err := s.newInvitation(volunteerID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// As ocorrências mantêm o horário local do voluntário, mesmo com mudança de horário de verão
+	loc := first.LocationOf(volunteerID)
+	dates := rule.Occurrences(first.Date.In(loc))
+	if len(dates) == 0 {
+		return nil, errors.New("a regra de recorrência não gera nenhuma ocorrência")
+	}
+
+	appointments := make([]domain.Appointment, 0, len(dates))
+	for _, date := range dates {
+		occurrence := *first
+		occurrence.ID = uuid.New()
+		occurrence.Date = date.UTC()
+
+		if err := s.checkAvailability(&occurrence); err != nil {
+			return nil, fmt.Errorf("ocorrência de %s: %w", date.Format("02/01/2006 15:04"), err)
+		}
+		appointments = append(appointments, occurrence)
+	}
+
+	series := &domain.AppointmentSeries{
+		ID:              uuid.New(),
+		VolunteerID:     volunteerID,
+		TargetID:        first.TargetID,
+		RRule:           rule.String(),
+		DurationMinutes: first.DurationMinutes,
+	}
+	err = s.appointmentRepo.CreateSeries(series, appointments, func(overlapping [][]domain.Appointment) error {
+		var conflicts []ScheduleConflict
+		for i := range appointments {
+			if err := s.conflictsWith(&appointments[i], overlapping[i]); err != nil {
+				var conflictErr *ConflictError
+				if !errors.As(err, &conflictErr) {
+					return err
+				}
+				conflicts = append(conflicts, conflictErr.Conflicts...)
+			}
+		}
+		if len(conflicts) > 0 {
+			return &ConflictError{Conflicts: conflicts}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyOther(&appointments[0], volunteerID, domain.NotificationInvitationReceived,
+		"Novo convite de conversas recorrentes", "%s convidou você para uma série de conversas a partir de %s.")
+
+	created, err := s.appointmentRepo.FindSeries(series.ID)
+	if err != nil {
+		return nil, err
+	}
+	localizeSeries(created, volunteerID)
+	return created, nil
+}
+
+// GetSeries retorna a série à qual o agendamento pertence (apenas para participantes).
+func (s *AppointmentService) GetSeries(appointmentID uuid.UUID, userID uuid.UUID) (*domain.AppointmentSeries, error) {
+	appointment, err := s.appointmentRepo.FindByID(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !appointment.IsParticipant(userID) {
+		return nil, ErrNotAppointmentParticipant
+	}
+	if appointment.SeriesID == nil {
+		return nil, errors.New("este agendamento não faz parte de uma série")
+	}
+
+	series, err := s.appointmentRepo.FindSeries(*appointment.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+	localizeSeries(series, userID)
+	return series, nil
+}
+
+// localizeSeries preenche o horário local das ocorrências no fuso de quem consulta.
+func localizeSeries(series *domain.AppointmentSeries, viewerID uuid.UUID) {
+	for i := range series.Appointments {
+		series.Appointments[i].Localize(series.Appointments[i].LocationOf(viewerID))
+	}
+}
+
+// occurrencesInScope devolve as ocorrências afetadas por uma operação no agendamento.
+// Fora de uma série, ou com SeriesScopeThis, é apenas o próprio agendamento.
+// Ocorrências já passadas nunca são alteradas.
+func (s *AppointmentService) occurrencesInScope(appointment *domain.Appointment, scope SeriesScope) ([]domain.Appointment, error) {
+	if scope == "" || scope == SeriesScopeThis {
+		return []domain.Appointment{*appointment}, nil
+	}
+	if appointment.SeriesID == nil {
+		return nil, errors.New("este agendamento não faz parte de uma série")
+	}
+
+	series, err := s.appointmentRepo.FindSeries(*appointment.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	occurrences := []domain.Appointment{*appointment}
+	for _, occurrence := range series.Appointments {
+		if occurrence.ID == appointment.ID || occurrence.Date.Before(now) {
+			continue
+		}
+		if scope == SeriesScopeFollowing && occurrence.Date.Before(appointment.Date) {
+			continue
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+	return occurrences, nil
+}
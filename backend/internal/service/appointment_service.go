@@ -3,6 +3,8 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"net/url"
 	"time"
 
 	"amigos-terceira-idade/internal/domain"
@@ -12,31 +14,83 @@ import (
 
 // AppointmentService gerencia os agendamentos de conversas.
 type AppointmentService struct {
-	appointmentRepo repository.AppointmentRepositoryInterface
-	userRepo        repository.UserRepositoryInterface
+	appointmentRepo  repository.AppointmentRepositoryInterface
+	userRepo         repository.UserRepositoryInterface
+	residentRepo     repository.ResidentRepositoryInterface
+	availabilityRepo repository.AvailabilityRepositoryInterface
+	notifications    NotificationSender
 }
 
 // NewAppointmentService cria uma nova instância do serviço de agendamentos.
 func NewAppointmentService(
 	appointmentRepo repository.AppointmentRepositoryInterface,
 	userRepo repository.UserRepositoryInterface,
+	residentRepo repository.ResidentRepositoryInterface,
+	availabilityRepo repository.AvailabilityRepositoryInterface,
+	notifications NotificationSender,
 ) *AppointmentService {
 	return &AppointmentService{
-		appointmentRepo: appointmentRepo,
-		userRepo:        userRepo,
+		appointmentRepo:  appointmentRepo,
+		userRepo:         userRepo,
+		residentRepo:     residentRepo,
+		availabilityRepo: availabilityRepo,
+		notifications:    notifications,
 	}
 }
 
 // CreateAppointmentRequest contém os dados para criar um agendamento.
+// Com Recurrence (RRULE), cria uma série de agendamentos (veja CreateSeries).
+// Com ResidentID, o convite é para um residente e vai para a instituição dele.
 type CreateAppointmentRequest struct {
-	TargetID        uuid.UUID `json:"target_id" binding:"required"`
-	Date            time.Time `json:"date" binding:"required"`
-	DurationMinutes int       `json:"duration_minutes"`
-	Notes           string    `json:"notes"`
+	TargetID        uuid.UUID  `json:"target_id" binding:"required_without=ResidentID"`
+	ResidentID      *uuid.UUID `json:"resident_id"`
+	Date            time.Time  `json:"date" binding:"required"`
+	DurationMinutes int        `json:"duration_minutes"`
+	Notes           string     `json:"notes"`
+	Recurrence      string     `json:"recurrence"` // Ex.: FREQ=WEEKLY;COUNT=8
 }
 
 // Create cria um novo agendamento (envia convite).
 func (s *AppointmentService) Create(volunteerID uuid.UUID, req CreateAppointmentRequest) (*domain.Appointment, error) {
+	if req.Recurrence != "" {
+		return nil, errors.New("agendamentos recorrentes devem ser criados como série")
+	}
+
+	appointment, err := s.newInvitation(volunteerID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verifica se o horário está dentro da disponibilidade declarada pelo voluntário
+	// e, para instituições, dentro de uma janela de visitas
+	if err := s.checkAvailability(appointment); err != nil {
+		return nil, err
+	}
+
+	// Verifica se o voluntário ou o destinatário já têm compromisso no horário
+	// (em instituições, se ainda há vaga para mais um visitante), na mesma transação
+	// da inserção, para que convites simultâneos não ocupem o mesmo horário
+	err = s.appointmentRepo.Create(appointment, func(overlapping []domain.Appointment) error {
+		return s.conflictsWith(appointment, overlapping)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyOther(appointment, volunteerID, domain.NotificationInvitationReceived,
+		"Novo convite de conversa", "%s convidou você para uma conversa em %s.")
+
+	// Retorna com os relacionamentos preenchidos, no fuso do voluntário
+	created, err := s.appointmentRepo.FindByID(appointment.ID)
+	if err != nil {
+		return nil, err
+	}
+	created.Localize(created.LocationOf(volunteerID))
+	return created, nil
+}
+
+// newInvitation valida os participantes, a data e a duração, e monta o agendamento pendente.
+func (s *AppointmentService) newInvitation(volunteerID uuid.UUID, req CreateAppointmentRequest) (*domain.Appointment, error) {
 	// Valida o voluntário
 	volunteer, err := s.userRepo.FindByID(volunteerID)
 	if err != nil {
@@ -46,8 +100,25 @@ func (s *AppointmentService) Create(volunteerID uuid.UUID, req CreateAppointment
 		return nil, errors.New("apenas voluntários podem criar agendamentos")
 	}
 
+	// Convites para residentes vão para a instituição, que responde em nome deles
+	targetID := req.TargetID
+	var resident *domain.Resident
+	if req.ResidentID != nil {
+		resident, err = s.residentRepo.FindByID(*req.ResidentID)
+		if err != nil {
+			return nil, err
+		}
+		if !resident.IsActive {
+			return nil, domain.ErrResidentNotFound
+		}
+		if targetID != uuid.Nil && targetID != resident.InstitutionID {
+			return nil, errors.New("o residente não pertence à instituição informada")
+		}
+		targetID = resident.InstitutionID
+	}
+
 	// Valida o destinatário
-	target, err := s.userRepo.FindByID(req.TargetID)
+	target, err := s.userRepo.FindByID(targetID)
 	if err != nil {
 		return nil, err
 	}
@@ -63,32 +134,46 @@ func (s *AppointmentService) Create(volunteerID uuid.UUID, req CreateAppointment
 	// Define duração padrão se não informada
 	duration := req.DurationMinutes
 	if duration <= 0 {
-		duration = 30 // 30 minutos padrão
+		duration = domain.DefaultAppointmentDuration
+	}
+	if duration > domain.MaxAppointmentDuration {
+		return nil, errors.New("a duração máxima de uma conversa é de 4 horas")
 	}
 
-	// Cria o agendamento
+	// A data é armazenada em UTC; o fuso do voluntário fica registrado para
+	// interpretar a agenda e as recorrências no horário local de quem criou
 	appointment := &domain.Appointment{
 		ID:              uuid.New(),
 		VolunteerID:     volunteerID,
-		TargetID:        req.TargetID,
+		TargetID:        targetID,
 		TargetType:      target.UserType,
-		Date:            req.Date,
+		Date:            req.Date.UTC(),
+		Timezone:        volunteer.Location().String(),
 		DurationMinutes: duration,
 		Status:          domain.AppointmentStatusPending,
 		Notes:           req.Notes,
+		Volunteer:       *volunteer,
+		Target:          *target,
 	}
+	if resident != nil {
+		appointment.ResidentID = &resident.ID
+	}
+	return appointment, nil
+}
 
-	if err := s.appointmentRepo.Create(appointment); err != nil {
+// GetByID busca um agendamento pelo ID (apenas para participantes), com o horário
+// no fuso de quem consulta.
+func (s *AppointmentService) GetByID(id uuid.UUID, viewerID uuid.UUID) (*domain.Appointment, error) {
+	appointment, err := s.appointmentRepo.FindByID(id)
+	if err != nil {
 		return nil, err
 	}
 
-	// Retorna com os relacionamentos preenchidos
-	return s.appointmentRepo.FindByID(appointment.ID)
-}
-
-// GetByID busca um agendamento pelo ID.
-func (s *AppointmentService) GetByID(id uuid.UUID) (*domain.Appointment, error) {
-	return s.appointmentRepo.FindByID(id)
+	if !appointment.IsParticipant(viewerID) {
+		return nil, ErrNotAppointmentParticipant
+	}
+	appointment.Localize(appointment.LocationOf(viewerID))
+	return appointment, nil
 }
 
 // GetMyAppointments retorna os agendamentos de um usuário.
@@ -98,29 +183,53 @@ func (s *AppointmentService) GetMyAppointments(userID uuid.UUID) ([]domain.Appoi
 		return nil, err
 	}
 
+	var appointments []domain.Appointment
 	if user.UserType == domain.UserTypeVolunteer {
-		return s.appointmentRepo.FindByVolunteerID(userID)
+		appointments, err = s.appointmentRepo.FindByVolunteerID(userID)
+	} else {
+		appointments, err = s.appointmentRepo.FindByTargetID(userID)
 	}
-	return s.appointmentRepo.FindByTargetID(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range appointments {
+		appointments[i].Localize(user.Location())
+	}
+	return appointments, nil
 }
 
 // GetUpcoming retorna os próximos agendamentos confirmados.
 func (s *AppointmentService) GetUpcoming(userID uuid.UUID) ([]domain.Appointment, error) {
-	return s.appointmentRepo.FindUpcoming(userID)
+	return localizeFor(userID)(s.appointmentRepo.FindUpcoming(userID))
 }
 
 // GetReceivedInvitations retorna os convites recebidos pendentes.
 func (s *AppointmentService) GetReceivedInvitations(userID uuid.UUID) ([]domain.Appointment, error) {
-	return s.appointmentRepo.FindPendingInvitations(userID)
+	return localizeFor(userID)(s.appointmentRepo.FindPendingInvitations(userID))
 }
 
 // GetSentInvitations retorna os convites enviados pendentes.
 func (s *AppointmentService) GetSentInvitations(userID uuid.UUID) ([]domain.Appointment, error) {
-	return s.appointmentRepo.FindSentInvitations(userID)
+	return localizeFor(userID)(s.appointmentRepo.FindSentInvitations(userID))
+}
+
+// localizeFor retorna uma função que preenche o horário local dos agendamentos
+// no fuso do participante que os consulta, repassando eventuais erros da busca.
+func localizeFor(viewerID uuid.UUID) func([]domain.Appointment, error) ([]domain.Appointment, error) {
+	return func(appointments []domain.Appointment, err error) ([]domain.Appointment, error) {
+		if err != nil {
+			return nil, err
+		}
+		for i := range appointments {
+			appointments[i].Localize(appointments[i].LocationOf(viewerID))
+		}
+		return appointments, nil
+	}
 }
 
 // Accept aceita um convite de agendamento.
-func (s *AppointmentService) Accept(appointmentID uuid.UUID, userID uuid.UUID) error {
+// Em séries, o escopo permite aceitar também as ocorrências seguintes ou a série toda.
+func (s *AppointmentService) Accept(appointmentID uuid.UUID, userID uuid.UUID, scope SeriesScope) error {
 	appointment, err := s.appointmentRepo.FindByID(appointmentID)
 	if err != nil {
 		return err
@@ -128,7 +237,7 @@ func (s *AppointmentService) Accept(appointmentID uuid.UUID, userID uuid.UUID) e
 
 	// Verifica se o usuário é o destinatário do convite
 	if appointment.TargetID != userID {
-		return errors.New("você não pode aceitar este convite")
+		return notParticipantError("você não pode aceitar este convite")
 	}
 
 	// Verifica se está pendente
@@ -136,11 +245,18 @@ func (s *AppointmentService) Accept(appointmentID uuid.UUID, userID uuid.UUID) e
 		return errors.New("este convite não está mais pendente")
 	}
 
-	return s.appointmentRepo.UpdateStatus(appointmentID, domain.AppointmentStatusConfirmed)
+	if err := s.transitionInScope(appointment, scope, domain.AppointmentStatusConfirmed, userID, "", isPending); err != nil {
+		return err
+	}
+
+	s.notifyOther(appointment, userID, domain.NotificationInvitationAccepted,
+		"Convite aceito", "%s aceitou a conversa de %s.")
+	return nil
 }
 
 // Decline recusa um convite de agendamento.
-func (s *AppointmentService) Decline(appointmentID uuid.UUID, userID uuid.UUID) error {
+// Em séries, o escopo permite recusar também as ocorrências seguintes ou a série toda.
+func (s *AppointmentService) Decline(appointmentID uuid.UUID, userID uuid.UUID, reason string, scope SeriesScope) error {
 	appointment, err := s.appointmentRepo.FindByID(appointmentID)
 	if err != nil {
 		return err
@@ -148,48 +264,362 @@ func (s *AppointmentService) Decline(appointmentID uuid.UUID, userID uuid.UUID)
 
 	// Verifica se o usuário é o destinatário do convite
 	if appointment.TargetID != userID {
-		return errors.New("você não pode recusar este convite")
+		return notParticipantError("você não pode recusar este convite")
 	}
 
-	return s.appointmentRepo.UpdateStatus(appointmentID, domain.AppointmentStatusCancelled)
+	// Apenas convites pendentes podem ser recusados; depois disso, use Cancel
+	if appointment.Status != domain.AppointmentStatusPending {
+		return errors.New("este convite não está mais pendente")
+	}
+
+	if err := s.transitionInScope(appointment, scope, domain.AppointmentStatusCancelled, userID, reason, isPending); err != nil {
+		return err
+	}
+
+	s.notifyOther(appointment, userID, domain.NotificationInvitationDeclined,
+		"Convite recusado", "%s recusou a conversa de %s.")
+	return nil
 }
 
 // Cancel cancela um agendamento.
-func (s *AppointmentService) Cancel(appointmentID uuid.UUID, userID uuid.UUID) error {
+// Em séries, o escopo permite cancelar também as ocorrências seguintes ou a série toda.
+func (s *AppointmentService) Cancel(appointmentID uuid.UUID, userID uuid.UUID, reason string, scope SeriesScope) error {
 	appointment, err := s.appointmentRepo.FindByID(appointmentID)
 	if err != nil {
 		return err
 	}
 
 	// Verifica se o usuário é participante do agendamento
-	if appointment.VolunteerID != userID && appointment.TargetID != userID {
-		return errors.New("você não pode cancelar este agendamento")
+	if !appointment.IsParticipant(userID) {
+		return notParticipantError("você não pode cancelar este agendamento")
+	}
+
+	if err := s.transitionInScope(appointment, scope, domain.AppointmentStatusCancelled, userID, reason, nil); err != nil {
+		return err
 	}
 
-	return s.appointmentRepo.UpdateStatus(appointmentID, domain.AppointmentStatusCancelled)
+	s.notifyOther(appointment, userID, domain.NotificationAppointmentCancelled,
+		"Conversa cancelada", "%s cancelou a conversa de %s.")
+	return nil
 }
 
-// Complete marca um agendamento como concluído.
-func (s *AppointmentService) Complete(appointmentID uuid.UUID, rating int) error {
+// Complete marca um agendamento confirmado como concluído.
+// Apenas os participantes podem concluir, e somente após o término previsto da conversa.
+func (s *AppointmentService) Complete(appointmentID uuid.UUID, userID uuid.UUID) error {
 	appointment, err := s.appointmentRepo.FindByID(appointmentID)
 	if err != nil {
 		return err
 	}
 
-	appointment.Status = domain.AppointmentStatusCompleted
-	if rating >= 1 && rating <= 5 {
-		appointment.Rating = rating
+	// Verifica se o usuário é participante do agendamento
+	if !appointment.IsParticipant(userID) {
+		return notParticipantError("você não pode concluir este agendamento")
 	}
 
-	return s.appointmentRepo.Update(appointment)
+	// Apenas agendamentos confirmados podem ser concluídos
+	if !appointment.Status.CanTransitionTo(domain.AppointmentStatusCompleted) {
+		return errors.New("apenas agendamentos confirmados podem ser concluídos")
+	}
+
+	// A conversa precisa ter terminado
+	if time.Now().Before(appointment.EndsAt()) {
+		return errors.New("a conversa ainda não terminou")
+	}
+
+	change := domain.NewStatusChange(appointment, domain.AppointmentStatusCompleted, userID, "")
+	return s.appointmentRepo.Complete(appointment, change)
 }
 
-// SetMeetingURL define o link da reunião para um agendamento.
-func (s *AppointmentService) SetMeetingURL(appointmentID uuid.UUID, url string) error {
+// MarkNoShow registra que a conversa confirmada não aconteceu por ausência de um participante.
+// Só pode ser feito por um participante, depois do horário de início.
+func (s *AppointmentService) MarkNoShow(appointmentID uuid.UUID, userID uuid.UUID, reason string) error {
 	appointment, err := s.appointmentRepo.FindByID(appointmentID)
 	if err != nil {
 		return err
 	}
-	appointment.MeetingURL = url
+
+	// Verifica se o usuário é participante do agendamento
+	if !appointment.IsParticipant(userID) {
+		return notParticipantError("você não pode alterar este agendamento")
+	}
+
+	// Ausência só pode ser registrada depois do horário marcado
+	if time.Now().Before(appointment.Date) {
+		return errors.New("a conversa ainda não começou")
+	}
+
+	return s.transitionInScope(appointment, SeriesScopeThis, domain.AppointmentStatusNoShow, userID, reason, nil)
+}
+
+// GetHistory retorna o histórico de status de um agendamento.
+// Apenas os participantes podem consultar.
+func (s *AppointmentService) GetHistory(appointmentID uuid.UUID, userID uuid.UUID) ([]domain.AppointmentStatusHistory, error) {
+	appointment, err := s.appointmentRepo.FindByID(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !appointment.IsParticipant(userID) {
+		return nil, ErrNotAppointmentParticipant
+	}
+
+	return s.appointmentRepo.FindHistory(appointmentID)
+}
+
+// checkAvailability retorna ErrOutsideAvailability se o agendamento não couber
+// inteiro em uma janela de disponibilidade do voluntário.
+// Voluntários sem disponibilidade declarada aceitam qualquer horário.
+// Visitas a instituições também precisam caber em uma janela de visitas.
+// As janelas são interpretadas no fuso de quem as declarou.
+func (s *AppointmentService) checkAvailability(appointment *domain.Appointment) error {
+	free, declared, err := freeIntervals(s.availabilityRepo, appointment.VolunteerID,
+		appointment.Date, appointment.EndsAt(), appointment.LocationOf(appointment.VolunteerID))
+	if err != nil {
+		return err
+	}
+	if declared && !coversInterval(free, appointment.Date, appointment.EndsAt()) {
+		return ErrOutsideAvailability
+	}
+
+	if appointment.TargetType != domain.UserTypeInstitution {
+		return nil
+	}
+	window, declared, err := s.visitWindowFor(appointment)
+	if err != nil {
+		return err
+	}
+	if !declared {
+		return nil
+	}
+	if window == nil {
+		return ErrOutsideVisitWindow
+	}
+	if !window.AllowsDuration(appointment.DurationMinutes) {
+		return fmt.Errorf("a duração máxima de uma visita neste horário é de %d minutos", window.MaxDurationMinutes)
+	}
+	return nil
+}
+
+// visitWindowFor retorna a janela de visitas da instituição que contém o agendamento.
+// declared é false se a instituição não tiver janelas cadastradas (qualquer horário é aceito).
+func (s *AppointmentService) visitWindowFor(appointment *domain.Appointment) (*domain.VisitWindow, bool, error) {
+	windows, err := s.availabilityRepo.FindVisitWindows(appointment.TargetID)
+	if err != nil {
+		return nil, false, err
+	}
+	for i := range windows {
+		if windows[i].Contains(appointment.Date, appointment.EndsAt(), appointment.LocationOf(appointment.TargetID)) {
+			return &windows[i], true, nil
+		}
+	}
+	return nil, len(windows) > 0, nil
+}
+
+// checkConflicts retorna um *ConflictError se o voluntário ou o destinatário já tiver
+// um agendamento pendente ou confirmado que se sobreponha ao horário do agendamento.
+// Instituições recebem vários visitantes ao mesmo tempo: para elas, em vez de conflito,
+// verifica-se o limite de visitantes simultâneos da janela de visitas.
+// O próprio agendamento e os IDs em ignore são desconsiderados, permitindo reaproveitar
+// a checagem em remarcações (inclusive de várias ocorrências de uma série).
+func (s *AppointmentService) checkConflicts(appointment *domain.Appointment, ignore ...uuid.UUID) error {
+	userIDs := []uuid.UUID{appointment.VolunteerID, appointment.TargetID}
+	overlapping, err := s.appointmentRepo.FindOverlapping(userIDs, appointment.Date, appointment.EndsAt())
+	if err != nil {
+		return err
+	}
+	return s.conflictsWith(appointment, overlapping, ignore...)
+}
+
+// conflictsWith faz a checagem de checkConflicts sobre os agendamentos sobrepostos já lidos,
+// como os travados pelo repositório na transação que insere o agendamento.
+func (s *AppointmentService) conflictsWith(appointment *domain.Appointment, overlapping []domain.Appointment, ignore ...uuid.UUID) error {
+	userIDs := []uuid.UUID{appointment.VolunteerID, appointment.TargetID}
+	skip := map[uuid.UUID]bool{appointment.ID: true}
+	for _, id := range ignore {
+		skip[id] = true
+	}
+
+	isInstitution := appointment.TargetType == domain.UserTypeInstitution
+	var conflicts []ScheduleConflict
+	var visits []domain.Appointment
+	for _, other := range overlapping {
+		if skip[other.ID] {
+			continue
+		}
+		// O mesmo residente não pode receber duas visitas ao mesmo tempo
+		if appointment.ResidentID != nil && other.ResidentID != nil && *other.ResidentID == *appointment.ResidentID {
+			conflicts = append(conflicts, ScheduleConflict{
+				AppointmentID:   other.ID,
+				UserID:          *appointment.ResidentID,
+				Date:            other.Date,
+				DurationMinutes: other.DurationMinutes,
+				Status:          other.Status,
+			})
+			continue
+		}
+		for _, userID := range userIDs {
+			if !other.IsParticipant(userID) {
+				continue
+			}
+			if isInstitution && userID == appointment.TargetID {
+				visits = append(visits, other)
+				continue
+			}
+			conflicts = append(conflicts, ScheduleConflict{
+				AppointmentID:   other.ID,
+				UserID:          userID,
+				Date:            other.Date,
+				DurationMinutes: other.DurationMinutes,
+				Status:          other.Status,
+			})
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return &ConflictError{Conflicts: conflicts}
+	}
+	if isInstitution && len(visits) > 0 {
+		return s.checkVisitCapacity(appointment, visits)
+	}
+	return nil
+}
+
+// checkVisitCapacity retorna ErrVisitCapacityReached se, em algum instante do agendamento,
+// o número de visitantes simultâneos ultrapassar o limite da janela de visitas.
+func (s *AppointmentService) checkVisitCapacity(appointment *domain.Appointment, visits []domain.Appointment) error {
+	window, _, err := s.visitWindowFor(appointment)
+	if err != nil {
+		return err
+	}
+	if window == nil || window.MaxVisitors <= 0 {
+		return nil
+	}
+
+	// A lotação máxima ocorre no início do agendamento ou no início de uma das outras visitas
+	instants := []time.Time{appointment.Date}
+	for _, visit := range visits {
+		if visit.Date.After(appointment.Date) {
+			instants = append(instants, visit.Date)
+		}
+	}
+	for _, instant := range instants {
+		concurrent := 1
+		for _, visit := range visits {
+			if visit.Overlaps(instant, instant.Add(time.Nanosecond)) {
+				concurrent++
+			}
+		}
+		if concurrent > window.MaxVisitors {
+			return ErrVisitCapacityReached
+		}
+	}
+	return nil
+}
+
+// transitionInScope valida a mudança de status pela máquina de estados na ocorrência
+// escolhida e a aplica, em uma única transação, às ocorrências do escopo que ainda
+// admitem a transição e passam pelo filtro (nil aceita todas).
+func (s *AppointmentService) transitionInScope(
+	appointment *domain.Appointment,
+	scope SeriesScope,
+	to domain.AppointmentStatus,
+	userID uuid.UUID,
+	reason string,
+	eligible func(*domain.Appointment) bool,
+) error {
+	if err := appointment.Status.ValidateTransition(to); err != nil {
+		return err
+	}
+
+	occurrences, err := s.occurrencesInScope(appointment, scope)
+	if err != nil {
+		return err
+	}
+
+	var changes []*domain.AppointmentStatusHistory
+	for i := range occurrences {
+		occurrence := &occurrences[i]
+		if occurrence.ID != appointment.ID {
+			if !occurrence.Status.CanTransitionTo(to) || (eligible != nil && !eligible(occurrence)) {
+				continue
+			}
+		}
+		changes = append(changes, domain.NewStatusChange(occurrence, to, userID, reason))
+	}
+	return s.appointmentRepo.ChangeStatus(changes...)
+}
+
+// notifyOther avisa o outro participante do agendamento sobre uma ação de actorID.
+// O texto recebe o nome de quem agiu e a data da conversa no fuso do destinatário.
+func (s *AppointmentService) notifyOther(appointment *domain.Appointment, actorID uuid.UUID, notificationType domain.NotificationType, title, body string) {
+	recipientID, actor := appointment.TargetID, appointment.Volunteer.Name
+	if actorID == appointment.TargetID {
+		recipientID, actor = appointment.VolunteerID, appointment.Target.Name
+	}
+	local := appointment.Date.In(appointment.LocationOf(recipientID))
+
+	sendNotification(s.notifications, &domain.Notification{
+		UserID:        recipientID,
+		Type:          notificationType,
+		Title:         title,
+		Body:          fmt.Sprintf(body, actor, local.Format("02/01/2006 às 15:04")),
+		ActorID:       &actorID,
+		AppointmentID: &appointment.ID,
+	})
+}
+
+// isPending filtra ocorrências que ainda aguardam aceite.
+func isPending(appointment *domain.Appointment) bool {
+	return appointment.Status == domain.AppointmentStatusPending
+}
+
+// Rate registra a avaliação (1-5) do voluntário após a conversa concluída.
+// Apenas o idoso/instituição que recebeu a conversa pode avaliar.
+func (s *AppointmentService) Rate(appointmentID uuid.UUID, userID uuid.UUID, rating int) error {
+	if rating < 1 || rating > 5 {
+		return errors.New("a avaliação deve ser entre 1 e 5")
+	}
+
+	appointment, err := s.appointmentRepo.FindByID(appointmentID)
+	if err != nil {
+		return err
+	}
+
+	// Verifica se o usuário é o destinatário do agendamento
+	if appointment.TargetID != userID {
+		return notParticipantError("você não pode avaliar este agendamento")
+	}
+
+	// Apenas conversas concluídas podem ser avaliadas, e uma única vez
+	if appointment.Status != domain.AppointmentStatusCompleted {
+		return errors.New("apenas agendamentos concluídos podem ser avaliados")
+	}
+	if appointment.Rating != 0 {
+		return errors.New("este agendamento já foi avaliado")
+	}
+
+	return s.appointmentRepo.Rate(appointment, rating)
+}
+
+// SetMeetingURL define o link da reunião para um agendamento; vazio remove o link.
+// O link vai para os e-mails e calendários do outro participante, então só são
+// aceitos endereços http(s) completos.
+func (s *AppointmentService) SetMeetingURL(appointmentID uuid.UUID, meetingURL string) error {
+	if meetingURL != "" && !isHTTPURL(meetingURL) {
+		return ErrInvalidMeetingURL
+	}
+	appointment, err := s.appointmentRepo.FindByID(appointmentID)
+	if err != nil {
+		return err
+	}
+	appointment.MeetingURL = meetingURL
+	appointment.CalendarSequence++ // Atualiza o evento nos calendários exportados
 	return s.appointmentRepo.Update(appointment)
 }
+
+// isHTTPURL verifica se o valor é um endereço http(s) com host. url.Parse já recusa
+// caracteres de controle, como quebras de linha.
+func isHTTPURL(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
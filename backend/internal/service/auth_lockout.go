@@ -0,0 +1,85 @@
+// Package service contém a lógica de negócio da aplicação.
+package service
+
+import (
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// checkLockout retorna AccountLockedError se a conta está bloqueada.
+func (s *AuthService) checkLockout(userID uuid.UUID, now time.Time) error {
+	if !s.lockoutEnabled() {
+		return nil
+	}
+	lockout, err := s.loginCodeRepo.FindLockout(userID)
+	if err != nil {
+		return err
+	}
+	if lockout.IsLocked(now) {
+		return &AccountLockedError{Until: *lockout.LockedUntil}
+	}
+	return nil
+}
+
+// recordLoginFailure soma uma falha à conta e a bloqueia ao atingir o limite. Cada
+// bloqueio seguido dobra a duração do anterior, até LockoutMaxDuration. A contagem e
+// a decisão de bloquear acontecem na mesma transação, com o registro travado. Falhas
+// de tentativas que começaram antes de a conta ser bloqueada não contam para o próximo
+// bloqueio.
+func (s *AuthService) recordLoginFailure(userID uuid.UUID, now time.Time) error {
+	if !s.lockoutEnabled() {
+		return nil
+	}
+	return s.loginCodeRepo.UpdateLockout(userID, func(lockout *domain.AccountLockout) {
+		if lockout.IsLocked(now) {
+			return
+		}
+		lockout.Failures++
+		if lockout.Failures >= s.codeConfig.LockoutThreshold {
+			until := now.Add(s.lockoutDuration(lockout.Lockouts))
+			lockout.LockedUntil = &until
+			lockout.Failures = 0
+			lockout.Lockouts++
+		}
+	})
+}
+
+// resetLockout zera as falhas e os bloqueios da conta após um login bem-sucedido.
+func (s *AuthService) resetLockout(userID uuid.UUID) error {
+	if !s.lockoutEnabled() {
+		return nil
+	}
+	lockout, err := s.loginCodeRepo.FindLockout(userID)
+	if err != nil {
+		return err
+	}
+	if lockout.Failures == 0 && lockout.Lockouts == 0 {
+		return nil
+	}
+	return s.loginCodeRepo.UpdateLockout(userID, func(lockout *domain.AccountLockout) {
+		lockout.Failures = 0
+		lockout.Lockouts = 0
+	})
+}
+
+// lockoutEnabled indica se o bloqueio de conta está configurado.
+func (s *AuthService) lockoutEnabled() bool {
+	return s.codeConfig.LockoutThreshold > 0
+}
+
+// lockoutDuration calcula a duração do bloqueio a partir dos bloqueios anteriores,
+// limitada a LockoutMaxDuration (inclusive no primeiro bloqueio).
+func (s *AuthService) lockoutDuration(previous int) time.Duration {
+	maxDuration := s.codeConfig.LockoutMaxDuration
+	duration := s.codeConfig.LockoutDuration
+	for i := 0; i < previous && (maxDuration <= 0 || duration < maxDuration); i++ {
+		duration *= 2
+	}
+	if maxDuration > 0 && duration > maxDuration {
+		return maxDuration
+	}
+	return duration
+}
@@ -0,0 +1,221 @@
+// Package service contém a lógica de negócio da aplicação.
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/pkg/notify"
+
+	"github.com/google/uuid"
+)
+
+// Erros do login por código de acesso.
+var (
+	ErrInvalidLoginCode = errors.New("código inválido ou expirado")
+	ErrLoginCodeChannel = errors.New("canal inválido: use email ou sms")
+)
+
+// Motivos para não enviar o código. Não chegam a quem pediu, para não revelar quais
+// e-mails têm conta nem o estado dela; ficam só no log do servidor.
+var (
+	errLoginCodeTooSoon  = errors.New("código pedido há pouco tempo")
+	errLoginCodeNoPhone  = errors.New("a conta não tem telefone cadastrado")
+	errLoginCodeNoSender = errors.New("envio de código por este canal não configurado")
+)
+
+// LoginCodeSenders entrega os códigos de acesso, um notificador por canal.
+type LoginCodeSenders struct {
+	Email notify.Notifier
+	SMS   notify.Notifier
+}
+
+// RequestLoginCodeRequest contém a conta e o canal para o envio do código.
+type RequestLoginCodeRequest struct {
+	Email   string `json:"email" binding:"required,email"`
+	Channel string `json:"channel"` // email (padrão) ou sms
+}
+
+// LoginWithCodeRequest contém a conta e o código recebido.
+type LoginWithCodeRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Code  string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// RequestLoginCode pede o envio de um código de 6 dígitos para entrar sem senha. Só o
+// canal é validado na requisição: a busca da conta, a emissão e o envio acontecem em
+// segundo plano, para que a resposta (inclusive o tempo dela) não revele quais e-mails
+// têm conta. E-mails desconhecidos, contas desativadas ou bloqueadas, pedidos repetidos
+// em pouco tempo e contas sem telefone para o SMS não recebem código; o motivo fica só
+// no log. Cada envio invalida os códigos anteriores.
+func (s *AuthService) RequestLoginCode(req RequestLoginCodeRequest) error {
+	channel := domain.LoginCodeChannel(req.Channel)
+	if channel == "" {
+		channel = domain.LoginCodeChannelEmail
+	}
+	if channel != domain.LoginCodeChannelEmail && channel != domain.LoginCodeChannelSMS {
+		return ErrLoginCodeChannel
+	}
+
+	s.background(func() {
+		user, err := s.userRepo.FindByEmail(req.Email)
+		if err != nil || !user.IsActive {
+			return
+		}
+		if err := s.sendLoginCode(user, channel); err != nil {
+			log.Printf("Código de acesso por %s não enviado para o usuário %s: %v", channel, user.ID, err)
+		}
+	})
+	return nil
+}
+
+// sendLoginCode emite e envia o código, ou devolve o motivo de não enviá-lo.
+func (s *AuthService) sendLoginCode(user *domain.User, channel domain.LoginCodeChannel) error {
+	now := time.Now()
+	if err := s.checkLockout(user.ID, now); err != nil {
+		return err
+	}
+
+	latest, err := s.loginCodeRepo.FindLatest(user.ID)
+	if err != nil && !errors.Is(err, domain.ErrLoginCodeNotFound) {
+		return err
+	}
+	if latest != nil && now.Sub(latest.CreatedAt) < s.codeConfig.ResendInterval {
+		return errLoginCodeTooSoon
+	}
+
+	sender, to, err := s.codeDestination(user, channel)
+	if err != nil {
+		return err
+	}
+
+	code, err := newLoginCode()
+	if err != nil {
+		return err
+	}
+	record := &domain.LoginCode{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Channel:   channel,
+		ExpiresAt: now.Add(s.codeConfig.TTL),
+	}
+	record.CodeHash = s.hashLoginCode(record.ID, code)
+	if err := s.loginCodeRepo.Create(record); err != nil {
+		return err
+	}
+
+	return sender.Send(context.Background(), notify.Message{
+		To:      to,
+		Name:    user.Name,
+		Subject: "Seu código de acesso",
+		Body: fmt.Sprintf("Seu código de acesso ao Amigos da Terceira Idade é %s. Ele vale por %d minutos. "+
+			"Não compartilhe este código com ninguém.", code, int(s.codeConfig.TTL/time.Minute)),
+	})
+}
+
+// LoginWithCode troca o código recebido pelos tokens, como no login com senha. Cada
+// código aceita poucas tentativas e falhas seguidas bloqueiam a conta por um tempo.
+func (s *AuthService) LoginWithCode(req LoginWithCodeRequest, client ClientInfo) (*AuthResponse, error) {
+	user, err := s.userRepo.FindByEmail(req.Email)
+	if err != nil {
+		return nil, ErrInvalidLoginCode
+	}
+
+	now := time.Now()
+	if err := s.checkLockout(user.ID, now); err != nil {
+		return nil, err
+	}
+
+	record, err := s.loginCodeRepo.FindLatest(user.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrLoginCodeNotFound) {
+			return nil, ErrInvalidLoginCode
+		}
+		return nil, err
+	}
+	if record.UsedAt != nil || !now.Before(record.ExpiresAt) {
+		return nil, ErrInvalidLoginCode
+	}
+
+	// A tentativa é reservada antes da comparação, para que tentativas simultâneas não
+	// passem do limite do código
+	claimed, err := s.loginCodeRepo.ClaimAttempt(record.ID, s.codeConfig.MaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrInvalidLoginCode
+	}
+	if !hmac.Equal([]byte(record.CodeHash), []byte(s.hashLoginCode(record.ID, req.Code))) {
+		if err := s.recordLoginFailure(record.UserID, now); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidLoginCode
+	}
+
+	consumed, err := s.loginCodeRepo.Consume(record.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, ErrInvalidLoginCode
+	}
+	if err := s.resetLockout(user.ID); err != nil {
+		return nil, err
+	}
+
+	if !user.IsActive {
+		return nil, ErrUserInactive
+	}
+	// Receber o código no e-mail também comprova que ele pertence ao usuário
+	if record.Channel == domain.LoginCodeChannelEmail && !user.IsEmailVerified() {
+		user.EmailVerifiedAt = &now
+		if err := s.userRepo.Update(user); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.generateAuthResponse(user, client)
+}
+
+// codeDestination escolhe o notificador e o destinatário do código conforme o canal.
+func (s *AuthService) codeDestination(user *domain.User, channel domain.LoginCodeChannel) (notify.Notifier, string, error) {
+	if channel == domain.LoginCodeChannelSMS {
+		if user.Phone == "" {
+			return nil, "", errLoginCodeNoPhone
+		}
+		if s.codeSenders.SMS == nil {
+			return nil, "", errLoginCodeNoSender
+		}
+		return s.codeSenders.SMS, user.Phone, nil
+	}
+	if s.codeSenders.Email == nil {
+		return nil, "", errLoginCodeNoSender
+	}
+	return s.codeSenders.Email, user.Email, nil
+}
+
+// hashLoginCode calcula o HMAC-SHA256 do código, ligado ao ID do registro.
+func (s *AuthService) hashLoginCode(id uuid.UUID, code string) string {
+	mac := hmac.New(sha256.New, []byte(s.jwtConfig.SecretKey))
+	mac.Write([]byte(id.String() + ":" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newLoginCode sorteia um código numérico de 6 dígitos.
+func newLoginCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
@@ -5,7 +5,9 @@ package service
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"amigos-terceira-idade/internal/config"
 	"amigos-terceira-idade/internal/domain"
@@ -28,23 +30,60 @@ func FixUUID(u uuid.UUID) uuid.UUID {
 	return b
 }
 
+// Erros da autenticação.
+var (
+	ErrInvalidToken       = errors.New("token inválido")
+	ErrInvalidCredentials = errors.New("credenciais inválidas")
+	ErrUserInactive       = errors.New("usuário desativado")
+	ErrSessionRevoked     = errors.New("sessão encerrada; faça login novamente")
+	ErrRefreshTokenReused = errors.New("refresh token já utilizado; a sessão foi encerrada por segurança")
+)
+
+// sessionTouchInterval é o intervalo mínimo entre duas gravações do último uso de uma
+// sessão, para não escrever no banco a cada requisição.
+const sessionTouchInterval = 5 * time.Minute
+
+// TokenType distingue o access token, usado nas requisições, do refresh token, usado
+// apenas para renovar a sessão.
+type TokenType string
+
+const (
+	TokenTypeAccess  TokenType = "access"
+	TokenTypeRefresh TokenType = "refresh"
+)
+
 // AuthService gerencia a autenticação e autorização de usuários.
 type AuthService struct {
-	userRepo     repository.UserRepositoryInterface
-	interestRepo repository.InterestRepositoryInterface
-	jwtConfig    config.JWTConfig
+	userRepo      repository.UserRepositoryInterface
+	interestRepo  repository.InterestRepositoryInterface
+	sessionRepo   repository.SessionRepositoryInterface
+	loginCodeRepo repository.LoginCodeRepositoryInterface
+	codeSenders   LoginCodeSenders
+	jwtConfig     config.JWTConfig
+	codeConfig    config.LoginCodeConfig
+	background    Background // Envia os códigos de acesso fora da requisição
 }
 
 // NewAuthService cria uma nova instância do serviço de autenticação.
 func NewAuthService(
 	userRepo repository.UserRepositoryInterface,
 	interestRepo repository.InterestRepositoryInterface,
+	sessionRepo repository.SessionRepositoryInterface,
+	loginCodeRepo repository.LoginCodeRepositoryInterface,
+	codeSenders LoginCodeSenders,
 	jwtConfig config.JWTConfig,
+	codeConfig config.LoginCodeConfig,
+	background Background,
 ) *AuthService {
 	return &AuthService{
-		userRepo:     userRepo,
-		interestRepo: interestRepo,
-		jwtConfig:    jwtConfig,
+		userRepo:      userRepo,
+		interestRepo:  interestRepo,
+		sessionRepo:   sessionRepo,
+		loginCodeRepo: loginCodeRepo,
+		codeSenders:   codeSenders,
+		jwtConfig:     jwtConfig,
+		codeConfig:    codeConfig,
+		background:    background,
 	}
 }
 
@@ -56,6 +95,7 @@ type RegisterRequest struct {
 	Age         int         `json:"age"`
 	Bio         string      `json:"bio"`
 	UserType    string      `json:"user_type" binding:"required"` // VOLUNTEER, ELDERLY, INSTITUTION
+	Timezone    string      `json:"timezone"`                     // Fuso IANA, padrão America/Sao_Paulo
 	InterestIDs []uuid.UUID `json:"interest_ids"`
 }
 
@@ -72,16 +112,44 @@ type AuthResponse struct {
 	User         *domain.User `json:"user"`
 }
 
+// ClientInfo identifica o dispositivo de onde vem a requisição.
+type ClientInfo struct {
+	Platform   string // Header X-Platform, ex.: android, ios, web
+	AppVersion string // Header X-App-Version
+	UserAgent  string
+	IPAddress  string
+}
+
+// DeviceName descreve o dispositivo para a lista de sessões do usuário.
+func (c ClientInfo) DeviceName() string {
+	if name := strings.TrimSpace(c.Platform + " " + c.AppVersion); name != "" {
+		return truncate(name, 255)
+	}
+	if c.UserAgent != "" {
+		return truncate(c.UserAgent, 255)
+	}
+	return "Dispositivo desconhecido"
+}
+
 // TokenClaims representa os dados contidos no JWT.
 type TokenClaims struct {
-	UserID   uuid.UUID       `json:"user_id"`
-	Email    string          `json:"email"`
-	UserType domain.UserType `json:"user_type"`
+	UserID    uuid.UUID       `json:"user_id"`
+	Email     string          `json:"email"`
+	UserType  domain.UserType `json:"user_type"`
+	Role      domain.Role     `json:"role,omitempty"`
+	Type      TokenType       `json:"typ"`
+	SessionID uuid.UUID       `json:"sid"`
+
+	// EmailVerified reflete o usuário na emissão; muda a partir do próximo login ou refresh
+	EmailVerified bool `json:"email_verified"`
+	// StaffInstitutionID é a instituição da equipe (papel INSTITUTION_STAFF)
+	StaffInstitutionID *uuid.UUID `json:"staff_institution_id,omitempty"`
+
 	jwt.RegisteredClaims
 }
 
 // Register realiza o cadastro de um novo usuário.
-func (s *AuthService) Register(req RegisterRequest) (*AuthResponse, error) {
+func (s *AuthService) Register(req RegisterRequest, client ClientInfo) (*AuthResponse, error) {
 	// Verifica se o email já está em uso
 	exists, err := s.userRepo.ExistsByEmail(req.Email)
 	if err != nil {
@@ -91,6 +159,15 @@ func (s *AuthService) Register(req RegisterRequest) (*AuthResponse, error) {
 		return nil, errors.New("email já está em uso")
 	}
 
+	// Valida o fuso horário, se informado
+	timezone := domain.DefaultTimezone
+	if req.Timezone != "" {
+		if err := domain.ValidateTimezone(req.Timezone); err != nil {
+			return nil, err
+		}
+		timezone = req.Timezone
+	}
+
 	// Gera o hash da senha
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -106,6 +183,7 @@ func (s *AuthService) Register(req RegisterRequest) (*AuthResponse, error) {
 		Age:          req.Age,
 		Bio:          req.Bio,
 		UserType:     domain.UserType(req.UserType),
+		Timezone:     timezone,
 		IsActive:     true,
 	}
 
@@ -129,73 +207,244 @@ func (s *AuthService) Register(req RegisterRequest) (*AuthResponse, error) {
 	}
 
 	// Gera os tokens
-	return s.generateAuthResponse(user)
+	return s.generateAuthResponse(user, client)
 }
 
-// Login realiza a autenticação de um usuário.
-func (s *AuthService) Login(req LoginRequest) (*AuthResponse, error) {
+// Login realiza a autenticação de um usuário e abre uma sessão para o dispositivo.
+func (s *AuthService) Login(req LoginRequest, client ClientInfo) (*AuthResponse, error) {
 	// Busca o usuário pelo email
 	user, err := s.userRepo.FindByEmail(req.Email)
 	if err != nil {
-		return nil, errors.New("credenciais inválidas")
+		return nil, ErrInvalidCredentials
+	}
+
+	// Contas bloqueadas são recusadas antes de comparar a senha
+	now := time.Now()
+	if err := s.checkLockout(user.ID, now); err != nil {
+		return nil, err
 	}
 
 	// Verifica a senha
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password))
 	if err != nil {
-		return nil, errors.New("credenciais inválidas")
+		if err := s.recordLoginFailure(user.ID, now); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
+	}
+	if err := s.resetLockout(user.ID); err != nil {
+		return nil, err
 	}
 
 	// Verifica se o usuário está ativo
 	if !user.IsActive {
-		return nil, errors.New("usuário desativado")
+		return nil, ErrUserInactive
 	}
 
 	// Gera os tokens
-	return s.generateAuthResponse(user)
+	return s.generateAuthResponse(user, client)
 }
 
-// ValidateToken valida um token JWT e retorna as claims.
+// ValidateToken valida um access token e retorna as claims. Rejeita refresh tokens,
+// tokens de sessões encerradas ou expiradas e de usuários desativados.
 func (s *AuthService) ValidateToken(tokenString string) (*TokenClaims, error) {
+	claims, _, err := s.validateAccessToken(tokenString)
+	return claims, err
+}
+
+// Authenticate valida o access token como ValidateToken e registra o uso da sessão pelo
+// dispositivo, no máximo uma vez a cada sessionTouchInterval ou quando o IP muda.
+func (s *AuthService) Authenticate(tokenString string, client ClientInfo) (*TokenClaims, error) {
+	claims, session, err := s.validateAccessToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if now.Sub(session.LastSeenAt) >= sessionTouchInterval || session.IPAddress != client.IPAddress {
+		if err := s.sessionRepo.Touch(session.ID, client.IPAddress, now); err != nil {
+			return nil, err
+		}
+	}
+
+	return claims, nil
+}
+
+// validateAccessToken valida o access token e retorna as claims e a sessão ativa.
+func (s *AuthService) validateAccessToken(tokenString string) (*TokenClaims, *domain.Session, error) {
+	claims, err := s.parseToken(tokenString, TokenTypeAccess)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	session, err := s.activeSession(claims.SessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// O papel vem do usuário da sessão, e não do token, para que uma mudança de papel
+	// valha já na próxima requisição
+	claims.Role = session.User.Role
+	claims.StaffInstitutionID = session.User.StaffInstitutionID
+
+	return claims, session, nil
+}
+
+// CheckSession verifica se a sessão continua ativa, para conexões longas que foram
+// autenticadas uma única vez, como o stream de eventos.
+func (s *AuthService) CheckSession(sessionID uuid.UUID) error {
+	_, err := s.activeSession(sessionID)
+	return err
+}
+
+// RefreshToken troca um refresh token válido por um novo par de tokens da mesma sessão.
+// Cada refresh token vale uma única vez: reapresentar um token já trocado indica que
+// ele vazou, e a sessão é encerrada.
+func (s *AuthService) RefreshToken(refreshToken string) (*AuthResponse, error) {
+	claims, err := s.parseToken(refreshToken, TokenTypeRefresh)
+	if err != nil {
+		return nil, err
+	}
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	session, err := s.activeSession(claims.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.RefreshTokenID != tokenID {
+		return nil, s.revokeReused(session.ID)
+	}
+
+	nextTokenID := uuid.New()
+	expiresAt := time.Now().Add(s.refreshTokenExpiry())
+	rotated, err := s.sessionRepo.Rotate(session.ID, tokenID, nextTokenID, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Outra renovação usou o mesmo token primeiro
+		return nil, s.revokeReused(session.ID)
+	}
+	session.RefreshTokenID = nextTokenID
+	session.ExpiresAt = expiresAt
+
+	return s.issueTokens(&session.User, session)
+}
+
+// Logout encerra a sessão do token usado na requisição.
+func (s *AuthService) Logout(sessionID uuid.UUID) error {
+	return s.sessionRepo.Revoke(sessionID, domain.SessionRevokedLogout)
+}
+
+// LogoutAll encerra todas as sessões do usuário, em todos os dispositivos.
+func (s *AuthService) LogoutAll(userID uuid.UUID) error {
+	return s.sessionRepo.RevokeAllByUser(userID, domain.SessionRevokedLogoutAll)
+}
+
+// ListSessions lista as sessões ativas do usuário, marcando a da requisição atual.
+func (s *AuthService) ListSessions(userID, currentSessionID uuid.UUID) ([]domain.Session, error) {
+	sessions, err := s.sessionRepo.FindActiveByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+// RevokeSession encerra uma sessão do próprio usuário, como a de um dispositivo
+// esquecido. Sessões de outros usuários são tratadas como inexistentes.
+func (s *AuthService) RevokeSession(userID, sessionID uuid.UUID) error {
+	session, err := s.sessionRepo.FindByID(sessionID)
+	if err != nil {
+		return err
+	}
+	if session.UserID != userID {
+		return domain.ErrSessionNotFound
+	}
+	if session.RevokedAt != nil {
+		return nil
+	}
+	return s.sessionRepo.Revoke(session.ID, domain.SessionRevokedByUser)
+}
+
+// parseToken valida a assinatura e a expiração do token e confere o seu tipo.
+func (s *AuthService) parseToken(tokenString string, tokenType TokenType) (*TokenClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &TokenClaims{}, func(token *jwt.Token) (interface{}, error) {
 		return []byte(s.jwtConfig.SecretKey), nil
-	})
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 
 	if err != nil {
-		return nil, errors.New("token inválido")
+		return nil, ErrInvalidToken
 	}
 
 	claims, ok := token.Claims.(*TokenClaims)
-	if !ok || !token.Valid {
-		return nil, errors.New("token inválido")
+	if !ok || !token.Valid || claims.Type != tokenType {
+		return nil, ErrInvalidToken
 	}
 
 	return claims, nil
 }
 
-// RefreshToken gera novos tokens a partir de um refresh token válido.
-func (s *AuthService) RefreshToken(refreshToken string) (*AuthResponse, error) {
-	claims, err := s.ValidateToken(refreshToken)
+// activeSession busca a sessão e confere se ela e o seu usuário continuam ativos.
+func (s *AuthService) activeSession(sessionID uuid.UUID) (*domain.Session, error) {
+	session, err := s.sessionRepo.FindByID(sessionID)
 	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			return nil, ErrInvalidToken
+		}
 		return nil, err
 	}
+	if !session.IsActive(time.Now()) {
+		return nil, ErrSessionRevoked
+	}
+	if !session.User.IsActive {
+		return nil, ErrUserInactive
+	}
+	return session, nil
+}
 
-	user, err := s.userRepo.FindByID(claims.UserID)
-	if err != nil {
+// revokeReused encerra a sessão cujo refresh token foi reapresentado.
+func (s *AuthService) revokeReused(sessionID uuid.UUID) error {
+	if err := s.sessionRepo.Revoke(sessionID, domain.SessionRevokedReuse); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// generateAuthResponse abre uma nova sessão para o usuário no dispositivo e gera os seus tokens.
+func (s *AuthService) generateAuthResponse(user *domain.User, client ClientInfo) (*AuthResponse, error) {
+	now := time.Now()
+	session := &domain.Session{
+		ID:             uuid.New(),
+		UserID:         user.ID,
+		RefreshTokenID: uuid.New(),
+		DeviceName:     client.DeviceName(),
+		UserAgent:      truncate(client.UserAgent, 500),
+		IPAddress:      client.IPAddress,
+		LastSeenAt:     now,
+		ExpiresAt:      now.Add(s.refreshTokenExpiry()),
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
 		return nil, err
 	}
 
-	return s.generateAuthResponse(user)
+	return s.issueTokens(user, session)
 }
 
-// generateAuthResponse gera os tokens de autenticação para um usuário.
-func (s *AuthService) generateAuthResponse(user *domain.User) (*AuthResponse, error) {
-	accessToken, err := s.generateToken(user, time.Duration(s.jwtConfig.AccessTokenExpiry)*time.Hour)
+// issueTokens gera o access token e o refresh token atual da sessão.
+func (s *AuthService) issueTokens(user *domain.User, session *domain.Session) (*AuthResponse, error) {
+	accessExpiry := time.Now().Add(time.Duration(s.jwtConfig.AccessTokenExpiry) * time.Hour)
+	accessToken, err := s.generateToken(user, TokenTypeAccess, session.ID, uuid.New(), accessExpiry)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := s.generateToken(user, time.Duration(s.jwtConfig.RefreshTokenExpiry)*24*time.Hour)
+	refreshToken, err := s.generateToken(user, TokenTypeRefresh, session.ID, session.RefreshTokenID, session.ExpiresAt)
 	if err != nil {
 		return nil, err
 	}
@@ -207,14 +456,21 @@ func (s *AuthService) generateAuthResponse(user *domain.User) (*AuthResponse, er
 	}, nil
 }
 
-// generateToken gera um token JWT com o tempo de expiração especificado.
-func (s *AuthService) generateToken(user *domain.User, expiry time.Duration) (string, error) {
+// generateToken gera um token JWT do tipo e da sessão informados.
+func (s *AuthService) generateToken(user *domain.User, tokenType TokenType, sessionID, tokenID uuid.UUID, expiresAt time.Time) (string, error) {
 	claims := TokenClaims{
-		UserID:   user.ID,
-		Email:    user.Email,
-		UserType: user.UserType,
+		UserID:    user.ID,
+		Email:     user.Email,
+		UserType:  user.UserType,
+		Role:      user.Role,
+		Type:      tokenType,
+		SessionID: sessionID,
+
+		EmailVerified:      user.IsEmailVerified(),
+		StaffInstitutionID: user.StaffInstitutionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
+			ID:        tokenID.String(),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
@@ -222,3 +478,19 @@ func (s *AuthService) generateToken(user *domain.User, expiry time.Duration) (st
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(s.jwtConfig.SecretKey))
 }
+
+// refreshTokenExpiry retorna a validade da sessão a partir da última renovação.
+func (s *AuthService) refreshTokenExpiry() time.Duration {
+	return time.Duration(s.jwtConfig.RefreshTokenExpiry) * 24 * time.Hour
+}
+
+// truncate corta o texto em até size bytes sem partir caracteres, para caber na coluna.
+func truncate(value string, size int) string {
+	if len(value) <= size {
+		return value
+	}
+	for size > 0 && !utf8.RuneStart(value[size]) {
+		size--
+	}
+	return value[:size]
+}
@@ -0,0 +1,431 @@
+// Package service contém a lógica de negócio da aplicação.
+package service
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// MaxSlotRange é o maior intervalo de datas aceito na busca de horários livres.
+const MaxSlotRange = 31 * 24 * time.Hour
+
+// AvailabilityService gerencia a disponibilidade dos voluntários, os horários livres
+// e as janelas de visita das instituições.
+type AvailabilityService struct {
+	availabilityRepo repository.AvailabilityRepositoryInterface
+	appointmentRepo  repository.AppointmentRepositoryInterface
+	userRepo         repository.UserRepositoryInterface
+}
+
+// NewAvailabilityService cria uma nova instância do serviço de disponibilidade.
+func NewAvailabilityService(
+	availabilityRepo repository.AvailabilityRepositoryInterface,
+	appointmentRepo repository.AppointmentRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+) *AvailabilityService {
+	return &AvailabilityService{
+		availabilityRepo: availabilityRepo,
+		appointmentRepo:  appointmentRepo,
+		userRepo:         userRepo,
+	}
+}
+
+// AvailabilityWindowRequest contém uma janela semanal de disponibilidade.
+type AvailabilityWindowRequest struct {
+	Weekday   time.Weekday `json:"weekday" binding:"min=0,max=6"` // 0 = domingo
+	StartTime string       `json:"start_time" binding:"required"` // HH:MM
+	EndTime   string       `json:"end_time" binding:"required"`   // HH:MM
+}
+
+// SetAvailabilityRequest contém todas as janelas semanais do voluntário.
+type SetAvailabilityRequest struct {
+	Windows []AvailabilityWindowRequest `json:"windows" binding:"dive"`
+}
+
+// AvailabilityExceptionRequest contém os dados de uma exceção de disponibilidade.
+type AvailabilityExceptionRequest struct {
+	Date      string `json:"date" binding:"required"` // YYYY-MM-DD
+	StartTime string `json:"start_time"`              // Vazio = dia inteiro
+	EndTime   string `json:"end_time"`
+	Available bool   `json:"available"` // true = janela extra; false = bloqueio
+	Reason    string `json:"reason"`
+}
+
+// VisitWindowRequest contém uma janela semanal de visitas de uma instituição.
+type VisitWindowRequest struct {
+	Weekday            time.Weekday `json:"weekday" binding:"min=0,max=6"` // 0 = domingo
+	StartTime          string       `json:"start_time" binding:"required"` // HH:MM
+	EndTime            string       `json:"end_time" binding:"required"`   // HH:MM
+	MaxDurationMinutes int          `json:"max_duration_minutes" binding:"min=0"`
+	MaxVisitors        int          `json:"max_visitors" binding:"min=0"`
+}
+
+// SetVisitWindowsRequest contém todas as janelas de visita da instituição.
+type SetVisitWindowsRequest struct {
+	Windows []VisitWindowRequest `json:"windows" binding:"dive"`
+}
+
+// TimeSlot representa um intervalo de tempo [Start, End).
+type TimeSlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// In retorna o horário expresso no fuso informado.
+func (t TimeSlot) In(loc *time.Location) TimeSlot {
+	return TimeSlot{Start: t.Start.In(loc), End: t.End.In(loc)}
+}
+
+// GetWeekly retorna as janelas semanais de um voluntário.
+func (s *AvailabilityService) GetWeekly(volunteerID uuid.UUID) ([]domain.VolunteerAvailability, error) {
+	return s.availabilityRepo.FindWeekly(volunteerID)
+}
+
+// SetWeekly substitui as janelas semanais do voluntário.
+// Uma lista vazia remove a disponibilidade declarada.
+func (s *AvailabilityService) SetWeekly(volunteerID uuid.UUID, req SetAvailabilityRequest) ([]domain.VolunteerAvailability, error) {
+	if _, err := s.requireVolunteer(volunteerID); err != nil {
+		return nil, err
+	}
+
+	windows := make([]domain.VolunteerAvailability, 0, len(req.Windows))
+	for _, w := range req.Windows {
+		if err := domain.ValidateClockRange(w.StartTime, w.EndTime); err != nil {
+			return nil, err
+		}
+		windows = append(windows, domain.VolunteerAvailability{
+			ID:          uuid.New(),
+			VolunteerID: volunteerID,
+			Weekday:     w.Weekday,
+			StartTime:   w.StartTime,
+			EndTime:     w.EndTime,
+		})
+	}
+
+	// Janelas do mesmo dia não podem se sobrepor
+	for i := range windows {
+		for j := i + 1; j < len(windows); j++ {
+			a, b := windows[i], windows[j]
+			if a.Weekday == b.Weekday && a.StartTime < b.EndTime && b.StartTime < a.EndTime {
+				return nil, errors.New("existem janelas sobrepostas no mesmo dia da semana")
+			}
+		}
+	}
+
+	if err := s.availabilityRepo.ReplaceWeekly(volunteerID, windows); err != nil {
+		return nil, err
+	}
+	return s.availabilityRepo.FindWeekly(volunteerID)
+}
+
+// GetExceptions retorna as exceções do voluntário entre duas datas.
+func (s *AvailabilityService) GetExceptions(volunteerID uuid.UUID, from, to time.Time) ([]domain.AvailabilityException, error) {
+	return s.availabilityRepo.FindExceptions(volunteerID, from, to)
+}
+
+// AddException registra uma exceção (bloqueio ou janela extra) em uma data.
+func (s *AvailabilityService) AddException(volunteerID uuid.UUID, req AvailabilityExceptionRequest) (*domain.AvailabilityException, error) {
+	if _, err := s.requireVolunteer(volunteerID); err != nil {
+		return nil, err
+	}
+
+	date, err := time.Parse(domain.DateLayout, req.Date)
+	if err != nil {
+		return nil, errors.New("data inválida (use AAAA-MM-DD)")
+	}
+
+	exception := &domain.AvailabilityException{
+		ID:          uuid.New(),
+		VolunteerID: volunteerID,
+		Date:        date,
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		Available:   req.Available,
+		Reason:      req.Reason,
+	}
+
+	// Uma janela extra precisa de horário; um bloqueio sem horário vale para o dia inteiro
+	if !exception.IsFullDay() || exception.Available {
+		if err := domain.ValidateClockRange(req.StartTime, req.EndTime); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.availabilityRepo.CreateException(exception); err != nil {
+		return nil, err
+	}
+	return exception, nil
+}
+
+// RemoveException remove uma exceção do voluntário.
+func (s *AvailabilityService) RemoveException(volunteerID, exceptionID uuid.UUID) error {
+	return s.availabilityRepo.DeleteException(volunteerID, exceptionID)
+}
+
+// GetSlots calcula os horários livres de um voluntário no intervalo [from, to):
+// disponibilidade semanal, ajustada pelas exceções, menos os agendamentos pendentes
+// ou confirmados. Cada horário tem a duração informada (padrão de 30 minutos).
+// Voluntários sem disponibilidade declarada não têm horários sugeridos.
+// As janelas são interpretadas no fuso do voluntário; os horários retornam em UTC.
+func (s *AvailabilityService) GetSlots(volunteerID uuid.UUID, from, to time.Time, durationMinutes int) ([]TimeSlot, error) {
+	if !from.Before(to) {
+		return nil, errors.New("o início do intervalo deve ser anterior ao fim")
+	}
+	if to.Sub(from) > MaxSlotRange {
+		return nil, errors.New("o intervalo máximo de busca é de 31 dias")
+	}
+	if durationMinutes <= 0 {
+		durationMinutes = domain.DefaultAppointmentDuration
+	}
+	if durationMinutes > domain.MaxAppointmentDuration {
+		return nil, errors.New("a duração máxima de uma conversa é de 4 horas")
+	}
+
+	volunteer, err := s.requireVolunteer(volunteerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Horários passados não são oferecidos
+	from, to = from.UTC(), to.UTC()
+	if now := time.Now().UTC(); from.Before(now) {
+		from = now
+	}
+	if !from.Before(to) {
+		return []TimeSlot{}, nil
+	}
+
+	free, _, err := freeIntervals(s.availabilityRepo, volunteerID, from, to, volunteer.Location())
+	if err != nil {
+		return nil, err
+	}
+
+	busy, err := s.appointmentRepo.FindOverlapping([]uuid.UUID{volunteerID}, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, appointment := range busy {
+		free = subtractInterval(free, TimeSlot{Start: appointment.Date, End: appointment.EndsAt()})
+	}
+
+	duration := time.Duration(durationMinutes) * time.Minute
+	slots := []TimeSlot{}
+	for _, interval := range free {
+		for start := interval.Start; !start.Add(duration).After(interval.End); start = start.Add(duration) {
+			slots = append(slots, TimeSlot{Start: start.UTC(), End: start.Add(duration).UTC()})
+		}
+	}
+	return slots, nil
+}
+
+// GetVisitWindows retorna as janelas de visita de uma instituição.
+func (s *AvailabilityService) GetVisitWindows(institutionID uuid.UUID) ([]domain.VisitWindow, error) {
+	return s.availabilityRepo.FindVisitWindows(institutionID)
+}
+
+// SetVisitWindows substitui as janelas de visita da instituição.
+// Uma lista vazia remove as restrições de horário de visita.
+func (s *AvailabilityService) SetVisitWindows(institutionID uuid.UUID, req SetVisitWindowsRequest) ([]domain.VisitWindow, error) {
+	user, err := s.userRepo.FindByID(institutionID)
+	if err != nil {
+		return nil, err
+	}
+	if user.UserType != domain.UserTypeInstitution {
+		return nil, errors.New("apenas instituições possuem janelas de visita")
+	}
+
+	windows := make([]domain.VisitWindow, 0, len(req.Windows))
+	for _, w := range req.Windows {
+		if err := domain.ValidateClockRange(w.StartTime, w.EndTime); err != nil {
+			return nil, err
+		}
+		if w.MaxDurationMinutes > domain.MaxAppointmentDuration {
+			return nil, errors.New("a duração máxima de uma visita é de 4 horas")
+		}
+		windows = append(windows, domain.VisitWindow{
+			ID:                 uuid.New(),
+			InstitutionID:      institutionID,
+			Weekday:            w.Weekday,
+			StartTime:          w.StartTime,
+			EndTime:            w.EndTime,
+			MaxDurationMinutes: w.MaxDurationMinutes,
+			MaxVisitors:        w.MaxVisitors,
+		})
+	}
+
+	// Janelas do mesmo dia não podem se sobrepor
+	for i := range windows {
+		for j := i + 1; j < len(windows); j++ {
+			a, b := windows[i], windows[j]
+			if a.Weekday == b.Weekday && a.StartTime < b.EndTime && b.StartTime < a.EndTime {
+				return nil, errors.New("existem janelas sobrepostas no mesmo dia da semana")
+			}
+		}
+	}
+
+	if err := s.availabilityRepo.ReplaceVisitWindows(institutionID, windows); err != nil {
+		return nil, err
+	}
+	return s.availabilityRepo.FindVisitWindows(institutionID)
+}
+
+// UserLocation retorna o fuso horário do usuário, usado para interpretar datas
+// sem horário e exibir os horários livres no fuso de quem consulta.
+func (s *AvailabilityService) UserLocation(userID uuid.UUID) (*time.Location, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return user.Location(), nil
+}
+
+// requireVolunteer garante que o usuário existe e é voluntário.
+func (s *AvailabilityService) requireVolunteer(userID uuid.UUID) (*domain.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.UserType != domain.UserTypeVolunteer {
+		return nil, errors.New("apenas voluntários possuem disponibilidade")
+	}
+	return user, nil
+}
+
+// freeIntervals calcula os intervalos disponíveis de um voluntário em [from, to),
+// interpretando os horários das janelas no fuso informado.
+// declared é false quando o voluntário não declarou nenhuma janela semanal.
+func freeIntervals(
+	availabilityRepo repository.AvailabilityRepositoryInterface,
+	volunteerID uuid.UUID,
+	from, to time.Time,
+	loc *time.Location,
+) (intervals []TimeSlot, declared bool, err error) {
+	weekly, err := availabilityRepo.FindWeekly(volunteerID)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(weekly) == 0 {
+		return nil, false, nil
+	}
+
+	from, to = from.In(loc), to.In(loc)
+	exceptions, err := availabilityRepo.FindExceptions(volunteerID, from, to)
+	if err != nil {
+		return nil, true, err
+	}
+
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		var dayIntervals []TimeSlot
+		var blocks []TimeSlot
+		fullDayBlocked := false
+
+		for _, e := range exceptions {
+			if e.Date.Format(domain.DateLayout) != day.Format(domain.DateLayout) {
+				continue
+			}
+			switch {
+			case e.Available:
+				dayIntervals = append(dayIntervals, clockInterval(day, e.StartTime, e.EndTime))
+			case e.IsFullDay():
+				fullDayBlocked = true
+			default:
+				blocks = append(blocks, clockInterval(day, e.StartTime, e.EndTime))
+			}
+		}
+
+		if !fullDayBlocked {
+			for _, w := range weekly {
+				if w.Weekday == day.Weekday() {
+					dayIntervals = append(dayIntervals, clockInterval(day, w.StartTime, w.EndTime))
+				}
+			}
+		}
+
+		for _, block := range blocks {
+			dayIntervals = subtractInterval(dayIntervals, block)
+		}
+		intervals = append(intervals, dayIntervals...)
+	}
+
+	// Recorta no intervalo pedido e junta janelas contíguas
+	var clipped []TimeSlot
+	for _, interval := range mergeIntervals(intervals) {
+		if interval.Start.Before(from) {
+			interval.Start = from
+		}
+		if interval.End.After(to) {
+			interval.End = to
+		}
+		if interval.Start.Before(interval.End) {
+			clipped = append(clipped, interval)
+		}
+	}
+	return clipped, true, nil
+}
+
+// clockInterval monta o intervalo de um dia a partir de horários HH:MM já validados.
+func clockInterval(day time.Time, start, end string) TimeSlot {
+	startMinute, _ := domain.ParseClock(start)
+	endMinute, _ := domain.ParseClock(end)
+	return TimeSlot{
+		Start: time.Date(day.Year(), day.Month(), day.Day(), 0, startMinute, 0, 0, day.Location()),
+		End:   time.Date(day.Year(), day.Month(), day.Day(), 0, endMinute, 0, 0, day.Location()),
+	}
+}
+
+// mergeIntervals ordena e une intervalos sobrepostos ou contíguos.
+func mergeIntervals(intervals []TimeSlot) []TimeSlot {
+	if len(intervals) == 0 {
+		return nil
+	}
+	sorted := append([]TimeSlot(nil), intervals...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	merged := []TimeSlot{sorted[0]}
+	for _, interval := range sorted[1:] {
+		last := &merged[len(merged)-1]
+		if !interval.Start.After(last.End) {
+			if interval.End.After(last.End) {
+				last.End = interval.End
+			}
+			continue
+		}
+		merged = append(merged, interval)
+	}
+	return merged
+}
+
+// subtractInterval remove o trecho ocupado de cada intervalo livre.
+func subtractInterval(intervals []TimeSlot, busy TimeSlot) []TimeSlot {
+	var result []TimeSlot
+	for _, interval := range intervals {
+		if !busy.Start.Before(interval.End) || !busy.End.After(interval.Start) {
+			result = append(result, interval)
+			continue
+		}
+		if interval.Start.Before(busy.Start) {
+			result = append(result, TimeSlot{Start: interval.Start, End: busy.Start})
+		}
+		if busy.End.Before(interval.End) {
+			result = append(result, TimeSlot{Start: busy.End, End: interval.End})
+		}
+	}
+	return result
+}
+
+// coversInterval verifica se algum dos intervalos contém [start, end) por inteiro.
+func coversInterval(intervals []TimeSlot, start, end time.Time) bool {
+	for _, interval := range intervals {
+		if !start.Before(interval.Start) && !end.After(interval.End) {
+			return true
+		}
+	}
+	return false
+}
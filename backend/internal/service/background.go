@@ -0,0 +1,24 @@
+// Package service contém a lógica de negócio da aplicação.
+package service
+
+import (
+	"log"
+	"runtime/debug"
+)
+
+// Background executa tarefas fora da requisição, para que a resposta não dependa delas
+// nem do tempo que levam, como nos envios que não podem revelar se uma conta existe.
+type Background func(task func())
+
+// RunInBackground executa cada tarefa em uma goroutine própria; um panic na tarefa é
+// registrado no log em vez de derrubar o servidor.
+func RunInBackground(task func()) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("Tarefa em segundo plano falhou: %v\n%s", r, debug.Stack())
+			}
+		}()
+		task()
+	}()
+}
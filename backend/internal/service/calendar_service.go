@@ -0,0 +1,190 @@
+// Package service contém a lógica de negócio da aplicação.
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+	"amigos-terceira-idade/pkg/ical"
+
+	"github.com/google/uuid"
+)
+
+// Identificação dos calendários gerados pela aplicação.
+const (
+	calendarProdID    = "-//Amigos da Terceira Idade//Agenda//PT-BR"
+	calendarUIDDomain = "amigos-terceira-idade"
+)
+
+// CalendarService exporta agendamentos no formato iCalendar (.ics), tanto para
+// download avulso quanto como feed de assinatura protegido por token secreto.
+type CalendarService struct {
+	appointmentRepo repository.AppointmentRepositoryInterface
+	feedRepo        repository.CalendarFeedRepositoryInterface
+}
+
+// NewCalendarService cria uma nova instância do serviço de calendário.
+func NewCalendarService(
+	appointmentRepo repository.AppointmentRepositoryInterface,
+	feedRepo repository.CalendarFeedRepositoryInterface,
+) *CalendarService {
+	return &CalendarService{
+		appointmentRepo: appointmentRepo,
+		feedRepo:        feedRepo,
+	}
+}
+
+// AppointmentICS gera o .ics de um único agendamento para um dos participantes.
+// Agendamentos cancelados são exportados com METHOD:CANCEL, removendo o evento já importado.
+func (s *CalendarService) AppointmentICS(appointmentID uuid.UUID, userID uuid.UUID) (*ical.Calendar, error) {
+	appointment, err := s.appointmentRepo.FindByID(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !appointment.IsParticipant(userID) {
+		return nil, ErrNotAppointmentParticipant
+	}
+
+	method := ical.MethodPublish
+	if appointment.Status == domain.AppointmentStatusCancelled {
+		method = ical.MethodCancel
+	}
+
+	return &ical.Calendar{
+		ProdID: calendarProdID,
+		Method: method,
+		Events: []ical.Event{appointmentEvent(appointment, userID)},
+	}, nil
+}
+
+// GetFeed retorna o feed de calendário do usuário, gerando o token na primeira vez.
+func (s *CalendarService) GetFeed(userID uuid.UUID) (*domain.CalendarFeed, error) {
+	token, err := newFeedToken()
+	if err != nil {
+		return nil, err
+	}
+	return s.feedRepo.FindOrCreate(userID, token)
+}
+
+// RotateFeed troca o token do feed; o endereço anterior deixa de funcionar.
+func (s *CalendarService) RotateFeed(userID uuid.UUID) (*domain.CalendarFeed, error) {
+	feed, err := s.GetFeed(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := newFeedToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.feedRepo.UpdateToken(userID, token); err != nil {
+		return nil, err
+	}
+
+	feed.Token = token
+	return feed, nil
+}
+
+// FeedICS gera o feed de assinatura com os próximos agendamentos confirmados do dono do token.
+// Agendamentos futuros cancelados continuam no feed com STATUS:CANCELLED e SEQUENCE maior,
+// para que os aplicativos removam os eventos já sincronizados.
+func (s *CalendarService) FeedICS(token string) (*ical.Calendar, error) {
+	feed, err := s.feedRepo.FindByToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	upcoming, err := s.appointmentRepo.FindUpcoming(feed.UserID)
+	if err != nil {
+		return nil, err
+	}
+	cancelled, err := s.appointmentRepo.FindUpcomingCancelled(feed.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]ical.Event, 0, len(upcoming)+len(cancelled))
+	for i := range upcoming {
+		events = append(events, appointmentEvent(&upcoming[i], feed.UserID))
+	}
+	for i := range cancelled {
+		events = append(events, appointmentEvent(&cancelled[i], feed.UserID))
+	}
+
+	return &ical.Calendar{
+		ProdID: calendarProdID,
+		Method: ical.MethodPublish,
+		Name:   "Amigos da Terceira Idade",
+		Events: events,
+	}, nil
+}
+
+// appointmentEvent converte o agendamento em VEVENT do ponto de vista do usuário.
+// O UID é derivado do ID do agendamento, então é o mesmo em todas as exportações.
+func appointmentEvent(appointment *domain.Appointment, userID uuid.UUID) ical.Event {
+	other := appointment.Target
+	if appointment.TargetID == userID {
+		other = appointment.Volunteer
+	}
+
+	var description []string
+	if appointment.MeetingURL != "" {
+		description = append(description, "Link da conversa: "+appointment.MeetingURL)
+	}
+	if appointment.Notes != "" {
+		description = append(description, appointment.Notes)
+	}
+
+	summary := "Conversa - Amigos da Terceira Idade"
+	if other.Name != "" {
+		summary = "Conversa com " + other.Name
+	}
+
+	event := ical.Event{
+		UID:         fmt.Sprintf("%s@%s", appointment.ID, calendarUIDDomain),
+		Sequence:    appointment.CalendarSequence,
+		Stamp:       appointment.UpdatedAt,
+		Start:       appointment.Date,
+		End:         appointment.EndsAt(),
+		Summary:     summary,
+		Description: strings.Join(description, "\n\n"),
+		Location:    appointment.MeetingURL,
+		URL:         appointment.MeetingURL,
+		Status:      eventStatus(appointment.Status),
+	}
+	for _, participant := range []domain.User{appointment.Volunteer, appointment.Target} {
+		if participant.Email != "" {
+			event.Attendees = append(event.Attendees, ical.Attendee{Name: participant.Name, Email: participant.Email})
+		}
+	}
+	if appointment.Volunteer.Email != "" {
+		event.Organizer = &event.Attendees[0]
+	}
+	return event
+}
+
+// eventStatus mapeia o status do agendamento para o STATUS do evento.
+func eventStatus(status domain.AppointmentStatus) string {
+	switch status {
+	case domain.AppointmentStatusConfirmed:
+		return ical.StatusConfirmed
+	case domain.AppointmentStatusCancelled:
+		return ical.StatusCancelled
+	default:
+		return ical.StatusTentative
+	}
+}
+
+// newFeedToken gera um token aleatório de 256 bits.
+func newFeedToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,82 @@
+// Package service contém a lógica de negócio da aplicação.
+package service
+
+import (
+	"errors"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// ErrOutsideAvailability indica um horário fora da disponibilidade declarada pelo voluntário.
+var ErrOutsideAvailability = errors.New("o voluntário não está disponível neste horário")
+
+// ErrOutsideVisitWindow indica um horário fora das janelas de visita da instituição.
+var ErrOutsideVisitWindow = errors.New("a instituição não recebe visitas neste horário")
+
+// ErrVisitCapacityReached indica que a instituição já tem o máximo de visitantes no horário.
+var ErrVisitCapacityReached = errors.New("a instituição já atingiu o limite de visitantes neste horário")
+
+// ErrNotConnectionTarget indica que apenas o destinatário do pedido pode responder à conexão.
+var ErrNotConnectionTarget = errors.New("apenas o destinatário pode responder a esta conexão")
+
+// ErrNotAppointmentParticipant indica que o usuário não participa do agendamento.
+var ErrNotAppointmentParticipant = errors.New("você não pode ver este agendamento")
+
+// notParticipantError recusa uma ação a quem não participa do agendamento (ou não tem
+// nele o papel exigido), com uma mensagem própria da ação; corresponde, em errors.Is,
+// a ErrNotAppointmentParticipant.
+type notParticipantError string
+
+// Error implementa a interface error.
+func (e notParticipantError) Error() string {
+	return string(e)
+}
+
+// Is faz o erro corresponder a ErrNotAppointmentParticipant.
+func (e notParticipantError) Is(target error) bool {
+	return target == ErrNotAppointmentParticipant
+}
+
+// ErrInvalidMeetingURL indica um link de reunião que não é um endereço http(s) completo.
+var ErrInvalidMeetingURL = errors.New("link da reunião inválido: use um endereço http(s) completo")
+
+// ScheduleConflict descreve um agendamento que ocupa o horário solicitado.
+// Não expõe dados do outro participante, apenas o horário ocupado.
+type ScheduleConflict struct {
+	AppointmentID   uuid.UUID                `json:"appointment_id"`
+	UserID          uuid.UUID                `json:"user_id"` // Participante (ou residente) com o horário ocupado
+	Date            time.Time                `json:"date"`
+	DurationMinutes int                      `json:"duration_minutes"`
+	Status          domain.AppointmentStatus `json:"status"`
+}
+
+// ConflictError indica que o horário solicitado se sobrepõe a outros agendamentos.
+type ConflictError struct {
+	Conflicts []ScheduleConflict
+}
+
+// Error implementa a interface error.
+func (e *ConflictError) Error() string {
+	return "o horário conflita com outros agendamentos"
+}
+
+// AccountLockedError indica que a conta está bloqueada por falhas seguidas de login.
+type AccountLockedError struct {
+	Until time.Time
+}
+
+// Error implementa a interface error.
+func (e *AccountLockedError) Error() string {
+	return "muitas tentativas sem sucesso; a conta está bloqueada temporariamente"
+}
+
+// RetryAfter retorna quanto falta para o bloqueio terminar.
+func (e *AccountLockedError) RetryAfter(now time.Time) time.Duration {
+	if wait := e.Until.Sub(now); wait > 0 {
+		return wait
+	}
+	return 0
+}
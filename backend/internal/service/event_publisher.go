@@ -0,0 +1,36 @@
+// Package service contém a lógica de negócio da aplicação.
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/pkg/pubsub"
+)
+
+// HubPublisher publica os eventos do outbox no hub em memória do stream SSE,
+// com uma chave por usuário afetado.
+type HubPublisher struct {
+	hub *pubsub.Hub
+}
+
+// NewHubPublisher cria um publicador sobre o hub informado.
+func NewHubPublisher(hub *pubsub.Hub) *HubPublisher {
+	return &HubPublisher{hub: hub}
+}
+
+// Handle serializa o evento e o entrega aos usuários afetados que estiverem conectados.
+func (p *HubPublisher) Handle(ctx context.Context, event domain.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(event.UserIDs))
+	for _, userID := range event.UserIDs {
+		keys = append(keys, userID.String())
+	}
+	p.hub.Publish(string(event.Type), data, keys...)
+	return nil
+}
@@ -10,32 +10,53 @@ import (
 	"github.com/google/uuid"
 )
 
+// MatchingOptions configura as regras do pareamento.
+type MatchingOptions struct {
+	// RequireVerifiedVolunteers impede que voluntários sem verificação aprovada
+	// busquem sugestões ou peçam conexões.
+	RequireVerifiedVolunteers bool
+}
+
 // MatchingService gerencia o pareamento entre voluntários e idosos/instituições.
 type MatchingService struct {
-	userRepo       repository.UserRepositoryInterface
-	connectionRepo repository.ConnectionRepositoryInterface
+	userRepo         repository.UserRepositoryInterface
+	connectionRepo   repository.ConnectionRepositoryInterface
+	residentRepo     repository.ResidentRepositoryInterface
+	verificationRepo repository.VerificationRepositoryInterface
+	notifications    NotificationSender
+	options          MatchingOptions
 }
 
 // NewMatchingService cria uma nova instância do serviço de pareamento.
 func NewMatchingService(
 	userRepo repository.UserRepositoryInterface,
 	connectionRepo repository.ConnectionRepositoryInterface,
+	residentRepo repository.ResidentRepositoryInterface,
+	verificationRepo repository.VerificationRepositoryInterface,
+	notifications NotificationSender,
+	options MatchingOptions,
 ) *MatchingService {
 	return &MatchingService{
-		userRepo:       userRepo,
-		connectionRepo: connectionRepo,
+		userRepo:         userRepo,
+		connectionRepo:   connectionRepo,
+		residentRepo:     residentRepo,
+		verificationRepo: verificationRepo,
+		notifications:    notifications,
+		options:          options,
 	}
 }
 
 // MatchSuggestion representa uma sugestão de pareamento com score de compatibilidade.
+// Nas sugestões de residentes, Resident é preenchido e User é a instituição responsável.
 type MatchSuggestion struct {
-	User             domain.User `json:"user"`
-	MatchedInterests int         `json:"matched_interests"` // Quantidade de interesses em comum
-	MatchScore       float64     `json:"match_score"`       // Porcentagem de match (0-100)
+	User             domain.User      `json:"user"`
+	Resident         *domain.Resident `json:"resident,omitempty"`
+	MatchedInterests int              `json:"matched_interests"` // Quantidade de interesses em comum
+	MatchScore       float64          `json:"match_score"`       // Porcentagem de match (0-100)
 }
 
-// GetSuggestions retorna sugestões de pareamento para um voluntário.
-// Ordena por quantidade de interesses em comum.
+// GetSuggestions retorna sugestões de pareamento para um voluntário, incluindo os
+// residentes das instituições. Ordena por quantidade de interesses em comum.
 func (s *MatchingService) GetSuggestions(volunteerID uuid.UUID, filterType string) ([]MatchSuggestion, error) {
 	// Busca o voluntário para obter seus interesses
 	volunteer, err := s.userRepo.FindByID(volunteerID)
@@ -46,16 +67,23 @@ func (s *MatchingService) GetSuggestions(volunteerID uuid.UUID, filterType strin
 	if volunteer.UserType != domain.UserTypeVolunteer {
 		return nil, errors.New("apenas voluntários podem buscar conexões")
 	}
+	if err := s.checkVerified(volunteerID); err != nil {
+		return nil, err
+	}
 
 	// Define o tipo de usuário a buscar
 	var targetTypes []domain.UserType
+	includeResidents := false
 	switch filterType {
 	case "elderly":
 		targetTypes = []domain.UserType{domain.UserTypeElderly}
 	case "institution":
 		targetTypes = []domain.UserType{domain.UserTypeInstitution}
+	case "resident":
+		includeResidents = true
 	default:
 		targetTypes = []domain.UserType{domain.UserTypeElderly, domain.UserTypeInstitution}
+		includeResidents = true
 	}
 
 	// Busca os usuários dos tipos especificados
@@ -90,12 +118,56 @@ func (s *MatchingService) GetSuggestions(volunteerID uuid.UUID, filterType strin
 		}
 	}
 
+	if includeResidents {
+		residents, err := s.residentRepo.FindActive()
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range residents {
+			resident := &residents[i]
+			exists, _ := s.connectionRepo.ExistsForResident(volunteerID, resident.ID)
+			if exists {
+				continue
+			}
+
+			matchedCount := s.countMatchedInterests(volunteer.Interests, resident.Interests)
+			var matchScore float64
+			if len(volunteer.Interests) > 0 {
+				matchScore = float64(matchedCount) / float64(len(volunteer.Interests)) * 100
+			}
+
+			suggestions = append(suggestions, MatchSuggestion{
+				User:             resident.Institution,
+				Resident:         resident,
+				MatchedInterests: matchedCount,
+				MatchScore:       matchScore,
+			})
+		}
+	}
+
 	// Ordena por quantidade de matches (maior primeiro)
 	s.sortByMatchScore(suggestions)
 
 	return suggestions, nil
 }
 
+// checkVerified retorna ErrVolunteerNotVerified se a plataforma exige verificação
+// e a do voluntário não está aprovada.
+func (s *MatchingService) checkVerified(volunteerID uuid.UUID) error {
+	if !s.options.RequireVerifiedVolunteers {
+		return nil
+	}
+	volunteer, err := s.verificationRepo.FindVolunteer(volunteerID)
+	if err != nil {
+		return err
+	}
+	if !volunteer.IsVerified {
+		return ErrVolunteerNotVerified
+	}
+	return nil
+}
+
 // countMatchedInterests conta quantos interesses são comuns entre duas listas.
 func (s *MatchingService) countMatchedInterests(interests1, interests2 []domain.Interest) int {
 	interestMap := make(map[uuid.UUID]bool)
@@ -143,6 +215,9 @@ func (s *MatchingService) Connect(volunteerID, targetID uuid.UUID) (*domain.Conn
 	if volunteer.UserType != domain.UserTypeVolunteer {
 		return nil, errors.New("apenas voluntários podem iniciar conexões")
 	}
+	if err := s.checkVerified(volunteerID); err != nil {
+		return nil, err
+	}
 
 	target, err := s.userRepo.FindByID(targetID)
 	if err != nil {
@@ -169,6 +244,72 @@ func (s *MatchingService) Connect(volunteerID, targetID uuid.UUID) (*domain.Conn
 		return nil, err
 	}
 
+	sendNotification(s.notifications, &domain.Notification{
+		UserID:       targetID,
+		Type:         domain.NotificationConnectionRequested,
+		Title:        "Novo pedido de conexão",
+		Body:         volunteer.Name + " quer se conectar com você.",
+		ActorID:      &volunteerID,
+		ConnectionID: &connection.ID,
+	})
+
+	return connection, nil
+}
+
+// ConnectResident cria um pedido de conexão entre um voluntário e um residente.
+// A conexão tem a instituição como destinatária: é ela quem aceita ou recusa o pedido
+// em nome do residente.
+func (s *MatchingService) ConnectResident(volunteerID, residentID uuid.UUID) (*domain.Connection, error) {
+	exists, err := s.connectionRepo.ExistsForResident(volunteerID, residentID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("conexão já existe")
+	}
+
+	volunteer, err := s.userRepo.FindByID(volunteerID)
+	if err != nil {
+		return nil, err
+	}
+	if volunteer.UserType != domain.UserTypeVolunteer {
+		return nil, errors.New("apenas voluntários podem iniciar conexões")
+	}
+	if err := s.checkVerified(volunteerID); err != nil {
+		return nil, err
+	}
+
+	resident, err := s.residentRepo.FindByID(residentID)
+	if err != nil {
+		return nil, err
+	}
+	if !resident.IsActive {
+		return nil, domain.ErrResidentNotFound
+	}
+
+	connection := &domain.Connection{
+		ID:               uuid.New(),
+		VolunteerID:      volunteerID,
+		TargetID:         resident.InstitutionID,
+		TargetType:       domain.UserTypeInstitution,
+		ResidentID:       &resident.ID,
+		Status:           domain.ConnectionStatusPending,
+		MatchedInterests: s.countMatchedInterests(volunteer.Interests, resident.Interests),
+	}
+
+	if err := s.connectionRepo.Create(connection); err != nil {
+		return nil, err
+	}
+
+	sendNotification(s.notifications, &domain.Notification{
+		UserID:       resident.InstitutionID,
+		Type:         domain.NotificationConnectionRequested,
+		Title:        "Novo pedido de conexão",
+		Body:         volunteer.Name + " quer se conectar com o residente " + resident.Name + ".",
+		ActorID:      &volunteerID,
+		ConnectionID: &connection.ID,
+	})
+
 	return connection, nil
 }
 
@@ -185,12 +326,60 @@ func (s *MatchingService) GetConnections(userID uuid.UUID) ([]domain.Connection,
 	return s.connectionRepo.FindByTargetID(userID)
 }
 
-// AcceptConnection aceita uma conexão pendente.
-func (s *MatchingService) AcceptConnection(connectionID uuid.UUID) error {
-	return s.connectionRepo.UpdateStatus(connectionID, domain.ConnectionStatusAccepted)
+// AcceptConnection aceita uma conexão pendente e avisa o voluntário.
+// Apenas o destinatário do pedido (TargetID) pode aceitar.
+func (s *MatchingService) AcceptConnection(connectionID, userID uuid.UUID) error {
+	connection, err := s.findPendingConnection(connectionID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.connectionRepo.UpdateStatus(connectionID, domain.ConnectionStatusAccepted); err != nil {
+		return err
+	}
+
+	sendNotification(s.notifications, &domain.Notification{
+		UserID:       connection.VolunteerID,
+		Type:         domain.NotificationConnectionAccepted,
+		Title:        "Conexão aceita",
+		Body:         acceptedConnectionBody(connection),
+		ActorID:      &connection.TargetID,
+		ConnectionID: &connection.ID,
+	})
+	return nil
+}
+
+// acceptedConnectionBody monta o aviso de conexão aceita, indicando o residente
+// quando a instituição aceitou em nome dele.
+func acceptedConnectionBody(connection *domain.Connection) string {
+	if connection.Resident != nil {
+		return connection.Target.Name + " aceitou seu pedido de conexão com " + connection.Resident.Name + "."
+	}
+	return connection.Target.Name + " aceitou seu pedido de conexão."
 }
 
 // RejectConnection rejeita uma conexão pendente.
-func (s *MatchingService) RejectConnection(connectionID uuid.UUID) error {
+// Apenas o destinatário do pedido (TargetID) pode rejeitar.
+func (s *MatchingService) RejectConnection(connectionID, userID uuid.UUID) error {
+	if _, err := s.findPendingConnection(connectionID, userID); err != nil {
+		return err
+	}
 	return s.connectionRepo.UpdateStatus(connectionID, domain.ConnectionStatusRejected)
 }
+
+// findPendingConnection busca a conexão e verifica se o usuário pode respondê-la.
+// Retorna domain.ErrConnectionNotFound, ErrNotConnectionTarget ou domain.ErrConnectionNotPending.
+func (s *MatchingService) findPendingConnection(connectionID, userID uuid.UUID) (*domain.Connection, error) {
+	connection, err := s.connectionRepo.FindByID(connectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if connection.TargetID != userID {
+		return nil, ErrNotConnectionTarget
+	}
+	if connection.Status != domain.ConnectionStatusPending {
+		return nil, domain.ErrConnectionNotPending
+	}
+	return connection, nil
+}
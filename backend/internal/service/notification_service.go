@@ -0,0 +1,174 @@
+// Package service contém a lógica de negócio da aplicação.
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// DefaultNotificationPageSize e MaxNotificationPageSize controlam a paginação da caixa de entrada.
+const (
+	DefaultNotificationPageSize = 20
+	MaxNotificationPageSize     = 100
+)
+
+// NotificationSender registra notificações para os usuários.
+// AppointmentService, MatchingService e ReminderService dependem apenas desta interface.
+type NotificationSender interface {
+	Notify(notification *domain.Notification) error
+}
+
+// NotificationService gerencia a caixa de entrada de notificações e as preferências.
+type NotificationService struct {
+	notificationRepo repository.NotificationRepositoryInterface
+}
+
+// NewNotificationService cria uma nova instância do serviço de notificações.
+func NewNotificationService(notificationRepo repository.NotificationRepositoryInterface) *NotificationService {
+	return &NotificationService{notificationRepo: notificationRepo}
+}
+
+// Garante que o serviço pode ser usado como NotificationSender
+var _ NotificationSender = (*NotificationService)(nil)
+
+// NotificationPage é uma página da caixa de entrada.
+type NotificationPage struct {
+	Notifications []domain.Notification
+	Page          int
+	PerPage       int
+	Total         int64
+}
+
+// UnreadCount resume as notificações não lidas, no total e por tipo.
+type UnreadCount struct {
+	Total  int64                             `json:"total"`
+	ByType map[domain.NotificationType]int64 `json:"by_type"`
+}
+
+// UpdatePreferencesRequest ativa ou desativa tipos de notificação.
+type UpdatePreferencesRequest struct {
+	Preferences map[domain.NotificationType]bool `json:"preferences" binding:"required"`
+}
+
+// Notify registra a notificação, a menos que o usuário tenha desativado o tipo.
+func (s *NotificationService) Notify(notification *domain.Notification) error {
+	enabled, err := s.isEnabled(notification.UserID, notification.Type)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return nil
+	}
+	return s.notificationRepo.Create(notification)
+}
+
+// List retorna uma página das notificações do usuário, das mais recentes para as mais antigas.
+func (s *NotificationService) List(userID uuid.UUID, unreadOnly bool, page, perPage int) (*NotificationPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = DefaultNotificationPageSize
+	}
+	if perPage > MaxNotificationPageSize {
+		perPage = MaxNotificationPageSize
+	}
+
+	notifications, total, err := s.notificationRepo.FindByUser(userID, unreadOnly, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+	return &NotificationPage{Notifications: notifications, Page: page, PerPage: perPage, Total: total}, nil
+}
+
+// GetUnreadCount retorna a quantidade de notificações não lidas.
+func (s *NotificationService) GetUnreadCount(userID uuid.UUID) (*UnreadCount, error) {
+	byType, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	count := &UnreadCount{ByType: byType}
+	for _, n := range byType {
+		count.Total += n
+	}
+	return count, nil
+}
+
+// MarkRead marca uma notificação do usuário como lida.
+func (s *NotificationService) MarkRead(userID, notificationID uuid.UUID) error {
+	return s.notificationRepo.MarkRead(userID, notificationID, time.Now().UTC())
+}
+
+// MarkAllRead marca todas as notificações do usuário como lidas e retorna quantas mudaram.
+func (s *NotificationService) MarkAllRead(userID uuid.UUID) (int64, error) {
+	return s.notificationRepo.MarkAllRead(userID, time.Now().UTC())
+}
+
+// GetPreferences retorna a preferência de cada tipo de notificação (ativos por padrão).
+func (s *NotificationService) GetPreferences(userID uuid.UUID) ([]domain.NotificationPreference, error) {
+	saved, err := s.notificationRepo.FindPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := make(map[domain.NotificationType]bool, len(saved))
+	for _, preference := range saved {
+		enabled[preference.Type] = preference.Enabled
+	}
+
+	preferences := make([]domain.NotificationPreference, 0, len(domain.NotificationTypes))
+	for _, t := range domain.NotificationTypes {
+		value, ok := enabled[t]
+		preferences = append(preferences, domain.NotificationPreference{
+			UserID:  userID,
+			Type:    t,
+			Enabled: !ok || value,
+		})
+	}
+	return preferences, nil
+}
+
+// UpdatePreferences ativa ou desativa os tipos informados e retorna todas as preferências.
+func (s *NotificationService) UpdatePreferences(userID uuid.UUID, req UpdatePreferencesRequest) ([]domain.NotificationPreference, error) {
+	preferences := make([]domain.NotificationPreference, 0, len(req.Preferences))
+	for t, enabled := range req.Preferences {
+		if !t.IsValid() {
+			return nil, errors.New("tipo de notificação inválido: " + string(t))
+		}
+		preferences = append(preferences, domain.NotificationPreference{UserID: userID, Type: t, Enabled: enabled})
+	}
+
+	if err := s.notificationRepo.SavePreferences(preferences); err != nil {
+		return nil, err
+	}
+	return s.GetPreferences(userID)
+}
+
+// isEnabled verifica se o usuário quer receber o tipo de notificação.
+func (s *NotificationService) isEnabled(userID uuid.UUID, t domain.NotificationType) (bool, error) {
+	preferences, err := s.notificationRepo.FindPreferences(userID)
+	if err != nil {
+		return false, err
+	}
+	for _, preference := range preferences {
+		if preference.Type == t {
+			return preference.Enabled, nil
+		}
+	}
+	return true, nil
+}
+
+// sendNotification registra a notificação sem interromper a operação que a originou:
+// falhas são apenas registradas no log.
+func sendNotification(sender NotificationSender, notification *domain.Notification) {
+	if err := sender.Notify(notification); err != nil {
+		log.Printf("Erro ao registrar notificação %s para %s: %v", notification.Type, notification.UserID, err)
+	}
+}
@@ -0,0 +1,212 @@
+// Package service contém a lógica de negócio da aplicação.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+)
+
+// maxOutboxErrorLength é o tamanho da coluna last_error.
+const maxOutboxErrorLength = 1000
+
+// EventHandlerFunc processa um evento entregue pelo outbox. A entrega é "ao menos uma vez":
+// após uma queda, o mesmo evento pode chegar de novo, então o handler deve ser idempotente.
+type EventHandlerFunc func(ctx context.Context, event domain.Event) error
+
+// OutboxOptions configura as tentativas de entrega do dispatcher.
+type OutboxOptions struct {
+	BatchSize   int           // Mensagens lidas por varredura
+	MaxAttempts int           // Tentativas antes de mover a mensagem para a fila de mortos
+	BaseBackoff time.Duration // Espera após a primeira falha; dobra a cada nova falha
+	MaxBackoff  time.Duration // Espera máxima entre tentativas
+	Lease       time.Duration // Reserva da mensagem durante a entrega
+}
+
+// registeredHandler é um handler com os tipos de evento que ele recebe (vazio = todos).
+type registeredHandler struct {
+	name   string
+	types  map[domain.EventType]bool
+	handle EventHandlerFunc
+}
+
+// OutboxDispatcher entrega as mensagens do outbox aos handlers registrados, com novas
+// tentativas em backoff exponencial. Cada handler recebe a mensagem uma vez: se apenas
+// um deles falhar, só ele é chamado na próxima tentativa.
+type OutboxDispatcher struct {
+	outboxRepo repository.OutboxRepositoryInterface
+	options    OutboxOptions
+	handlers   []registeredHandler
+}
+
+// NewOutboxDispatcher cria o dispatcher, aplicando valores padrão às opções não informadas.
+func NewOutboxDispatcher(outboxRepo repository.OutboxRepositoryInterface, options OutboxOptions) *OutboxDispatcher {
+	if options.BatchSize <= 0 {
+		options.BatchSize = 100
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = 10
+	}
+	if options.BaseBackoff <= 0 {
+		options.BaseBackoff = 5 * time.Second
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = time.Hour
+	}
+	if options.MaxBackoff < options.BaseBackoff {
+		options.MaxBackoff = options.BaseBackoff
+	}
+	if options.Lease <= 0 {
+		options.Lease = time.Minute
+	}
+
+	return &OutboxDispatcher{
+		outboxRepo: outboxRepo,
+		options:    options,
+	}
+}
+
+// Register adiciona um handler para os tipos de evento informados (nenhum = todos).
+// O nome identifica o handler nas entregas já realizadas e não deve mudar entre versões.
+func (d *OutboxDispatcher) Register(name string, handle EventHandlerFunc, types ...domain.EventType) {
+	handler := registeredHandler{name: name, handle: handle}
+	if len(types) > 0 {
+		handler.types = make(map[domain.EventType]bool, len(types))
+		for _, eventType := range types {
+			handler.types[eventType] = true
+		}
+	}
+	d.handlers = append(d.handlers, handler)
+}
+
+// Run entrega as mensagens pendentes a cada intervalo até o contexto ser cancelado.
+func (d *OutboxDispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DispatchDue(ctx, time.Now().UTC()); err != nil {
+			log.Printf("Outbox: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue entrega as mensagens cuja tentativa já chegou e retorna quantas foram
+// concluídas. Falhas dos handlers reagendam a mensagem e são devolvidas no erro.
+func (d *OutboxDispatcher) DispatchDue(ctx context.Context, now time.Time) (int, error) {
+	messages, err := d.outboxRepo.FindDue(now, d.options.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	var errs []error
+	for i := range messages {
+		if ctx.Err() != nil {
+			break
+		}
+		message := &messages[i]
+
+		leased, err := d.outboxRepo.Lease(message, now.Add(d.options.Lease))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !leased {
+			continue // Outra instância está entregando
+		}
+
+		if err := d.dispatch(ctx, message, now); err != nil {
+			errs = append(errs, fmt.Errorf("evento %s (%s): %w", message.ID, message.EventType, err))
+		}
+		if message.Status == domain.OutboxStatusDelivered {
+			delivered++
+		}
+		if err := d.outboxRepo.SaveAttempt(message); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return delivered, errors.Join(errs...)
+}
+
+// dispatch chama os handlers que ainda não processaram a mensagem e atualiza o seu estado:
+// entregue, reagendada com backoff ou, esgotadas as tentativas, morta.
+func (d *OutboxDispatcher) dispatch(ctx context.Context, message *domain.OutboxMessage, now time.Time) error {
+	event, err := message.Event()
+	if err != nil {
+		// Um payload ilegível não melhora com novas tentativas
+		d.fail(message, now, err, true)
+		return err
+	}
+
+	var errs []error
+	for _, handler := range d.handlers {
+		if handler.types != nil && !handler.types[event.Type] {
+			continue
+		}
+		if message.DeliveredToHandler(handler.name) {
+			continue
+		}
+		if err := handler.handle(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", handler.name, err))
+			continue
+		}
+		message.MarkDeliveredTo(handler.name)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		d.fail(message, now, err, false)
+		return err
+	}
+
+	message.Status = domain.OutboxStatusDelivered
+	message.LastError = ""
+	message.ProcessedAt = &now
+	return nil
+}
+
+// fail registra a falha e agenda a próxima tentativa, ou move a mensagem para a fila de mortos.
+func (d *OutboxDispatcher) fail(message *domain.OutboxMessage, now time.Time, err error, permanent bool) {
+	message.Attempts++
+	message.LastError = err.Error()
+	if len(message.LastError) > maxOutboxErrorLength {
+		message.LastError = message.LastError[:maxOutboxErrorLength]
+	}
+
+	if permanent || message.Attempts >= d.options.MaxAttempts {
+		message.Status = domain.OutboxStatusDead
+		message.ProcessedAt = &now
+		log.Printf("Outbox: evento %s (%s) movido para a fila de mortos após %d tentativas: %s",
+			message.ID, message.EventType, message.Attempts, message.LastError)
+		return
+	}
+	message.NextAttemptAt = now.Add(d.Backoff(message.Attempts))
+}
+
+// Backoff devolve a espera antes da próxima tentativa, após o número de falhas informado.
+func (d *OutboxDispatcher) Backoff(attempts int) time.Duration {
+	return exponentialBackoff(d.options.BaseBackoff, d.options.MaxBackoff, attempts)
+}
+
+// exponentialBackoff dobra a espera base a cada falha após a primeira, até o máximo.
+func exponentialBackoff(base, max time.Duration, attempts int) time.Duration {
+	wait := base
+	for i := 1; i < attempts && wait < max; i++ {
+		wait *= 2
+	}
+	if wait > max {
+		wait = max
+	}
+	return wait
+}
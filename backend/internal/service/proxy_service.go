@@ -0,0 +1,447 @@
+// Package service contém a lógica de negócio da aplicação.
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrElderlyOnly indica que apenas idosos podem delegar o acesso à conta.
+	ErrElderlyOnly = errors.New("apenas idosos podem conceder acesso a familiares")
+	// ErrProxyAlreadyGranted indica que o familiar já tem uma delegação ativa do idoso.
+	ErrProxyAlreadyGranted = errors.New("este familiar já tem acesso à sua conta")
+	// ErrProxyPermissionDenied indica que a delegação não inclui a permissão necessária.
+	ErrProxyPermissionDenied = errors.New("a delegação não permite esta ação")
+	// ErrProxyOwnInvitation indica um procurador tentando responder a um convite ou
+	// pedido de conexão feito por ele mesmo.
+	ErrProxyOwnInvitation = errors.New("você não pode responder em nome do idoso a um convite seu")
+)
+
+// ProxyService gerencia as delegações de idosos para familiares (procuradores) e as
+// ações feitas por eles em nome do idoso. Cada ação do procurador fica registrada no
+// log da delegação, com quem agiu.
+type ProxyService struct {
+	proxyRepo      repository.ProxyRepositoryInterface
+	userRepo       repository.UserRepositoryInterface
+	connectionRepo repository.ConnectionRepositoryInterface
+	users          *UserService
+	appointments   *AppointmentService
+	matching       *MatchingService
+	notifications  NotificationSender
+}
+
+// NewProxyService cria uma nova instância do serviço de delegações.
+func NewProxyService(
+	proxyRepo repository.ProxyRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	connectionRepo repository.ConnectionRepositoryInterface,
+	users *UserService,
+	appointments *AppointmentService,
+	matching *MatchingService,
+	notifications NotificationSender,
+) *ProxyService {
+	return &ProxyService{
+		proxyRepo:      proxyRepo,
+		userRepo:       userRepo,
+		connectionRepo: connectionRepo,
+		users:          users,
+		appointments:   appointments,
+		matching:       matching,
+		notifications:  notifications,
+	}
+}
+
+// GrantProxyRequest contém os dados para dar acesso a um familiar.
+// A permissão view é sempre concedida.
+type GrantProxyRequest struct {
+	Email        string                   `json:"email" binding:"required,email"`
+	Relationship string                   `json:"relationship" binding:"max=100"`
+	Permissions  []domain.ProxyPermission `json:"permissions"`
+}
+
+// UpdateProxyRequest contém as novas permissões da delegação.
+type UpdateProxyRequest struct {
+	Permissions []domain.ProxyPermission `json:"permissions"`
+}
+
+// ProxyActionPage é uma página do log de ações das delegações.
+type ProxyActionPage struct {
+	Entries []domain.ProxyActionLog
+	Page    int
+	PerPage int
+	Total   int64
+}
+
+// Grant dá ao familiar com o e-mail informado acesso à conta do idoso.
+func (s *ProxyService) Grant(elderlyID uuid.UUID, req GrantProxyRequest) (*domain.ProxyGrant, error) {
+	elderly, err := s.userRepo.FindByID(elderlyID)
+	if err != nil {
+		return nil, err
+	}
+	if elderly.UserType != domain.UserTypeElderly {
+		return nil, ErrElderlyOnly
+	}
+
+	permissions, err := normalizeProxyPermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy, err := s.userRepo.FindByEmail(strings.TrimSpace(req.Email))
+	if err != nil || !proxy.IsActive {
+		return nil, errors.New("nenhuma conta ativa encontrada com este e-mail")
+	}
+	if proxy.ID == elderlyID {
+		return nil, errors.New("não é possível conceder acesso à própria conta")
+	}
+
+	if _, err := s.proxyRepo.FindActive(elderlyID, proxy.ID); err == nil {
+		return nil, ErrProxyAlreadyGranted
+	} else if !errors.Is(err, domain.ErrProxyGrantNotFound) {
+		return nil, err
+	}
+
+	grant := &domain.ProxyGrant{
+		ID:           uuid.New(),
+		ElderlyID:    elderlyID,
+		ProxyID:      proxy.ID,
+		Permissions:  permissions,
+		Relationship: req.Relationship,
+	}
+	entry := &domain.ProxyActionLog{
+		ElderlyID: elderlyID,
+		ActorID:   elderlyID,
+		Action:    domain.ProxyActionGrantCreated,
+		Details:   "permissões: " + joinProxyPermissions(permissions),
+	}
+	if err := s.proxyRepo.Create(grant, entry); err != nil {
+		return nil, err
+	}
+
+	sendNotification(s.notifications, &domain.Notification{
+		UserID:  proxy.ID,
+		Type:    domain.NotificationProxyGranted,
+		Title:   "Acesso de familiar",
+		Body:    elderly.Name + " deu a você acesso à conta dele(a).",
+		ActorID: &elderlyID,
+	})
+
+	return s.proxyRepo.FindByID(grant.ID)
+}
+
+// ListGrants retorna as delegações concedidas pelo idoso, inclusive as revogadas.
+func (s *ProxyService) ListGrants(elderlyID uuid.UUID) ([]domain.ProxyGrant, error) {
+	return s.proxyRepo.FindByElderly(elderlyID)
+}
+
+// UpdatePermissions altera as permissões de uma delegação ativa do idoso.
+func (s *ProxyService) UpdatePermissions(elderlyID, grantID uuid.UUID, req UpdateProxyRequest) (*domain.ProxyGrant, error) {
+	grant, err := s.findActiveGrant(grantID)
+	if err != nil {
+		return nil, err
+	}
+	if grant.ElderlyID != elderlyID {
+		return nil, domain.ErrProxyGrantNotFound
+	}
+
+	permissions, err := normalizeProxyPermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	grant.Permissions = permissions
+	entry := &domain.ProxyActionLog{
+		GrantID:   grant.ID,
+		ElderlyID: elderlyID,
+		ActorID:   elderlyID,
+		Action:    domain.ProxyActionGrantUpdated,
+		Details:   "permissões: " + joinProxyPermissions(permissions),
+	}
+	if err := s.proxyRepo.Update(grant, entry); err != nil {
+		return nil, err
+	}
+	return grant, nil
+}
+
+// Revoke encerra uma delegação. O idoso pode revogar o acesso a qualquer momento e o
+// familiar pode abrir mão dele; o outro lado é avisado.
+func (s *ProxyService) Revoke(userID, grantID uuid.UUID) error {
+	grant, err := s.findActiveGrant(grantID)
+	if err != nil {
+		return err
+	}
+	if grant.ElderlyID != userID && grant.ProxyID != userID {
+		return domain.ErrProxyGrantNotFound
+	}
+
+	now := time.Now()
+	grant.RevokedAt = &now
+	grant.RevokedBy = &userID
+	entry := &domain.ProxyActionLog{
+		GrantID:   grant.ID,
+		ElderlyID: grant.ElderlyID,
+		ActorID:   userID,
+		Action:    domain.ProxyActionGrantRevoked,
+	}
+	if err := s.proxyRepo.Update(grant, entry); err != nil {
+		return err
+	}
+
+	notified, body := grant.ProxyID, grant.Elderly.Name+" revogou o seu acesso à conta dele(a)."
+	if userID == grant.ProxyID {
+		notified, body = grant.ElderlyID, grant.Proxy.Name+" deixou de ter acesso à sua conta."
+	}
+	sendNotification(s.notifications, &domain.Notification{
+		UserID:  notified,
+		Type:    domain.NotificationProxyRevoked,
+		Title:   "Acesso de familiar encerrado",
+		Body:    body,
+		ActorID: &userID,
+	})
+	return nil
+}
+
+// Relinquish encerra, a pedido do familiar, a delegação ativa recebida do idoso.
+func (s *ProxyService) Relinquish(proxyID, elderlyID uuid.UUID) error {
+	grant, err := s.proxyRepo.FindActive(elderlyID, proxyID)
+	if err != nil {
+		return err
+	}
+	return s.Revoke(proxyID, grant.ID)
+}
+
+// ListActions retorna o log das delegações do idoso: o que cada familiar fez e quando.
+func (s *ProxyService) ListActions(elderlyID uuid.UUID, page, perPage int) (*ProxyActionPage, error) {
+	page, perPage = adminPage(page, perPage)
+	entries, total, err := s.proxyRepo.FindActions(elderlyID, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+	return &ProxyActionPage{Entries: entries, Page: page, PerPage: perPage, Total: total}, nil
+}
+
+// ListManaged retorna as delegações ativas recebidas pelo familiar.
+func (s *ProxyService) ListManaged(proxyID uuid.UUID) ([]domain.ProxyGrant, error) {
+	return s.proxyRepo.FindActiveByProxy(proxyID)
+}
+
+// GetProfile retorna o perfil do idoso para o procurador.
+func (s *ProxyService) GetProfile(proxyID, elderlyID uuid.UUID) (*domain.User, error) {
+	if _, err := s.authorize(proxyID, elderlyID, domain.ProxyPermissionView); err != nil {
+		return nil, err
+	}
+	return s.users.GetByID(elderlyID)
+}
+
+// UpdateProfile altera o perfil do idoso em nome dele.
+func (s *ProxyService) UpdateProfile(proxyID, elderlyID uuid.UUID, req UpdateProfileRequest) (*domain.User, error) {
+	grant, err := s.authorize(proxyID, elderlyID, domain.ProxyPermissionEditProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *domain.User
+	err = s.record(grant, domain.ProxyActionProfileUpdated, nil, "", func() error {
+		user, err = s.users.UpdateProfile(elderlyID, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetAppointments retorna os agendamentos do idoso para o procurador.
+func (s *ProxyService) GetAppointments(proxyID, elderlyID uuid.UUID) ([]domain.Appointment, error) {
+	if _, err := s.authorize(proxyID, elderlyID, domain.ProxyPermissionView); err != nil {
+		return nil, err
+	}
+	return s.appointments.GetMyAppointments(elderlyID)
+}
+
+// GetInvitations retorna os convites pendentes recebidos pelo idoso.
+func (s *ProxyService) GetInvitations(proxyID, elderlyID uuid.UUID) ([]domain.Appointment, error) {
+	if _, err := s.authorize(proxyID, elderlyID, domain.ProxyPermissionView); err != nil {
+		return nil, err
+	}
+	return s.appointments.GetReceivedInvitations(elderlyID)
+}
+
+// GetConnections retorna as conexões do idoso para o procurador.
+func (s *ProxyService) GetConnections(proxyID, elderlyID uuid.UUID) ([]domain.Connection, error) {
+	if _, err := s.authorize(proxyID, elderlyID, domain.ProxyPermissionView); err != nil {
+		return nil, err
+	}
+	return s.matching.GetConnections(elderlyID)
+}
+
+// AcceptInvitation aceita um convite de agendamento em nome do idoso.
+func (s *ProxyService) AcceptInvitation(proxyID, elderlyID, appointmentID uuid.UUID, scope SeriesScope) error {
+	grant, err := s.authorizeAppointment(proxyID, elderlyID, appointmentID)
+	if err != nil {
+		return err
+	}
+	return s.record(grant, domain.ProxyActionInvitationAccepted, &appointmentID, scopeDetails(scope), func() error {
+		return s.appointments.Accept(appointmentID, elderlyID, scope)
+	})
+}
+
+// DeclineInvitation recusa um convite de agendamento em nome do idoso.
+func (s *ProxyService) DeclineInvitation(proxyID, elderlyID, appointmentID uuid.UUID, reason string, scope SeriesScope) error {
+	grant, err := s.authorizeAppointment(proxyID, elderlyID, appointmentID)
+	if err != nil {
+		return err
+	}
+	return s.record(grant, domain.ProxyActionInvitationDeclined, &appointmentID, scopeDetails(scope), func() error {
+		return s.appointments.Decline(appointmentID, elderlyID, reason, scope)
+	})
+}
+
+// AcceptConnection aceita um pedido de conexão em nome do idoso.
+func (s *ProxyService) AcceptConnection(proxyID, elderlyID, connectionID uuid.UUID) error {
+	grant, err := s.authorize(proxyID, elderlyID, domain.ProxyPermissionRespondInvitations)
+	if err != nil {
+		return err
+	}
+	if proxyID == s.connectionVolunteer(connectionID) {
+		return ErrProxyOwnInvitation
+	}
+	return s.record(grant, domain.ProxyActionConnectionAccepted, &connectionID, "", func() error {
+		return s.matching.AcceptConnection(connectionID, elderlyID)
+	})
+}
+
+// RejectConnection rejeita um pedido de conexão em nome do idoso.
+func (s *ProxyService) RejectConnection(proxyID, elderlyID, connectionID uuid.UUID) error {
+	grant, err := s.authorize(proxyID, elderlyID, domain.ProxyPermissionRespondInvitations)
+	if err != nil {
+		return err
+	}
+	if proxyID == s.connectionVolunteer(connectionID) {
+		return ErrProxyOwnInvitation
+	}
+	return s.record(grant, domain.ProxyActionConnectionRejected, &connectionID, "", func() error {
+		return s.matching.RejectConnection(connectionID, elderlyID)
+	})
+}
+
+// authorize busca a delegação ativa do idoso para o procurador e verifica a permissão.
+func (s *ProxyService) authorize(proxyID, elderlyID uuid.UUID, permission domain.ProxyPermission) (*domain.ProxyGrant, error) {
+	grant, err := s.proxyRepo.FindActive(elderlyID, proxyID)
+	if err != nil {
+		return nil, err
+	}
+	if !grant.Allows(permission) {
+		return nil, ErrProxyPermissionDenied
+	}
+	return grant, nil
+}
+
+// authorizeAppointment verifica a permissão de responder convites e impede que o
+// procurador responda a um convite enviado por ele mesmo.
+func (s *ProxyService) authorizeAppointment(proxyID, elderlyID, appointmentID uuid.UUID) (*domain.ProxyGrant, error) {
+	grant, err := s.authorize(proxyID, elderlyID, domain.ProxyPermissionRespondInvitations)
+	if err != nil {
+		return nil, err
+	}
+	appointment, err := s.appointments.GetByID(appointmentID, elderlyID)
+	if err != nil {
+		return nil, err
+	}
+	if appointment.VolunteerID == proxyID {
+		return nil, ErrProxyOwnInvitation
+	}
+	return grant, nil
+}
+
+// connectionVolunteer retorna o voluntário que pediu a conexão, ou uuid.Nil se ela
+// não for encontrada (o erro é tratado pelo serviço de pareamento em seguida).
+func (s *ProxyService) connectionVolunteer(connectionID uuid.UUID) uuid.UUID {
+	connection, err := s.connectionRepo.FindByID(connectionID)
+	if err != nil {
+		return uuid.Nil
+	}
+	return connection.VolunteerID
+}
+
+// findActiveGrant busca a delegação e retorna domain.ErrProxyGrantNotFound se já foi revogada.
+func (s *ProxyService) findActiveGrant(grantID uuid.UUID) (*domain.ProxyGrant, error) {
+	grant, err := s.proxyRepo.FindByID(grantID)
+	if err != nil {
+		return nil, err
+	}
+	if !grant.IsActive() {
+		return nil, domain.ErrProxyGrantNotFound
+	}
+	return grant, nil
+}
+
+// record executa uma ação do procurador registrando-a no log da delegação. O registro
+// é gravado antes da ação, para que nenhuma ação fique fora do log: se a gravação
+// falhar a ação não é feita, e se a ação falhar o registro é removido.
+func (s *ProxyService) record(grant *domain.ProxyGrant, action domain.ProxyAction, resourceID *uuid.UUID, details string, do func() error) error {
+	entry := &domain.ProxyActionLog{
+		ID:         uuid.New(),
+		GrantID:    grant.ID,
+		ElderlyID:  grant.ElderlyID,
+		ActorID:    grant.ProxyID,
+		Action:     action,
+		ResourceID: resourceID,
+		Details:    details,
+	}
+	if err := s.proxyRepo.CreateAction(entry); err != nil {
+		return err
+	}
+	if err := do(); err != nil {
+		if deleteErr := s.proxyRepo.DeleteAction(entry.ID); deleteErr != nil {
+			return errors.Join(err, deleteErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// normalizeProxyPermissions valida as permissões, remove repetições e inclui view,
+// necessária para as demais.
+func normalizeProxyPermissions(permissions []domain.ProxyPermission) ([]domain.ProxyPermission, error) {
+	granted := map[domain.ProxyPermission]bool{domain.ProxyPermissionView: true}
+	for _, permission := range permissions {
+		if !permission.IsValid() {
+			return nil, errors.New("permissão inválida: " + string(permission))
+		}
+		granted[permission] = true
+	}
+
+	// Mantém a ordem de domain.ProxyPermissions
+	normalized := make([]domain.ProxyPermission, 0, len(granted))
+	for _, permission := range domain.ProxyPermissions {
+		if granted[permission] {
+			normalized = append(normalized, permission)
+		}
+	}
+	return normalized, nil
+}
+
+// joinProxyPermissions formata as permissões para o log.
+func joinProxyPermissions(permissions []domain.ProxyPermission) string {
+	names := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		names = append(names, string(permission))
+	}
+	return strings.Join(names, ", ")
+}
+
+// scopeDetails descreve no log o escopo de respostas a séries recorrentes.
+func scopeDetails(scope SeriesScope) string {
+	if scope == "" || scope == SeriesScopeThis {
+		return ""
+	}
+	return "escopo: " + string(scope)
+}
@@ -0,0 +1,195 @@
+// Package service contém a lógica de negócio da aplicação.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+	"amigos-terceira-idade/pkg/notify"
+)
+
+// ReminderService envia lembretes dos agendamentos confirmados aos participantes,
+// com as antecedências configuradas (ex.: 24 horas e 15 minutos antes).
+type ReminderService struct {
+	appointmentRepo repository.AppointmentRepositoryInterface
+	reminderRepo    repository.ReminderRepositoryInterface
+	notifier        notify.Notifier
+	notifications   NotificationSender
+	offsets         []time.Duration // Em ordem crescente
+}
+
+// NewReminderService cria uma nova instância do serviço de lembretes.
+func NewReminderService(
+	appointmentRepo repository.AppointmentRepositoryInterface,
+	reminderRepo repository.ReminderRepositoryInterface,
+	notifier notify.Notifier,
+	notifications NotificationSender,
+	offsets []time.Duration,
+) *ReminderService {
+	sorted := make([]time.Duration, 0, len(offsets))
+	for _, offset := range offsets {
+		if offset > 0 {
+			sorted = append(sorted, offset)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return &ReminderService{
+		appointmentRepo: appointmentRepo,
+		reminderRepo:    reminderRepo,
+		notifier:        notifier,
+		notifications:   notifications,
+		offsets:         sorted,
+	}
+}
+
+// Run varre os agendamentos a cada intervalo até o contexto ser cancelado.
+func (s *ReminderService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if sent, err := s.SendDue(ctx, time.Now()); err != nil {
+			log.Printf("Lembretes: %d enviados, com erros: %v", sent, err)
+		} else if sent > 0 {
+			log.Printf("Lembretes: %d enviados", sent)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDue envia os lembretes devidos no instante informado e retorna quantos foram enviados.
+// Cada participante recebe, por agendamento, apenas o lembrete da menor antecedência já
+// alcançada: um agendamento criado 2 horas antes recebe só o de 15 minutos, não o de 24 horas.
+// Lembretes já enviados são ignorados; os que falharem serão tentados de novo na próxima varredura.
+func (s *ReminderService) SendDue(ctx context.Context, now time.Time) (int, error) {
+	if len(s.offsets) == 0 {
+		return 0, nil
+	}
+
+	appointments, err := s.appointmentRepo.FindConfirmedBetween(now, now.Add(s.offsets[len(s.offsets)-1]))
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var errs []error
+	for i := range appointments {
+		appointment := &appointments[i]
+		offset := s.dueOffset(appointment.Date.Sub(now))
+
+		for _, participant := range []*domain.User{&appointment.Volunteer, &appointment.Target} {
+			if !participant.IsActive {
+				continue
+			}
+			ok, err := s.send(ctx, appointment, participant, offset, now)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("agendamento %s: %w", appointment.ID, err))
+				continue
+			}
+			if ok {
+				sent++
+			}
+		}
+	}
+	return sent, errors.Join(errs...)
+}
+
+// dueOffset retorna a menor antecedência configurada que já foi alcançada.
+func (s *ReminderService) dueOffset(remaining time.Duration) time.Duration {
+	for _, offset := range s.offsets {
+		if remaining <= offset {
+			return offset
+		}
+	}
+	return s.offsets[len(s.offsets)-1]
+}
+
+// send reserva e envia um lembrete. Retorna false se ele já havia sido reservado.
+func (s *ReminderService) send(ctx context.Context, appointment *domain.Appointment, recipient *domain.User, offset time.Duration, now time.Time) (bool, error) {
+	reminder := &domain.AppointmentReminder{
+		AppointmentID:   appointment.ID,
+		UserID:          recipient.ID,
+		OffsetMinutes:   int(offset / time.Minute),
+		AppointmentDate: appointment.Date,
+		Channel:         s.notifier.Channel(),
+	}
+	claimed, err := s.reminderRepo.Claim(reminder)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	msg := reminderMessage(appointment, recipient, now)
+	if err := s.notifier.Send(ctx, msg); err != nil {
+		if releaseErr := s.reminderRepo.Release(reminder.ID); releaseErr != nil {
+			return false, errors.Join(err, releaseErr)
+		}
+		return false, err
+	}
+
+	// Também fica registrado na caixa de entrada do aplicativo
+	sendNotification(s.notifications, &domain.Notification{
+		UserID:        recipient.ID,
+		Type:          domain.NotificationAppointmentReminder,
+		Title:         msg.Subject,
+		Body:          msg.Body,
+		AppointmentID: &appointment.ID,
+	})
+	return true, s.reminderRepo.MarkSent(reminder.ID, now)
+}
+
+// reminderMessage monta o texto do lembrete no fuso do destinatário.
+func reminderMessage(appointment *domain.Appointment, recipient *domain.User, now time.Time) notify.Message {
+	other := appointment.Target.Name
+	if recipient.ID == appointment.TargetID {
+		other = appointment.Volunteer.Name
+	}
+	local := appointment.Date.In(recipient.Location())
+
+	body := fmt.Sprintf("Olá, %s!\n\nSua conversa com %s está marcada para %s às %s (%s), com duração de %d minutos.\n",
+		recipient.Name, other, local.Format("02/01/2006"), local.Format("15:04"),
+		local.Location().String(), appointment.DurationMinutes)
+	if appointment.MeetingURL != "" {
+		body += "\nLink da conversa: " + appointment.MeetingURL + "\n"
+	}
+	body += "\nSe não puder participar, cancele pelo aplicativo para avisar a outra pessoa.\n"
+
+	return notify.Message{
+		To:      recipient.Email,
+		Name:    recipient.Name,
+		Subject: fmt.Sprintf("Lembrete: conversa com %s %s", other, describeRemaining(appointment.Date.Sub(now))),
+		Body:    body,
+	}
+}
+
+// describeRemaining descreve quanto falta para a conversa (ex.: "em 15 minutos", "em 1 dia").
+func describeRemaining(remaining time.Duration) string {
+	switch {
+	case remaining >= 24*time.Hour:
+		return plural(int(remaining/(24*time.Hour)), "dia", "dias")
+	case remaining >= time.Hour:
+		return plural(int(remaining/time.Hour), "hora", "horas")
+	case remaining >= time.Minute:
+		return plural(int(remaining/time.Minute), "minuto", "minutos")
+	}
+	return "agora"
+}
+
+// plural formata "em N unidade(s)".
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return "em 1 " + singular
+	}
+	return fmt.Sprintf("em %d %s", n, pluralForm)
+}
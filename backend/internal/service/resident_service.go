@@ -0,0 +1,173 @@
+// Package service contém a lógica de negócio da aplicação.
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInstitutionOnlyResidents indica que apenas instituições gerenciam residentes.
+	ErrInstitutionOnlyResidents = errors.New("apenas instituições podem gerenciar residentes")
+	// ErrNotResidentInstitution indica uma operação sobre um residente de outra instituição.
+	ErrNotResidentInstitution = errors.New("este residente não pertence à sua instituição")
+)
+
+// ResidentService gerencia os perfis dos residentes das instituições: idosos que não
+// usam o aplicativo e são representados pela instituição onde moram.
+type ResidentService struct {
+	residentRepo repository.ResidentRepositoryInterface
+	userRepo     repository.UserRepositoryInterface
+	interestRepo repository.InterestRepositoryInterface
+}
+
+// NewResidentService cria uma nova instância do serviço de residentes.
+func NewResidentService(
+	residentRepo repository.ResidentRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	interestRepo repository.InterestRepositoryInterface,
+) *ResidentService {
+	return &ResidentService{
+		residentRepo: residentRepo,
+		userRepo:     userRepo,
+		interestRepo: interestRepo,
+	}
+}
+
+// ResidentRequest contém os dados do perfil de um residente.
+// Na atualização, InterestIDs substitui a lista de interesses.
+type ResidentRequest struct {
+	Name            string      `json:"name" binding:"required,max=255"`
+	Age             int         `json:"age"`
+	Bio             string      `json:"bio"`
+	AssistanceNeeds string      `json:"assistance_needs" binding:"max=1000"`
+	PhotoURL        string      `json:"photo_url" binding:"max=500"`
+	InterestIDs     []uuid.UUID `json:"interest_ids"`
+}
+
+// Create cadastra um residente da instituição.
+func (s *ResidentService) Create(institutionID uuid.UUID, req ResidentRequest) (*domain.Resident, error) {
+	if err := s.checkInstitution(institutionID); err != nil {
+		return nil, err
+	}
+
+	resident := &domain.Resident{
+		ID:            uuid.New(),
+		InstitutionID: institutionID,
+		IsActive:      true,
+	}
+	if err := s.apply(resident, req); err != nil {
+		return nil, err
+	}
+	if err := s.residentRepo.Create(resident); err != nil {
+		return nil, err
+	}
+	return s.residentRepo.FindByID(resident.ID)
+}
+
+// List retorna os residentes da instituição, incluindo os desativados se solicitado.
+func (s *ResidentService) List(institutionID uuid.UUID, includeInactive bool) ([]domain.Resident, error) {
+	if err := s.checkInstitution(institutionID); err != nil {
+		return nil, err
+	}
+	return s.residentRepo.FindByInstitution(institutionID, includeInactive)
+}
+
+// GetByID retorna um residente. A instituição vê seus residentes, inclusive os
+// desativados; os demais usuários veem apenas residentes ativos.
+func (s *ResidentService) GetByID(id, viewerID uuid.UUID) (*domain.Resident, error) {
+	resident, err := s.residentRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if resident.InstitutionID != viewerID && !resident.IsActive {
+		return nil, domain.ErrResidentNotFound
+	}
+	return resident, nil
+}
+
+// Update altera o perfil de um residente da instituição.
+func (s *ResidentService) Update(institutionID, id uuid.UUID, req ResidentRequest) (*domain.Resident, error) {
+	resident, err := s.findOwned(institutionID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(resident, req); err != nil {
+		return nil, err
+	}
+	if err := s.residentRepo.Update(resident); err != nil {
+		return nil, err
+	}
+	return s.residentRepo.FindByID(id)
+}
+
+// SetActive desativa ou reativa um residente. Residentes desativados (por exemplo, que
+// deixaram a instituição) não aparecem no pareamento nem recebem novos convites.
+func (s *ResidentService) SetActive(institutionID, id uuid.UUID, active bool) (*domain.Resident, error) {
+	resident, err := s.findOwned(institutionID, id)
+	if err != nil {
+		return nil, err
+	}
+	resident.IsActive = active
+	if err := s.residentRepo.Update(resident); err != nil {
+		return nil, err
+	}
+	return resident, nil
+}
+
+// apply valida e copia os dados da requisição para o residente.
+func (s *ResidentService) apply(resident *domain.Resident, req ResidentRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return errors.New("informe o nome do residente")
+	}
+	if req.Age < 0 || req.Age > 130 {
+		return errors.New("idade inválida")
+	}
+
+	interests := []domain.Interest{}
+	if len(req.InterestIDs) > 0 {
+		found, err := s.interestRepo.FindByIDs(req.InterestIDs)
+		if err != nil {
+			return err
+		}
+		interests = found
+	}
+
+	resident.Name = name
+	resident.Age = req.Age
+	resident.Bio = req.Bio
+	resident.AssistanceNeeds = req.AssistanceNeeds
+	resident.PhotoURL = req.PhotoURL
+	resident.Interests = interests
+	return nil
+}
+
+// findOwned busca o residente e verifica se pertence à instituição.
+func (s *ResidentService) findOwned(institutionID, id uuid.UUID) (*domain.Resident, error) {
+	resident, err := s.residentRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if resident.InstitutionID != institutionID {
+		return nil, ErrNotResidentInstitution
+	}
+	return resident, nil
+}
+
+// checkInstitution garante que o usuário é uma instituição.
+func (s *ResidentService) checkInstitution(userID uuid.UUID) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.UserType != domain.UserTypeInstitution {
+		return ErrInstitutionOnlyResidents
+	}
+	return nil
+}
@@ -33,6 +33,7 @@ type UpdateProfileRequest struct {
 	Bio         string      `json:"bio"`
 	Phone       string      `json:"phone"`
 	PhotoURL    string      `json:"photo_url"`
+	Timezone    string      `json:"timezone"` // Fuso IANA, ex.: America/Manaus
 	InterestIDs []uuid.UUID `json:"interest_ids"`
 }
 
@@ -62,6 +63,12 @@ func (s *UserService) UpdateProfile(userID uuid.UUID, req UpdateProfileRequest)
 	if req.Phone != "" {
 		user.Phone = req.Phone
 	}
+	if req.Timezone != "" {
+		if err := domain.ValidateTimezone(req.Timezone); err != nil {
+			return nil, err
+		}
+		user.Timezone = req.Timezone
+	}
 	// // if req.PhotoURL != "" {
 	// 	user.PhotoURL = req.PhotoURL
 	// }
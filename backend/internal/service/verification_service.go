@@ -0,0 +1,349 @@
+// Package service contém a lógica de negócio da aplicação.
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+	"amigos-terceira-idade/pkg/storage"
+
+	"github.com/google/uuid"
+)
+
+// DefaultMaxDocumentSize é o tamanho máximo padrão de um documento da verificação.
+const DefaultMaxDocumentSize = 10 << 20 // 10 MB
+
+// allowedDocumentTypes lista os formatos aceitos, com a extensão usada no armazenamento.
+// O formato é detectado pelo conteúdo do arquivo, não pelo nome nem pelo header enviado.
+var allowedDocumentTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+// ErrVolunteerOnly indica uma operação de verificação feita por quem não é voluntário.
+var ErrVolunteerOnly = errors.New("apenas voluntários passam pela verificação")
+
+// ErrVolunteerNotVerified indica que a plataforma exige voluntários verificados para o pareamento.
+var ErrVolunteerNotVerified = errors.New("sua verificação de antecedentes precisa ser aprovada antes de se conectar com idosos")
+
+// VerificationOptions configura a verificação de voluntários.
+type VerificationOptions struct {
+	MaxDocumentSize int64 // Tamanho máximo de cada documento, em bytes
+}
+
+// VerificationService gerencia a verificação de antecedentes dos voluntários: o envio
+// dos documentos, o pedido de análise e a aprovação ou recusa pela equipe da plataforma.
+type VerificationService struct {
+	verificationRepo repository.VerificationRepositoryInterface
+	userRepo         repository.UserRepositoryInterface
+	auditRepo        repository.AuditRepositoryInterface
+	storage          storage.Storage
+	notifications    NotificationSender
+	options          VerificationOptions
+}
+
+// NewVerificationService cria uma nova instância do serviço de verificação,
+// aplicando valores padrão às opções não informadas.
+func NewVerificationService(
+	verificationRepo repository.VerificationRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	auditRepo repository.AuditRepositoryInterface,
+	store storage.Storage,
+	notifications NotificationSender,
+	options VerificationOptions,
+) *VerificationService {
+	if options.MaxDocumentSize <= 0 {
+		options.MaxDocumentSize = DefaultMaxDocumentSize
+	}
+	return &VerificationService{
+		verificationRepo: verificationRepo,
+		userRepo:         userRepo,
+		auditRepo:        auditRepo,
+		storage:          store,
+		notifications:    notifications,
+		options:          options,
+	}
+}
+
+// DocumentUpload contém um documento enviado pelo voluntário.
+type DocumentUpload struct {
+	Kind     domain.DocumentKind
+	FileName string
+	Size     int64
+	Content  io.Reader
+}
+
+// RejectVerificationRequest contém o motivo da recusa, exibido ao voluntário.
+type RejectVerificationRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// VerificationDetails reúne o status, os documentos e o histórico da verificação.
+type VerificationDetails struct {
+	VolunteerID uuid.UUID                          `json:"volunteer_id"`
+	Status      domain.VerificationStatus          `json:"status"`
+	IsVerified  bool                               `json:"is_verified"`
+	Documents   []domain.VerificationDocument      `json:"documents"`
+	History     []domain.VerificationStatusHistory `json:"history"`
+}
+
+// VerificationPage é uma página da fila de verificações.
+type VerificationPage struct {
+	Volunteers []domain.Volunteer
+	Page       int
+	PerPage    int
+	Total      int64
+}
+
+// UploadDocument guarda um documento do voluntário. Documentos podem ser enviados
+// enquanto a verificação não estiver aprovada, inclusive depois de uma recusa.
+func (s *VerificationService) UploadDocument(volunteerID uuid.UUID, upload DocumentUpload) (*domain.VerificationDocument, error) {
+	if !upload.Kind.IsValid() {
+		return nil, errors.New("tipo de documento inválido: use IDENTITY, CRIMINAL_RECORD, PROOF_OF_ADDRESS ou OTHER")
+	}
+	if upload.Size <= 0 {
+		return nil, errors.New("o arquivo está vazio")
+	}
+	if upload.Size > s.options.MaxDocumentSize {
+		return nil, fmt.Errorf("o arquivo excede o limite de %d MB", s.options.MaxDocumentSize>>20)
+	}
+
+	volunteer, err := s.findVolunteer(volunteerID)
+	if err != nil {
+		return nil, err
+	}
+	if volunteer.CurrentVerification() == domain.VerificationApproved {
+		return nil, errors.New("sua verificação já foi aprovada")
+	}
+
+	// Detecta o formato pelos primeiros bytes e devolve-os à leitura
+	head := make([]byte, 512)
+	n, err := io.ReadFull(upload.Content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	head = head[:n]
+	contentType := http.DetectContentType(head)
+	extension, ok := allowedDocumentTypes[contentType]
+	if !ok {
+		return nil, errors.New("formato não aceito: envie PDF, JPEG ou PNG")
+	}
+
+	document := &domain.VerificationDocument{
+		ID:          uuid.New(),
+		VolunteerID: volunteerID,
+		Kind:        upload.Kind,
+		FileName:    documentFileName(upload.FileName),
+		ContentType: contentType,
+		Size:        upload.Size,
+	}
+	document.StorageKey = fmt.Sprintf("verification/%s/%s%s", volunteerID, document.ID, extension)
+
+	content := io.LimitReader(io.MultiReader(bytes.NewReader(head), upload.Content), s.options.MaxDocumentSize)
+	if err := s.storage.Save(context.Background(), document.StorageKey, content); err != nil {
+		return nil, err
+	}
+	if err := s.verificationRepo.CreateDocument(document); err != nil {
+		// Sem o registro o arquivo ficaria órfão no armazenamento
+		_ = s.storage.Delete(context.Background(), document.StorageKey)
+		return nil, err
+	}
+	return document, nil
+}
+
+// Submit envia a verificação para análise. Exige ao menos um documento e vale tanto
+// para o primeiro pedido quanto para o reenvio depois de uma recusa.
+func (s *VerificationService) Submit(volunteerID uuid.UUID) (*VerificationDetails, error) {
+	volunteer, err := s.findVolunteer(volunteerID)
+	if err != nil {
+		return nil, err
+	}
+	current := volunteer.CurrentVerification()
+	if err := current.ValidateTransition(domain.VerificationPending); err != nil {
+		return nil, err
+	}
+
+	documents, err := s.verificationRepo.FindDocuments(volunteerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(documents) == 0 {
+		return nil, errors.New("envie ao menos um documento antes de pedir a verificação")
+	}
+
+	err = s.verificationRepo.ChangeStatus(&domain.VerificationStatusHistory{
+		VolunteerID: volunteerID,
+		FromStatus:  current,
+		ToStatus:    domain.VerificationPending,
+		ChangedBy:   volunteerID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetDetails(volunteerID)
+}
+
+// GetDetails retorna o status, os documentos e o histórico da verificação do voluntário.
+func (s *VerificationService) GetDetails(volunteerID uuid.UUID) (*VerificationDetails, error) {
+	volunteer, err := s.findVolunteer(volunteerID)
+	if err != nil {
+		return nil, err
+	}
+	documents, err := s.verificationRepo.FindDocuments(volunteerID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.verificationRepo.FindHistory(volunteerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VerificationDetails{
+		VolunteerID: volunteerID,
+		Status:      volunteer.CurrentVerification(),
+		IsVerified:  volunteer.IsVerified,
+		Documents:   documents,
+		History:     history,
+	}, nil
+}
+
+// ListByStatus retorna uma página dos voluntários com o status informado (padrão PENDING),
+// a fila de trabalho da equipe de verificação.
+func (s *VerificationService) ListByStatus(status domain.VerificationStatus, page, perPage int) (*VerificationPage, error) {
+	if status == "" {
+		status = domain.VerificationPending
+	}
+	if !status.IsValid() {
+		return nil, errors.New("status de verificação inválido")
+	}
+	page, perPage = adminPage(page, perPage)
+
+	volunteers, total, err := s.verificationRepo.FindByStatus(status, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+	return &VerificationPage{Volunteers: volunteers, Page: page, PerPage: perPage, Total: total}, nil
+}
+
+// OpenDocument abre um documento do voluntário para análise; quem chama deve fechá-lo.
+func (s *VerificationService) OpenDocument(volunteerID, documentID uuid.UUID) (*domain.VerificationDocument, io.ReadCloser, error) {
+	document, err := s.verificationRepo.FindDocument(volunteerID, documentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := s.storage.Open(context.Background(), document.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return document, content, nil
+}
+
+// Approve aprova a verificação pendente, marcando o voluntário como verificado.
+func (s *VerificationService) Approve(actorID, volunteerID uuid.UUID) (*VerificationDetails, error) {
+	err := s.review(actorID, volunteerID, domain.VerificationApproved, "")
+	if err != nil {
+		return nil, err
+	}
+
+	sendNotification(s.notifications, &domain.Notification{
+		UserID:  volunteerID,
+		Type:    domain.NotificationVerificationApproved,
+		Title:   "Verificação aprovada",
+		Body:    "Seus documentos foram aprovados. Você já pode se conectar com idosos e instituições.",
+		ActorID: &actorID,
+	})
+	return s.GetDetails(volunteerID)
+}
+
+// Reject recusa a verificação pendente ou revoga uma aprovação. O motivo é obrigatório
+// e fica visível ao voluntário, que pode enviar novos documentos e pedir nova análise.
+func (s *VerificationService) Reject(actorID, volunteerID uuid.UUID, req RejectVerificationRequest) (*VerificationDetails, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, errors.New("informe o motivo da recusa")
+	}
+	if err := s.review(actorID, volunteerID, domain.VerificationRejected, reason); err != nil {
+		return nil, err
+	}
+
+	sendNotification(s.notifications, &domain.Notification{
+		UserID:  volunteerID,
+		Type:    domain.NotificationVerificationRejected,
+		Title:   "Verificação não aprovada",
+		Body:    "Motivo: " + reason,
+		ActorID: &actorID,
+	})
+	return s.GetDetails(volunteerID)
+}
+
+// review aplica a decisão da equipe e a registra no log de auditoria.
+func (s *VerificationService) review(actorID, volunteerID uuid.UUID, to domain.VerificationStatus, reason string) error {
+	if actorID == volunteerID {
+		return ErrSelfAdministration
+	}
+	volunteer, err := s.findVolunteer(volunteerID)
+	if err != nil {
+		return err
+	}
+	current := volunteer.CurrentVerification()
+	if err := current.ValidateTransition(to); err != nil {
+		return err
+	}
+
+	err = s.verificationRepo.ChangeStatus(&domain.VerificationStatusHistory{
+		VolunteerID: volunteerID,
+		FromStatus:  current,
+		ToStatus:    to,
+		ChangedBy:   actorID,
+		Reason:      reason,
+	})
+	if err != nil {
+		return err
+	}
+
+	action := domain.AuditVolunteerApproved
+	if to == domain.VerificationRejected {
+		action = domain.AuditVolunteerRejected
+	}
+	return s.auditRepo.Create(&domain.AuditLog{
+		ActorID:      actorID,
+		Action:       action,
+		TargetUserID: volunteerID,
+		Details:      reason,
+	})
+}
+
+// findVolunteer busca os dados de verificação, garantindo que o usuário é voluntário.
+func (s *VerificationService) findVolunteer(userID uuid.UUID) (*domain.Volunteer, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.UserType != domain.UserTypeVolunteer {
+		return nil, ErrVolunteerOnly
+	}
+	return s.verificationRepo.FindVolunteer(userID)
+}
+
+// documentFileName limpa o nome original do arquivo, usado apenas para exibição.
+func documentFileName(name string) string {
+	name = strings.TrimSpace(name)
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	if name == "" {
+		return "documento"
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+	return name
+}
@@ -0,0 +1,450 @@
+// Package service contém a lógica de negócio da aplicação.
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+	"amigos-terceira-idade/pkg/webhook"
+
+	"github.com/google/uuid"
+)
+
+// DefaultWebhookPageSize e MaxWebhookPageSize controlam a paginação do registro de entregas.
+const (
+	DefaultWebhookPageSize = 20
+	MaxWebhookPageSize     = 100
+	maxWebhookErrorLength  = 1000
+)
+
+// ErrInstitutionOnly indica que apenas instituições podem gerenciar webhooks.
+var ErrInstitutionOnly = errors.New("apenas instituições podem cadastrar webhooks")
+
+// WebhookSender envia uma entrega assinada ao endpoint.
+type WebhookSender interface {
+	Send(ctx context.Context, req webhook.Request) (*webhook.Response, error)
+}
+
+// WebhookOptions configura as tentativas de entrega dos webhooks.
+type WebhookOptions struct {
+	BatchSize   int           // Entregas lidas por varredura
+	MaxAttempts int           // Tentativas antes de marcar a entrega como falha
+	BaseBackoff time.Duration // Espera após a primeira falha; dobra a cada nova falha
+	MaxBackoff  time.Duration // Espera máxima entre tentativas
+	Lease       time.Duration // Reserva da entrega durante o envio
+}
+
+// WebhookService gerencia os webhooks das instituições parceiras: cadastro dos endpoints,
+// enfileiramento dos eventos assinados e envio com novas tentativas.
+type WebhookService struct {
+	webhookRepo repository.WebhookRepositoryInterface
+	userRepo    repository.UserRepositoryInterface
+	sender      WebhookSender
+	options     WebhookOptions
+}
+
+// NewWebhookService cria uma nova instância do serviço de webhooks,
+// aplicando valores padrão às opções não informadas.
+func NewWebhookService(
+	webhookRepo repository.WebhookRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	sender WebhookSender,
+	options WebhookOptions,
+) *WebhookService {
+	if options.BatchSize <= 0 {
+		options.BatchSize = 50
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = 8
+	}
+	if options.BaseBackoff <= 0 {
+		options.BaseBackoff = 30 * time.Second
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = 6 * time.Hour
+	}
+	if options.MaxBackoff < options.BaseBackoff {
+		options.MaxBackoff = options.BaseBackoff
+	}
+	if options.Lease <= 0 {
+		options.Lease = 2 * time.Minute
+	}
+
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		userRepo:    userRepo,
+		sender:      sender,
+		options:     options,
+	}
+}
+
+// CreateWebhookRequest contém os dados para cadastrar um endpoint.
+type CreateWebhookRequest struct {
+	URL    string             `json:"url" binding:"required"`
+	Events []domain.EventType `json:"events" binding:"required"`
+}
+
+// UpdateWebhookRequest contém os campos alteráveis de um endpoint.
+type UpdateWebhookRequest struct {
+	URL      *string            `json:"url"`
+	Events   []domain.EventType `json:"events"`
+	IsActive *bool              `json:"is_active"`
+}
+
+// WebhookDeliveryPage é uma página do registro de entregas.
+type WebhookDeliveryPage struct {
+	Deliveries []domain.WebhookDelivery
+	Page       int
+	PerPage    int
+	Total      int64
+}
+
+// CreateEndpoint cadastra um endpoint da instituição. O segredo usado nas assinaturas
+// é gerado aqui e só aparece nesta resposta.
+func (s *WebhookService) CreateEndpoint(institutionID uuid.UUID, req CreateWebhookRequest) (*domain.WebhookEndpoint, error) {
+	if err := s.requireInstitution(institutionID); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookEvents(req.Events); err != nil {
+		return nil, err
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := &domain.WebhookEndpoint{
+		InstitutionID: institutionID,
+		URL:           req.URL,
+		Secret:        secret,
+		Events:        req.Events,
+		IsActive:      true,
+	}
+	if err := s.webhookRepo.CreateEndpoint(endpoint); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// ListEndpoints retorna os endpoints da instituição, sem os segredos.
+func (s *WebhookService) ListEndpoints(institutionID uuid.UUID) ([]domain.WebhookEndpoint, error) {
+	endpoints, err := s.webhookRepo.FindEndpointsByInstitution(institutionID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range endpoints {
+		endpoints[i].Secret = ""
+	}
+	return endpoints, nil
+}
+
+// GetEndpoint retorna um endpoint da instituição, sem o segredo.
+func (s *WebhookService) GetEndpoint(institutionID, id uuid.UUID) (*domain.WebhookEndpoint, error) {
+	endpoint, err := s.webhookRepo.FindEndpoint(institutionID, id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint.Secret = ""
+	return endpoint, nil
+}
+
+// UpdateEndpoint altera o endereço, os eventos assinados ou a ativação de um endpoint.
+func (s *WebhookService) UpdateEndpoint(institutionID, id uuid.UUID, req UpdateWebhookRequest) (*domain.WebhookEndpoint, error) {
+	endpoint, err := s.webhookRepo.FindEndpoint(institutionID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+		endpoint.URL = *req.URL
+	}
+	if req.Events != nil {
+		if err := validateWebhookEvents(req.Events); err != nil {
+			return nil, err
+		}
+		endpoint.Events = req.Events
+	}
+	if req.IsActive != nil {
+		endpoint.IsActive = *req.IsActive
+	}
+
+	if err := s.webhookRepo.UpdateEndpoint(endpoint); err != nil {
+		return nil, err
+	}
+	endpoint.Secret = ""
+	return endpoint, nil
+}
+
+// DeleteEndpoint remove um endpoint da instituição.
+func (s *WebhookService) DeleteEndpoint(institutionID, id uuid.UUID) error {
+	return s.webhookRepo.DeleteEndpoint(institutionID, id)
+}
+
+// ListDeliveries retorna uma página do registro de entregas do endpoint, das mais recentes
+// para as mais antigas.
+func (s *WebhookService) ListDeliveries(institutionID, endpointID uuid.UUID, page, perPage int) (*WebhookDeliveryPage, error) {
+	if _, err := s.webhookRepo.FindEndpoint(institutionID, endpointID); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = DefaultWebhookPageSize
+	}
+	if perPage > MaxWebhookPageSize {
+		perPage = MaxWebhookPageSize
+	}
+
+	deliveries, total, err := s.webhookRepo.FindDeliveries(endpointID, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhookDeliveryPage{Deliveries: deliveries, Page: page, PerPage: perPage, Total: total}, nil
+}
+
+// SendTest envia imediatamente um evento webhook.test ao endpoint, mesmo desativado,
+// e retorna a entrega com o status da resposta. Entregas de teste não têm novas tentativas.
+func (s *WebhookService) SendTest(ctx context.Context, institutionID, endpointID uuid.UUID) (*domain.WebhookDelivery, error) {
+	endpoint, err := s.webhookRepo.FindEndpoint(institutionID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := domain.Event{
+		ID:         uuid.New(),
+		Type:       domain.EventWebhookTest,
+		UserIDs:    []uuid.UUID{institutionID},
+		ActorID:    institutionID,
+		OccurredAt: time.Now().UTC(),
+	}
+	delivery, err := newWebhookDelivery(endpoint, event)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.webhookRepo.EnqueueDelivery(delivery); err != nil {
+		return nil, err
+	}
+
+	delivery.Endpoint = *endpoint
+	s.attempt(ctx, delivery, time.Now().UTC(), false)
+	if err := s.webhookRepo.SaveDeliveryAttempt(delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// Handle enfileira o evento para os endpoints ativos das instituições envolvidas que o
+// assinam. É registrado como handler do outbox; reprocessar o mesmo evento não duplica entregas.
+func (s *WebhookService) Handle(ctx context.Context, event domain.Event) error {
+	endpoints, err := s.webhookRepo.FindActiveEndpoints(event.UserIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range endpoints {
+		endpoint := &endpoints[i]
+		if !endpoint.Subscribes(event.Type) {
+			continue
+		}
+		delivery, err := newWebhookDelivery(endpoint, event)
+		if err != nil {
+			return err
+		}
+		if _, err := s.webhookRepo.EnqueueDelivery(delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run envia as entregas pendentes a cada intervalo até o contexto ser cancelado.
+func (s *WebhookService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if sent, err := s.DeliverDue(ctx, time.Now().UTC()); err != nil {
+			log.Printf("Webhooks: %d entregues, com erros: %v", sent, err)
+		} else if sent > 0 {
+			log.Printf("Webhooks: %d entregues", sent)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue envia as entregas cuja tentativa já chegou e retorna quantas foram aceitas.
+// Falhas são reagendadas com backoff exponencial até o limite de tentativas.
+func (s *WebhookService) DeliverDue(ctx context.Context, now time.Time) (int, error) {
+	deliveries, err := s.webhookRepo.FindDueDeliveries(now, s.options.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	var errs []error
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			break
+		}
+		delivery := &deliveries[i]
+
+		leased, err := s.webhookRepo.LeaseDelivery(delivery, now.Add(s.options.Lease))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !leased {
+			continue // Outra instância está enviando
+		}
+
+		if !delivery.Endpoint.IsActive {
+			delivery.Status = domain.WebhookDeliveryFailed
+			delivery.Error = "webhook desativado"
+		} else {
+			s.attempt(ctx, delivery, now, true)
+		}
+
+		switch delivery.Status {
+		case domain.WebhookDeliverySucceeded:
+			delivered++
+		case domain.WebhookDeliveryPending, domain.WebhookDeliveryFailed:
+			errs = append(errs, fmt.Errorf("entrega %s para %s: %s", delivery.ID, delivery.Endpoint.URL, delivery.Error))
+		}
+		if err := s.webhookRepo.SaveDeliveryAttempt(delivery); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return delivered, errors.Join(errs...)
+}
+
+// attempt envia a entrega e registra o resultado. Com retry, uma falha reagenda a entrega
+// até o limite de tentativas; sem retry, a falha é definitiva.
+func (s *WebhookService) attempt(ctx context.Context, delivery *domain.WebhookDelivery, now time.Time, retry bool) {
+	delivery.Attempts++
+	resp, err := s.sender.Send(ctx, webhook.Request{
+		ID:     delivery.ID.String(),
+		Event:  string(delivery.EventType),
+		URL:    delivery.Endpoint.URL,
+		Secret: delivery.Endpoint.Secret,
+		Body:   []byte(delivery.Payload),
+	})
+
+	delivery.ResponseStatus, delivery.DurationMs = 0, 0
+	if resp != nil {
+		delivery.ResponseStatus = resp.StatusCode
+		delivery.DurationMs = resp.Duration.Milliseconds()
+	}
+
+	switch {
+	case err != nil:
+		delivery.Error = err.Error()
+	case !resp.Success():
+		delivery.Error = fmt.Sprintf("o receptor respondeu HTTP %d", resp.StatusCode)
+	default:
+		delivery.Status = domain.WebhookDeliverySucceeded
+		delivery.Error = ""
+		delivery.DeliveredAt = &now
+		return
+	}
+
+	if len(delivery.Error) > maxWebhookErrorLength {
+		delivery.Error = delivery.Error[:maxWebhookErrorLength]
+	}
+	if !retry || delivery.Attempts >= s.options.MaxAttempts {
+		delivery.Status = domain.WebhookDeliveryFailed
+		return
+	}
+	delivery.NextAttemptAt = now.Add(exponentialBackoff(s.options.BaseBackoff, s.options.MaxBackoff, delivery.Attempts))
+}
+
+// requireInstitution verifica se o usuário é uma instituição.
+func (s *WebhookService) requireInstitution(userID uuid.UUID) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.UserType != domain.UserTypeInstitution {
+		return ErrInstitutionOnly
+	}
+	return nil
+}
+
+// newWebhookDelivery monta a entrega pendente do evento para o endpoint.
+func newWebhookDelivery(endpoint *domain.WebhookEndpoint, event domain.Event) (*domain.WebhookDelivery, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.WebhookDelivery{
+		EndpointID:    endpoint.ID,
+		EventID:       event.ID,
+		EventType:     event.Type,
+		Payload:       string(payload),
+		Status:        domain.WebhookDeliveryPending,
+		NextAttemptAt: event.OccurredAt,
+	}, nil
+}
+
+// validateWebhookURL exige um endereço absoluto http ou https que não aponte para a rede
+// interna. Nomes de domínio são conferidos de novo a cada conexão pelo cliente de webhooks.
+func validateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return errors.New("URL do webhook inválida: use um endereço http(s) completo")
+	}
+	host := strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return webhook.ErrForbiddenAddress
+	}
+	if ip := net.ParseIP(host); ip != nil && webhook.IsForbiddenIP(ip) {
+		return webhook.ErrForbiddenAddress
+	}
+	return nil
+}
+
+// validateWebhookEvents exige ao menos um tipo de evento, todos conhecidos.
+func validateWebhookEvents(events []domain.EventType) error {
+	if len(events) == 0 {
+		return errors.New("informe ao menos um evento")
+	}
+	for _, eventType := range events {
+		if !eventType.IsValid() {
+			return fmt.Errorf("evento desconhecido: %s", eventType)
+		}
+	}
+	return nil
+}
+
+// newWebhookSecret gera um segredo aleatório de 256 bits.
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
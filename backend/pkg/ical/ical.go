@@ -0,0 +1,202 @@
+// Package ical gera arquivos iCalendar (RFC 5545) para exportar agendamentos
+// para aplicativos de calendário (Google Agenda, Apple Calendário, Outlook).
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Métodos iTIP (RFC 5546) usados nos calendários gerados.
+const (
+	MethodPublish = "PUBLISH" // Evento informativo ou feed de assinatura
+	MethodCancel  = "CANCEL"  // Cancelamento de um evento publicado antes
+)
+
+// Status de um evento.
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+	StatusCancelled = "CANCELLED"
+)
+
+// dateTimeLayout é o formato de data-hora UTC do iCalendar.
+const dateTimeLayout = "20060102T150405Z"
+
+// maxLineOctets é o tamanho máximo de uma linha antes da dobra (RFC 5545, seção 3.1).
+const maxLineOctets = 75
+
+// Calendar representa um VCALENDAR com seus eventos.
+type Calendar struct {
+	ProdID string // Identificador do produto que gerou o calendário
+	Method string // PUBLISH ou CANCEL
+	Name   string // Nome exibido em feeds de assinatura (X-WR-CALNAME)
+	Events []Event
+}
+
+// Attendee representa um participante do evento.
+type Attendee struct {
+	Name  string
+	Email string
+}
+
+// Event representa um VEVENT.
+// O UID deve ser estável para que atualizações (SEQUENCE maior) e cancelamentos
+// substituam o evento já importado em vez de duplicá-lo.
+type Event struct {
+	UID         string
+	Sequence    int
+	Stamp       time.Time // DTSTAMP: momento da última alteração
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Status      string
+	Organizer   *Attendee
+	Attendees   []Attendee
+}
+
+// Encode escreve o calendário no formato iCalendar, com quebras de linha CRLF.
+func (c *Calendar) Encode(w io.Writer) error {
+	lw := &lineWriter{w: w}
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:" + stripControls(c.ProdID))
+	lw.line("CALSCALE:GREGORIAN")
+	if c.Method != "" {
+		lw.line("METHOD:" + c.Method)
+	}
+	if c.Name != "" {
+		lw.line("X-WR-CALNAME:" + escapeText(c.Name))
+	}
+	for i := range c.Events {
+		c.Events[i].encode(lw)
+	}
+	lw.line("END:VCALENDAR")
+	return lw.err
+}
+
+// String retorna o calendário codificado.
+func (c *Calendar) String() string {
+	var b strings.Builder
+	_ = c.Encode(&b)
+	return b.String()
+}
+
+func (e *Event) encode(lw *lineWriter) {
+	lw.line("BEGIN:VEVENT")
+	lw.line("UID:" + stripControls(e.UID))
+	lw.line(fmt.Sprintf("SEQUENCE:%d", e.Sequence))
+	lw.line("DTSTAMP:" + formatTime(e.Stamp))
+	lw.line("DTSTART:" + formatTime(e.Start))
+	lw.line("DTEND:" + formatTime(e.End))
+	lw.line("SUMMARY:" + escapeText(e.Summary))
+	if e.Description != "" {
+		lw.line("DESCRIPTION:" + escapeText(e.Description))
+	}
+	if e.Location != "" {
+		lw.line("LOCATION:" + escapeText(e.Location))
+	}
+	if e.URL != "" {
+		lw.line("URL:" + stripControls(e.URL))
+	}
+	if e.Status != "" {
+		lw.line("STATUS:" + stripControls(e.Status))
+	}
+	if e.Organizer != nil {
+		lw.line("ORGANIZER" + calAddress(*e.Organizer))
+	}
+	for _, attendee := range e.Attendees {
+		lw.line("ATTENDEE" + calAddress(attendee))
+	}
+	lw.line("END:VEVENT")
+}
+
+// calAddress formata os parâmetros e o endereço (mailto:) de um participante.
+func calAddress(a Attendee) string {
+	var b strings.Builder
+	if a.Name != "" {
+		b.WriteString(";CN=")
+		b.WriteString(quoteParam(a.Name))
+	}
+	b.WriteString(":mailto:")
+	b.WriteString(stripControls(a.Email))
+	return b.String()
+}
+
+// formatTime formata o horário em UTC.
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
+
+// escapeText escapa valores do tipo TEXT (RFC 5545, seção 3.3.11). Quebras de linha
+// viram \n e os demais caracteres de controle são removidos.
+func escapeText(value string) string {
+	return stripControls(strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(value))
+}
+
+// quoteParam coloca entre aspas valores de parâmetro com caracteres especiais.
+func quoteParam(value string) string {
+	value = strings.ReplaceAll(stripControls(value), `"`, "'")
+	if strings.ContainsAny(value, ";:,") {
+		return `"` + value + `"`
+	}
+	return value
+}
+
+// stripControls remove os caracteres de controle (CTL, RFC 5545 seção 3.1). Um CR ou LF
+// vindo de dados do usuário encerraria a linha e permitiria injetar propriedades.
+func stripControls(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, value)
+}
+
+// lineWriter escreve linhas de conteúdo dobradas em 75 octetos, guardando o primeiro erro.
+type lineWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (lw *lineWriter) line(content string) {
+	if lw.err != nil {
+		return
+	}
+	_, lw.err = io.WriteString(lw.w, fold(content)+"\r\n")
+}
+
+// fold dobra a linha sem quebrar caracteres UTF-8; continuações começam com espaço.
+func fold(content string) string {
+	if len(content) <= maxLineOctets {
+		return content
+	}
+
+	var b strings.Builder
+	width := 0
+	limit := maxLineOctets
+	for _, r := range content {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 0
+			limit = maxLineOctets - 1 // O espaço inicial conta no tamanho da linha
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
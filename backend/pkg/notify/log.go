@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// LogNotifier grava cada mensagem como uma linha JSON em um io.Writer
+// (stdout ou um arquivo de saída), útil em desenvolvimento e testes.
+type LogNotifier struct {
+	mu  sync.Mutex
+	out io.Writer
+	now func() time.Time
+}
+
+// NewLogNotifier cria um notificador que grava as mensagens em out.
+func NewLogNotifier(out io.Writer) *LogNotifier {
+	return &LogNotifier{out: out, now: time.Now}
+}
+
+// Channel implementa Notifier.
+func (n *LogNotifier) Channel() string {
+	return "log"
+}
+
+// Send implementa Notifier.
+func (n *LogNotifier) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	line, err := json.Marshal(struct {
+		SentAt time.Time `json:"sent_at"`
+		Message
+	}{n.now().UTC(), msg})
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, err = n.out.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,21 @@
+// Package notify envia mensagens aos usuários por canais plugáveis
+// (e-mail via SMTP, log/arquivo de saída para desenvolvimento).
+package notify
+
+import "context"
+
+// Message representa uma mensagem a ser entregue a um destinatário.
+type Message struct {
+	To      string `json:"to"`      // Endereço do destinatário (e-mail)
+	Name    string `json:"name"`    // Nome do destinatário
+	Subject string `json:"subject"` // Assunto
+	Body    string `json:"body"`    // Corpo em texto simples
+}
+
+// Notifier entrega mensagens por um canal.
+type Notifier interface {
+	// Channel identifica o canal (ex.: "email", "log").
+	Channel() string
+	// Send entrega a mensagem; erros indicam que ela não foi entregue.
+	Send(ctx context.Context, msg Message) error
+}
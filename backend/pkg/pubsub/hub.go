@@ -0,0 +1,227 @@
+// Package pubsub implementa um barramento de eventos em memória, com histórico
+// recente por assinante para retomar a entrega após uma reconexão.
+package pubsub
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tamanhos padrão do histórico por chave e do buffer de cada assinatura, e tempo padrão
+// durante o qual o histórico de uma chave sem assinantes continua disponível para retomada.
+const (
+	DefaultHistorySize = 100
+	DefaultRetention   = 10 * time.Minute
+	subscriptionBuffer = 64
+)
+
+// Message é um evento entregue aos assinantes de uma chave.
+// O ID tem o formato "<época>-<sequência>"; a época muda a cada reinício do processo.
+type Message struct {
+	ID   string
+	Type string
+	Data []byte
+}
+
+// Hub distribui mensagens para as assinaturas de cada chave (ex.: o ID do usuário)
+// e guarda as últimas mensagens de cada chave para reenvio. O histórico de uma chave
+// sem assinantes é descartado quando a última publicação fica mais antiga que retention.
+type Hub struct {
+	mu          sync.Mutex
+	epoch       string
+	seq         uint64
+	historySize int
+	retention   time.Duration
+	history     map[string][]entry
+	trimmed     map[string]uint64 // Última sequência descartada do histórico de cada chave
+	evicted     uint64            // Maior sequência já descartada junto com o histórico de uma chave
+	pruned      time.Time         // Última varredura de históricos expirados
+	subscribers map[string]map[*Subscription]struct{}
+}
+
+// NewHub cria um hub que guarda até historySize mensagens por chave, por até retention
+// após a última publicação quando a chave não tem assinantes.
+func NewHub(historySize int, retention time.Duration) *Hub {
+	if historySize <= 0 {
+		historySize = DefaultHistorySize
+	}
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &Hub{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		historySize: historySize,
+		retention:   retention,
+		pruned:      time.Now(),
+		history:     make(map[string][]entry),
+		trimmed:     make(map[string]uint64),
+		subscribers: make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// entry é uma mensagem do histórico com a sua sequência e o instante da publicação.
+type entry struct {
+	seq         uint64
+	message     Message
+	publishedAt time.Time
+}
+
+// Subscription recebe as mensagens publicadas para uma chave.
+type Subscription struct {
+	hub    *Hub
+	key    string
+	ch     chan Message
+	closed bool
+}
+
+// C devolve o canal de mensagens. O canal é fechado quando a assinatura é encerrada,
+// inclusive se o assinante não acompanhar o ritmo das publicações; nesse caso o cliente
+// deve se reconectar informando o último ID recebido.
+func (s *Subscription) C() <-chan Message {
+	return s.ch
+}
+
+// Close encerra a assinatura.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
+
+// Publish entrega a mensagem a todas as assinaturas das chaves informadas
+// e a registra no histórico de cada uma. Devolve o ID atribuído.
+func (h *Hub) Publish(messageType string, data []byte, keys ...string) string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(h.pruned) >= h.retention {
+		h.prune(now)
+	}
+
+	h.seq++
+	message := Message{ID: fmt.Sprintf("%s-%d", h.epoch, h.seq), Type: messageType, Data: data}
+
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if _, ok := h.history[key]; !ok && h.evicted > 0 {
+			// A chave pode ter tido um histórico já descartado: IDs anteriores não são retomáveis
+			h.trimmed[key] = h.evicted
+		}
+		history := append(h.history[key], entry{seq: h.seq, message: message, publishedAt: now})
+		if excess := len(history) - h.historySize; excess > 0 {
+			h.trimmed[key] = history[excess-1].seq
+			history = append([]entry(nil), history[excess:]...)
+		}
+		h.history[key] = history
+
+		for subscription := range h.subscribers[key] {
+			select {
+			case subscription.ch <- message:
+			default:
+				// Assinante lento: encerra para não bloquear os demais
+				h.remove(subscription)
+			}
+		}
+	}
+	return message.ID
+}
+
+// Subscribe assina a chave. Com lastID, devolve também as mensagens posteriores a ele
+// que ainda estão no histórico; complete é false quando não é possível garantir que
+// nada foi perdido (ID de outra época ou já fora do histórico), e o cliente deve
+// recarregar o estado completo.
+func (h *Hub) Subscribe(key, lastID string) (subscription *Subscription, missed []Message, complete bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subscription = &Subscription{hub: h, key: key, ch: make(chan Message, subscriptionBuffer)}
+	if h.subscribers[key] == nil {
+		h.subscribers[key] = make(map[*Subscription]struct{})
+	}
+	h.subscribers[key][subscription] = struct{}{}
+
+	if lastID == "" {
+		return subscription, nil, true
+	}
+
+	seq, ok := h.parseID(lastID)
+	if !ok {
+		return subscription, nil, false
+	}
+
+	history, ok := h.history[key]
+	if !ok {
+		// Sem histórico, a chave não recebeu mensagens ou teve o histórico descartado
+		return subscription, nil, seq >= h.evicted
+	}
+	for _, e := range history {
+		if e.seq > seq {
+			missed = append(missed, e.message)
+		}
+	}
+	return subscription, missed, seq >= h.trimmed[key]
+}
+
+// Prune descarta o histórico das chaves sem assinantes cuja última publicação é anterior
+// a now menos o tempo de retenção, e devolve quantas chaves foram descartadas. Publish
+// já faz essa varredura periodicamente.
+func (h *Hub) Prune(now time.Time) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.prune(now)
+}
+
+// prune implementa Prune; exige h.mu travado.
+func (h *Hub) prune(now time.Time) int {
+	h.pruned = now
+	expired := 0
+	for key, history := range h.history {
+		last := history[len(history)-1]
+		if len(h.subscribers[key]) > 0 || now.Sub(last.publishedAt) < h.retention {
+			continue
+		}
+		if last.seq > h.evicted {
+			h.evicted = last.seq
+		}
+		delete(h.history, key)
+		delete(h.trimmed, key)
+		expired++
+	}
+	return expired
+}
+
+// remove desfaz a assinatura e fecha o canal; exige h.mu travado.
+func (h *Hub) remove(subscription *Subscription) {
+	if subscription.closed {
+		return
+	}
+	subscription.closed = true
+	close(subscription.ch)
+
+	delete(h.subscribers[subscription.key], subscription)
+	if len(h.subscribers[subscription.key]) == 0 {
+		delete(h.subscribers, subscription.key)
+	}
+}
+
+// parseID extrai a sequência de um ID desta época.
+func (h *Hub) parseID(id string) (uint64, bool) {
+	epoch, seq, found := strings.Cut(id, "-")
+	if !found || epoch != h.epoch {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil || n > h.seq {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,114 @@
+// Package ratelimit limita a taxa de requisições por chave (IP, conta, usuário) com o
+// algoritmo de token bucket. O estado fica atrás da interface Store, para que a
+// implementação em memória possa ser trocada por uma compartilhada entre instâncias.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit define a capacidade do bucket: até Requests requisições seguidas, repostas
+// de forma contínua ao longo de Period.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// Enabled indica se o limite está configurado; limites zerados não restringem nada.
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+// interval é o tempo para repor uma requisição no bucket.
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+// Result é o resultado de uma tentativa de consumir o bucket de uma chave.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int           // Requisições ainda disponíveis agora
+	Reset      time.Duration // Tempo até o bucket voltar a ficar cheio
+	RetryAfter time.Duration // Tempo até a próxima requisição ser aceita, quando negada
+}
+
+// Store guarda os buckets de cada chave.
+type Store interface {
+	// Take consome uma requisição do bucket da chave, se houver.
+	Take(key string, limit Limit, now time.Time) Result
+}
+
+// sweepInterval é o intervalo mínimo entre duas limpezas dos buckets cheios.
+const sweepInterval = time.Minute
+
+// MemoryStore guarda os buckets em memória, no próprio processo. Com mais de uma
+// instância da API cada uma aplica o limite separadamente.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// bucket guarda as requisições disponíveis de uma chave na última atualização.
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	limit     Limit
+}
+
+// NewMemoryStore cria um store em memória vazio.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket)}
+}
+
+// Take implementa Store.
+func (s *MemoryStore) Take(key string, limit Limit, now time.Time) Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Requests), updatedAt: now, limit: limit}
+		s.buckets[key] = b
+	}
+	b.refill(limit, now)
+
+	result := Result{Limit: limit.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - b.tokens) * float64(limit.interval()))
+	}
+	result.Remaining = int(math.Floor(b.tokens))
+	result.Reset = time.Duration((float64(limit.Requests) - b.tokens) * float64(limit.interval()))
+	return result
+}
+
+// refill repõe as requisições acumuladas desde a última atualização.
+func (b *bucket) refill(limit Limit, now time.Time) {
+	elapsed := now.Sub(b.updatedAt)
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(limit.Requests), b.tokens+float64(elapsed)/float64(limit.interval()))
+		b.updatedAt = now
+	}
+	b.limit = limit
+}
+
+// sweep descarta os buckets que já estariam cheios, equivalentes a uma chave nova.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		b.refill(b.limit, now)
+		if b.tokens >= float64(b.limit.Requests) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
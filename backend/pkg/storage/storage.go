@@ -0,0 +1,26 @@
+// Package storage guarda arquivos enviados pelos usuários (como os documentos da
+// verificação de voluntários) em um armazenamento plugável, identificados por uma chave.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound indica que não existe arquivo com a chave informada.
+var ErrNotFound = errors.New("arquivo não encontrado")
+
+// ErrInvalidKey indica uma chave vazia, absoluta ou que sai da raiz do armazenamento.
+var ErrInvalidKey = errors.New("chave de arquivo inválida")
+
+// Storage grava, lê e remove arquivos por chave. As chaves usam "/" como separador
+// (ex.: "verification/<voluntário>/<documento>.pdf").
+type Storage interface {
+	// Save grava o conteúdo na chave, substituindo um arquivo existente.
+	Save(ctx context.Context, key string, content io.Reader) error
+	// Open abre o arquivo para leitura; quem chama deve fechá-lo.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete remove o arquivo; remover uma chave inexistente não é erro.
+	Delete(ctx context.Context, key string) error
+}
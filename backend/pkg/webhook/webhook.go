@@ -0,0 +1,203 @@
+// Package webhook envia eventos assinados com HMAC-SHA256 para endpoints HTTP de terceiros.
+//
+// Cada requisição leva os headers:
+//
+//	X-Webhook-Id:        identificador da entrega (o mesmo em todas as tentativas)
+//	X-Webhook-Event:     tipo do evento
+//	X-Webhook-Timestamp: instante do envio, em segundos Unix
+//	X-Webhook-Signature: "sha256=" + HMAC-SHA256(segredo, timestamp + "." + corpo), em hexadecimal
+//
+// O receptor deve recalcular a assinatura com Verify e rejeitar timestamps antigos.
+//
+// O Client recusa conexões com endereços internos (loopback, redes privadas, NAT de
+// operadora, NAT64, link-local e não especificados), conferidos no momento da conexão para cobrir também nomes que
+// passam a resolver para esses endereços depois do cadastro.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress indica um endpoint em endereço interno, que não recebe webhooks.
+var ErrForbiddenAddress = errors.New("o endereço do webhook aponta para uma rede interna")
+
+// Headers enviados em cada entrega.
+const (
+	HeaderID        = "X-Webhook-Id"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+
+	signaturePrefix = "sha256="
+	maxResponseBody = 64 << 10 // Lido e descartado, para reaproveitar a conexão
+)
+
+// Request é uma entrega a ser enviada.
+type Request struct {
+	ID     string // Identificador da entrega
+	Event  string // Tipo do evento
+	URL    string
+	Secret string
+	Body   []byte // JSON do evento
+}
+
+// Response é o resultado de uma tentativa de entrega.
+type Response struct {
+	StatusCode int
+	Duration   time.Duration
+}
+
+// Success indica se o receptor aceitou a entrega (resposta 2xx).
+func (r *Response) Success() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// Client envia as entregas por HTTP.
+type Client struct {
+	http      *http.Client
+	userAgent string
+}
+
+// NewClient cria um cliente com o tempo limite informado por requisição.
+// Redirecionamentos não são seguidos: o endpoint cadastrado deve responder diretamente.
+// Conexões com endereços internos são recusadas com ErrForbiddenAddress.
+func NewClient(timeout time.Duration, userAgent string) *Client {
+	return newClient(timeout, userAgent, func(network, address string, _ syscall.RawConn) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+		if ip := net.ParseIP(host); ip == nil || IsForbiddenIP(ip) {
+			return ErrForbiddenAddress
+		}
+		return nil
+	})
+}
+
+// NewUnrestrictedClient cria um cliente que também entrega em endereços internos.
+// Serve apenas para testes com receptores locais.
+func NewUnrestrictedClient(timeout time.Duration, userAgent string) *Client {
+	return newClient(timeout, userAgent, nil)
+}
+
+// newClient monta o cliente HTTP com a verificação de endereço informada. O proxy do
+// ambiente não é usado, para que a verificação valha para o endereço do receptor.
+func newClient(timeout time.Duration, userAgent string, control func(network, address string, c syscall.RawConn) error) *Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: control}
+	return &Client{
+		http: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+				MaxIdleConns:        10,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		userAgent: userAgent,
+	}
+}
+
+// forbiddenNetworks são as faixas internas que net.IP não classifica: "esta rede"
+// (0.0.0.0/8), o NAT de operadora (100.64.0.0/10) e os prefixos NAT64, que levam a
+// qualquer IPv4, inclusive os internos, pelo tradutor da rede.
+var forbiddenNetworks = parseNetworks("0.0.0.0/8", "100.64.0.0/10", "64:ff9b::/96", "64:ff9b:1::/48")
+
+// IsForbiddenIP indica se o endereço é interno: loopback, rede privada, NAT de operadora,
+// NAT64, link-local (incluindo o serviço de metadados das nuvens), multicast ou não
+// especificado. Endereços IPv4 mapeados em IPv6 (::ffff:a.b.c.d) valem como o IPv4.
+func IsForbiddenIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return true
+	}
+	for _, network := range forbiddenNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNetworks converte as faixas em notação CIDR, que são constantes do pacote.
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// Send envia a entrega assinada. Um erro indica falha de rede; respostas não 2xx
+// voltam em Response e devem ser verificadas com Success.
+func (c *Client) Send(ctx context.Context, req Request) (*Response, error) {
+	timestamp := time.Now().Unix()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", c.userAgent)
+	httpReq.Header.Set(HeaderID, req.ID)
+	httpReq.Header.Set(HeaderEvent, req.Event)
+	httpReq.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	httpReq.Header.Set(HeaderSignature, Sign(req.Secret, timestamp, req.Body))
+
+	start := time.Now()
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// O corpo não é guardado: o receptor pode ser qualquer servidor e a resposta não interessa
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Duration:   time.Since(start),
+	}, nil
+}
+
+// Sign calcula a assinatura do corpo enviado no instante informado.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify confere a assinatura recebida, rejeitando timestamps com mais de tolerance de diferença.
+func Verify(secret, signature, timestamp string, body []byte, tolerance time.Duration) bool {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := time.Since(time.Unix(sent, 0)); age > tolerance || age < -tolerance {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, sent, body)))
+}
@@ -0,0 +1,42 @@
+package ical_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"amigos-terceira-idade/pkg/ical"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCalendar_Encode_StripsControlCharacters testa que CRLF em nomes, e-mails e links não
+// injetam propriedades no evento.
+func TestCalendar_Encode_StripsControlCharacters(t *testing.T) {
+	// Arrange
+	start := time.Date(2026, 5, 10, 14, 0, 0, 0, time.UTC)
+	calendar := &ical.Calendar{
+		ProdID: "-//Teste//PT",
+		Events: []ical.Event{{
+			UID:       "evento@teste",
+			Start:     start,
+			End:       start.Add(time.Hour),
+			Summary:   "Conversa",
+			URL:       "https://meet.example.com/x\r\nATTACH:https://evil.example.com/a",
+			Organizer: &ical.Attendee{Name: "Maria\r\nATTACH:https://evil.example.com/b", Email: "maria@email.com\r\nX-INJ:1"},
+			Attendees: []ical.Attendee{{Name: "João\x00\x1f", Email: "joao@email.com"}},
+		}},
+	}
+
+	// Act
+	encoded := calendar.String()
+
+	// Assert
+	for _, line := range strings.Split(encoded, "\r\n") {
+		assert.False(t, strings.HasPrefix(line, "ATTACH"), line)
+		assert.False(t, strings.HasPrefix(line, "X-INJ"), line)
+	}
+	assert.NotContains(t, encoded, "\x00")
+	assert.NotContains(t, encoded, "\x1f")
+	assert.Contains(t, encoded, `ATTENDEE;CN=João:mailto:joao@email.com`)
+}
@@ -0,0 +1,32 @@
+package middleware_test
+
+import (
+	"net/http"
+	"testing"
+
+	"amigos-terceira-idade/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRequireVerifiedEmail testa que apenas tokens com e-mail confirmado passam.
+func TestRequireVerifiedEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	newVerifiedEngine := func(verified interface{}) *gin.Engine {
+		engine := gin.New()
+		engine.GET("/admin", func(c *gin.Context) {
+			if verified != nil {
+				c.Set("email_verified", verified)
+			}
+			c.Next()
+		}, middleware.RequireVerifiedEmail(), func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		})
+		return engine
+	}
+
+	assert.Equal(t, http.StatusNoContent, serve(newVerifiedEngine(true)))
+	assert.Equal(t, http.StatusForbidden, serve(newVerifiedEngine(false)))
+	assert.Equal(t, http.StatusForbidden, serve(newVerifiedEngine(nil)))
+}
@@ -0,0 +1,119 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"amigos-terceira-idade/internal/middleware"
+	"amigos-terceira-idade/pkg/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newLoginEngine cria um engine com a rota de login limitada pelo e-mail do corpo,
+// devolvendo o corpo recebido pelo handler.
+func newLoginEngine(store ratelimit.Store, limit ratelimit.Limit) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.POST("/login", middleware.RateLimit(store, "login", limit, middleware.ByJSONField("email")), func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+	return engine
+}
+
+// postLogin envia o corpo JSON para a rota de login.
+func postLogin(engine *gin.Engine, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	engine.ServeHTTP(recorder, request)
+	return recorder
+}
+
+// TestRateLimit_ByJSONField testa o limite por conta, os headers e o 429 com Retry-After.
+func TestRateLimit_ByJSONField(t *testing.T) {
+	// Arrange
+	engine := newLoginEngine(ratelimit.NewMemoryStore(), ratelimit.Limit{Requests: 2, Period: time.Minute})
+	body := `{"email":"Maria@Email.com","password":"errada"}`
+
+	// Act
+	first := postLogin(engine, body)
+	postLogin(engine, `{"email":"maria@email.com","password":"errada"}`)
+	denied := postLogin(engine, body)
+	other := postLogin(engine, `{"email":"joao@email.com","password":"errada"}`)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, body, first.Body.String())
+	assert.Equal(t, "2", first.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", first.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", first.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, http.StatusTooManyRequests, denied.Code)
+	assert.Equal(t, "30", denied.Header().Get("Retry-After"))
+	assert.Contains(t, denied.Body.String(), "RATE_LIMITED")
+	assert.Equal(t, http.StatusOK, other.Code)
+}
+
+// TestRateLimit_ByJSONField_TooLarge testa que corpos acima de 1 MiB são recusados em vez de truncados.
+func TestRateLimit_ByJSONField_TooLarge(t *testing.T) {
+	// Arrange
+	engine := newLoginEngine(ratelimit.NewMemoryStore(), ratelimit.Limit{Requests: 2, Period: time.Minute})
+	body := `{"email":"maria@email.com","password":"` + strings.Repeat("a", 1<<20) + `"}`
+
+	// Act
+	recorder := postLogin(engine, body)
+
+	// Assert
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "PAYLOAD_TOO_LARGE")
+	assert.Empty(t, recorder.Header().Get("RateLimit-Limit"))
+}
+
+// TestRateLimit_DisabledLimit testa que limites zerados não restringem a rota.
+func TestRateLimit_DisabledLimit(t *testing.T) {
+	// Arrange
+	engine := newLoginEngine(ratelimit.NewMemoryStore(), ratelimit.Limit{})
+
+	// Act
+	recorder := postLogin(engine, `{"email":"maria@email.com"}`)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("RateLimit-Limit"))
+}
+
+// TestRateLimit_ByIP_IgnoresForwardedForWithoutTrustedProxies testa que, sem proxies confiáveis,
+// trocar o X-Forwarded-For não escapa do limite por IP.
+func TestRateLimit_ByIP_IgnoresForwardedForWithoutTrustedProxies(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	assert.NoError(t, engine.SetTrustedProxies(nil))
+	limit := ratelimit.Limit{Requests: 1, Period: time.Minute}
+	engine.GET("/ping", middleware.RateLimit(ratelimit.NewMemoryStore(), "ip", limit, middleware.ByIP), func(c *gin.Context) {
+		c.String(http.StatusOK, c.ClientIP())
+	})
+	get := func(forwardedFor string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		request.RemoteAddr = "203.0.113.7:4000"
+		request.Header.Set("X-Forwarded-For", forwardedFor)
+		engine.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// Act
+	first := get("198.51.100.1")
+	second := get("198.51.100.2")
+
+	// Assert
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "203.0.113.7", first.Body.String())
+	assert.Equal(t, http.StatusTooManyRequests, second.Code)
+}
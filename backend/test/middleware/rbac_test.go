@@ -0,0 +1,89 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// newEngine cria um engine com o papel informado no contexto, como faz o AuthMiddleware.
+func newEngine(role domain.Role, guard gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/admin", func(c *gin.Context) {
+		c.Set("user_role", role)
+		c.Next()
+	}, guard, func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return engine
+}
+
+// serve executa uma requisição no engine e retorna o status.
+func serve(engine *gin.Engine) int {
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/admin", nil))
+	return recorder.Code
+}
+
+// TestRequireRole testa que apenas os papéis listados passam.
+func TestRequireRole(t *testing.T) {
+	guard := middleware.RequireRole(domain.RoleAdmin, domain.RoleModerator)
+
+	assert.Equal(t, http.StatusNoContent, serve(newEngine(domain.RoleAdmin, guard)))
+	assert.Equal(t, http.StatusNoContent, serve(newEngine(domain.RoleModerator, guard)))
+	assert.Equal(t, http.StatusForbidden, serve(newEngine(domain.RoleInstitutionStaff, guard)))
+	assert.Equal(t, http.StatusForbidden, serve(newEngine("", guard)))
+}
+
+// TestRequirePermission testa a tabela de permissões de cada papel.
+func TestRequirePermission(t *testing.T) {
+	deactivate := middleware.RequirePermission(domain.PermissionUsersDeactivate)
+	manageRoles := middleware.RequirePermission(domain.PermissionRolesManage)
+
+	assert.Equal(t, http.StatusNoContent, serve(newEngine(domain.RoleModerator, deactivate)))
+	assert.Equal(t, http.StatusForbidden, serve(newEngine(domain.RoleModerator, manageRoles)))
+	assert.Equal(t, http.StatusNoContent, serve(newEngine(domain.RoleAdmin, manageRoles)))
+	assert.Equal(t, http.StatusForbidden, serve(newEngine(domain.RoleInstitutionStaff, deactivate)))
+	assert.Equal(t, http.StatusForbidden, serve(newEngine("", deactivate)))
+}
+
+// TestRequirePermission_WithoutRoleInContext testa que a ausência de papel nega o acesso.
+func TestRequirePermission_WithoutRoleInContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/admin", middleware.RequirePermission(domain.PermissionUsersRead), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	assert.Equal(t, http.StatusForbidden, serve(engine))
+}
+
+// TestActingInstitutionID testa que a equipe atua pela instituição vinculada e os demais pela própria conta.
+func TestActingInstitutionID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+	institutionID := uuid.New()
+
+	acting := func(role domain.Role, staffInstitution *uuid.UUID) uuid.UUID {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Set("user_id", userID)
+		c.Set("user_role", role)
+		if staffInstitution != nil {
+			c.Set("staff_institution_id", *staffInstitution)
+		}
+		return middleware.ActingInstitutionID(c)
+	}
+
+	assert.Equal(t, institutionID, acting(domain.RoleInstitutionStaff, &institutionID))
+	assert.Equal(t, userID, acting(domain.RoleInstitutionStaff, nil))
+	assert.Equal(t, userID, acting("", nil))
+	assert.Equal(t, userID, acting(domain.RoleModerator, &institutionID))
+}
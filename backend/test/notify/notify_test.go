@@ -0,0 +1,137 @@
+package notify_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+
+	"amigos-terceira-idade/pkg/notify"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer aceita uma única conexão e registra os comandos e os dados recebidos.
+type fakeSMTPServer struct {
+	listener net.Listener
+	commands []string
+	data     string
+	done     chan struct{}
+}
+
+// newFakeSMTPServer inicia o servidor em uma porta local livre.
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &fakeSMTPServer{listener: listener, done: make(chan struct{})}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+	return server
+}
+
+func (s *fakeSMTPServer) serve() {
+	defer close(s.done)
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	reply("220 localhost ESMTP fake")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimRight(line, "\r\n")
+		s.commands = append(s.commands, command)
+
+		switch verb := strings.ToUpper(strings.SplitN(command, " ", 2)[0]); verb {
+		case "EHLO", "HELO":
+			reply("250 localhost")
+		case "DATA":
+			reply("354 envie os dados")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			s.data = data.String()
+			reply("250 ok")
+		case "QUIT":
+			reply("221 tchau")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+// TestSMTPNotifier_Send testa o envio de um e-mail para o servidor local.
+func TestSMTPNotifier_Send(t *testing.T) {
+	// Arrange
+	server := newFakeSMTPServer(t)
+	host, port, _ := net.SplitHostPort(server.listener.Addr().String())
+	notifier := notify.NewSMTPNotifier(notify.SMTPConfig{
+		Host: host,
+		Port: port,
+		From: "Amigos da Terceira Idade <nao-responda@example.com>",
+	})
+
+	// Act
+	err := notifier.Send(context.Background(), notify.Message{
+		To:      "jose@example.com",
+		Name:    "Seu José",
+		Subject: "Lembrete: conversa em 15 minutos",
+		Body:    "Olá!\nSua conversa começa em breve.",
+	})
+	<-server.done
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "email", notifier.Channel())
+	assert.Contains(t, server.commands, "MAIL FROM:<nao-responda@example.com>")
+	assert.Contains(t, server.commands, "RCPT TO:<jose@example.com>")
+	assert.Contains(t, server.data, "Subject: Lembrete: conversa em 15 minutos\r\n")
+	assert.Contains(t, server.data, "To: =?utf-8?q?Seu_Jos=C3=A9?= <jose@example.com>\r\n")
+	assert.Contains(t, server.data, "Olá!\r\nSua conversa começa em breve.")
+}
+
+// TestSMTPNotifier_Send_InvalidRecipient testa a rejeição de destinatários sem e-mail.
+func TestSMTPNotifier_Send_InvalidRecipient(t *testing.T) {
+	notifier := notify.NewSMTPNotifier(notify.SMTPConfig{Host: "127.0.0.1", Port: "1"})
+
+	assert.Error(t, notifier.Send(context.Background(), notify.Message{}))
+	assert.Error(t, notifier.Send(context.Background(), notify.Message{To: "a@example.com\r\nBcc: b@example.com"}))
+}
+
+// TestLogNotifier_Send testa a gravação das mensagens como linhas JSON.
+func TestLogNotifier_Send(t *testing.T) {
+	// Arrange
+	var out bytes.Buffer
+	notifier := notify.NewLogNotifier(&out)
+
+	// Act
+	err := notifier.Send(context.Background(), notify.Message{To: "ana@example.com", Subject: "Lembrete"})
+
+	// Assert
+	require.NoError(t, err)
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &line))
+	assert.Equal(t, "ana@example.com", line["to"])
+	assert.Equal(t, "Lembrete", line["subject"])
+	assert.NotEmpty(t, line["sent_at"])
+}
@@ -0,0 +1,145 @@
+package pubsub_test
+
+import (
+	"testing"
+	"time"
+
+	"amigos-terceira-idade/pkg/pubsub"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHub_Publish_OnlyToKey testa que cada assinatura recebe apenas as mensagens da sua chave.
+func TestHub_Publish_OnlyToKey(t *testing.T) {
+	// Arrange
+	hub := pubsub.NewHub(10, pubsub.DefaultRetention)
+	ana, _, _ := hub.Subscribe("ana", "")
+	defer ana.Close()
+	jose, _, _ := hub.Subscribe("jose", "")
+	defer jose.Close()
+
+	// Act
+	id := hub.Publish("appointment.created", []byte(`{}`), "ana", "ana")
+
+	// Assert
+	require.Len(t, ana.C(), 1)
+	assert.Equal(t, id, (<-ana.C()).ID)
+	assert.Empty(t, jose.C())
+}
+
+// TestHub_Subscribe_ResumesAfterLastID testa o reenvio das mensagens posteriores ao último ID.
+func TestHub_Subscribe_ResumesAfterLastID(t *testing.T) {
+	// Arrange
+	hub := pubsub.NewHub(10, pubsub.DefaultRetention)
+	first := hub.Publish("a", nil, "ana")
+	hub.Publish("outro", nil, "jose")
+	hub.Publish("b", nil, "ana")
+	hub.Publish("c", nil, "ana")
+
+	// Act
+	subscription, missed, complete := hub.Subscribe("ana", first)
+	defer subscription.Close()
+
+	// Assert
+	assert.True(t, complete)
+	require.Len(t, missed, 2)
+	assert.Equal(t, "b", missed[0].Type)
+	assert.Equal(t, "c", missed[1].Type)
+}
+
+// TestHub_Subscribe_IncompleteHistory testa que IDs fora do histórico ou de outra
+// época pedem que o cliente recarregue o estado.
+func TestHub_Subscribe_IncompleteHistory(t *testing.T) {
+	// Arrange
+	hub := pubsub.NewHub(1, pubsub.DefaultRetention)
+	first := hub.Publish("a", nil, "ana")
+	hub.Publish("b", nil, "ana")
+	hub.Publish("c", nil, "ana")
+
+	// Act
+	trimmed, missed, complete := hub.Subscribe("ana", first)
+	defer trimmed.Close()
+	foreign, _, foreignComplete := hub.Subscribe("ana", "outra-epoca-1")
+	defer foreign.Close()
+
+	// Assert
+	assert.False(t, complete) // "b" já saiu do histórico
+	assert.Len(t, missed, 1)
+	assert.False(t, foreignComplete)
+}
+
+// TestHub_Close_StopsDelivery testa que uma assinatura encerrada não recebe mensagens.
+func TestHub_Close_StopsDelivery(t *testing.T) {
+	// Arrange
+	hub := pubsub.NewHub(10, pubsub.DefaultRetention)
+	subscription, _, _ := hub.Subscribe("ana", "")
+
+	// Act
+	subscription.Close()
+	subscription.Close()
+	hub.Publish("a", nil, "ana")
+
+	// Assert
+	_, open := <-subscription.C()
+	assert.False(t, open)
+}
+
+// TestHub_SlowSubscriberIsDropped testa que um assinante que não consome é desconectado
+// sem bloquear a publicação.
+func TestHub_SlowSubscriberIsDropped(t *testing.T) {
+	// Arrange
+	hub := pubsub.NewHub(500, pubsub.DefaultRetention)
+	subscription, _, _ := hub.Subscribe("ana", "")
+
+	// Act
+	var last string
+	for i := 0; i < 200; i++ {
+		last = hub.Publish("a", nil, "ana")
+	}
+
+	// Assert
+	count := 0
+	for range subscription.C() {
+		count++
+	}
+	assert.Less(t, count, 200)
+
+	resumed, missed, complete := hub.Subscribe("ana", "")
+	defer resumed.Close()
+	assert.True(t, complete)
+	assert.Empty(t, missed)
+	assert.NotEmpty(t, last)
+}
+
+// TestHub_Prune_EvictsIdleKeys testa que o histórico de chaves sem assinantes é descartado
+// após o tempo de retenção, e que retomar com um ID anterior pede a recarga do estado.
+func TestHub_Prune_EvictsIdleKeys(t *testing.T) {
+	// Arrange
+	hub := pubsub.NewHub(10, time.Minute)
+	first := hub.Publish("a", nil, "ana")
+	hub.Publish("b", nil, "ana")
+	watching, _, _ := hub.Subscribe("jose", "")
+	defer watching.Close()
+	hub.Publish("c", nil, "jose")
+
+	// Act
+	notYet := hub.Prune(time.Now())
+	evicted := hub.Prune(time.Now().Add(2 * time.Minute))
+
+	// Assert
+	assert.Equal(t, 0, notYet)
+	assert.Equal(t, 1, evicted)
+
+	resumed, missed, complete := hub.Subscribe("ana", first)
+	defer resumed.Close()
+	assert.False(t, complete)
+	assert.Empty(t, missed)
+
+	hub.Publish("d", nil, "ana")
+	again, missed, complete := hub.Subscribe("ana", first)
+	defer again.Close()
+	assert.False(t, complete)
+	require.Len(t, missed, 1)
+	assert.Equal(t, "d", missed[0].Type)
+}
@@ -0,0 +1,72 @@
+package ratelimit_test
+
+import (
+	"testing"
+	"time"
+
+	"amigos-terceira-idade/pkg/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var fivePerMinute = ratelimit.Limit{Requests: 5, Period: time.Minute}
+
+// TestMemoryStore_Take_BurstThenDeny testa que o bucket aceita a rajada e nega a seguinte.
+func TestMemoryStore_Take_BurstThenDeny(t *testing.T) {
+	// Arrange
+	store := ratelimit.NewMemoryStore()
+	now := time.Now()
+
+	// Act
+	var results []ratelimit.Result
+	for i := 0; i < 6; i++ {
+		results = append(results, store.Take("ip:1", fivePerMinute, now))
+	}
+
+	// Assert
+	for _, result := range results[:5] {
+		assert.True(t, result.Allowed)
+	}
+	assert.Equal(t, 4, results[0].Remaining)
+	assert.Equal(t, 0, results[4].Remaining)
+	assert.False(t, results[5].Allowed)
+	assert.Equal(t, 12*time.Second, results[5].RetryAfter)
+	assert.Equal(t, time.Minute, results[5].Reset)
+}
+
+// TestMemoryStore_Take_Refills testa a reposição contínua das requisições ao longo do período.
+func TestMemoryStore_Take_Refills(t *testing.T) {
+	// Arrange
+	store := ratelimit.NewMemoryStore()
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		store.Take("ip:1", fivePerMinute, now)
+	}
+
+	// Act
+	early := store.Take("ip:1", fivePerMinute, now.Add(6*time.Second))
+	later := store.Take("ip:1", fivePerMinute, now.Add(13*time.Second))
+
+	// Assert
+	assert.False(t, early.Allowed)
+	assert.Equal(t, 6*time.Second, early.RetryAfter)
+	assert.True(t, later.Allowed)
+	assert.Equal(t, 0, later.Remaining)
+}
+
+// TestMemoryStore_Take_KeysAreIndependent testa que cada chave tem o seu próprio bucket.
+func TestMemoryStore_Take_KeysAreIndependent(t *testing.T) {
+	// Arrange
+	store := ratelimit.NewMemoryStore()
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		store.Take("ip:1", fivePerMinute, now)
+	}
+
+	// Act
+	result := store.Take("ip:2", fivePerMinute, now)
+
+	// Assert
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 4, result.Remaining)
+}
@@ -0,0 +1,271 @@
+package service_test
+
+import (
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"amigos-terceira-idade/internal/config"
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+	"amigos-terceira-idade/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MockAccountTokenRepository implementa repository.AccountTokenRepositoryInterface para testes.
+type MockAccountTokenRepository struct {
+	mock.Mock
+}
+
+var _ repository.AccountTokenRepositoryInterface = (*MockAccountTokenRepository)(nil)
+
+func (m *MockAccountTokenRepository) Create(token *domain.AccountToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockAccountTokenRepository) FindByID(id uuid.UUID) (*domain.AccountToken, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AccountToken), args.Error(1)
+}
+
+func (m *MockAccountTokenRepository) Consume(id uuid.UUID) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAccountTokenRepository) InvalidateByUser(userID uuid.UUID, purpose domain.AccountTokenPurpose) error {
+	args := m.Called(userID, purpose)
+	return args.Error(0)
+}
+
+// accountFixture reúne o serviço de conta e as dependências usadas nos testes.
+type accountFixture struct {
+	service     *service.AccountService
+	userRepo    *MockUserRepository
+	tokenRepo   *MockAccountTokenRepository
+	sessionRepo *MockSessionRepository
+	mailer      *fakeNotifier
+	user        *domain.User
+	issued      *domain.AccountToken
+}
+
+// newAccountFixture cria o serviço com um usuário ativo e guarda o último token emitido.
+func newAccountFixture() *accountFixture {
+	f := &accountFixture{
+		userRepo:    new(MockUserRepository),
+		tokenRepo:   new(MockAccountTokenRepository),
+		sessionRepo: new(MockSessionRepository),
+		mailer:      &fakeNotifier{},
+		user:        &domain.User{ID: uuid.New(), Name: "Dona Maria", Email: "maria@email.com", IsActive: true},
+	}
+	f.service = service.NewAccountService(f.userRepo, f.tokenRepo, f.sessionRepo, f.mailer, config.AccountConfig{
+		AppURL:               "https://app.exemplo.org/",
+		PasswordResetTTL:     time.Hour,
+		EmailVerificationTTL: 48 * time.Hour,
+	}, "test-secret-key", inline)
+
+	f.userRepo.On("FindByID", f.user.ID).Return(f.user, nil).Maybe()
+	f.userRepo.On("FindByEmail", f.user.Email).Return(f.user, nil).Maybe()
+	f.tokenRepo.On("InvalidateByUser", f.user.ID, mock.Anything).Return(nil).Maybe()
+	f.tokenRepo.On("Create", mock.AnythingOfType("*domain.AccountToken")).
+		Run(func(args mock.Arguments) { f.issued = args.Get(0).(*domain.AccountToken) }).
+		Return(nil).Maybe()
+	return f
+}
+
+var tokenInLink = regexp.MustCompile(`\?token=(\S+)`)
+
+// sentToken extrai o token do link no último e-mail enviado e prepara o repositório para resgatá-lo.
+func (f *accountFixture) sentToken(t *testing.T) string {
+	t.Helper()
+	require.NotEmpty(t, f.mailer.sent)
+	match := tokenInLink.FindStringSubmatch(f.mailer.sent[len(f.mailer.sent)-1].Body)
+	require.Len(t, match, 2)
+	token, err := url.QueryUnescape(match[1])
+	require.NoError(t, err)
+
+	f.tokenRepo.On("FindByID", f.issued.ID).Return(f.issued, nil).Maybe()
+	return token
+}
+
+// TestAccountService_ForgotAndResetPassword testa o fluxo completo de redefinição de senha.
+func TestAccountService_ForgotAndResetPassword(t *testing.T) {
+	// Arrange
+	f := newAccountFixture()
+	require.NoError(t, f.service.ForgotPassword(service.ForgotPasswordRequest{Email: f.user.Email}))
+	token := f.sentToken(t)
+
+	f.tokenRepo.On("Consume", f.issued.ID).Return(true, nil)
+	f.userRepo.On("Update", f.user).Return(nil)
+	f.sessionRepo.On("RevokeAllByUser", f.user.ID, domain.SessionRevokedPassword).Return(nil)
+
+	// Act
+	err := f.service.ResetPassword(service.ResetPasswordRequest{Token: token, Password: "novaSenha1"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "maria@email.com", f.mailer.sent[0].To)
+	assert.Contains(t, f.mailer.sent[0].Body, "https://app.exemplo.org/reset-password?token=")
+	assert.Equal(t, domain.AccountTokenPasswordReset, f.issued.Purpose)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(f.user.PasswordHash), []byte("novaSenha1")))
+	assert.True(t, f.user.IsEmailVerified())
+	f.tokenRepo.AssertCalled(t, "InvalidateByUser", f.user.ID, domain.AccountTokenPasswordReset)
+	f.sessionRepo.AssertExpectations(t)
+}
+
+// TestAccountService_ForgotPassword_UnknownEmail testa que e-mails sem conta não geram erro nem envio.
+func TestAccountService_ForgotPassword_UnknownEmail(t *testing.T) {
+	// Arrange
+	f := newAccountFixture()
+	f.userRepo.On("FindByEmail", "ninguem@email.com").Return(nil, assert.AnError)
+
+	// Act
+	err := f.service.ForgotPassword(service.ForgotPasswordRequest{Email: "ninguem@email.com"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Empty(t, f.mailer.sent)
+}
+
+// TestAccountService_ForgotPassword_DeliversInBackground testa que a requisição só agenda o envio,
+// sem consultar a conta, para responder no mesmo tempo com ou sem conta.
+func TestAccountService_ForgotPassword_DeliversInBackground(t *testing.T) {
+	// Arrange
+	var queued []func()
+	userRepo := new(MockUserRepository)
+	mailer := &fakeNotifier{}
+	accountService := service.NewAccountService(userRepo, new(MockAccountTokenRepository), new(MockSessionRepository),
+		mailer, config.AccountConfig{}, "test-secret-key", func(task func()) { queued = append(queued, task) })
+
+	// Act
+	err := accountService.ForgotPassword(service.ForgotPasswordRequest{Email: "maria@email.com"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, queued, 1)
+	userRepo.AssertNotCalled(t, "FindByEmail", mock.Anything)
+	assert.Empty(t, mailer.sent)
+}
+
+// TestAccountService_ResetPassword_TamperedToken testa que um token com assinatura adulterada é recusado.
+func TestAccountService_ResetPassword_TamperedToken(t *testing.T) {
+	// Arrange
+	f := newAccountFixture()
+	require.NoError(t, f.service.ForgotPassword(service.ForgotPasswordRequest{Email: f.user.Email}))
+	token := f.sentToken(t)
+
+	// Act
+	err := f.service.ResetPassword(service.ResetPasswordRequest{Token: token + "x", Password: "novaSenha1"})
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrInvalidAccountToken)
+	f.tokenRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+}
+
+// TestAccountService_ResetPassword_AlreadyUsed testa que o token vale uma única vez.
+func TestAccountService_ResetPassword_AlreadyUsed(t *testing.T) {
+	// Arrange
+	f := newAccountFixture()
+	require.NoError(t, f.service.ForgotPassword(service.ForgotPasswordRequest{Email: f.user.Email}))
+	token := f.sentToken(t)
+	f.tokenRepo.On("Consume", f.issued.ID).Return(false, nil)
+
+	// Act
+	err := f.service.ResetPassword(service.ResetPasswordRequest{Token: token, Password: "novaSenha1"})
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrInvalidAccountToken)
+	f.userRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+// TestAccountService_ResetPassword_Expired testa que um token vencido é recusado.
+func TestAccountService_ResetPassword_Expired(t *testing.T) {
+	// Arrange
+	f := newAccountFixture()
+	require.NoError(t, f.service.ForgotPassword(service.ForgotPasswordRequest{Email: f.user.Email}))
+	token := f.sentToken(t)
+	f.issued.ExpiresAt = time.Now().Add(-time.Minute)
+
+	// Act
+	err := f.service.ResetPassword(service.ResetPasswordRequest{Token: token, Password: "novaSenha1"})
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrInvalidAccountToken)
+	f.tokenRepo.AssertNotCalled(t, "Consume", mock.Anything)
+}
+
+// TestAccountService_VerifyEmail testa a confirmação do e-mail pelo link enviado.
+func TestAccountService_VerifyEmail(t *testing.T) {
+	// Arrange
+	f := newAccountFixture()
+	require.NoError(t, f.service.SendEmailVerification(f.user.ID))
+	token := f.sentToken(t)
+	f.tokenRepo.On("Consume", f.issued.ID).Return(true, nil)
+	f.userRepo.On("Update", f.user).Return(nil)
+
+	// Act
+	user, err := f.service.VerifyEmail(service.VerifyEmailRequest{Token: token})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, user.IsEmailVerified())
+	assert.Contains(t, f.mailer.sent[0].Body, "https://app.exemplo.org/verify-email?token=")
+}
+
+// TestAccountService_VerifyEmail_RejectsResetToken testa que o token de redefinição não confirma o e-mail.
+func TestAccountService_VerifyEmail_RejectsResetToken(t *testing.T) {
+	// Arrange
+	f := newAccountFixture()
+	require.NoError(t, f.service.ForgotPassword(service.ForgotPasswordRequest{Email: f.user.Email}))
+	token := f.sentToken(t)
+
+	// Act
+	user, err := f.service.VerifyEmail(service.VerifyEmailRequest{Token: token})
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrInvalidAccountToken)
+	assert.Nil(t, user)
+}
+
+// TestAccountService_VerifyEmail_EmailChanged testa que o token só vale para o endereço ao qual foi enviado.
+func TestAccountService_VerifyEmail_EmailChanged(t *testing.T) {
+	// Arrange
+	f := newAccountFixture()
+	require.NoError(t, f.service.SendEmailVerification(f.user.ID))
+	token := f.sentToken(t)
+	f.tokenRepo.On("Consume", f.issued.ID).Return(true, nil)
+	f.user.Email = "outro@email.com"
+
+	// Act
+	user, err := f.service.VerifyEmail(service.VerifyEmailRequest{Token: token})
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrInvalidAccountToken)
+	assert.Nil(t, user)
+	assert.False(t, f.user.IsEmailVerified())
+}
+
+// TestAccountService_SendEmailVerification_AlreadyVerified testa que não há novo envio para e-mails confirmados.
+func TestAccountService_SendEmailVerification_AlreadyVerified(t *testing.T) {
+	// Arrange
+	f := newAccountFixture()
+	verifiedAt := time.Now()
+	f.user.EmailVerifiedAt = &verifiedAt
+
+	// Act
+	err := f.service.SendEmailVerification(f.user.ID)
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrEmailAlreadyVerified)
+	assert.Empty(t, f.mailer.sent)
+}
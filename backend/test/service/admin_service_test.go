@@ -0,0 +1,363 @@
+package service_test
+
+import (
+	"testing"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+	"amigos-terceira-idade/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MockAuditRepository implementa repository.AuditRepositoryInterface para testes.
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+var _ repository.AuditRepositoryInterface = (*MockAuditRepository)(nil)
+
+func (m *MockAuditRepository) Create(entry *domain.AuditLog) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) FindPage(filter repository.AuditFilter, limit, offset int) ([]domain.AuditLog, int64, error) {
+	args := m.Called(filter, limit, offset)
+	return args.Get(0).([]domain.AuditLog), args.Get(1).(int64), args.Error(2)
+}
+
+// adminMocks agrupa os repositórios usados pelo AdminService.
+type adminMocks struct {
+	users        *MockUserRepository
+	connections  *MockConnectionRepository
+	appointments *MockAppointmentRepository
+	audit        *MockAuditRepository
+	sessions     *MockSessionRepository
+}
+
+// newAdminService cria o serviço administrativo com repositórios mockados.
+func newAdminService() (*service.AdminService, adminMocks) {
+	mocks := adminMocks{
+		users:        new(MockUserRepository),
+		connections:  new(MockConnectionRepository),
+		appointments: new(MockAppointmentRepository),
+		audit:        new(MockAuditRepository),
+		sessions:     new(MockSessionRepository),
+	}
+	return service.NewAdminService(mocks.users, mocks.connections, mocks.appointments, mocks.audit, mocks.sessions), mocks
+}
+
+// newActor cadastra no mock quem executa as ações administrativas, com o papel informado.
+func newActor(mocks adminMocks, role domain.Role) uuid.UUID {
+	actor := &domain.User{ID: uuid.New(), Role: role}
+	mocks.users.On("FindByID", actor.ID).Return(actor, nil).Maybe()
+	return actor.ID
+}
+
+// auditOf casa o registro de auditoria da ação sobre o usuário.
+func auditOf(actorID uuid.UUID, action domain.AuditAction, targetID uuid.UUID) interface{} {
+	return mock.MatchedBy(func(entry *domain.AuditLog) bool {
+		return entry.ActorID == actorID && entry.Action == action && entry.TargetUserID == targetID
+	})
+}
+
+// expectAudit espera um registro de auditoria da ação sobre o usuário.
+func expectAudit(audit *MockAuditRepository, actorID uuid.UUID, action domain.AuditAction, targetID uuid.UUID) {
+	audit.On("Create", auditOf(actorID, action, targetID)).Return(nil).Once()
+}
+
+// expectAuditedUpdate espera a alteração do usuário gravada junto com o registro de auditoria da ação.
+func expectAuditedUpdate(users *MockUserRepository, user *domain.User, actorID uuid.UUID, action domain.AuditAction) {
+	users.On("UpdateAudited", user, auditOf(actorID, action, user.ID)).Return(nil).Once()
+}
+
+// TestAdminService_SearchUsers_Filters testa o repasse dos filtros e a paginação.
+func TestAdminService_SearchUsers_Filters(t *testing.T) {
+	// Arrange
+	adminService, mocks := newAdminService()
+
+	active := false
+	filter := repository.UserFilter{Query: "silva", UserType: domain.UserTypeElderly, IsActive: &active}
+	users := []domain.User{{ID: uuid.New()}, {ID: uuid.New()}}
+	mocks.users.On("Search", filter, service.MaxAdminPageSize, service.MaxAdminPageSize).Return(users, int64(102), nil)
+
+	// Act
+	result, err := adminService.SearchUsers(service.UserSearch{
+		Query: "silva", UserType: domain.UserTypeElderly, IsActive: &active, Page: 2, PerPage: 500,
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, result.Users, 2)
+	assert.Equal(t, 2, result.Page)
+	assert.Equal(t, service.MaxAdminPageSize, result.PerPage)
+	assert.Equal(t, int64(102), result.Total)
+}
+
+// TestAdminService_SearchUsers_InvalidType testa a recusa de um tipo desconhecido.
+func TestAdminService_SearchUsers_InvalidType(t *testing.T) {
+	// Arrange
+	adminService, mocks := newAdminService()
+
+	// Act
+	_, err := adminService.SearchUsers(service.UserSearch{UserType: "ADMIN"})
+
+	// Assert
+	assert.Error(t, err)
+	mocks.users.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestAdminService_GetUserConnections_Elderly testa que as conexões de um idoso são buscadas como destinatário.
+func TestAdminService_GetUserConnections_Elderly(t *testing.T) {
+	// Arrange
+	adminService, mocks := newAdminService()
+
+	user := &domain.User{ID: uuid.New(), UserType: domain.UserTypeElderly}
+	mocks.users.On("FindByID", user.ID).Return(user, nil)
+	mocks.connections.On("FindByTargetID", user.ID).Return([]domain.Connection{{ID: uuid.New()}}, nil)
+
+	// Act
+	connections, err := adminService.GetUserConnections(user.ID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, connections, 1)
+}
+
+// TestAdminService_DeactivateAndReactivate testa as duas operações e seus registros de auditoria.
+func TestAdminService_DeactivateAndReactivate(t *testing.T) {
+	// Arrange
+	adminService, mocks := newAdminService()
+
+	actorID := newActor(mocks, domain.RoleModerator)
+	user := &domain.User{ID: uuid.New(), IsActive: true}
+	mocks.users.On("FindByID", user.ID).Return(user, nil)
+	expectAuditedUpdate(mocks.users, user, actorID, domain.AuditUserDeactivated)
+	expectAuditedUpdate(mocks.users, user, actorID, domain.AuditUserReactivated)
+
+	// Act
+	errDeactivate := adminService.DeactivateUser(actorID, user.ID)
+	deactivated := user.IsActive
+	errAgain := adminService.DeactivateUser(actorID, user.ID)
+	errReactivate := adminService.ReactivateUser(actorID, user.ID)
+
+	// Assert
+	assert.NoError(t, errDeactivate)
+	assert.False(t, deactivated)
+	assert.EqualError(t, errAgain, "o usuário já está desativado")
+	assert.NoError(t, errReactivate)
+	assert.True(t, user.IsActive)
+	mocks.users.AssertExpectations(t)
+}
+
+// TestAdminService_DeactivateUser_Self testa que o administrador não desativa a própria conta.
+func TestAdminService_DeactivateUser_Self(t *testing.T) {
+	// Arrange
+	adminService, mocks := newAdminService()
+	adminID := uuid.New()
+
+	// Act
+	err := adminService.DeactivateUser(adminID, adminID)
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrSelfAdministration)
+	mocks.users.AssertNotCalled(t, "UpdateAudited", mock.Anything, mock.Anything)
+}
+
+// TestAdminService_SetRole_InstitutionStaff testa que a equipe fica ligada a uma instituição.
+func TestAdminService_SetRole_InstitutionStaff(t *testing.T) {
+	// Arrange
+	adminService, mocks := newAdminService()
+
+	actorID := newActor(mocks, domain.RoleAdmin)
+	user := &domain.User{ID: uuid.New(), UserType: domain.UserTypeVolunteer}
+	institution := &domain.User{ID: uuid.New(), UserType: domain.UserTypeInstitution}
+	mocks.users.On("FindByID", user.ID).Return(user, nil)
+	mocks.users.On("FindByID", institution.ID).Return(institution, nil)
+	expectAuditedUpdate(mocks.users, user, actorID, domain.AuditUserRoleChanged)
+
+	// Act
+	result, err := adminService.SetRole(actorID, user.ID, service.SetRoleRequest{
+		Role:          domain.RoleInstitutionStaff,
+		InstitutionID: &institution.ID,
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RoleInstitutionStaff, result.Role)
+	assert.Equal(t, institution.ID, *result.StaffInstitutionID)
+	mocks.users.AssertExpectations(t)
+}
+
+// TestAdminService_SetRole_Invalid testa papéis desconhecidos e equipe sem instituição.
+func TestAdminService_SetRole_Invalid(t *testing.T) {
+	// Arrange
+	adminService, mocks := newAdminService()
+
+	actorID := newActor(mocks, domain.RoleAdmin)
+	user := &domain.User{ID: uuid.New()}
+	mocks.users.On("FindByID", user.ID).Return(user, nil)
+
+	// Act
+	_, errUnknown := adminService.SetRole(actorID, user.ID, service.SetRoleRequest{Role: "ROOT"})
+	_, errStaff := adminService.SetRole(actorID, user.ID, service.SetRoleRequest{Role: domain.RoleInstitutionStaff})
+
+	// Assert
+	assert.ErrorIs(t, errUnknown, domain.ErrInvalidRole)
+	assert.EqualError(t, errStaff, "informe a instituição da equipe")
+	mocks.users.AssertNotCalled(t, "UpdateAudited", mock.Anything, mock.Anything)
+}
+
+// TestAdminService_ResetPassword testa que a senha temporária passa a valer para o login e encerra as sessões abertas.
+func TestAdminService_ResetPassword(t *testing.T) {
+	// Arrange
+	adminService, mocks := newAdminService()
+
+	actorID := newActor(mocks, domain.RoleAdmin)
+	user := &domain.User{ID: uuid.New(), PasswordHash: "antigo"}
+	mocks.users.On("FindByID", user.ID).Return(user, nil)
+	expectAuditedUpdate(mocks.users, user, actorID, domain.AuditUserPasswordReset)
+	mocks.sessions.On("RevokeAllByUser", user.ID, domain.SessionRevokedPassword).Return(nil).Once()
+
+	// Act
+	password, err := adminService.ResetPassword(actorID, user.ID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, password, 12)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)))
+	mocks.users.AssertExpectations(t)
+	mocks.sessions.AssertExpectations(t)
+}
+
+// TestAdminService_ResetPassword_RevokeFails testa que a senha não é trocada se as sessões
+// não puderem ser encerradas.
+func TestAdminService_ResetPassword_RevokeFails(t *testing.T) {
+	// Arrange
+	adminService, mocks := newAdminService()
+
+	actorID := newActor(mocks, domain.RoleAdmin)
+	user := &domain.User{ID: uuid.New(), PasswordHash: "antigo"}
+	mocks.users.On("FindByID", user.ID).Return(user, nil)
+	mocks.sessions.On("RevokeAllByUser", user.ID, domain.SessionRevokedPassword).Return(assert.AnError)
+
+	// Act
+	password, err := adminService.ResetPassword(actorID, user.ID)
+
+	// Assert
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Empty(t, password)
+	assert.Equal(t, "antigo", user.PasswordHash)
+	mocks.users.AssertNotCalled(t, "UpdateAudited", mock.Anything, mock.Anything)
+}
+
+// TestAdminService_MergeUsers_Refused testa que a recusa do repositório chega a quem pediu a mesclagem.
+func TestAdminService_MergeUsers_Refused(t *testing.T) {
+	// Arrange
+	adminService, mocks := newAdminService()
+
+	actorID := newActor(mocks, domain.RoleAdmin)
+	kept := &domain.User{ID: uuid.New(), UserType: domain.UserTypeVolunteer, IsActive: true}
+	duplicate := &domain.User{ID: uuid.New(), UserType: domain.UserTypeVolunteer, IsActive: true}
+	mocks.users.On("FindByID", kept.ID).Return(kept, nil)
+	mocks.users.On("FindByID", duplicate.ID).Return(duplicate, nil)
+	mocks.users.On("Merge", kept.ID, duplicate.ID, mock.Anything).Return(domain.ErrMergeVerifiedDuplicate)
+
+	// Act
+	result, err := adminService.MergeUsers(actorID, kept.ID, service.MergeUsersRequest{DuplicateID: duplicate.ID})
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrMergeVerifiedDuplicate)
+	assert.Nil(t, result)
+}
+
+// TestAdminService_MergeUsers_Success testa a incorporação da conta duplicada.
+func TestAdminService_MergeUsers_Success(t *testing.T) {
+	// Arrange
+	adminService, mocks := newAdminService()
+
+	actorID := newActor(mocks, domain.RoleAdmin)
+	kept := &domain.User{ID: uuid.New(), UserType: domain.UserTypeElderly, IsActive: true}
+	duplicate := &domain.User{ID: uuid.New(), UserType: domain.UserTypeElderly, IsActive: true, Email: "dup@email.com"}
+	mocks.users.On("FindByID", kept.ID).Return(kept, nil)
+	mocks.users.On("FindByID", duplicate.ID).Return(duplicate, nil)
+	mocks.users.On("Merge", kept.ID, duplicate.ID, auditOf(actorID, domain.AuditUserMerged, kept.ID)).Return(nil).Once()
+
+	// Act
+	result, err := adminService.MergeUsers(actorID, kept.ID, service.MergeUsersRequest{DuplicateID: duplicate.ID})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, kept.ID, result.ID)
+	mocks.users.AssertExpectations(t)
+}
+
+// TestAdminService_MergeUsers_DifferentTypes testa que contas de tipos diferentes não são mescladas.
+func TestAdminService_MergeUsers_DifferentTypes(t *testing.T) {
+	// Arrange
+	adminService, mocks := newAdminService()
+
+	kept := &domain.User{ID: uuid.New(), UserType: domain.UserTypeElderly, IsActive: true}
+	duplicate := &domain.User{ID: uuid.New(), UserType: domain.UserTypeVolunteer, IsActive: true}
+	mocks.users.On("FindByID", kept.ID).Return(kept, nil)
+	mocks.users.On("FindByID", duplicate.ID).Return(duplicate, nil)
+
+	// Act
+	_, err := adminService.MergeUsers(uuid.New(), kept.ID, service.MergeUsersRequest{DuplicateID: duplicate.ID})
+
+	// Assert
+	assert.EqualError(t, err, "apenas contas do mesmo tipo podem ser mescladas")
+	mocks.users.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestAdminService_RoleHierarchy testa que um moderador não age sobre contas com papel igual ou superior.
+func TestAdminService_RoleHierarchy(t *testing.T) {
+	// Arrange
+	adminService, mocks := newAdminService()
+
+	actorID := newActor(mocks, domain.RoleModerator)
+	admin := &domain.User{ID: uuid.New(), UserType: domain.UserTypeVolunteer, Role: domain.RoleAdmin, IsActive: true}
+	moderator := &domain.User{ID: uuid.New(), UserType: domain.UserTypeVolunteer, Role: domain.RoleModerator, IsActive: true}
+	volunteer := &domain.User{ID: uuid.New(), UserType: domain.UserTypeVolunteer, IsActive: true}
+	for _, user := range []*domain.User{admin, moderator, volunteer} {
+		mocks.users.On("FindByID", user.ID).Return(user, nil)
+	}
+
+	// Act
+	errDeactivate := adminService.DeactivateUser(actorID, admin.ID)
+	errDeactivatePeer := adminService.DeactivateUser(actorID, moderator.ID)
+	_, errReset := adminService.ResetPassword(actorID, admin.ID)
+	_, errMerge := adminService.MergeUsers(actorID, volunteer.ID, service.MergeUsersRequest{DuplicateID: admin.ID})
+
+	// Assert
+	assert.ErrorIs(t, errDeactivate, service.ErrRoleHierarchy)
+	assert.ErrorIs(t, errDeactivatePeer, service.ErrRoleHierarchy)
+	assert.ErrorIs(t, errReset, service.ErrRoleHierarchy)
+	assert.ErrorIs(t, errMerge, service.ErrRoleHierarchy)
+	assert.True(t, admin.IsActive)
+	mocks.users.AssertNotCalled(t, "UpdateAudited", mock.Anything, mock.Anything)
+	mocks.users.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestAdminService_ListAppointments_FilterByStatus testa o filtro por status.
+func TestAdminService_ListAppointments_FilterByStatus(t *testing.T) {
+	// Arrange
+	adminService, mocks := newAdminService()
+
+	mocks.appointments.On("FindPage", domain.AppointmentStatusCancelled, service.DefaultAdminPageSize, 0).
+		Return([]domain.Appointment{{ID: uuid.New()}}, int64(1), nil)
+
+	// Act
+	result, err := adminService.ListAppointments(domain.AppointmentStatusCancelled, 0, 0)
+	_, errInvalid := adminService.ListAppointments("DELETED", 1, 20)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, result.Appointments, 1)
+	assert.Error(t, errInvalid)
+	mocks.appointments.AssertNumberOfCalls(t, "FindPage", 1)
+}
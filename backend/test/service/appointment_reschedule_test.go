@@ -0,0 +1,296 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestAppointmentService_ProposeReschedule_Success testa que a proposta preserva a data original.
+func TestAppointmentService_ProposeReschedule_Success(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	targetID := uuid.New()
+	original := time.Now().UTC().Add(24 * time.Hour)
+	proposed := original.Add(48 * time.Hour)
+	appointment := &domain.Appointment{
+		ID:              uuid.New(),
+		VolunteerID:     uuid.New(),
+		TargetID:        targetID,
+		Date:            original,
+		DurationMinutes: 30,
+		Status:          domain.AppointmentStatusConfirmed,
+	}
+
+	appointmentRepo.On("FindByID", appointment.ID).Return(appointment, nil)
+	appointmentRepo.On("FindOverlapping", mock.Anything, proposed, proposed.Add(30*time.Minute)).
+		Return([]domain.Appointment{}, nil)
+	appointmentRepo.On("ChangeStatus", mock.MatchedBy(func(change *domain.AppointmentStatusHistory) bool {
+		return change.FromStatus == domain.AppointmentStatusConfirmed &&
+			change.ToStatus == domain.AppointmentStatusRescheduleProposed &&
+			change.ChangedBy == targetID &&
+			change.FromDate.Equal(original) &&
+			change.ToDate.Equal(proposed)
+	})).Return(nil)
+
+	// Act
+	err := appointmentService.ProposeReschedule(appointment.ID, targetID, service.RescheduleRequest{Date: proposed}, service.SeriesScopeThis)
+
+	// Assert
+	assert.NoError(t, err)
+	appointmentRepo.AssertExpectations(t)
+}
+
+// TestAppointmentService_ProposeReschedule_Conflict testa que o novo horário passa pela checagem de conflitos.
+func TestAppointmentService_ProposeReschedule_Conflict(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	volunteerID := uuid.New()
+	proposed := time.Now().UTC().Add(72 * time.Hour)
+	appointment := &domain.Appointment{
+		ID:              uuid.New(),
+		VolunteerID:     volunteerID,
+		TargetID:        uuid.New(),
+		Date:            time.Now().UTC().Add(24 * time.Hour),
+		DurationMinutes: 30,
+		Status:          domain.AppointmentStatusConfirmed,
+	}
+	busy := domain.Appointment{
+		ID:              uuid.New(),
+		VolunteerID:     volunteerID,
+		TargetID:        uuid.New(),
+		Date:            proposed,
+		DurationMinutes: 60,
+		Status:          domain.AppointmentStatusPending,
+	}
+
+	appointmentRepo.On("FindByID", appointment.ID).Return(appointment, nil)
+	appointmentRepo.On("FindOverlapping", mock.Anything, proposed, mock.Anything).Return([]domain.Appointment{busy}, nil)
+
+	// Act
+	err := appointmentService.ProposeReschedule(appointment.ID, volunteerID, service.RescheduleRequest{Date: proposed}, service.SeriesScopeThis)
+
+	// Assert
+	var conflictErr *service.ConflictError
+	assert.True(t, errors.As(err, &conflictErr))
+	appointmentRepo.AssertNotCalled(t, "ChangeStatus", mock.Anything)
+}
+
+// TestAppointmentService_AcceptReschedule_Success testa que o aceite move a data e confirma.
+func TestAppointmentService_AcceptReschedule_Success(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	volunteerID := uuid.New()
+	targetID := uuid.New()
+	original := time.Now().UTC().Add(24 * time.Hour)
+	proposed := original.Add(2 * time.Hour)
+	appointment := &domain.Appointment{
+		ID:                 uuid.New(),
+		VolunteerID:        volunteerID,
+		TargetID:           targetID,
+		Date:               original,
+		DurationMinutes:    30,
+		Status:             domain.AppointmentStatusRescheduleProposed,
+		ProposedDate:       &proposed,
+		ProposedBy:         &volunteerID,
+		ProposedFromStatus: domain.AppointmentStatusConfirmed,
+	}
+
+	appointmentRepo.On("FindByID", appointment.ID).Return(appointment, nil)
+	appointmentRepo.On("FindOverlapping", mock.Anything, proposed, mock.Anything).Return([]domain.Appointment{}, nil)
+	appointmentRepo.On("ChangeStatus", mock.MatchedBy(func(change *domain.AppointmentStatusHistory) bool {
+		return change.ToStatus == domain.AppointmentStatusConfirmed &&
+			change.FromDate.Equal(original) &&
+			change.ToDate.Equal(proposed)
+	})).Return(nil)
+
+	// Act
+	err := appointmentService.AcceptReschedule(appointment.ID, targetID, service.SeriesScopeThis)
+
+	// Assert
+	assert.NoError(t, err)
+	appointmentRepo.AssertExpectations(t)
+}
+
+// TestAppointmentService_AcceptReschedule_ByProposer testa que quem propôs não pode aceitar.
+func TestAppointmentService_AcceptReschedule_ByProposer(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	volunteerID := uuid.New()
+	proposed := time.Now().UTC().Add(48 * time.Hour)
+	appointment := &domain.Appointment{
+		ID:           uuid.New(),
+		VolunteerID:  volunteerID,
+		TargetID:     uuid.New(),
+		Date:         time.Now().UTC().Add(24 * time.Hour),
+		Status:       domain.AppointmentStatusRescheduleProposed,
+		ProposedDate: &proposed,
+		ProposedBy:   &volunteerID,
+	}
+
+	appointmentRepo.On("FindByID", appointment.ID).Return(appointment, nil)
+
+	// Act
+	err := appointmentService.AcceptReschedule(appointment.ID, volunteerID, service.SeriesScopeThis)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, "a proposta deve ser aceita pelo outro participante", err.Error())
+	appointmentRepo.AssertNotCalled(t, "ChangeStatus", mock.Anything)
+}
+
+// TestAppointmentService_RejectReschedule_RestoresStatus testa que a recusa volta ao status anterior.
+func TestAppointmentService_RejectReschedule_RestoresStatus(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	volunteerID := uuid.New()
+	targetID := uuid.New()
+	proposed := time.Now().UTC().Add(48 * time.Hour)
+	appointment := &domain.Appointment{
+		ID:                 uuid.New(),
+		VolunteerID:        volunteerID,
+		TargetID:           targetID,
+		Date:               time.Now().UTC().Add(24 * time.Hour),
+		Status:             domain.AppointmentStatusRescheduleProposed,
+		ProposedDate:       &proposed,
+		ProposedBy:         &targetID,
+		ProposedFromStatus: domain.AppointmentStatusPending,
+	}
+
+	appointmentRepo.On("FindByID", appointment.ID).Return(appointment, nil)
+	appointmentRepo.On("ChangeStatus", mock.MatchedBy(func(change *domain.AppointmentStatusHistory) bool {
+		return change.FromStatus == domain.AppointmentStatusRescheduleProposed &&
+			change.ToStatus == domain.AppointmentStatusPending &&
+			change.ToDate == nil &&
+			change.Reason == "não posso nesse dia"
+	})).Return(nil)
+
+	// Act
+	err := appointmentService.RejectReschedule(appointment.ID, volunteerID, "não posso nesse dia", service.SeriesScopeThis)
+
+	// Assert
+	assert.NoError(t, err)
+	appointmentRepo.AssertExpectations(t)
+}
+
+// TestAppointmentService_ProposeReschedule_SeriesScope testa que remarcar a série propõe a nova data
+// em cada ocorrência, sem mover as confirmadas antes do aceite do outro participante.
+func TestAppointmentService_ProposeReschedule_SeriesScope(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	volunteerID := uuid.New()
+	targetID := uuid.New()
+	seriesID := uuid.New()
+	start := time.Now().UTC().Add(24 * time.Hour)
+	occurrences := make([]domain.Appointment, 3)
+	for i := range occurrences {
+		occurrences[i] = domain.Appointment{
+			ID:              uuid.New(),
+			VolunteerID:     volunteerID,
+			TargetID:        targetID,
+			SeriesID:        &seriesID,
+			Date:            start.AddDate(0, 0, 7*i),
+			DurationMinutes: 30,
+			Status:          domain.AppointmentStatusConfirmed,
+		}
+	}
+	// A última já foi cancelada e deve ser ignorada
+	occurrences[2].Status = domain.AppointmentStatusCancelled
+
+	proposal := func(occurrence domain.Appointment) interface{} {
+		return mock.MatchedBy(func(change *domain.AppointmentStatusHistory) bool {
+			return change.AppointmentID == occurrence.ID &&
+				change.FromStatus == domain.AppointmentStatusConfirmed &&
+				change.ToStatus == domain.AppointmentStatusRescheduleProposed &&
+				change.FromDate.Equal(occurrence.Date) &&
+				change.ToDate.Equal(occurrence.Date.Add(2*time.Hour))
+		})
+	}
+
+	selected := occurrences[0]
+	appointmentRepo.On("FindByID", selected.ID).Return(&selected, nil)
+	appointmentRepo.On("FindSeries", seriesID).Return(&domain.AppointmentSeries{ID: seriesID, Appointments: occurrences}, nil)
+	appointmentRepo.On("FindOverlapping", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Appointment{}, nil)
+	appointmentRepo.On("ChangeStatus", proposal(occurrences[0]), proposal(occurrences[1])).Return(nil)
+
+	// Act
+	err := appointmentService.ProposeReschedule(selected.ID, volunteerID,
+		service.RescheduleRequest{Date: selected.Date.Add(2 * time.Hour)}, service.SeriesScopeAll)
+
+	// Assert
+	assert.NoError(t, err)
+	appointmentRepo.AssertExpectations(t)
+}
+
+// TestAppointmentService_AcceptReschedule_SeriesScope testa que o aceite na série confirma apenas
+// as propostas feitas pela mesma pessoa.
+func TestAppointmentService_AcceptReschedule_SeriesScope(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	volunteerID := uuid.New()
+	targetID := uuid.New()
+	seriesID := uuid.New()
+	start := time.Now().UTC().Add(24 * time.Hour)
+	occurrences := make([]domain.Appointment, 3)
+	for i := range occurrences {
+		proposed := start.AddDate(0, 0, 7*i).Add(2 * time.Hour)
+		occurrences[i] = domain.Appointment{
+			ID:                 uuid.New(),
+			VolunteerID:        volunteerID,
+			TargetID:           targetID,
+			SeriesID:           &seriesID,
+			Date:               start.AddDate(0, 0, 7*i),
+			DurationMinutes:    30,
+			Status:             domain.AppointmentStatusRescheduleProposed,
+			ProposedDate:       &proposed,
+			ProposedBy:         &volunteerID,
+			ProposedFromStatus: domain.AppointmentStatusConfirmed,
+		}
+	}
+	// A proposta da última foi feita pelo outro participante e fica de fora
+	occurrences[2].ProposedBy = &targetID
+
+	selected := occurrences[0]
+	appointmentRepo.On("FindByID", selected.ID).Return(&selected, nil)
+	appointmentRepo.On("FindSeries", seriesID).Return(&domain.AppointmentSeries{ID: seriesID, Appointments: occurrences}, nil)
+	appointmentRepo.On("FindOverlapping", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Appointment{}, nil)
+	appointmentRepo.On("ChangeStatus",
+		statusChange(occurrences[0].ID, domain.AppointmentStatusConfirmed),
+		statusChange(occurrences[1].ID, domain.AppointmentStatusConfirmed),
+	).Return(nil)
+
+	// Act
+	err := appointmentService.AcceptReschedule(selected.ID, targetID, service.SeriesScopeAll)
+
+	// Assert
+	assert.NoError(t, err)
+	appointmentRepo.AssertExpectations(t)
+}
@@ -0,0 +1,173 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestParseRecurrenceRule testa as regras de recorrência aceitas e rejeitadas.
+func TestParseRecurrenceRule(t *testing.T) {
+	rule, err := domain.ParseRecurrenceRule("RRULE:FREQ=WEEKLY;INTERVAL=2;COUNT=6")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RecurrenceWeekly, rule.Frequency)
+	assert.Equal(t, 2, rule.Interval)
+	assert.Equal(t, "FREQ=WEEKLY;INTERVAL=2;COUNT=6", rule.String())
+
+	invalid := []string{
+		"FREQ=DAILY;COUNT=3",
+		"FREQ=WEEKLY",
+		"FREQ=WEEKLY;COUNT=3;UNTIL=20300101",
+		"FREQ=WEEKLY;INTERVAL=3;COUNT=3",
+		"FREQ=MONTHLY;COUNT=100",
+	}
+	for _, value := range invalid {
+		_, err := domain.ParseRecurrenceRule(value)
+		assert.Error(t, err, value)
+	}
+}
+
+// TestRecurrenceRule_Occurrences testa a geração de datas semanais e mensais.
+func TestRecurrenceRule_Occurrences(t *testing.T) {
+	start := time.Date(2030, time.January, 31, 15, 0, 0, 0, time.UTC)
+
+	weekly, _ := domain.ParseRecurrenceRule("FREQ=WEEKLY;UNTIL=20300214")
+	assert.Equal(t, []time.Time{start, start.AddDate(0, 0, 7), start.AddDate(0, 0, 14)}, weekly.Occurrences(start))
+
+	// Meses sem dia 31 são pulados
+	monthly, _ := domain.ParseRecurrenceRule("FREQ=MONTHLY;COUNT=3")
+	dates := monthly.Occurrences(start)
+	assert.Len(t, dates, 3)
+	assert.Equal(t, time.March, dates[1].Month())
+	assert.Equal(t, time.May, dates[2].Month())
+}
+
+// TestAppointmentService_CreateSeries_Success testa a criação de uma série semanal.
+func TestAppointmentService_CreateSeries_Success(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	volunteerID := uuid.New()
+	targetID := uuid.New()
+	userRepo.On("FindByID", volunteerID).Return(&domain.User{ID: volunteerID, UserType: domain.UserTypeVolunteer}, nil)
+	userRepo.On("FindByID", targetID).Return(&domain.User{ID: targetID, UserType: domain.UserTypeElderly}, nil)
+
+	req := service.CreateAppointmentRequest{
+		TargetID:   targetID,
+		Date:       time.Now().UTC().Add(24 * time.Hour),
+		Recurrence: "FREQ=WEEKLY;COUNT=4",
+	}
+
+	appointmentRepo.On("FindOverlapping", []uuid.UUID{volunteerID, targetID}, mock.Anything, mock.Anything).
+		Return([]domain.Appointment{}, nil)
+	appointmentRepo.On("CreateSeries", mock.MatchedBy(func(series *domain.AppointmentSeries) bool {
+		return series.RRule == "FREQ=WEEKLY;COUNT=4" && series.DurationMinutes == domain.DefaultAppointmentDuration
+	}), mock.MatchedBy(func(appointments []domain.Appointment) bool {
+		return len(appointments) == 4 &&
+			appointments[3].Date.Equal(req.Date.AddDate(0, 0, 21)) &&
+			appointments[0].ID != appointments[1].ID
+	})).Return(nil)
+	appointmentRepo.On("FindSeries", mock.AnythingOfType("uuid.UUID")).Return(&domain.AppointmentSeries{}, nil)
+
+	// Act
+	result, err := appointmentService.CreateSeries(volunteerID, req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	appointmentRepo.AssertNumberOfCalls(t, "FindOverlapping", 4)
+	appointmentRepo.AssertExpectations(t)
+}
+
+// TestAppointmentService_CreateSeries_Conflict testa que um conflito impede a série inteira.
+func TestAppointmentService_CreateSeries_Conflict(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	volunteerID := uuid.New()
+	targetID := uuid.New()
+	userRepo.On("FindByID", volunteerID).Return(&domain.User{ID: volunteerID, UserType: domain.UserTypeVolunteer}, nil)
+	userRepo.On("FindByID", targetID).Return(&domain.User{ID: targetID, UserType: domain.UserTypeElderly}, nil)
+
+	start := time.Now().UTC().Add(24 * time.Hour)
+	req := service.CreateAppointmentRequest{
+		TargetID:   targetID,
+		Date:       start,
+		Recurrence: "FREQ=WEEKLY;COUNT=3",
+	}
+
+	// A segunda ocorrência colide com outro compromisso do idoso
+	second := start.AddDate(0, 0, 7)
+	existing := domain.Appointment{
+		ID:              uuid.New(),
+		VolunteerID:     uuid.New(),
+		TargetID:        targetID,
+		Date:            second,
+		DurationMinutes: 30,
+		Status:          domain.AppointmentStatusConfirmed,
+	}
+	appointmentRepo.On("FindOverlapping", mock.Anything, second, mock.Anything).Return([]domain.Appointment{existing}, nil)
+	appointmentRepo.On("FindOverlapping", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Appointment{}, nil)
+
+	// Act
+	result, err := appointmentService.CreateSeries(volunteerID, req)
+
+	// Assert
+	assert.Nil(t, result)
+	var conflictErr *service.ConflictError
+	assert.True(t, errors.As(err, &conflictErr))
+	assert.Len(t, conflictErr.Conflicts, 1)
+	assert.Equal(t, existing.ID, conflictErr.Conflicts[0].AppointmentID)
+	appointmentRepo.AssertNotCalled(t, "CreateSeries", mock.Anything, mock.Anything)
+}
+
+// TestAppointmentService_Cancel_FollowingOccurrences testa cancelar esta e as próximas ocorrências.
+func TestAppointmentService_Cancel_FollowingOccurrences(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	volunteerID := uuid.New()
+	seriesID := uuid.New()
+	start := time.Now().UTC().Add(24 * time.Hour)
+	occurrences := make([]domain.Appointment, 4)
+	for i := range occurrences {
+		occurrences[i] = domain.Appointment{
+			ID:          uuid.New(),
+			VolunteerID: volunteerID,
+			TargetID:    uuid.New(),
+			SeriesID:    &seriesID,
+			Date:        start.AddDate(0, 0, 7*i),
+			Status:      domain.AppointmentStatusConfirmed,
+		}
+	}
+	// A última já foi concluída e deve ser ignorada
+	occurrences[3].Status = domain.AppointmentStatusCompleted
+
+	selected := occurrences[1]
+	appointmentRepo.On("FindByID", selected.ID).Return(&selected, nil)
+	appointmentRepo.On("FindSeries", seriesID).Return(&domain.AppointmentSeries{ID: seriesID, Appointments: occurrences}, nil)
+	appointmentRepo.On("ChangeStatus",
+		statusChange(occurrences[1].ID, domain.AppointmentStatusCancelled),
+		statusChange(occurrences[2].ID, domain.AppointmentStatusCancelled),
+	).Return(nil)
+
+	// Act
+	err := appointmentService.Cancel(selected.ID, volunteerID, "", service.SeriesScopeFollowing)
+
+	// Assert
+	assert.NoError(t, err)
+	appointmentRepo.AssertExpectations(t)
+}
@@ -3,6 +3,7 @@ package service_test
 
 import (
 	"testing"
+	"time"
 
 	"amigos-terceira-idade/internal/domain"
 	"amigos-terceira-idade/internal/service"
@@ -17,7 +18,7 @@ func TestAppointmentService_SetMeetingURL_Success(t *testing.T) {
 	// Arrange
 	appointmentRepo := new(MockAppointmentRepository)
 	userRepo := new(MockUserRepository)
-	appointmentService := service.NewAppointmentService(appointmentRepo, userRepo)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
 
 	appointmentID := uuid.New()
 	appointment := &domain.Appointment{
@@ -42,7 +43,7 @@ func TestAppointmentService_GetReceivedInvitations_Success(t *testing.T) {
 	// Arrange
 	appointmentRepo := new(MockAppointmentRepository)
 	userRepo := new(MockUserRepository)
-	appointmentService := service.NewAppointmentService(appointmentRepo, userRepo)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
 
 	userID := uuid.New()
 	invitations := []domain.Appointment{
@@ -64,7 +65,7 @@ func TestAppointmentService_GetSentInvitations_Success(t *testing.T) {
 	// Arrange
 	appointmentRepo := new(MockAppointmentRepository)
 	userRepo := new(MockUserRepository)
-	appointmentService := service.NewAppointmentService(appointmentRepo, userRepo)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
 
 	volunteerID := uuid.New()
 	invitations := []domain.Appointment{
@@ -86,18 +87,21 @@ func TestAppointmentService_GetByID_Success(t *testing.T) {
 	// Arrange
 	appointmentRepo := new(MockAppointmentRepository)
 	userRepo := new(MockUserRepository)
-	appointmentService := service.NewAppointmentService(appointmentRepo, userRepo)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
 
 	appointmentID := uuid.New()
+	targetID := uuid.New()
 	appointment := &domain.Appointment{
-		ID:     appointmentID,
-		Status: domain.AppointmentStatusConfirmed,
+		ID:          appointmentID,
+		VolunteerID: uuid.New(),
+		TargetID:    targetID,
+		Status:      domain.AppointmentStatusConfirmed,
 	}
 
 	appointmentRepo.On("FindByID", appointmentID).Return(appointment, nil)
 
 	// Act
-	result, err := appointmentService.GetByID(appointmentID)
+	result, err := appointmentService.GetByID(appointmentID, targetID)
 
 	// Assert
 	assert.NoError(t, err)
@@ -105,12 +109,36 @@ func TestAppointmentService_GetByID_Success(t *testing.T) {
 	assert.Equal(t, appointmentID, result.ID)
 }
 
+// TestAppointmentService_GetByID_NotParticipant testa que apenas os participantes veem o agendamento.
+func TestAppointmentService_GetByID_NotParticipant(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	appointment := &domain.Appointment{
+		ID:          uuid.New(),
+		VolunteerID: uuid.New(),
+		TargetID:    uuid.New(),
+		Status:      domain.AppointmentStatusConfirmed,
+	}
+
+	appointmentRepo.On("FindByID", appointment.ID).Return(appointment, nil)
+
+	// Act
+	result, err := appointmentService.GetByID(appointment.ID, uuid.New())
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrNotAppointmentParticipant)
+	assert.Nil(t, result)
+}
+
 // TestAppointmentService_Decline_NotTarget testa recusar convite por não ser destinatário.
 func TestAppointmentService_Decline_NotTarget(t *testing.T) {
 	// Arrange
 	appointmentRepo := new(MockAppointmentRepository)
 	userRepo := new(MockUserRepository)
-	appointmentService := service.NewAppointmentService(appointmentRepo, userRepo)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
 
 	appointmentID := uuid.New()
 	targetID := uuid.New()
@@ -125,11 +153,12 @@ func TestAppointmentService_Decline_NotTarget(t *testing.T) {
 	appointmentRepo.On("FindByID", appointmentID).Return(appointment, nil)
 
 	// Act
-	err := appointmentService.Decline(appointmentID, wrongUserID)
+	err := appointmentService.Decline(appointmentID, wrongUserID, "", service.SeriesScopeThis)
 
 	// Assert
 	assert.Error(t, err)
 	assert.Equal(t, "você não pode recusar este convite", err.Error())
+	assert.ErrorIs(t, err, service.ErrNotAppointmentParticipant)
 }
 
 // TestAppointmentService_GetMyAppointments_Elderly testa listar agendamentos de idoso.
@@ -137,7 +166,7 @@ func TestAppointmentService_GetMyAppointments_Elderly(t *testing.T) {
 	// Arrange
 	appointmentRepo := new(MockAppointmentRepository)
 	userRepo := new(MockUserRepository)
-	appointmentService := service.NewAppointmentService(appointmentRepo, userRepo)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
 
 	elderlyID := uuid.New()
 	elderly := &domain.User{ID: elderlyID, UserType: domain.UserTypeElderly}
@@ -155,3 +184,166 @@ func TestAppointmentService_GetMyAppointments_Elderly(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, result, 1)
 }
+
+// TestAppointmentService_Cancel_Completed testa que um agendamento concluído não pode ser cancelado.
+func TestAppointmentService_Cancel_Completed(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	appointmentID := uuid.New()
+	volunteerID := uuid.New()
+	appointment := &domain.Appointment{
+		ID:          appointmentID,
+		VolunteerID: volunteerID,
+		Status:      domain.AppointmentStatusCompleted,
+	}
+
+	appointmentRepo.On("FindByID", appointmentID).Return(appointment, nil)
+
+	// Act
+	err := appointmentService.Cancel(appointmentID, volunteerID, "mudança de planos", service.SeriesScopeThis)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrInvalidStatusTransition)
+	appointmentRepo.AssertNotCalled(t, "ChangeStatus", mock.Anything)
+}
+
+// TestAppointmentService_Decline_NotPending testa que um convite já confirmado não pode ser recusado.
+func TestAppointmentService_Decline_NotPending(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	appointmentID := uuid.New()
+	targetID := uuid.New()
+	appointment := &domain.Appointment{
+		ID:       appointmentID,
+		TargetID: targetID,
+		Status:   domain.AppointmentStatusConfirmed,
+	}
+
+	appointmentRepo.On("FindByID", appointmentID).Return(appointment, nil)
+
+	// Act
+	err := appointmentService.Decline(appointmentID, targetID, "", service.SeriesScopeThis)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, "este convite não está mais pendente", err.Error())
+}
+
+// TestAppointmentService_Cancel_RecordsReason testa que o motivo e o autor vão para o histórico.
+func TestAppointmentService_Cancel_RecordsReason(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	appointmentID := uuid.New()
+	targetID := uuid.New()
+	appointment := &domain.Appointment{
+		ID:          appointmentID,
+		VolunteerID: uuid.New(),
+		TargetID:    targetID,
+		Status:      domain.AppointmentStatusConfirmed,
+	}
+
+	appointmentRepo.On("FindByID", appointmentID).Return(appointment, nil)
+	appointmentRepo.On("ChangeStatus", mock.MatchedBy(func(change *domain.AppointmentStatusHistory) bool {
+		return change.FromStatus == domain.AppointmentStatusConfirmed &&
+			change.ToStatus == domain.AppointmentStatusCancelled &&
+			change.ChangedBy == targetID &&
+			change.Reason == "consulta médica"
+	})).Return(nil)
+
+	// Act
+	err := appointmentService.Cancel(appointmentID, targetID, "consulta médica", service.SeriesScopeThis)
+
+	// Assert
+	assert.NoError(t, err)
+	appointmentRepo.AssertExpectations(t)
+}
+
+// TestAppointmentService_MarkNoShow_Success testa registrar ausência após o início.
+func TestAppointmentService_MarkNoShow_Success(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	appointmentID := uuid.New()
+	volunteerID := uuid.New()
+	appointment := &domain.Appointment{
+		ID:          appointmentID,
+		VolunteerID: volunteerID,
+		Date:        time.Now().UTC().Add(-time.Hour),
+		Status:      domain.AppointmentStatusConfirmed,
+	}
+
+	appointmentRepo.On("FindByID", appointmentID).Return(appointment, nil)
+	appointmentRepo.On("ChangeStatus", statusChange(appointmentID, domain.AppointmentStatusNoShow)).Return(nil)
+
+	// Act
+	err := appointmentService.MarkNoShow(appointmentID, volunteerID, "")
+
+	// Assert
+	assert.NoError(t, err)
+	appointmentRepo.AssertExpectations(t)
+}
+
+// TestAppointmentService_GetHistory_NotParticipant testa que terceiros não veem o histórico.
+func TestAppointmentService_GetHistory_NotParticipant(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	appointmentID := uuid.New()
+	appointment := &domain.Appointment{
+		ID:          appointmentID,
+		VolunteerID: uuid.New(),
+		TargetID:    uuid.New(),
+	}
+
+	appointmentRepo.On("FindByID", appointmentID).Return(appointment, nil)
+
+	// Act
+	result, err := appointmentService.GetHistory(appointmentID, uuid.New())
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrNotAppointmentParticipant)
+	assert.Nil(t, result)
+	appointmentRepo.AssertNotCalled(t, "FindHistory", mock.Anything)
+}
+
+// TestAppointmentStatus_Transitions testa a máquina de estados dos agendamentos.
+func TestAppointmentStatus_Transitions(t *testing.T) {
+	assert.True(t, domain.AppointmentStatusPending.CanTransitionTo(domain.AppointmentStatusConfirmed))
+	assert.True(t, domain.AppointmentStatusPending.CanTransitionTo(domain.AppointmentStatusCancelled))
+	assert.False(t, domain.AppointmentStatusPending.CanTransitionTo(domain.AppointmentStatusCompleted))
+	assert.True(t, domain.AppointmentStatusConfirmed.CanTransitionTo(domain.AppointmentStatusNoShow))
+	assert.False(t, domain.AppointmentStatusConfirmed.CanTransitionTo(domain.AppointmentStatusPending))
+	assert.False(t, domain.AppointmentStatusCompleted.CanTransitionTo(domain.AppointmentStatusCancelled))
+	assert.True(t, domain.AppointmentStatusCancelled.IsFinal())
+	assert.False(t, domain.AppointmentStatusConfirmed.IsFinal())
+}
+
+// TestAppointmentService_SetMeetingURL_Invalid testa a recusa de links que não são http(s) ou que
+// trazem quebras de linha.
+func TestAppointmentService_SetMeetingURL_Invalid(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	appointmentService := newAppointmentService(appointmentRepo, new(MockUserRepository))
+
+	// Act
+	errCRLF := appointmentService.SetMeetingURL(uuid.New(), "https://meet.google.com/x\r\nATTACH:https://evil.example.com")
+	errScheme := appointmentService.SetMeetingURL(uuid.New(), "javascript:alert(1)")
+
+	// Assert
+	assert.ErrorIs(t, errCRLF, service.ErrInvalidMeetingURL)
+	assert.ErrorIs(t, errScheme, service.ErrInvalidMeetingURL)
+	appointmentRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
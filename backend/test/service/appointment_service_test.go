@@ -22,7 +22,16 @@ type MockAppointmentRepository struct {
 // Garante que implementa a interface
 var _ repository.AppointmentRepositoryInterface = (*MockAppointmentRepository)(nil)
 
-func (m *MockAppointmentRepository) Create(appointment *domain.Appointment) error {
+// Create simula a transação do repositório: lê os agendamentos sobrepostos pelo mock de
+// FindOverlapping e só registra a inserção se check aceitar.
+func (m *MockAppointmentRepository) Create(appointment *domain.Appointment, check func([]domain.Appointment) error) error {
+	overlapping, err := m.lockOverlapping(appointment)
+	if err != nil {
+		return err
+	}
+	if err := check(overlapping); err != nil {
+		return err
+	}
 	args := m.Called(appointment)
 	return args.Error(0)
 }
@@ -50,23 +59,92 @@ func (m *MockAppointmentRepository) FindUpcoming(userID uuid.UUID) ([]domain.App
 	return args.Get(0).([]domain.Appointment), args.Error(1)
 }
 
+func (m *MockAppointmentRepository) FindUpcomingCancelled(userID uuid.UUID) ([]domain.Appointment, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]domain.Appointment), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) FindConfirmedBetween(from, to time.Time) ([]domain.Appointment, error) {
+	args := m.Called(from, to)
+	return args.Get(0).([]domain.Appointment), args.Error(1)
+}
+
 func (m *MockAppointmentRepository) FindPendingInvitations(targetID uuid.UUID) ([]domain.Appointment, error) {
 	args := m.Called(targetID)
 	return args.Get(0).([]domain.Appointment), args.Error(1)
 }
 
+func (m *MockAppointmentRepository) FindOverlapping(userIDs []uuid.UUID, start, end time.Time) ([]domain.Appointment, error) {
+	args := m.Called(userIDs, start, end)
+	return args.Get(0).([]domain.Appointment), args.Error(1)
+}
+
 func (m *MockAppointmentRepository) FindSentInvitations(volunteerID uuid.UUID) ([]domain.Appointment, error) {
 	args := m.Called(volunteerID)
 	return args.Get(0).([]domain.Appointment), args.Error(1)
 }
 
+func (m *MockAppointmentRepository) FindPage(status domain.AppointmentStatus, limit, offset int) ([]domain.Appointment, int64, error) {
+	args := m.Called(status, limit, offset)
+	return args.Get(0).([]domain.Appointment), args.Get(1).(int64), args.Error(2)
+}
+
 func (m *MockAppointmentRepository) Update(appointment *domain.Appointment) error {
 	args := m.Called(appointment)
 	return args.Error(0)
 }
 
-func (m *MockAppointmentRepository) UpdateStatus(id uuid.UUID, status domain.AppointmentStatus) error {
-	args := m.Called(id, status)
+func (m *MockAppointmentRepository) ChangeStatus(changes ...*domain.AppointmentStatusHistory) error {
+	called := make([]interface{}, len(changes))
+	for i, change := range changes {
+		called[i] = change
+	}
+	args := m.Called(called...)
+	return args.Error(0)
+}
+
+func (m *MockAppointmentRepository) Complete(appointment *domain.Appointment, change *domain.AppointmentStatusHistory) error {
+	args := m.Called(appointment, change)
+	return args.Error(0)
+}
+
+func (m *MockAppointmentRepository) FindHistory(appointmentID uuid.UUID) ([]domain.AppointmentStatusHistory, error) {
+	args := m.Called(appointmentID)
+	return args.Get(0).([]domain.AppointmentStatusHistory), args.Error(1)
+}
+
+// CreateSeries simula a transação do repositório como Create, para cada ocorrência.
+func (m *MockAppointmentRepository) CreateSeries(series *domain.AppointmentSeries, appointments []domain.Appointment, check func([][]domain.Appointment) error) error {
+	overlapping := make([][]domain.Appointment, len(appointments))
+	for i := range appointments {
+		found, err := m.lockOverlapping(&appointments[i])
+		if err != nil {
+			return err
+		}
+		overlapping[i] = found
+	}
+	if err := check(overlapping); err != nil {
+		return err
+	}
+	args := m.Called(series, appointments)
+	return args.Error(0)
+}
+
+// lockOverlapping faz, pelo mock de FindOverlapping, a leitura travada do repositório.
+func (m *MockAppointmentRepository) lockOverlapping(appointment *domain.Appointment) ([]domain.Appointment, error) {
+	return m.FindOverlapping([]uuid.UUID{appointment.VolunteerID, appointment.TargetID}, appointment.Date, appointment.EndsAt())
+}
+
+func (m *MockAppointmentRepository) FindSeries(id uuid.UUID) (*domain.AppointmentSeries, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AppointmentSeries), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) Rate(appointment *domain.Appointment, rating int) error {
+	args := m.Called(appointment, rating)
 	return args.Error(0)
 }
 
@@ -75,12 +153,27 @@ func (m *MockAppointmentRepository) Delete(id uuid.UUID) error {
 	return args.Error(0)
 }
 
+// newAppointmentService cria o serviço de agendamentos para testes, com um voluntário
+// sem disponibilidade declarada (qualquer horário é aceito).
+func newAppointmentService(appointmentRepo *MockAppointmentRepository, userRepo *MockUserRepository) *service.AppointmentService {
+	availabilityRepo := new(MockAvailabilityRepository)
+	availabilityRepo.On("FindWeekly", mock.Anything).Return([]domain.VolunteerAvailability{}, nil).Maybe()
+	return service.NewAppointmentService(appointmentRepo, userRepo, new(MockResidentRepository), availabilityRepo, ignoreNotifications())
+}
+
+// statusChange casa com o registro de histórico de uma mudança para o status esperado.
+func statusChange(appointmentID uuid.UUID, to domain.AppointmentStatus) interface{} {
+	return mock.MatchedBy(func(change *domain.AppointmentStatusHistory) bool {
+		return change.AppointmentID == appointmentID && change.ToStatus == to
+	})
+}
+
 // TestAppointmentService_Create_Success testa criação de agendamento com sucesso.
 func TestAppointmentService_Create_Success(t *testing.T) {
 	// Arrange
 	appointmentRepo := new(MockAppointmentRepository)
 	userRepo := new(MockUserRepository)
-	appointmentService := service.NewAppointmentService(appointmentRepo, userRepo)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
 
 	volunteerID := uuid.New()
 	targetID := uuid.New()
@@ -88,7 +181,7 @@ func TestAppointmentService_Create_Success(t *testing.T) {
 	volunteer := &domain.User{ID: volunteerID, UserType: domain.UserTypeVolunteer}
 	target := &domain.User{ID: targetID, UserType: domain.UserTypeElderly}
 
-	futureDate := time.Now().Add(24 * time.Hour)
+	futureDate := time.Now().UTC().Add(24 * time.Hour)
 	req := service.CreateAppointmentRequest{
 		TargetID:        targetID,
 		Date:            futureDate,
@@ -97,6 +190,8 @@ func TestAppointmentService_Create_Success(t *testing.T) {
 
 	userRepo.On("FindByID", volunteerID).Return(volunteer, nil)
 	userRepo.On("FindByID", targetID).Return(target, nil)
+	appointmentRepo.On("FindOverlapping", []uuid.UUID{volunteerID, targetID}, futureDate, futureDate.Add(30*time.Minute)).
+		Return([]domain.Appointment{}, nil)
 	appointmentRepo.On("Create", mock.AnythingOfType("*domain.Appointment")).Return(nil)
 	appointmentRepo.On("FindByID", mock.AnythingOfType("uuid.UUID")).Return(&domain.Appointment{
 		ID:              uuid.New(),
@@ -115,6 +210,8 @@ func TestAppointmentService_Create_Success(t *testing.T) {
 	assert.NotNil(t, result)
 	assert.Equal(t, targetID, result.TargetID)
 	assert.Equal(t, domain.AppointmentStatusPending, result.Status)
+	// Os conflitos são conferidos apenas na transação que insere o agendamento
+	appointmentRepo.AssertNumberOfCalls(t, "FindOverlapping", 1)
 }
 
 // TestAppointmentService_Create_NotVolunteer testa erro quando criador não é voluntário.
@@ -122,14 +219,14 @@ func TestAppointmentService_Create_NotVolunteer(t *testing.T) {
 	// Arrange
 	appointmentRepo := new(MockAppointmentRepository)
 	userRepo := new(MockUserRepository)
-	appointmentService := service.NewAppointmentService(appointmentRepo, userRepo)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
 
 	elderlyID := uuid.New()
 	elderly := &domain.User{ID: elderlyID, UserType: domain.UserTypeElderly}
 
 	req := service.CreateAppointmentRequest{
 		TargetID: uuid.New(),
-		Date:     time.Now().Add(24 * time.Hour),
+		Date:     time.Now().UTC().Add(24 * time.Hour),
 	}
 
 	userRepo.On("FindByID", elderlyID).Return(elderly, nil)
@@ -148,7 +245,7 @@ func TestAppointmentService_Create_PastDate(t *testing.T) {
 	// Arrange
 	appointmentRepo := new(MockAppointmentRepository)
 	userRepo := new(MockUserRepository)
-	appointmentService := service.NewAppointmentService(appointmentRepo, userRepo)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
 
 	volunteerID := uuid.New()
 	targetID := uuid.New()
@@ -156,7 +253,7 @@ func TestAppointmentService_Create_PastDate(t *testing.T) {
 	volunteer := &domain.User{ID: volunteerID, UserType: domain.UserTypeVolunteer}
 	target := &domain.User{ID: targetID, UserType: domain.UserTypeElderly}
 
-	pastDate := time.Now().Add(-24 * time.Hour) // Data passada
+	pastDate := time.Now().UTC().Add(-24 * time.Hour) // Data passada
 	req := service.CreateAppointmentRequest{
 		TargetID: targetID,
 		Date:     pastDate,
@@ -174,12 +271,88 @@ func TestAppointmentService_Create_PastDate(t *testing.T) {
 	assert.Equal(t, "a data deve ser futura", err.Error())
 }
 
+// TestAppointmentService_Create_Conflict testa erro quando o voluntário já tem compromisso no horário.
+func TestAppointmentService_Create_Conflict(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	volunteerID := uuid.New()
+	targetID := uuid.New()
+
+	volunteer := &domain.User{ID: volunteerID, UserType: domain.UserTypeVolunteer}
+	target := &domain.User{ID: targetID, UserType: domain.UserTypeElderly}
+
+	date := time.Now().UTC().Add(24 * time.Hour)
+	req := service.CreateAppointmentRequest{
+		TargetID:        targetID,
+		Date:            date,
+		DurationMinutes: 60,
+	}
+
+	// Outro idoso já tem conversa com o voluntário começando 30 minutos depois
+	existing := domain.Appointment{
+		ID:              uuid.New(),
+		VolunteerID:     volunteerID,
+		TargetID:        uuid.New(),
+		Date:            date.Add(30 * time.Minute),
+		DurationMinutes: 30,
+		Status:          domain.AppointmentStatusConfirmed,
+	}
+
+	userRepo.On("FindByID", volunteerID).Return(volunteer, nil)
+	userRepo.On("FindByID", targetID).Return(target, nil)
+	appointmentRepo.On("FindOverlapping", []uuid.UUID{volunteerID, targetID}, date, date.Add(time.Hour)).
+		Return([]domain.Appointment{existing}, nil)
+
+	// Act
+	result, err := appointmentService.Create(volunteerID, req)
+
+	// Assert
+	assert.Nil(t, result)
+	var conflictErr *service.ConflictError
+	assert.ErrorAs(t, err, &conflictErr)
+	assert.Len(t, conflictErr.Conflicts, 1)
+	assert.Equal(t, existing.ID, conflictErr.Conflicts[0].AppointmentID)
+	assert.Equal(t, volunteerID, conflictErr.Conflicts[0].UserID)
+	appointmentRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestAppointmentService_Create_TooLong testa erro quando a duração excede o máximo.
+func TestAppointmentService_Create_TooLong(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	volunteerID := uuid.New()
+	targetID := uuid.New()
+
+	userRepo.On("FindByID", volunteerID).Return(&domain.User{ID: volunteerID, UserType: domain.UserTypeVolunteer}, nil)
+	userRepo.On("FindByID", targetID).Return(&domain.User{ID: targetID, UserType: domain.UserTypeElderly}, nil)
+
+	req := service.CreateAppointmentRequest{
+		TargetID:        targetID,
+		Date:            time.Now().UTC().Add(24 * time.Hour),
+		DurationMinutes: 300,
+	}
+
+	// Act
+	result, err := appointmentService.Create(volunteerID, req)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, "a duração máxima de uma conversa é de 4 horas", err.Error())
+}
+
 // TestAppointmentService_Accept_Success testa aceitar convite com sucesso.
 func TestAppointmentService_Accept_Success(t *testing.T) {
 	// Arrange
 	appointmentRepo := new(MockAppointmentRepository)
 	userRepo := new(MockUserRepository)
-	appointmentService := service.NewAppointmentService(appointmentRepo, userRepo)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
 
 	appointmentID := uuid.New()
 	targetID := uuid.New()
@@ -191,10 +364,10 @@ func TestAppointmentService_Accept_Success(t *testing.T) {
 	}
 
 	appointmentRepo.On("FindByID", appointmentID).Return(appointment, nil)
-	appointmentRepo.On("UpdateStatus", appointmentID, domain.AppointmentStatusConfirmed).Return(nil)
+	appointmentRepo.On("ChangeStatus", statusChange(appointmentID, domain.AppointmentStatusConfirmed)).Return(nil)
 
 	// Act
-	err := appointmentService.Accept(appointmentID, targetID)
+	err := appointmentService.Accept(appointmentID, targetID, service.SeriesScopeThis)
 
 	// Assert
 	assert.NoError(t, err)
@@ -206,7 +379,7 @@ func TestAppointmentService_Accept_NotTarget(t *testing.T) {
 	// Arrange
 	appointmentRepo := new(MockAppointmentRepository)
 	userRepo := new(MockUserRepository)
-	appointmentService := service.NewAppointmentService(appointmentRepo, userRepo)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
 
 	appointmentID := uuid.New()
 	targetID := uuid.New()
@@ -221,11 +394,12 @@ func TestAppointmentService_Accept_NotTarget(t *testing.T) {
 	appointmentRepo.On("FindByID", appointmentID).Return(appointment, nil)
 
 	// Act
-	err := appointmentService.Accept(appointmentID, wrongUserID)
+	err := appointmentService.Accept(appointmentID, wrongUserID, service.SeriesScopeThis)
 
 	// Assert
 	assert.Error(t, err)
 	assert.Equal(t, "você não pode aceitar este convite", err.Error())
+	assert.ErrorIs(t, err, service.ErrNotAppointmentParticipant)
 }
 
 // TestAppointmentService_Accept_NotPending testa erro quando convite não está pendente.
@@ -233,7 +407,7 @@ func TestAppointmentService_Accept_NotPending(t *testing.T) {
 	// Arrange
 	appointmentRepo := new(MockAppointmentRepository)
 	userRepo := new(MockUserRepository)
-	appointmentService := service.NewAppointmentService(appointmentRepo, userRepo)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
 
 	appointmentID := uuid.New()
 	targetID := uuid.New()
@@ -247,7 +421,7 @@ func TestAppointmentService_Accept_NotPending(t *testing.T) {
 	appointmentRepo.On("FindByID", appointmentID).Return(appointment, nil)
 
 	// Act
-	err := appointmentService.Accept(appointmentID, targetID)
+	err := appointmentService.Accept(appointmentID, targetID, service.SeriesScopeThis)
 
 	// Assert
 	assert.Error(t, err)
@@ -259,7 +433,7 @@ func TestAppointmentService_Decline_Success(t *testing.T) {
 	// Arrange
 	appointmentRepo := new(MockAppointmentRepository)
 	userRepo := new(MockUserRepository)
-	appointmentService := service.NewAppointmentService(appointmentRepo, userRepo)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
 
 	appointmentID := uuid.New()
 	targetID := uuid.New()
@@ -271,10 +445,10 @@ func TestAppointmentService_Decline_Success(t *testing.T) {
 	}
 
 	appointmentRepo.On("FindByID", appointmentID).Return(appointment, nil)
-	appointmentRepo.On("UpdateStatus", appointmentID, domain.AppointmentStatusCancelled).Return(nil)
+	appointmentRepo.On("ChangeStatus", statusChange(appointmentID, domain.AppointmentStatusCancelled)).Return(nil)
 
 	// Act
-	err := appointmentService.Decline(appointmentID, targetID)
+	err := appointmentService.Decline(appointmentID, targetID, "", service.SeriesScopeThis)
 
 	// Assert
 	assert.NoError(t, err)
@@ -286,7 +460,7 @@ func TestAppointmentService_Cancel_Success(t *testing.T) {
 	// Arrange
 	appointmentRepo := new(MockAppointmentRepository)
 	userRepo := new(MockUserRepository)
-	appointmentService := service.NewAppointmentService(appointmentRepo, userRepo)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
 
 	appointmentID := uuid.New()
 	volunteerID := uuid.New()
@@ -298,10 +472,10 @@ func TestAppointmentService_Cancel_Success(t *testing.T) {
 	}
 
 	appointmentRepo.On("FindByID", appointmentID).Return(appointment, nil)
-	appointmentRepo.On("UpdateStatus", appointmentID, domain.AppointmentStatusCancelled).Return(nil)
+	appointmentRepo.On("ChangeStatus", statusChange(appointmentID, domain.AppointmentStatusCancelled)).Return(nil)
 
 	// Act
-	err := appointmentService.Cancel(appointmentID, volunteerID)
+	err := appointmentService.Cancel(appointmentID, volunteerID, "", service.SeriesScopeThis)
 
 	// Assert
 	assert.NoError(t, err)
@@ -312,7 +486,7 @@ func TestAppointmentService_Cancel_NotParticipant(t *testing.T) {
 	// Arrange
 	appointmentRepo := new(MockAppointmentRepository)
 	userRepo := new(MockUserRepository)
-	appointmentService := service.NewAppointmentService(appointmentRepo, userRepo)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
 
 	appointmentID := uuid.New()
 	volunteerID := uuid.New()
@@ -328,11 +502,12 @@ func TestAppointmentService_Cancel_NotParticipant(t *testing.T) {
 	appointmentRepo.On("FindByID", appointmentID).Return(appointment, nil)
 
 	// Act
-	err := appointmentService.Cancel(appointmentID, wrongUserID)
+	err := appointmentService.Cancel(appointmentID, wrongUserID, "", service.SeriesScopeThis)
 
 	// Assert
 	assert.Error(t, err)
 	assert.Equal(t, "você não pode cancelar este agendamento", err.Error())
+	assert.ErrorIs(t, err, service.ErrNotAppointmentParticipant)
 }
 
 // TestAppointmentService_GetMyAppointments_Volunteer testa listar agendamentos de voluntário.
@@ -340,7 +515,7 @@ func TestAppointmentService_GetMyAppointments_Volunteer(t *testing.T) {
 	// Arrange
 	appointmentRepo := new(MockAppointmentRepository)
 	userRepo := new(MockUserRepository)
-	appointmentService := service.NewAppointmentService(appointmentRepo, userRepo)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
 
 	volunteerID := uuid.New()
 	volunteer := &domain.User{ID: volunteerID, UserType: domain.UserTypeVolunteer}
@@ -365,10 +540,10 @@ func TestAppointmentService_GetUpcoming_Success(t *testing.T) {
 	// Arrange
 	appointmentRepo := new(MockAppointmentRepository)
 	userRepo := new(MockUserRepository)
-	appointmentService := service.NewAppointmentService(appointmentRepo, userRepo)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
 
 	userID := uuid.New()
-	futureDate := time.Now().Add(24 * time.Hour)
+	futureDate := time.Now().UTC().Add(24 * time.Hour)
 	appointments := []domain.Appointment{
 		{ID: uuid.New(), Date: futureDate, Status: domain.AppointmentStatusConfirmed},
 	}
@@ -383,27 +558,208 @@ func TestAppointmentService_GetUpcoming_Success(t *testing.T) {
 	assert.Len(t, result, 1)
 }
 
-// TestAppointmentService_Complete_WithRating testa marcar como completo com avaliação.
-func TestAppointmentService_Complete_WithRating(t *testing.T) {
+// TestAppointmentService_Complete_Success testa concluir agendamento após o término.
+func TestAppointmentService_Complete_Success(t *testing.T) {
 	// Arrange
 	appointmentRepo := new(MockAppointmentRepository)
 	userRepo := new(MockUserRepository)
-	appointmentService := service.NewAppointmentService(appointmentRepo, userRepo)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
 
 	appointmentID := uuid.New()
+	volunteerID := uuid.New()
 	appointment := &domain.Appointment{
-		ID:     appointmentID,
-		Status: domain.AppointmentStatusConfirmed,
+		ID:              appointmentID,
+		VolunteerID:     volunteerID,
+		TargetID:        uuid.New(),
+		Date:            time.Now().UTC().Add(-2 * time.Hour),
+		DurationMinutes: 30,
+		Status:          domain.AppointmentStatusConfirmed,
 	}
 
 	appointmentRepo.On("FindByID", appointmentID).Return(appointment, nil)
-	appointmentRepo.On("Update", mock.AnythingOfType("*domain.Appointment")).Return(nil)
+	appointmentRepo.On("Complete", appointment, statusChange(appointmentID, domain.AppointmentStatusCompleted)).Return(nil)
 
 	// Act
-	err := appointmentService.Complete(appointmentID, 5)
+	err := appointmentService.Complete(appointmentID, volunteerID)
 
 	// Assert
 	assert.NoError(t, err)
-	assert.Equal(t, domain.AppointmentStatusCompleted, appointment.Status)
-	assert.Equal(t, 5, appointment.Rating)
+	appointmentRepo.AssertExpectations(t)
+}
+
+// TestAppointmentService_Complete_NotParticipant testa erro quando usuário não participa.
+func TestAppointmentService_Complete_NotParticipant(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	appointmentID := uuid.New()
+	appointment := &domain.Appointment{
+		ID:              appointmentID,
+		VolunteerID:     uuid.New(),
+		TargetID:        uuid.New(),
+		Date:            time.Now().UTC().Add(-2 * time.Hour),
+		DurationMinutes: 30,
+		Status:          domain.AppointmentStatusConfirmed,
+	}
+
+	appointmentRepo.On("FindByID", appointmentID).Return(appointment, nil)
+
+	// Act
+	err := appointmentService.Complete(appointmentID, uuid.New())
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, "você não pode concluir este agendamento", err.Error())
+	appointmentRepo.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything)
+}
+
+// TestAppointmentService_Complete_NotFinished testa erro quando a conversa ainda não terminou.
+func TestAppointmentService_Complete_NotFinished(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	appointmentID := uuid.New()
+	targetID := uuid.New()
+	appointment := &domain.Appointment{
+		ID:              appointmentID,
+		VolunteerID:     uuid.New(),
+		TargetID:        targetID,
+		Date:            time.Now().UTC().Add(-10 * time.Minute), // Começou, mas dura 30 minutos
+		DurationMinutes: 30,
+		Status:          domain.AppointmentStatusConfirmed,
+	}
+
+	appointmentRepo.On("FindByID", appointmentID).Return(appointment, nil)
+
+	// Act
+	err := appointmentService.Complete(appointmentID, targetID)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, "a conversa ainda não terminou", err.Error())
+}
+
+// TestAppointmentService_Complete_NotConfirmed testa erro ao concluir agendamento pendente.
+func TestAppointmentService_Complete_NotConfirmed(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	appointmentID := uuid.New()
+	volunteerID := uuid.New()
+	appointment := &domain.Appointment{
+		ID:              appointmentID,
+		VolunteerID:     volunteerID,
+		Date:            time.Now().UTC().Add(-2 * time.Hour),
+		DurationMinutes: 30,
+		Status:          domain.AppointmentStatusPending,
+	}
+
+	appointmentRepo.On("FindByID", appointmentID).Return(appointment, nil)
+
+	// Act
+	err := appointmentService.Complete(appointmentID, volunteerID)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, "apenas agendamentos confirmados podem ser concluídos", err.Error())
+}
+
+// TestAppointmentService_Rate_Success testa avaliar agendamento concluído.
+func TestAppointmentService_Rate_Success(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	appointmentID := uuid.New()
+	targetID := uuid.New()
+	appointment := &domain.Appointment{
+		ID:          appointmentID,
+		VolunteerID: uuid.New(),
+		TargetID:    targetID,
+		Status:      domain.AppointmentStatusCompleted,
+	}
+
+	appointmentRepo.On("FindByID", appointmentID).Return(appointment, nil)
+	appointmentRepo.On("Rate", appointment, 5).Return(nil)
+
+	// Act
+	err := appointmentService.Rate(appointmentID, targetID, 5)
+
+	// Assert
+	assert.NoError(t, err)
+	appointmentRepo.AssertExpectations(t)
+}
+
+// TestAppointmentService_Rate_ByVolunteer testa que o voluntário não avalia a si mesmo.
+func TestAppointmentService_Rate_ByVolunteer(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	appointmentID := uuid.New()
+	volunteerID := uuid.New()
+	appointment := &domain.Appointment{
+		ID:          appointmentID,
+		VolunteerID: volunteerID,
+		TargetID:    uuid.New(),
+		Status:      domain.AppointmentStatusCompleted,
+	}
+
+	appointmentRepo.On("FindByID", appointmentID).Return(appointment, nil)
+
+	// Act
+	err := appointmentService.Rate(appointmentID, volunteerID, 5)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, "você não pode avaliar este agendamento", err.Error())
+}
+
+// TestAppointmentService_Rate_AlreadyRated testa erro ao avaliar duas vezes.
+func TestAppointmentService_Rate_AlreadyRated(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	appointmentID := uuid.New()
+	targetID := uuid.New()
+	appointment := &domain.Appointment{
+		ID:       appointmentID,
+		TargetID: targetID,
+		Status:   domain.AppointmentStatusCompleted,
+		Rating:   4,
+	}
+
+	appointmentRepo.On("FindByID", appointmentID).Return(appointment, nil)
+
+	// Act
+	err := appointmentService.Rate(appointmentID, targetID, 5)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, "este agendamento já foi avaliado", err.Error())
+}
+
+// TestAppointmentService_Rate_InvalidValue testa erro com nota fora do intervalo.
+func TestAppointmentService_Rate_InvalidValue(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	appointmentService := newAppointmentService(appointmentRepo, userRepo)
+
+	// Act
+	err := appointmentService.Rate(uuid.New(), uuid.New(), 6)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, "a avaliação deve ser entre 1 e 5", err.Error())
 }
@@ -0,0 +1,128 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"amigos-terceira-idade/internal/config"
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// newPasswordLoginFixture cria o fixture do login com a senha "senha123" no usuário.
+func newPasswordLoginFixture(t *testing.T) *loginCodeFixture {
+	t.Helper()
+	f := newLoginCodeFixture()
+	hash, err := bcrypt.GenerateFromPassword([]byte("senha123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	f.user.PasswordHash = string(hash)
+	return f
+}
+
+// TestAuthService_Login_LocksAfterFailures testa o bloqueio da conta após senhas erradas seguidas.
+func TestAuthService_Login_LocksAfterFailures(t *testing.T) {
+	// Arrange
+	f := newPasswordLoginFixture(t)
+	wrong := service.LoginRequest{Email: f.user.Email, Password: "errada"}
+	for i := 0; i < 5; i++ {
+		_, err := f.service.Login(wrong, service.ClientInfo{})
+		require.ErrorIs(t, err, service.ErrInvalidCredentials)
+	}
+
+	// Act
+	_, err := f.service.Login(service.LoginRequest{Email: f.user.Email, Password: "senha123"}, service.ClientInfo{})
+
+	// Assert
+	var lockedErr *service.AccountLockedError
+	require.True(t, errors.As(err, &lockedErr))
+	assert.InDelta(t, (15 * time.Minute).Seconds(), lockedErr.RetryAfter(time.Now()).Seconds(), 5)
+	assert.Equal(t, 1, f.lockout.Lockouts)
+}
+
+// TestAuthService_Login_ProgressiveLockout testa que cada bloqueio seguido dobra a duração do anterior.
+func TestAuthService_Login_ProgressiveLockout(t *testing.T) {
+	// Arrange
+	f := newPasswordLoginFixture(t)
+	f.lockout.Lockouts = 2
+	f.lockout.Failures = 4
+
+	// Act
+	_, failErr := f.service.Login(service.LoginRequest{Email: f.user.Email, Password: "errada"}, service.ClientInfo{})
+
+	// Assert
+	assert.ErrorIs(t, failErr, service.ErrInvalidCredentials)
+	require.NotNil(t, f.lockout.LockedUntil)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *f.lockout.LockedUntil, 5*time.Second)
+	assert.Equal(t, 3, f.lockout.Lockouts)
+}
+
+// TestAuthService_Login_SuccessResetsLockout testa que o login com a senha certa zera as falhas e os bloqueios.
+func TestAuthService_Login_SuccessResetsLockout(t *testing.T) {
+	// Arrange
+	f := newPasswordLoginFixture(t)
+	f.lockout.Lockouts = 1
+	f.lockout.Failures = 3
+
+	// Act
+	result, err := f.service.Login(service.LoginRequest{Email: f.user.Email, Password: "senha123"}, service.ClientInfo{})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.AccessToken)
+	assert.Equal(t, 0, f.lockout.Failures)
+	assert.Equal(t, 0, f.lockout.Lockouts)
+}
+
+// TestAuthService_Login_FirstLockoutRespectsMax testa que o primeiro bloqueio também respeita a duração máxima.
+func TestAuthService_Login_FirstLockoutRespectsMax(t *testing.T) {
+	// Arrange
+	f := newPasswordLoginFixture(t)
+	f.configure(func(cfg *config.LoginCodeConfig) {
+		cfg.LockoutDuration = 2 * time.Hour
+		cfg.LockoutMaxDuration = 30 * time.Minute
+	})
+	f.lockout.Failures = 4
+
+	// Act
+	_, err := f.service.Login(service.LoginRequest{Email: f.user.Email, Password: "errada"}, service.ClientInfo{})
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrInvalidCredentials)
+	require.NotNil(t, f.lockout.LockedUntil)
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), *f.lockout.LockedUntil, 5*time.Second)
+}
+
+// TestAuthService_Login_FailureWhileLockedNotCounted testa que a falha de uma tentativa que leu a
+// conta antes de outra bloqueá-la não conta para o próximo bloqueio.
+func TestAuthService_Login_FailureWhileLockedNotCounted(t *testing.T) {
+	// Arrange
+	f := newPasswordLoginFixture(t)
+	var stale []*mock.Call
+	for _, call := range f.codeRepo.ExpectedCalls {
+		if call.Method == "FindLockout" {
+			stale = append(stale, call)
+		}
+	}
+	for _, call := range stale {
+		call.Unset()
+	}
+	f.codeRepo.On("FindLockout", f.user.ID).Return(&domain.AccountLockout{UserID: f.user.ID}, nil)
+	until := time.Now().Add(15 * time.Minute)
+	f.lockout.LockedUntil = &until
+	f.lockout.Lockouts = 1
+
+	// Act
+	_, err := f.service.Login(service.LoginRequest{Email: f.user.Email, Password: "errada"}, service.ClientInfo{})
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrInvalidCredentials)
+	assert.Equal(t, 0, f.lockout.Failures)
+	assert.Equal(t, 1, f.lockout.Lockouts)
+	assert.Equal(t, until, *f.lockout.LockedUntil)
+}
@@ -0,0 +1,378 @@
+package service_test
+
+import (
+	"errors"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"amigos-terceira-idade/internal/config"
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+	"amigos-terceira-idade/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockLoginCodeRepository implementa repository.LoginCodeRepositoryInterface para testes.
+type MockLoginCodeRepository struct {
+	mock.Mock
+}
+
+var _ repository.LoginCodeRepositoryInterface = (*MockLoginCodeRepository)(nil)
+
+func (m *MockLoginCodeRepository) Create(code *domain.LoginCode) error {
+	args := m.Called(code)
+	return args.Error(0)
+}
+
+func (m *MockLoginCodeRepository) FindLatest(userID uuid.UUID) (*domain.LoginCode, error) {
+	args := m.Called(userID)
+	if fn, ok := args.Get(0).(func(uuid.UUID) (*domain.LoginCode, error)); ok {
+		return fn(userID)
+	}
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LoginCode), args.Error(1)
+}
+
+func (m *MockLoginCodeRepository) ClaimAttempt(id uuid.UUID, maxAttempts int) (bool, error) {
+	args := m.Called(id, maxAttempts)
+	if fn, ok := args.Get(0).(func(uuid.UUID, int) (bool, error)); ok {
+		return fn(id, maxAttempts)
+	}
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockLoginCodeRepository) Consume(id uuid.UUID) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockLoginCodeRepository) FindLockout(userID uuid.UUID) (*domain.AccountLockout, error) {
+	args := m.Called(userID)
+	if fn, ok := args.Get(0).(func(uuid.UUID) *domain.AccountLockout); ok {
+		return fn(userID), args.Error(1)
+	}
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AccountLockout), args.Error(1)
+}
+
+func (m *MockLoginCodeRepository) UpdateLockout(userID uuid.UUID, change func(lockout *domain.AccountLockout)) error {
+	args := m.Called(userID, change)
+	return args.Error(0)
+}
+
+// loginCodeFixture reúne o serviço de autenticação e as dependências do login por código.
+type loginCodeFixture struct {
+	mu       sync.Mutex // Protege issued e lockout nos testes concorrentes
+	service  *service.AuthService
+	userRepo *MockUserRepository
+	codeRepo *MockLoginCodeRepository
+	email    *fakeNotifier
+	sms      *fakeNotifier
+	user     *domain.User
+	issued   *domain.LoginCode
+	lockout  *domain.AccountLockout
+}
+
+// configure recria o serviço do fixture com a configuração padrão alterada por change.
+func (f *loginCodeFixture) configure(change func(cfg *config.LoginCodeConfig)) {
+	cfg := config.LoginCodeConfig{
+		TTL:              10 * time.Minute,
+		MaxAttempts:      3,
+		ResendInterval:   time.Minute,
+		LockoutThreshold: 5,
+		LockoutDuration:  15 * time.Minute,
+	}
+	change(&cfg)
+	f.service = service.NewAuthService(f.userRepo, new(MockInterestRepository), acceptSessions(), f.codeRepo,
+		service.LoginCodeSenders{Email: f.email, SMS: f.sms}, getTestJWTConfig(), cfg, inline)
+}
+
+// newLoginCodeFixture cria o serviço com um usuário ativo; o último código emitido é
+// devolvido por FindLatest, ClaimAttempt reserva as tentativas dele como o UPDATE
+// condicionado do repositório e o bloqueio da conta fica em memória, alterado por
+// UpdateLockout.
+func newLoginCodeFixture() *loginCodeFixture {
+	f := &loginCodeFixture{
+		userRepo: new(MockUserRepository),
+		codeRepo: new(MockLoginCodeRepository),
+		email:    &fakeNotifier{},
+		sms:      &fakeNotifier{},
+		user:     &domain.User{ID: uuid.New(), Name: "Seu João", Email: "joao@email.com", IsActive: true},
+	}
+	f.lockout = &domain.AccountLockout{UserID: f.user.ID}
+	f.configure(func(*config.LoginCodeConfig) {})
+
+	f.userRepo.On("FindByEmail", f.user.Email).Return(f.user, nil).Maybe()
+	f.codeRepo.On("FindLatest", f.user.ID).Return(func(uuid.UUID) (*domain.LoginCode, error) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if f.issued == nil {
+			return nil, domain.ErrLoginCodeNotFound
+		}
+		copied := *f.issued
+		return &copied, nil
+	}).Maybe()
+	f.codeRepo.On("Create", mock.AnythingOfType("*domain.LoginCode")).
+		Run(func(args mock.Arguments) {
+			f.issued = args.Get(0).(*domain.LoginCode)
+			f.issued.CreatedAt = time.Now()
+		}).
+		Return(nil).Maybe()
+	f.codeRepo.On("FindLockout", f.user.ID).Return(func(uuid.UUID) *domain.AccountLockout {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		copied := *f.lockout
+		return &copied
+	}, nil).Maybe()
+	f.codeRepo.On("UpdateLockout", f.user.ID, mock.Anything).
+		Run(func(args mock.Arguments) {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			args.Get(1).(func(*domain.AccountLockout))(f.lockout)
+		}).
+		Return(nil).Maybe()
+	f.codeRepo.On("ClaimAttempt", mock.Anything, mock.Anything).Return(func(_ uuid.UUID, maxAttempts int) (bool, error) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if f.issued.UsedAt != nil || f.issued.Attempts >= maxAttempts {
+			return false, nil
+		}
+		f.issued.Attempts++
+		return true, nil
+	}).Maybe()
+	return f
+}
+
+var sixDigits = regexp.MustCompile(`\b\d{6}\b`)
+
+// sentCode extrai o código da última mensagem enviada pelo notificador.
+func sentCode(t *testing.T, n *fakeNotifier) string {
+	t.Helper()
+	require.NotEmpty(t, n.sent)
+	code := sixDigits.FindString(n.sent[len(n.sent)-1].Body)
+	require.NotEmpty(t, code)
+	return code
+}
+
+// wrongCode devolve um código de 6 dígitos diferente do correto.
+func wrongCode(code string) string {
+	if code == "000000" {
+		return "111111"
+	}
+	return "000000"
+}
+
+// TestAuthService_LoginWithCode_Email testa o fluxo completo de login por código enviado por e-mail.
+func TestAuthService_LoginWithCode_Email(t *testing.T) {
+	// Arrange
+	f := newLoginCodeFixture()
+	require.NoError(t, f.service.RequestLoginCode(service.RequestLoginCodeRequest{Email: f.user.Email}))
+	code := sentCode(t, f.email)
+	f.codeRepo.On("Consume", f.issued.ID).Return(true, nil)
+	f.userRepo.On("Update", f.user).Return(nil)
+
+	// Act
+	result, err := f.service.LoginWithCode(service.LoginWithCodeRequest{Email: f.user.Email, Code: code}, service.ClientInfo{})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.AccessToken)
+	assert.Equal(t, "joao@email.com", f.email.sent[0].To)
+	assert.Equal(t, domain.LoginCodeChannelEmail, f.issued.Channel)
+	assert.NotContains(t, f.issued.CodeHash, code)
+	assert.True(t, f.user.IsEmailVerified())
+	assert.Empty(t, f.sms.sent)
+}
+
+// TestAuthService_RequestLoginCode_SMS testa o envio do código por SMS para o telefone da conta.
+func TestAuthService_RequestLoginCode_SMS(t *testing.T) {
+	// Arrange
+	f := newLoginCodeFixture()
+	f.user.Phone = "+5511999990000"
+
+	// Act
+	err := f.service.RequestLoginCode(service.RequestLoginCodeRequest{Email: f.user.Email, Channel: "sms"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "+5511999990000", f.sms.sent[0].To)
+	assert.Empty(t, f.email.sent)
+	assert.Equal(t, domain.LoginCodeChannelSMS, f.issued.Channel)
+}
+
+// TestAuthService_RequestLoginCode_SMSWithoutPhone testa que contas sem telefone não recebem código por
+// SMS, com a mesma resposta de um envio.
+func TestAuthService_RequestLoginCode_SMSWithoutPhone(t *testing.T) {
+	// Arrange
+	f := newLoginCodeFixture()
+
+	// Act
+	err := f.service.RequestLoginCode(service.RequestLoginCodeRequest{Email: f.user.Email, Channel: "sms"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Empty(t, f.sms.sent)
+	f.codeRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestAuthService_RequestLoginCode_UnknownEmail testa que e-mails sem conta não geram erro nem envio.
+func TestAuthService_RequestLoginCode_UnknownEmail(t *testing.T) {
+	// Arrange
+	f := newLoginCodeFixture()
+	f.userRepo.On("FindByEmail", "ninguem@email.com").Return(nil, assert.AnError)
+
+	// Act
+	err := f.service.RequestLoginCode(service.RequestLoginCodeRequest{Email: "ninguem@email.com"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Empty(t, f.email.sent)
+}
+
+// TestAuthService_RequestLoginCode_DeliversInBackground testa que a requisição só agenda o envio,
+// sem consultar a conta, para responder no mesmo tempo com ou sem conta.
+func TestAuthService_RequestLoginCode_DeliversInBackground(t *testing.T) {
+	// Arrange
+	var queued []func()
+	userRepo := new(MockUserRepository)
+	codeRepo := new(MockLoginCodeRepository)
+	authService := service.NewAuthService(userRepo, new(MockInterestRepository), acceptSessions(), codeRepo,
+		service.LoginCodeSenders{Email: &fakeNotifier{}}, getTestJWTConfig(), config.LoginCodeConfig{},
+		func(task func()) { queued = append(queued, task) })
+
+	// Act
+	err := authService.RequestLoginCode(service.RequestLoginCodeRequest{Email: "maria@email.com"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, queued, 1)
+	userRepo.AssertNotCalled(t, "FindByEmail", mock.Anything)
+	codeRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestAuthService_RequestLoginCode_TooSoon testa a espera mínima entre dois envios de código, sem
+// diferenciar a resposta.
+func TestAuthService_RequestLoginCode_TooSoon(t *testing.T) {
+	// Arrange
+	f := newLoginCodeFixture()
+	require.NoError(t, f.service.RequestLoginCode(service.RequestLoginCodeRequest{Email: f.user.Email}))
+
+	// Act
+	err := f.service.RequestLoginCode(service.RequestLoginCodeRequest{Email: f.user.Email})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, f.email.sent, 1)
+}
+
+// TestAuthService_RequestLoginCode_LockedAccount testa que contas bloqueadas não recebem código, com a
+// mesma resposta de um envio.
+func TestAuthService_RequestLoginCode_LockedAccount(t *testing.T) {
+	// Arrange
+	f := newLoginCodeFixture()
+	until := time.Now().Add(10 * time.Minute)
+	f.lockout.LockedUntil = &until
+
+	// Act
+	err := f.service.RequestLoginCode(service.RequestLoginCodeRequest{Email: f.user.Email})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Empty(t, f.email.sent)
+	f.codeRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestAuthService_LoginWithCode_StopsAfterMaxAttempts testa que o código deixa de valer ao esgotar as tentativas.
+func TestAuthService_LoginWithCode_StopsAfterMaxAttempts(t *testing.T) {
+	// Arrange
+	f := newLoginCodeFixture()
+	require.NoError(t, f.service.RequestLoginCode(service.RequestLoginCodeRequest{Email: f.user.Email}))
+	code := sentCode(t, f.email)
+	req := service.LoginWithCodeRequest{Email: f.user.Email, Code: wrongCode(code)}
+
+	// Act
+	for i := 0; i < 3; i++ {
+		_, err := f.service.LoginWithCode(req, service.ClientInfo{})
+		assert.ErrorIs(t, err, service.ErrInvalidLoginCode)
+	}
+	_, err := f.service.LoginWithCode(service.LoginWithCodeRequest{Email: f.user.Email, Code: code}, service.ClientInfo{})
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrInvalidLoginCode)
+	assert.Equal(t, 3, f.issued.Attempts)
+	assert.Equal(t, 3, f.lockout.Failures)
+	f.codeRepo.AssertNotCalled(t, "Consume", mock.Anything)
+}
+
+// TestAuthService_LoginWithCode_ConcurrentGuesses testa que tentativas simultâneas não passam do
+// limite de tentativas do código.
+func TestAuthService_LoginWithCode_ConcurrentGuesses(t *testing.T) {
+	// Arrange
+	f := newLoginCodeFixture()
+	require.NoError(t, f.service.RequestLoginCode(service.RequestLoginCodeRequest{Email: f.user.Email}))
+	req := service.LoginWithCodeRequest{Email: f.user.Email, Code: wrongCode(sentCode(t, f.email))}
+
+	// Act
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := f.service.LoginWithCode(req, service.ClientInfo{})
+			assert.ErrorIs(t, err, service.ErrInvalidLoginCode)
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	assert.Equal(t, 3, f.issued.Attempts)
+	assert.Equal(t, 3, f.lockout.Failures)
+}
+
+// TestAuthService_LoginWithCode_LocksAccount testa o bloqueio da conta após falhas seguidas.
+func TestAuthService_LoginWithCode_LocksAccount(t *testing.T) {
+	// Arrange
+	f := newLoginCodeFixture()
+	require.NoError(t, f.service.RequestLoginCode(service.RequestLoginCodeRequest{Email: f.user.Email}))
+	code := sentCode(t, f.email)
+	f.lockout.Failures = 4
+
+	// Act
+	_, failErr := f.service.LoginWithCode(service.LoginWithCodeRequest{Email: f.user.Email, Code: wrongCode(code)}, service.ClientInfo{})
+	_, err := f.service.LoginWithCode(service.LoginWithCodeRequest{Email: f.user.Email, Code: code}, service.ClientInfo{})
+
+	// Assert
+	assert.ErrorIs(t, failErr, service.ErrInvalidLoginCode)
+	var lockedErr *service.AccountLockedError
+	require.True(t, errors.As(err, &lockedErr))
+	assert.InDelta(t, (15 * time.Minute).Seconds(), lockedErr.RetryAfter(time.Now()).Seconds(), 5)
+	assert.Equal(t, 0, f.lockout.Failures)
+	f.codeRepo.AssertNotCalled(t, "Consume", mock.Anything)
+}
+
+// TestAuthService_LoginWithCode_Expired testa que um código vencido é recusado.
+func TestAuthService_LoginWithCode_Expired(t *testing.T) {
+	// Arrange
+	f := newLoginCodeFixture()
+	require.NoError(t, f.service.RequestLoginCode(service.RequestLoginCodeRequest{Email: f.user.Email}))
+	code := sentCode(t, f.email)
+	f.issued.ExpiresAt = time.Now().Add(-time.Minute)
+
+	// Act
+	result, err := f.service.LoginWithCode(service.LoginWithCodeRequest{Email: f.user.Email, Code: code}, service.ClientInfo{})
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrInvalidLoginCode)
+	assert.Nil(t, result)
+	f.codeRepo.AssertNotCalled(t, "Consume", mock.Anything)
+}
@@ -15,30 +15,11 @@ import (
 // TestAuthService_RefreshToken_Success testa renovação de token com sucesso.
 func TestAuthService_RefreshToken_Success(t *testing.T) {
 	// Arrange
-	userRepo := new(MockUserRepository)
-	interestRepo := new(MockInterestRepository)
-	authService := service.NewAuthService(userRepo, interestRepo, getTestJWTConfig())
-
-	// Primeiro registra um usuário para obter um refresh token válido
-	req := service.RegisterRequest{
-		Name:     "Refresh User",
-		Email:    "refresh@email.com",
-		Password: "senha123",
-		UserType: "VOLUNTEER",
-	}
-
-	userRepo.On("ExistsByEmail", req.Email).Return(false, nil)
-	userRepo.On("Create", mock.AnythingOfType("*domain.User")).Return(nil)
-
-	result, _ := authService.Register(req)
+	authService, sessionRepo, result, session := registerWithSession(t)
+	firstTokenID := session.RefreshTokenID
 
-	// Prepara o mock para FindByID
-	userRepo.On("FindByID", mock.AnythingOfType("uuid.UUID")).Return(&domain.User{
-		ID:       uuid.New(),
-		Email:    req.Email,
-		Name:     req.Name,
-		UserType: domain.UserTypeVolunteer,
-	}, nil)
+	sessionRepo.On("Rotate", session.ID, firstTokenID, mock.AnythingOfType("uuid.UUID"), mock.AnythingOfType("time.Time")).
+		Return(true, nil)
 
 	// Act
 	newTokens, err := authService.RefreshToken(result.RefreshToken)
@@ -48,6 +29,9 @@ func TestAuthService_RefreshToken_Success(t *testing.T) {
 	assert.NotNil(t, newTokens)
 	assert.NotEmpty(t, newTokens.AccessToken)
 	assert.NotEmpty(t, newTokens.RefreshToken)
+	assert.NotEqual(t, result.RefreshToken, newTokens.RefreshToken)
+	assert.NotEqual(t, firstTokenID, session.RefreshTokenID)
+	sessionRepo.AssertExpectations(t)
 }
 
 // TestAuthService_RefreshToken_InvalidToken testa renovação com token inválido.
@@ -55,7 +39,7 @@ func TestAuthService_RefreshToken_InvalidToken(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	interestRepo := new(MockInterestRepository)
-	authService := service.NewAuthService(userRepo, interestRepo, getTestJWTConfig())
+	authService := newAuthService(userRepo, interestRepo, acceptSessions())
 
 	// Act
 	result, err := authService.RefreshToken("token-invalido")
@@ -70,7 +54,7 @@ func TestAuthService_Register_DBError(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	interestRepo := new(MockInterestRepository)
-	authService := service.NewAuthService(userRepo, interestRepo, getTestJWTConfig())
+	authService := newAuthService(userRepo, interestRepo, acceptSessions())
 
 	req := service.RegisterRequest{
 		Name:     "Erro User",
@@ -83,7 +67,7 @@ func TestAuthService_Register_DBError(t *testing.T) {
 	userRepo.On("Create", mock.AnythingOfType("*domain.User")).Return(assert.AnError)
 
 	// Act
-	result, err := authService.Register(req)
+	result, err := authService.Register(req, service.ClientInfo{})
 
 	// Assert
 	assert.Error(t, err)
@@ -95,7 +79,7 @@ func TestAuthService_Register_InterestFetchError(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	interestRepo := new(MockInterestRepository)
-	authService := service.NewAuthService(userRepo, interestRepo, getTestJWTConfig())
+	authService := newAuthService(userRepo, interestRepo, acceptSessions())
 
 	interestID := uuid.New()
 	req := service.RegisterRequest{
@@ -110,7 +94,7 @@ func TestAuthService_Register_InterestFetchError(t *testing.T) {
 	interestRepo.On("FindByIDs", req.InterestIDs).Return([]domain.Interest{}, assert.AnError)
 
 	// Act
-	result, err := authService.Register(req)
+	result, err := authService.Register(req, service.ClientInfo{})
 
 	// Assert
 	assert.Error(t, err)
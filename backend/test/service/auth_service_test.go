@@ -4,6 +4,7 @@ package service_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"amigos-terceira-idade/internal/config"
 	"amigos-terceira-idade/internal/domain"
@@ -55,6 +56,11 @@ func (m *MockUserRepository) Update(user *domain.User) error {
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) UpdateAudited(user *domain.User, entry *domain.AuditLog) error {
+	args := m.Called(user, entry)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) Delete(id uuid.UUID) error {
 	args := m.Called(id)
 	return args.Error(0)
@@ -65,6 +71,16 @@ func (m *MockUserRepository) FindByType(userType domain.UserType) ([]domain.User
 	return args.Get(0).([]domain.User), args.Error(1)
 }
 
+func (m *MockUserRepository) Search(filter repository.UserFilter, limit, offset int) ([]domain.User, int64, error) {
+	args := m.Called(filter, limit, offset)
+	return args.Get(0).([]domain.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserRepository) Merge(targetID, sourceID uuid.UUID, entry *domain.AuditLog) error {
+	args := m.Called(targetID, sourceID, entry)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) AddInterests(userID uuid.UUID, interests []domain.Interest) error {
 	args := m.Called(userID, interests)
 	return args.Error(0)
@@ -124,6 +140,59 @@ func (m *MockInterestRepository) SeedDefaults() error {
 	return args.Error(0)
 }
 
+// MockSessionRepository implementa repository.SessionRepositoryInterface para testes.
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+// Garante que implementa a interface
+var _ repository.SessionRepositoryInterface = (*MockSessionRepository)(nil)
+
+func (m *MockSessionRepository) Create(session *domain.Session) error {
+	args := m.Called(session)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) FindByID(id uuid.UUID) (*domain.Session, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) FindActiveByUser(userID uuid.UUID) ([]domain.Session, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]domain.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) Touch(id uuid.UUID, ipAddress string, seenAt time.Time) error {
+	args := m.Called(id, ipAddress, seenAt)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) Rotate(id, currentTokenID, nextTokenID uuid.UUID, expiresAt time.Time) (bool, error) {
+	args := m.Called(id, currentTokenID, nextTokenID, expiresAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSessionRepository) Revoke(id uuid.UUID, reason domain.SessionRevokeReason) error {
+	args := m.Called(id, reason)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) RevokeAllByUser(userID uuid.UUID, reason domain.SessionRevokeReason) error {
+	args := m.Called(userID, reason)
+	return args.Error(0)
+}
+
+// acceptSessions retorna um repositório de sessões que aceita a criação de qualquer sessão.
+func acceptSessions() *MockSessionRepository {
+	sessionRepo := new(MockSessionRepository)
+	sessionRepo.On("Create", mock.AnythingOfType("*domain.Session")).Return(nil).Maybe()
+	return sessionRepo
+}
+
 // getTestJWTConfig retorna uma configuração JWT para testes.
 func getTestJWTConfig() config.JWTConfig {
 	return config.JWTConfig{
@@ -133,12 +202,24 @@ func getTestJWTConfig() config.JWTConfig {
 	}
 }
 
+// newAuthService cria o serviço de autenticação com a configuração de testes e sem
+// login por código.
+func newAuthService(userRepo *MockUserRepository, interestRepo *MockInterestRepository, sessionRepo *MockSessionRepository) *service.AuthService {
+	return service.NewAuthService(userRepo, interestRepo, sessionRepo, new(MockLoginCodeRepository),
+		service.LoginCodeSenders{}, getTestJWTConfig(), config.LoginCodeConfig{}, inline)
+}
+
+// inline executa na hora as tarefas em segundo plano, para que os testes vejam o resultado.
+func inline(task func()) {
+	task()
+}
+
 // TestAuthService_Register_Success testa o cadastro com sucesso.
 func TestAuthService_Register_Success(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	interestRepo := new(MockInterestRepository)
-	authService := service.NewAuthService(userRepo, interestRepo, getTestJWTConfig())
+	authService := newAuthService(userRepo, interestRepo, acceptSessions())
 
 	req := service.RegisterRequest{
 		Name:     "João Silva",
@@ -152,7 +233,7 @@ func TestAuthService_Register_Success(t *testing.T) {
 	userRepo.On("Create", mock.AnythingOfType("*domain.User")).Return(nil)
 
 	// Act
-	result, err := authService.Register(req)
+	result, err := authService.Register(req, service.ClientInfo{})
 
 	// Assert
 	assert.NoError(t, err)
@@ -169,7 +250,7 @@ func TestAuthService_Register_EmailAlreadyExists(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	interestRepo := new(MockInterestRepository)
-	authService := service.NewAuthService(userRepo, interestRepo, getTestJWTConfig())
+	authService := newAuthService(userRepo, interestRepo, acceptSessions())
 
 	req := service.RegisterRequest{
 		Name:     "João Silva",
@@ -181,7 +262,7 @@ func TestAuthService_Register_EmailAlreadyExists(t *testing.T) {
 	userRepo.On("ExistsByEmail", req.Email).Return(true, nil)
 
 	// Act
-	result, err := authService.Register(req)
+	result, err := authService.Register(req, service.ClientInfo{})
 
 	// Assert
 	assert.Error(t, err)
@@ -195,7 +276,7 @@ func TestAuthService_Register_WithInterests(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	interestRepo := new(MockInterestRepository)
-	authService := service.NewAuthService(userRepo, interestRepo, getTestJWTConfig())
+	authService := newAuthService(userRepo, interestRepo, acceptSessions())
 
 	interestID := uuid.New()
 	req := service.RegisterRequest{
@@ -216,7 +297,7 @@ func TestAuthService_Register_WithInterests(t *testing.T) {
 	userRepo.On("Create", mock.AnythingOfType("*domain.User")).Return(nil)
 
 	// Act
-	result, err := authService.Register(req)
+	result, err := authService.Register(req, service.ClientInfo{})
 
 	// Assert
 	assert.NoError(t, err)
@@ -231,7 +312,7 @@ func TestAuthService_Login_Success(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	interestRepo := new(MockInterestRepository)
-	authService := service.NewAuthService(userRepo, interestRepo, getTestJWTConfig())
+	authService := newAuthService(userRepo, interestRepo, acceptSessions())
 
 	password := "senha123"
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -253,7 +334,7 @@ func TestAuthService_Login_Success(t *testing.T) {
 	userRepo.On("FindByEmail", req.Email).Return(user, nil)
 
 	// Act
-	result, err := authService.Login(req)
+	result, err := authService.Login(req, service.ClientInfo{})
 
 	// Assert
 	assert.NoError(t, err)
@@ -268,7 +349,7 @@ func TestAuthService_Login_InvalidPassword(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	interestRepo := new(MockInterestRepository)
-	authService := service.NewAuthService(userRepo, interestRepo, getTestJWTConfig())
+	authService := newAuthService(userRepo, interestRepo, acceptSessions())
 
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("senha123"), bcrypt.DefaultCost)
 
@@ -287,7 +368,7 @@ func TestAuthService_Login_InvalidPassword(t *testing.T) {
 	userRepo.On("FindByEmail", req.Email).Return(user, nil)
 
 	// Act
-	result, err := authService.Login(req)
+	result, err := authService.Login(req, service.ClientInfo{})
 
 	// Assert
 	assert.Error(t, err)
@@ -300,7 +381,7 @@ func TestAuthService_Login_UserNotFound(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	interestRepo := new(MockInterestRepository)
-	authService := service.NewAuthService(userRepo, interestRepo, getTestJWTConfig())
+	authService := newAuthService(userRepo, interestRepo, acceptSessions())
 
 	req := service.LoginRequest{
 		Email:    "naoexiste@email.com",
@@ -310,7 +391,7 @@ func TestAuthService_Login_UserNotFound(t *testing.T) {
 	userRepo.On("FindByEmail", req.Email).Return(nil, errors.New("usuário não encontrado"))
 
 	// Act
-	result, err := authService.Login(req)
+	result, err := authService.Login(req, service.ClientInfo{})
 
 	// Assert
 	assert.Error(t, err)
@@ -323,7 +404,7 @@ func TestAuthService_Login_InactiveUser(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	interestRepo := new(MockInterestRepository)
-	authService := service.NewAuthService(userRepo, interestRepo, getTestJWTConfig())
+	authService := newAuthService(userRepo, interestRepo, acceptSessions())
 
 	password := "senha123"
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -343,7 +424,7 @@ func TestAuthService_Login_InactiveUser(t *testing.T) {
 	userRepo.On("FindByEmail", req.Email).Return(user, nil)
 
 	// Act
-	result, err := authService.Login(req)
+	result, err := authService.Login(req, service.ClientInfo{})
 
 	// Assert
 	assert.Error(t, err)
@@ -354,22 +435,7 @@ func TestAuthService_Login_InactiveUser(t *testing.T) {
 // TestAuthService_ValidateToken_Success testa validação de token válido.
 func TestAuthService_ValidateToken_Success(t *testing.T) {
 	// Arrange
-	userRepo := new(MockUserRepository)
-	interestRepo := new(MockInterestRepository)
-	authService := service.NewAuthService(userRepo, interestRepo, getTestJWTConfig())
-
-	// Primeiro registra um usuário para obter um token válido
-	req := service.RegisterRequest{
-		Name:     "Teste",
-		Email:    "teste@email.com",
-		Password: "senha123",
-		UserType: "VOLUNTEER",
-	}
-
-	userRepo.On("ExistsByEmail", req.Email).Return(false, nil)
-	userRepo.On("Create", mock.AnythingOfType("*domain.User")).Return(nil)
-
-	result, _ := authService.Register(req)
+	authService, _, result, session := registerWithSession(t)
 
 	// Act
 	claims, err := authService.ValidateToken(result.AccessToken)
@@ -377,7 +443,9 @@ func TestAuthService_ValidateToken_Success(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	assert.NotNil(t, claims)
-	assert.Equal(t, req.Email, claims.Email)
+	assert.Equal(t, result.User.Email, claims.Email)
+	assert.Equal(t, session.ID, claims.SessionID)
+	assert.Equal(t, service.TokenTypeAccess, claims.Type)
 }
 
 // TestAuthService_ValidateToken_Invalid testa validação de token inválido.
@@ -385,7 +453,7 @@ func TestAuthService_ValidateToken_Invalid(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	interestRepo := new(MockInterestRepository)
-	authService := service.NewAuthService(userRepo, interestRepo, getTestJWTConfig())
+	authService := newAuthService(userRepo, interestRepo, acceptSessions())
 
 	// Act
 	claims, err := authService.ValidateToken("token-invalido")
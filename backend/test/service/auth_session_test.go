@@ -0,0 +1,327 @@
+// Package service_test contém os testes das sessões e dos tokens de autenticação.
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// registerWithSession cadastra um voluntário e devolve o serviço, o repositório de
+// sessões (que já devolve a sessão criada em FindByID), os tokens e a sessão.
+func registerWithSession(t *testing.T) (*service.AuthService, *MockSessionRepository, *service.AuthResponse, *domain.Session) {
+	t.Helper()
+
+	userRepo := new(MockUserRepository)
+	sessionRepo := new(MockSessionRepository)
+	authService := newAuthService(userRepo, new(MockInterestRepository), sessionRepo)
+
+	req := service.RegisterRequest{
+		Name:     "Sessão",
+		Email:    "sessao@email.com",
+		Password: "senha123",
+		UserType: "VOLUNTEER",
+	}
+	userRepo.On("ExistsByEmail", req.Email).Return(false, nil)
+	userRepo.On("Create", mock.AnythingOfType("*domain.User")).Return(nil)
+
+	var session *domain.Session
+	sessionRepo.On("Create", mock.AnythingOfType("*domain.Session")).
+		Run(func(args mock.Arguments) { session = args.Get(0).(*domain.Session) }).
+		Return(nil)
+
+	result, err := authService.Register(req, service.ClientInfo{})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	session.User = *result.User
+	sessionRepo.On("FindByID", session.ID).Return(session, nil)
+
+	return authService, sessionRepo, result, session
+}
+
+// TestAuthService_Register_CreatesSession testa que o cadastro abre uma sessão com o refresh token emitido.
+func TestAuthService_Register_CreatesSession(t *testing.T) {
+	// Act
+	_, _, result, session := registerWithSession(t)
+
+	// Assert
+	assert.Equal(t, result.User.ID, session.UserID)
+	assert.NotEqual(t, uuid.Nil, session.RefreshTokenID)
+	assert.True(t, session.ExpiresAt.After(time.Now().Add(6*24*time.Hour)))
+}
+
+// TestAuthService_ValidateToken_RejectsRefreshToken testa que o refresh token não autentica requisições.
+func TestAuthService_ValidateToken_RejectsRefreshToken(t *testing.T) {
+	// Arrange
+	authService, _, result, _ := registerWithSession(t)
+
+	// Act
+	claims, err := authService.ValidateToken(result.RefreshToken)
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrInvalidToken)
+	assert.Nil(t, claims)
+}
+
+// TestAuthService_ValidateToken_RoleFromSessionUser testa que o papel vem do usuário da sessão
+// e não do token, para que mudanças de papel valham antes de o access token expirar.
+func TestAuthService_ValidateToken_RoleFromSessionUser(t *testing.T) {
+	// Arrange
+	authService, _, result, session := registerWithSession(t)
+	institutionID := uuid.New()
+	session.User.Role = domain.RoleInstitutionStaff
+	session.User.StaffInstitutionID = &institutionID
+
+	// Act
+	claims, err := authService.ValidateToken(result.AccessToken)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RoleInstitutionStaff, claims.Role)
+	assert.Equal(t, &institutionID, claims.StaffInstitutionID)
+}
+
+// TestAuthService_ValidateToken_RevokedSession testa que o access token deixa de valer após o logout.
+func TestAuthService_ValidateToken_RevokedSession(t *testing.T) {
+	// Arrange
+	authService, sessionRepo, result, session := registerWithSession(t)
+	sessionRepo.On("Revoke", session.ID, domain.SessionRevokedLogout).
+		Run(func(args mock.Arguments) {
+			now := time.Now()
+			session.RevokedAt = &now
+		}).
+		Return(nil)
+
+	// Act
+	logoutErr := authService.Logout(session.ID)
+	claims, err := authService.ValidateToken(result.AccessToken)
+
+	// Assert
+	assert.NoError(t, logoutErr)
+	assert.ErrorIs(t, err, service.ErrSessionRevoked)
+	assert.Nil(t, claims)
+	sessionRepo.AssertExpectations(t)
+}
+
+// TestAuthService_ValidateToken_InactiveUser testa que o token de um usuário desativado é rejeitado.
+func TestAuthService_ValidateToken_InactiveUser(t *testing.T) {
+	// Arrange
+	authService, _, result, session := registerWithSession(t)
+	session.User.IsActive = false
+
+	// Act
+	claims, err := authService.ValidateToken(result.AccessToken)
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrUserInactive)
+	assert.Nil(t, claims)
+}
+
+// TestAuthService_RefreshToken_RejectsAccessToken testa que o access token não renova a sessão.
+func TestAuthService_RefreshToken_RejectsAccessToken(t *testing.T) {
+	// Arrange
+	authService, sessionRepo, result, _ := registerWithSession(t)
+
+	// Act
+	tokens, err := authService.RefreshToken(result.AccessToken)
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrInvalidToken)
+	assert.Nil(t, tokens)
+	sessionRepo.AssertNotCalled(t, "Rotate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestAuthService_RefreshToken_ReuseRevokesSession testa que reapresentar um refresh token já trocado encerra a sessão.
+func TestAuthService_RefreshToken_ReuseRevokesSession(t *testing.T) {
+	// Arrange
+	authService, sessionRepo, result, session := registerWithSession(t)
+	sessionRepo.On("Rotate", session.ID, session.RefreshTokenID, mock.AnythingOfType("uuid.UUID"), mock.AnythingOfType("time.Time")).
+		Return(true, nil)
+	sessionRepo.On("Revoke", session.ID, domain.SessionRevokedReuse).Return(nil)
+
+	_, err := authService.RefreshToken(result.RefreshToken)
+	assert.NoError(t, err)
+
+	// Act
+	tokens, err := authService.RefreshToken(result.RefreshToken)
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrRefreshTokenReused)
+	assert.Nil(t, tokens)
+	sessionRepo.AssertExpectations(t)
+}
+
+// TestAuthService_RefreshToken_ConcurrentRotation testa que, se outra renovação trocou o token antes, a sessão é encerrada.
+func TestAuthService_RefreshToken_ConcurrentRotation(t *testing.T) {
+	// Arrange
+	authService, sessionRepo, result, session := registerWithSession(t)
+	sessionRepo.On("Rotate", session.ID, session.RefreshTokenID, mock.AnythingOfType("uuid.UUID"), mock.AnythingOfType("time.Time")).
+		Return(false, nil)
+	sessionRepo.On("Revoke", session.ID, domain.SessionRevokedReuse).Return(nil)
+
+	// Act
+	tokens, err := authService.RefreshToken(result.RefreshToken)
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrRefreshTokenReused)
+	assert.Nil(t, tokens)
+	sessionRepo.AssertExpectations(t)
+}
+
+// TestAuthService_LogoutAll testa o encerramento de todas as sessões do usuário.
+func TestAuthService_LogoutAll(t *testing.T) {
+	// Arrange
+	sessionRepo := new(MockSessionRepository)
+	authService := newAuthService(new(MockUserRepository), new(MockInterestRepository), sessionRepo)
+	userID := uuid.New()
+	sessionRepo.On("RevokeAllByUser", userID, domain.SessionRevokedLogoutAll).Return(nil)
+
+	// Act
+	err := authService.LogoutAll(userID)
+
+	// Assert
+	assert.NoError(t, err)
+	sessionRepo.AssertExpectations(t)
+}
+
+// TestAuthService_Login_RecordsDevice testa que o login registra o dispositivo e o IP na sessão.
+func TestAuthService_Login_RecordsDevice(t *testing.T) {
+	// Arrange
+	userRepo := new(MockUserRepository)
+	sessionRepo := new(MockSessionRepository)
+	authService := newAuthService(userRepo, new(MockInterestRepository), sessionRepo)
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("senha123"), bcrypt.MinCost)
+	user := &domain.User{ID: uuid.New(), Email: "tablet@email.com", PasswordHash: string(hashedPassword), IsActive: true}
+	userRepo.On("FindByEmail", user.Email).Return(user, nil)
+
+	var session *domain.Session
+	sessionRepo.On("Create", mock.AnythingOfType("*domain.Session")).
+		Run(func(args mock.Arguments) { session = args.Get(0).(*domain.Session) }).
+		Return(nil)
+
+	client := service.ClientInfo{Platform: "android", AppVersion: "2.4.0", UserAgent: "okhttp/4.12", IPAddress: "10.0.0.5"}
+
+	// Act
+	_, err := authService.Login(service.LoginRequest{Email: user.Email, Password: "senha123"}, client)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "android 2.4.0", session.DeviceName)
+	assert.Equal(t, "okhttp/4.12", session.UserAgent)
+	assert.Equal(t, "10.0.0.5", session.IPAddress)
+	assert.False(t, session.LastSeenAt.IsZero())
+}
+
+// TestClientInfo_DeviceName testa o nome do dispositivo sem os headers do aplicativo.
+func TestClientInfo_DeviceName(t *testing.T) {
+	assert.Equal(t, "ios", service.ClientInfo{Platform: "ios"}.DeviceName())
+	assert.Equal(t, "Mozilla/5.0", service.ClientInfo{UserAgent: "Mozilla/5.0"}.DeviceName())
+	assert.Equal(t, "Dispositivo desconhecido", service.ClientInfo{}.DeviceName())
+}
+
+// TestAuthService_Authenticate_TouchesStaleSession testa que o uso da sessão é registrado quando o último está antigo.
+func TestAuthService_Authenticate_TouchesStaleSession(t *testing.T) {
+	// Arrange
+	authService, sessionRepo, result, session := registerWithSession(t)
+	session.LastSeenAt = time.Now().Add(-time.Hour)
+	sessionRepo.On("Touch", session.ID, "10.0.0.9", mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Act
+	claims, err := authService.Authenticate(result.AccessToken, service.ClientInfo{IPAddress: "10.0.0.9"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, session.ID, claims.SessionID)
+	sessionRepo.AssertExpectations(t)
+}
+
+// TestAuthService_Authenticate_SkipsRecentTouch testa que uma sessão usada há pouco, do mesmo IP, não é regravada.
+func TestAuthService_Authenticate_SkipsRecentTouch(t *testing.T) {
+	// Arrange
+	authService, sessionRepo, result, _ := registerWithSession(t)
+
+	// Act
+	_, err := authService.Authenticate(result.AccessToken, service.ClientInfo{})
+
+	// Assert
+	assert.NoError(t, err)
+	sessionRepo.AssertNotCalled(t, "Touch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestAuthService_ListSessions_MarksCurrent testa que a sessão da requisição vem marcada.
+func TestAuthService_ListSessions_MarksCurrent(t *testing.T) {
+	// Arrange
+	sessionRepo := new(MockSessionRepository)
+	authService := newAuthService(new(MockUserRepository), new(MockInterestRepository), sessionRepo)
+	userID := uuid.New()
+	current := domain.Session{ID: uuid.New(), UserID: userID, DeviceName: "ios 3.0.0"}
+	tablet := domain.Session{ID: uuid.New(), UserID: userID, DeviceName: "android 2.4.0"}
+	sessionRepo.On("FindActiveByUser", userID).Return([]domain.Session{tablet, current}, nil)
+
+	// Act
+	sessions, err := authService.ListSessions(userID, current.ID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+	assert.False(t, sessions[0].Current)
+	assert.True(t, sessions[1].Current)
+}
+
+// TestAuthService_RevokeSession testa o encerramento de uma sessão pela lista de dispositivos.
+func TestAuthService_RevokeSession(t *testing.T) {
+	// Arrange
+	sessionRepo := new(MockSessionRepository)
+	authService := newAuthService(new(MockUserRepository), new(MockInterestRepository), sessionRepo)
+	session := &domain.Session{ID: uuid.New(), UserID: uuid.New()}
+	sessionRepo.On("FindByID", session.ID).Return(session, nil)
+	sessionRepo.On("Revoke", session.ID, domain.SessionRevokedByUser).Return(nil)
+
+	// Act
+	err := authService.RevokeSession(session.UserID, session.ID)
+
+	// Assert
+	assert.NoError(t, err)
+	sessionRepo.AssertExpectations(t)
+}
+
+// TestAuthService_RevokeSession_OtherUser testa que a sessão de outro usuário é tratada como inexistente.
+func TestAuthService_RevokeSession_OtherUser(t *testing.T) {
+	// Arrange
+	sessionRepo := new(MockSessionRepository)
+	authService := newAuthService(new(MockUserRepository), new(MockInterestRepository), sessionRepo)
+	session := &domain.Session{ID: uuid.New(), UserID: uuid.New()}
+	sessionRepo.On("FindByID", session.ID).Return(session, nil)
+
+	// Act
+	err := authService.RevokeSession(uuid.New(), session.ID)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrSessionNotFound)
+	sessionRepo.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything)
+}
+
+// TestAuthService_CheckSession testa a conferência da sessão usada pelas conexões longas.
+func TestAuthService_CheckSession(t *testing.T) {
+	// Arrange
+	authService, _, _, session := registerWithSession(t)
+
+	// Act
+	activeErr := authService.CheckSession(session.ID)
+	now := time.Now()
+	session.RevokedAt = &now
+	revokedErr := authService.CheckSession(session.ID)
+
+	// Assert
+	assert.NoError(t, activeErr)
+	assert.ErrorIs(t, revokedErr, service.ErrSessionRevoked)
+}
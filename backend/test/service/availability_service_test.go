@@ -0,0 +1,227 @@
+// Package service_test contém os testes dos serviços da aplicação.
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+	"amigos-terceira-idade/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAvailabilityRepository implementa repository.AvailabilityRepositoryInterface para testes.
+type MockAvailabilityRepository struct {
+	mock.Mock
+}
+
+// Garante que implementa a interface
+var _ repository.AvailabilityRepositoryInterface = (*MockAvailabilityRepository)(nil)
+
+func (m *MockAvailabilityRepository) FindWeekly(volunteerID uuid.UUID) ([]domain.VolunteerAvailability, error) {
+	args := m.Called(volunteerID)
+	return args.Get(0).([]domain.VolunteerAvailability), args.Error(1)
+}
+
+func (m *MockAvailabilityRepository) ReplaceWeekly(volunteerID uuid.UUID, windows []domain.VolunteerAvailability) error {
+	args := m.Called(volunteerID, windows)
+	return args.Error(0)
+}
+
+func (m *MockAvailabilityRepository) FindExceptions(volunteerID uuid.UUID, from, to time.Time) ([]domain.AvailabilityException, error) {
+	args := m.Called(volunteerID, from, to)
+	return args.Get(0).([]domain.AvailabilityException), args.Error(1)
+}
+
+func (m *MockAvailabilityRepository) CreateException(exception *domain.AvailabilityException) error {
+	args := m.Called(exception)
+	return args.Error(0)
+}
+
+func (m *MockAvailabilityRepository) DeleteException(volunteerID, id uuid.UUID) error {
+	args := m.Called(volunteerID, id)
+	return args.Error(0)
+}
+
+func (m *MockAvailabilityRepository) FindVisitWindows(institutionID uuid.UUID) ([]domain.VisitWindow, error) {
+	args := m.Called(institutionID)
+	return args.Get(0).([]domain.VisitWindow), args.Error(1)
+}
+
+func (m *MockAvailabilityRepository) ReplaceVisitWindows(institutionID uuid.UUID, windows []domain.VisitWindow) error {
+	args := m.Called(institutionID, windows)
+	return args.Error(0)
+}
+
+// nextWeekday retorna, em UTC, a meia-noite no fuso padrão dos usuários da próxima
+// ocorrência do dia da semana (a partir de amanhã).
+func nextWeekday(weekday time.Weekday) time.Time {
+	loc := domain.LoadLocation(domain.DefaultTimezone)
+	now := time.Now().In(loc)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+	for day.Weekday() != weekday {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day.UTC()
+}
+
+// TestAvailabilityService_GetSlots_Success testa o cálculo de horários livres
+// descontando um agendamento já existente.
+func TestAvailabilityService_GetSlots_Success(t *testing.T) {
+	// Arrange
+	availabilityRepo := new(MockAvailabilityRepository)
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	availabilityService := service.NewAvailabilityService(availabilityRepo, appointmentRepo, userRepo)
+
+	volunteerID := uuid.New()
+	day := nextWeekday(time.Tuesday)
+	from, to := day, day.AddDate(0, 0, 1)
+
+	weekly := []domain.VolunteerAvailability{
+		{VolunteerID: volunteerID, Weekday: time.Tuesday, StartTime: "14:00", EndTime: "16:00"},
+	}
+	busy := []domain.Appointment{
+		{ID: uuid.New(), VolunteerID: volunteerID, Date: day.Add(14*time.Hour + 30*time.Minute), DurationMinutes: 30},
+	}
+
+	userRepo.On("FindByID", volunteerID).Return(&domain.User{ID: volunteerID, UserType: domain.UserTypeVolunteer}, nil)
+	availabilityRepo.On("FindWeekly", volunteerID).Return(weekly, nil)
+	availabilityRepo.On("FindExceptions", volunteerID, mock.Anything, mock.Anything).Return([]domain.AvailabilityException{}, nil)
+	appointmentRepo.On("FindOverlapping", []uuid.UUID{volunteerID}, from, to).Return(busy, nil)
+
+	// Act
+	slots, err := availabilityService.GetSlots(volunteerID, from, to, 30)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, slots, 3) // 14:00, 15:00 e 15:30 (14:30 está ocupado)
+	assert.Equal(t, day.Add(14*time.Hour), slots[0].Start)
+	assert.Equal(t, day.Add(15*time.Hour), slots[1].Start)
+	assert.Equal(t, day.Add(15*time.Hour+30*time.Minute), slots[2].Start)
+}
+
+// TestAvailabilityService_GetSlots_FullDayException testa que um bloqueio de dia inteiro remove os horários.
+func TestAvailabilityService_GetSlots_FullDayException(t *testing.T) {
+	// Arrange
+	availabilityRepo := new(MockAvailabilityRepository)
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	availabilityService := service.NewAvailabilityService(availabilityRepo, appointmentRepo, userRepo)
+
+	volunteerID := uuid.New()
+	day := nextWeekday(time.Wednesday)
+	from, to := day, day.AddDate(0, 0, 1)
+
+	weekly := []domain.VolunteerAvailability{
+		{VolunteerID: volunteerID, Weekday: time.Wednesday, StartTime: "09:00", EndTime: "12:00"},
+	}
+	exceptions := []domain.AvailabilityException{
+		{VolunteerID: volunteerID, Date: day, Available: false, Reason: "Feriado"},
+	}
+
+	userRepo.On("FindByID", volunteerID).Return(&domain.User{ID: volunteerID, UserType: domain.UserTypeVolunteer}, nil)
+	availabilityRepo.On("FindWeekly", volunteerID).Return(weekly, nil)
+	availabilityRepo.On("FindExceptions", volunteerID, mock.Anything, mock.Anything).Return(exceptions, nil)
+	appointmentRepo.On("FindOverlapping", []uuid.UUID{volunteerID}, from, to).Return([]domain.Appointment{}, nil)
+
+	// Act
+	slots, err := availabilityService.GetSlots(volunteerID, from, to, 60)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Empty(t, slots)
+}
+
+// TestAvailabilityService_SetWeekly_Overlapping testa erro com janelas sobrepostas.
+func TestAvailabilityService_SetWeekly_Overlapping(t *testing.T) {
+	// Arrange
+	availabilityRepo := new(MockAvailabilityRepository)
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	availabilityService := service.NewAvailabilityService(availabilityRepo, appointmentRepo, userRepo)
+
+	volunteerID := uuid.New()
+	userRepo.On("FindByID", volunteerID).Return(&domain.User{ID: volunteerID, UserType: domain.UserTypeVolunteer}, nil)
+
+	req := service.SetAvailabilityRequest{
+		Windows: []service.AvailabilityWindowRequest{
+			{Weekday: time.Monday, StartTime: "09:00", EndTime: "11:00"},
+			{Weekday: time.Monday, StartTime: "10:30", EndTime: "12:00"},
+		},
+	}
+
+	// Act
+	result, err := availabilityService.SetWeekly(volunteerID, req)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	availabilityRepo.AssertNotCalled(t, "ReplaceWeekly", mock.Anything, mock.Anything)
+}
+
+// TestAvailabilityService_SetWeekly_InvalidTime testa erro com horário inválido.
+func TestAvailabilityService_SetWeekly_InvalidTime(t *testing.T) {
+	// Arrange
+	availabilityRepo := new(MockAvailabilityRepository)
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	availabilityService := service.NewAvailabilityService(availabilityRepo, appointmentRepo, userRepo)
+
+	volunteerID := uuid.New()
+	userRepo.On("FindByID", volunteerID).Return(&domain.User{ID: volunteerID, UserType: domain.UserTypeVolunteer}, nil)
+
+	req := service.SetAvailabilityRequest{
+		Windows: []service.AvailabilityWindowRequest{
+			{Weekday: time.Friday, StartTime: "18:00", EndTime: "17:00"},
+		},
+	}
+
+	// Act
+	_, err := availabilityService.SetWeekly(volunteerID, req)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, "o horário de início deve ser anterior ao de término", err.Error())
+}
+
+// TestAppointmentService_Create_OutsideAvailability testa recusa de convite fora da disponibilidade.
+func TestAppointmentService_Create_OutsideAvailability(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	availabilityRepo := new(MockAvailabilityRepository)
+	appointmentService := service.NewAppointmentService(appointmentRepo, userRepo, new(MockResidentRepository), availabilityRepo, ignoreNotifications())
+
+	volunteerID := uuid.New()
+	targetID := uuid.New()
+	day := nextWeekday(time.Thursday)
+
+	weekly := []domain.VolunteerAvailability{
+		{VolunteerID: volunteerID, Weekday: time.Thursday, StartTime: "14:00", EndTime: "15:00"},
+	}
+
+	userRepo.On("FindByID", volunteerID).Return(&domain.User{ID: volunteerID, UserType: domain.UserTypeVolunteer}, nil)
+	userRepo.On("FindByID", targetID).Return(&domain.User{ID: targetID, UserType: domain.UserTypeElderly}, nil)
+	availabilityRepo.On("FindWeekly", volunteerID).Return(weekly, nil)
+	availabilityRepo.On("FindExceptions", volunteerID, mock.Anything, mock.Anything).Return([]domain.AvailabilityException{}, nil)
+
+	// Começa dentro da janela, mas termina depois dela
+	req := service.CreateAppointmentRequest{
+		TargetID:        targetID,
+		Date:            day.Add(14*time.Hour + 45*time.Minute),
+		DurationMinutes: 30,
+	}
+
+	// Act
+	result, err := appointmentService.Create(volunteerID, req)
+
+	// Assert
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, service.ErrOutsideAvailability)
+	appointmentRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
@@ -0,0 +1,168 @@
+package service_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+	"amigos-terceira-idade/internal/service"
+	"amigos-terceira-idade/pkg/ical"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCalendarFeedRepository implementa repository.CalendarFeedRepositoryInterface para testes.
+type MockCalendarFeedRepository struct {
+	mock.Mock
+}
+
+var _ repository.CalendarFeedRepositoryInterface = (*MockCalendarFeedRepository)(nil)
+
+func (m *MockCalendarFeedRepository) FindOrCreate(userID uuid.UUID, token string) (*domain.CalendarFeed, error) {
+	args := m.Called(userID, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CalendarFeed), args.Error(1)
+}
+
+func (m *MockCalendarFeedRepository) FindByToken(token string) (*domain.CalendarFeed, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CalendarFeed), args.Error(1)
+}
+
+func (m *MockCalendarFeedRepository) UpdateToken(userID uuid.UUID, token string) error {
+	args := m.Called(userID, token)
+	return args.Error(0)
+}
+
+// calendarAppointment cria um agendamento com os participantes preenchidos.
+func calendarAppointment(status domain.AppointmentStatus) domain.Appointment {
+	volunteer := domain.User{ID: uuid.New(), Name: "Ana Voluntária", Email: "ana@example.com"}
+	target := domain.User{ID: uuid.New(), Name: "Seu José", Email: "jose@example.com"}
+	return domain.Appointment{
+		ID:               uuid.New(),
+		VolunteerID:      volunteer.ID,
+		TargetID:         target.ID,
+		Date:             time.Date(2030, time.March, 5, 17, 0, 0, 0, time.UTC),
+		DurationMinutes:  45,
+		Status:           status,
+		MeetingURL:       "https://meet.google.com/abc-defg-hij",
+		Notes:            "Conversar sobre futebol; levar fotos",
+		CalendarSequence: 3,
+		Volunteer:        volunteer,
+		Target:           target,
+	}
+}
+
+// TestCalendarService_AppointmentICS_Confirmed testa a exportação de um agendamento confirmado.
+func TestCalendarService_AppointmentICS_Confirmed(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	calendarService := service.NewCalendarService(appointmentRepo, new(MockCalendarFeedRepository))
+
+	appointment := calendarAppointment(domain.AppointmentStatusConfirmed)
+	appointmentRepo.On("FindByID", appointment.ID).Return(&appointment, nil)
+
+	// Act
+	calendar, err := calendarService.AppointmentICS(appointment.ID, appointment.VolunteerID)
+
+	// Assert
+	assert.NoError(t, err)
+	ics := calendar.String()
+	assert.Contains(t, ics, "METHOD:PUBLISH\r\n")
+	assert.Contains(t, ics, fmt.Sprintf("UID:%s@amigos-terceira-idade\r\n", appointment.ID))
+	assert.Contains(t, ics, "SEQUENCE:3\r\n")
+	assert.Contains(t, ics, "DTSTART:20300305T170000Z\r\n")
+	assert.Contains(t, ics, "DTEND:20300305T174500Z\r\n")
+	assert.Contains(t, ics, "SUMMARY:Conversa com Seu José\r\n")
+	assert.Contains(t, ics, "STATUS:CONFIRMED\r\n")
+	assert.Contains(t, ics, `futebol\; levar fotos`)
+	assert.Contains(t, ics, "ATTENDEE;CN=Seu José:mailto:jose@example.com\r\n")
+}
+
+// TestCalendarService_AppointmentICS_Cancelled testa que cancelamentos usam METHOD:CANCEL.
+func TestCalendarService_AppointmentICS_Cancelled(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	calendarService := service.NewCalendarService(appointmentRepo, new(MockCalendarFeedRepository))
+
+	appointment := calendarAppointment(domain.AppointmentStatusCancelled)
+	appointmentRepo.On("FindByID", appointment.ID).Return(&appointment, nil)
+
+	// Act
+	calendar, err := calendarService.AppointmentICS(appointment.ID, appointment.TargetID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, ical.MethodCancel, calendar.Method)
+	assert.Equal(t, ical.StatusCancelled, calendar.Events[0].Status)
+	assert.Equal(t, "Conversa com Ana Voluntária", calendar.Events[0].Summary)
+}
+
+// TestCalendarService_AppointmentICS_NotParticipant testa que terceiros não exportam o agendamento.
+func TestCalendarService_AppointmentICS_NotParticipant(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	calendarService := service.NewCalendarService(appointmentRepo, new(MockCalendarFeedRepository))
+
+	appointment := calendarAppointment(domain.AppointmentStatusConfirmed)
+	appointmentRepo.On("FindByID", appointment.ID).Return(&appointment, nil)
+
+	// Act
+	calendar, err := calendarService.AppointmentICS(appointment.ID, uuid.New())
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrNotAppointmentParticipant)
+	assert.Nil(t, calendar)
+}
+
+// TestCalendarService_FeedICS testa que o feed traz os próximos agendamentos e os cancelamentos.
+func TestCalendarService_FeedICS(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	feedRepo := new(MockCalendarFeedRepository)
+	calendarService := service.NewCalendarService(appointmentRepo, feedRepo)
+
+	confirmed := calendarAppointment(domain.AppointmentStatusConfirmed)
+	cancelled := calendarAppointment(domain.AppointmentStatusCancelled)
+	userID := confirmed.VolunteerID
+
+	feedRepo.On("FindByToken", "segredo").Return(&domain.CalendarFeed{UserID: userID, Token: "segredo"}, nil)
+	appointmentRepo.On("FindUpcoming", userID).Return([]domain.Appointment{confirmed}, nil)
+	appointmentRepo.On("FindUpcomingCancelled", userID).Return([]domain.Appointment{cancelled}, nil)
+
+	// Act
+	calendar, err := calendarService.FeedICS("segredo")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, ical.MethodPublish, calendar.Method)
+	assert.Len(t, calendar.Events, 2)
+	assert.Equal(t, ical.StatusCancelled, calendar.Events[1].Status)
+}
+
+// TestCalendar_FoldsLongLines testa a dobra de linhas longas em 75 octetos.
+func TestCalendar_FoldsLongLines(t *testing.T) {
+	calendar := &ical.Calendar{
+		ProdID: "-//Teste//PT-BR",
+		Events: []ical.Event{{
+			UID:         "1@teste",
+			Summary:     "Conversa",
+			Description: strings.Repeat("ação ", 40),
+		}},
+	}
+
+	for _, line := range strings.Split(calendar.String(), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+	}
+	assert.Contains(t, calendar.String(), "\r\n ")
+}
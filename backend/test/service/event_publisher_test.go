@@ -0,0 +1,99 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/service"
+	"amigos-terceira-idade/pkg/pubsub"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewStatusChangeEvent_Types testa o tipo de evento gerado para cada mudança de status.
+func TestNewStatusChangeEvent_Types(t *testing.T) {
+	appointment := calendarAppointment(domain.AppointmentStatusPending)
+	newDate := appointment.Date.Add(48 * time.Hour)
+
+	tests := []struct {
+		name     string
+		change   *domain.AppointmentStatusHistory
+		expected domain.EventType
+	}{
+		{"aceite", domain.NewStatusChange(&appointment, domain.AppointmentStatusConfirmed, appointment.TargetID, ""), domain.EventAppointmentConfirmed},
+		{"cancelamento", domain.NewStatusChange(&appointment, domain.AppointmentStatusCancelled, appointment.TargetID, ""), domain.EventAppointmentCancelled},
+		{"remarcação", domain.NewReschedule(&appointment, domain.AppointmentStatusConfirmed, newDate, appointment.VolunteerID, ""), domain.EventAppointmentRescheduled},
+		{"proposta", domain.NewReschedule(&appointment, domain.AppointmentStatusRescheduleProposed, newDate, appointment.VolunteerID, ""), domain.EventAppointmentRescheduleProposed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := domain.NewStatusChangeEvent(&appointment, tt.change)
+
+			assert.Equal(t, tt.expected, event.Type)
+			assert.Equal(t, tt.change.ChangedBy, event.ActorID)
+			assert.ElementsMatch(t, []uuid.UUID{appointment.VolunteerID, appointment.TargetID}, event.UserIDs)
+			assert.Equal(t, tt.change.ToStatus, event.Appointment.Status)
+			assert.Equal(t, domain.AppointmentStatusPending, event.Appointment.PreviousStatus)
+		})
+	}
+}
+
+// TestNewStatusChangeEvent_RescheduleDates testa as datas levadas pelos eventos de remarcação.
+func TestNewStatusChangeEvent_RescheduleDates(t *testing.T) {
+	// Arrange
+	appointment := calendarAppointment(domain.AppointmentStatusConfirmed)
+	newDate := appointment.Date.Add(2 * time.Hour)
+	proposal := domain.NewReschedule(&appointment, domain.AppointmentStatusRescheduleProposed, newDate, appointment.TargetID, "")
+
+	proposed := appointment
+	proposed.Status = domain.AppointmentStatusRescheduleProposed
+	rejection := domain.NewStatusChange(&proposed, domain.AppointmentStatusConfirmed, appointment.VolunteerID, "")
+
+	// Act
+	proposalEvent := domain.NewStatusChangeEvent(&appointment, proposal)
+	rejectionEvent := domain.NewStatusChangeEvent(&proposed, rejection)
+
+	// Assert
+	assert.True(t, proposalEvent.Appointment.Date.Equal(appointment.Date))
+	assert.True(t, proposalEvent.Appointment.ProposedDate.Equal(newDate))
+	assert.Equal(t, domain.EventAppointmentRescheduleRejected, rejectionEvent.Type)
+	assert.Nil(t, rejectionEvent.Appointment.ProposedDate)
+}
+
+// TestHubPublisher_Handle testa a entrega do evento serializado a cada usuário afetado.
+func TestHubPublisher_Handle(t *testing.T) {
+	// Arrange
+	hub := pubsub.NewHub(10, pubsub.DefaultRetention)
+	publisher := service.NewHubPublisher(hub)
+	connection := &domain.Connection{
+		ID:          uuid.New(),
+		VolunteerID: uuid.New(),
+		TargetID:    uuid.New(),
+		Status:      domain.ConnectionStatusAccepted,
+	}
+	volunteerSub, _, _ := hub.Subscribe(connection.VolunteerID.String(), "")
+	defer volunteerSub.Close()
+	otherSub, _, _ := hub.Subscribe(uuid.New().String(), "")
+	defer otherSub.Close()
+
+	// Act
+	err := publisher.Handle(context.Background(), domain.NewConnectionStatusEvent(connection))
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, volunteerSub.C(), 1)
+	message := <-volunteerSub.C()
+	assert.Equal(t, "connection.accepted", message.Type)
+
+	var event domain.Event
+	require.NoError(t, json.Unmarshal(message.Data, &event))
+	assert.Equal(t, connection.ID, event.Connection.ID)
+	assert.Equal(t, connection.TargetID, event.ActorID)
+	assert.Empty(t, otherSub.C())
+}
@@ -17,7 +17,7 @@ func TestMatchingService_Connect_NotVolunteer(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	connectionRepo := new(MockConnectionRepository)
-	matchingService := service.NewMatchingService(userRepo, connectionRepo)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, noResidents(), new(MockVerificationRepository), ignoreNotifications(), service.MatchingOptions{})
 
 	elderlyID := uuid.New()
 	targetID := uuid.New()
@@ -41,7 +41,7 @@ func TestMatchingService_GetSuggestions_UserNotFound(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	connectionRepo := new(MockConnectionRepository)
-	matchingService := service.NewMatchingService(userRepo, connectionRepo)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, noResidents(), new(MockVerificationRepository), ignoreNotifications(), service.MatchingOptions{})
 
 	userID := uuid.New()
 	userRepo.On("FindByID", userID).Return(nil, errors.New("usuário não encontrado"))
@@ -59,7 +59,7 @@ func TestMatchingService_GetSuggestions_FilterElderly(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	connectionRepo := new(MockConnectionRepository)
-	matchingService := service.NewMatchingService(userRepo, connectionRepo)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, noResidents(), new(MockVerificationRepository), ignoreNotifications(), service.MatchingOptions{})
 
 	volunteerID := uuid.New()
 	volunteer := &domain.User{
@@ -91,7 +91,7 @@ func TestMatchingService_GetSuggestions_SkipsConnected(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	connectionRepo := new(MockConnectionRepository)
-	matchingService := service.NewMatchingService(userRepo, connectionRepo)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, noResidents(), new(MockVerificationRepository), ignoreNotifications(), service.MatchingOptions{})
 
 	volunteerID := uuid.New()
 	volunteer := &domain.User{
@@ -127,7 +127,7 @@ func TestMatchingService_GetSuggestions_MultipleMatches(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	connectionRepo := new(MockConnectionRepository)
-	matchingService := service.NewMatchingService(userRepo, connectionRepo)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, noResidents(), new(MockVerificationRepository), ignoreNotifications(), service.MatchingOptions{})
 
 	interestID1 := uuid.New()
 	interestID2 := uuid.New()
@@ -188,7 +188,7 @@ func TestMatchingService_Connect_UserNotFound(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	connectionRepo := new(MockConnectionRepository)
-	matchingService := service.NewMatchingService(userRepo, connectionRepo)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, noResidents(), new(MockVerificationRepository), ignoreNotifications(), service.MatchingOptions{})
 
 	volunteerID := uuid.New()
 	targetID := uuid.New()
@@ -209,7 +209,7 @@ func TestMatchingService_Connect_TargetNotFound(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	connectionRepo := new(MockConnectionRepository)
-	matchingService := service.NewMatchingService(userRepo, connectionRepo)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, noResidents(), new(MockVerificationRepository), ignoreNotifications(), service.MatchingOptions{})
 
 	volunteerID := uuid.New()
 	targetID := uuid.New()
@@ -232,7 +232,7 @@ func TestMatchingService_GetConnections_Error(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	connectionRepo := new(MockConnectionRepository)
-	matchingService := service.NewMatchingService(userRepo, connectionRepo)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, noResidents(), new(MockVerificationRepository), ignoreNotifications(), service.MatchingOptions{})
 
 	userID := uuid.New()
 	userRepo.On("FindByID", userID).Return(nil, errors.New("erro"))
@@ -54,6 +54,11 @@ func (m *MockConnectionRepository) Exists(volunteerID, targetID uuid.UUID) (bool
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockConnectionRepository) ExistsForResident(volunteerID, residentID uuid.UUID) (bool, error) {
+	args := m.Called(volunteerID, residentID)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockConnectionRepository) Update(connection *domain.Connection) error {
 	args := m.Called(connection)
 	return args.Error(0)
@@ -74,7 +79,7 @@ func TestMatchingService_GetSuggestions_Success(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	connectionRepo := new(MockConnectionRepository)
-	matchingService := service.NewMatchingService(userRepo, connectionRepo)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, noResidents(), new(MockVerificationRepository), ignoreNotifications(), service.MatchingOptions{})
 
 	volunteerID := uuid.New()
 	volunteer := &domain.User{
@@ -119,7 +124,7 @@ func TestMatchingService_GetSuggestions_FilterByType(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	connectionRepo := new(MockConnectionRepository)
-	matchingService := service.NewMatchingService(userRepo, connectionRepo)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, noResidents(), new(MockVerificationRepository), ignoreNotifications(), service.MatchingOptions{})
 
 	volunteerID := uuid.New()
 	volunteer := &domain.User{
@@ -151,7 +156,7 @@ func TestMatchingService_GetSuggestions_NotVolunteer(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	connectionRepo := new(MockConnectionRepository)
-	matchingService := service.NewMatchingService(userRepo, connectionRepo)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, noResidents(), new(MockVerificationRepository), ignoreNotifications(), service.MatchingOptions{})
 
 	elderlyID := uuid.New()
 	elderly := &domain.User{
@@ -175,7 +180,7 @@ func TestMatchingService_Connect_Success(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	connectionRepo := new(MockConnectionRepository)
-	matchingService := service.NewMatchingService(userRepo, connectionRepo)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, noResidents(), new(MockVerificationRepository), ignoreNotifications(), service.MatchingOptions{})
 
 	volunteerID := uuid.New()
 	targetID := uuid.New()
@@ -214,7 +219,7 @@ func TestMatchingService_Connect_AlreadyExists(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	connectionRepo := new(MockConnectionRepository)
-	matchingService := service.NewMatchingService(userRepo, connectionRepo)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, noResidents(), new(MockVerificationRepository), ignoreNotifications(), service.MatchingOptions{})
 
 	volunteerID := uuid.New()
 	targetID := uuid.New()
@@ -235,7 +240,7 @@ func TestMatchingService_Connect_CannotConnectWithVolunteer(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	connectionRepo := new(MockConnectionRepository)
-	matchingService := service.NewMatchingService(userRepo, connectionRepo)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, noResidents(), new(MockVerificationRepository), ignoreNotifications(), service.MatchingOptions{})
 
 	volunteerID := uuid.New()
 	targetID := uuid.New()
@@ -261,17 +266,32 @@ func TestMatchingService_AcceptConnection_Success(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	connectionRepo := new(MockConnectionRepository)
-	matchingService := service.NewMatchingService(userRepo, connectionRepo)
+	notifications := new(MockNotificationSender)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, noResidents(), new(MockVerificationRepository), notifications, service.MatchingOptions{})
 
 	connectionID := uuid.New()
+	volunteerID := uuid.New()
+	targetID := uuid.New()
+	connectionRepo.On("FindByID", connectionID).Return(&domain.Connection{
+		ID:          connectionID,
+		VolunteerID: volunteerID,
+		TargetID:    targetID,
+		Status:      domain.ConnectionStatusPending,
+		Target:      domain.User{Name: "Dona Maria"},
+	}, nil)
 	connectionRepo.On("UpdateStatus", connectionID, domain.ConnectionStatusAccepted).Return(nil)
+	notifications.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.UserID == volunteerID && n.Type == domain.NotificationConnectionAccepted &&
+			*n.ConnectionID == connectionID && n.Body == "Dona Maria aceitou seu pedido de conexão."
+	})).Return(nil).Once()
 
 	// Act
-	err := matchingService.AcceptConnection(connectionID)
+	err := matchingService.AcceptConnection(connectionID, targetID)
 
 	// Assert
 	assert.NoError(t, err)
 	connectionRepo.AssertExpectations(t)
+	notifications.AssertExpectations(t)
 }
 
 // TestMatchingService_RejectConnection_Success testa rejeitar conexão com sucesso.
@@ -279,25 +299,98 @@ func TestMatchingService_RejectConnection_Success(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	connectionRepo := new(MockConnectionRepository)
-	matchingService := service.NewMatchingService(userRepo, connectionRepo)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, noResidents(), new(MockVerificationRepository), ignoreNotifications(), service.MatchingOptions{})
 
 	connectionID := uuid.New()
+	targetID := uuid.New()
+	connectionRepo.On("FindByID", connectionID).Return(&domain.Connection{
+		ID:          connectionID,
+		VolunteerID: uuid.New(),
+		TargetID:    targetID,
+		Status:      domain.ConnectionStatusPending,
+	}, nil)
 	connectionRepo.On("UpdateStatus", connectionID, domain.ConnectionStatusRejected).Return(nil)
 
 	// Act
-	err := matchingService.RejectConnection(connectionID)
+	err := matchingService.RejectConnection(connectionID, targetID)
 
 	// Assert
 	assert.NoError(t, err)
 	connectionRepo.AssertExpectations(t)
 }
 
+// TestMatchingService_AcceptConnection_NotTarget testa que o voluntário não aceita o próprio pedido.
+func TestMatchingService_AcceptConnection_NotTarget(t *testing.T) {
+	// Arrange
+	userRepo := new(MockUserRepository)
+	connectionRepo := new(MockConnectionRepository)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, noResidents(), new(MockVerificationRepository), ignoreNotifications(), service.MatchingOptions{})
+
+	connectionID := uuid.New()
+	volunteerID := uuid.New()
+	connectionRepo.On("FindByID", connectionID).Return(&domain.Connection{
+		ID:          connectionID,
+		VolunteerID: volunteerID,
+		TargetID:    uuid.New(),
+		Status:      domain.ConnectionStatusPending,
+	}, nil)
+
+	// Act
+	err := matchingService.AcceptConnection(connectionID, volunteerID)
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrNotConnectionTarget)
+	connectionRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+}
+
+// TestMatchingService_AcceptConnection_AlreadyAnswered testa que uma conexão respondida não muda de novo.
+func TestMatchingService_AcceptConnection_AlreadyAnswered(t *testing.T) {
+	// Arrange
+	userRepo := new(MockUserRepository)
+	connectionRepo := new(MockConnectionRepository)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, noResidents(), new(MockVerificationRepository), ignoreNotifications(), service.MatchingOptions{})
+
+	connectionID := uuid.New()
+	targetID := uuid.New()
+	connectionRepo.On("FindByID", connectionID).Return(&domain.Connection{
+		ID:          connectionID,
+		VolunteerID: uuid.New(),
+		TargetID:    targetID,
+		Status:      domain.ConnectionStatusRejected,
+	}, nil)
+
+	// Act
+	err := matchingService.AcceptConnection(connectionID, targetID)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrConnectionNotPending)
+	connectionRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+}
+
+// TestMatchingService_RejectConnection_NotFound testa o erro de conexão inexistente.
+func TestMatchingService_RejectConnection_NotFound(t *testing.T) {
+	// Arrange
+	userRepo := new(MockUserRepository)
+	connectionRepo := new(MockConnectionRepository)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, noResidents(), new(MockVerificationRepository), ignoreNotifications(), service.MatchingOptions{})
+
+	connectionID := uuid.New()
+	connectionRepo.On("FindByID", connectionID).Return(nil, domain.ErrConnectionNotFound)
+
+	// Act
+	err := matchingService.RejectConnection(connectionID, uuid.New())
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrConnectionNotFound)
+	connectionRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+}
+
 // TestMatchingService_GetConnections_Volunteer testa listar conexões de voluntário.
 func TestMatchingService_GetConnections_Volunteer(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	connectionRepo := new(MockConnectionRepository)
-	matchingService := service.NewMatchingService(userRepo, connectionRepo)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, noResidents(), new(MockVerificationRepository), ignoreNotifications(), service.MatchingOptions{})
 
 	volunteerID := uuid.New()
 	volunteer := &domain.User{ID: volunteerID, UserType: domain.UserTypeVolunteer}
@@ -321,7 +414,7 @@ func TestMatchingService_GetConnections_Elderly(t *testing.T) {
 	// Arrange
 	userRepo := new(MockUserRepository)
 	connectionRepo := new(MockConnectionRepository)
-	matchingService := service.NewMatchingService(userRepo, connectionRepo)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, noResidents(), new(MockVerificationRepository), ignoreNotifications(), service.MatchingOptions{})
 
 	elderlyID := uuid.New()
 	elderly := &domain.User{ID: elderlyID, UserType: domain.UserTypeElderly}
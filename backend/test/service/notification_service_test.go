@@ -0,0 +1,207 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+	"amigos-terceira-idade/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockNotificationRepository implementa repository.NotificationRepositoryInterface para testes.
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+var _ repository.NotificationRepositoryInterface = (*MockNotificationRepository)(nil)
+
+func (m *MockNotificationRepository) Create(notification *domain.Notification) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) FindByUser(userID uuid.UUID, unreadOnly bool, limit, offset int) ([]domain.Notification, int64, error) {
+	args := m.Called(userID, unreadOnly, limit, offset)
+	return args.Get(0).([]domain.Notification), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockNotificationRepository) CountUnread(userID uuid.UUID) (map[domain.NotificationType]int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(map[domain.NotificationType]int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) MarkRead(userID, id uuid.UUID, readAt time.Time) error {
+	args := m.Called(userID, id, readAt)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) MarkAllRead(userID uuid.UUID, readAt time.Time) (int64, error) {
+	args := m.Called(userID, readAt)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) FindPreferences(userID uuid.UUID) ([]domain.NotificationPreference, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]domain.NotificationPreference), args.Error(1)
+}
+
+func (m *MockNotificationRepository) SavePreferences(preferences []domain.NotificationPreference) error {
+	args := m.Called(preferences)
+	return args.Error(0)
+}
+
+// MockNotificationSender implementa service.NotificationSender para testes.
+type MockNotificationSender struct {
+	mock.Mock
+}
+
+var _ service.NotificationSender = (*MockNotificationSender)(nil)
+
+func (m *MockNotificationSender) Notify(notification *domain.Notification) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
+
+// ignoreNotifications aceita qualquer notificação, para testes que não as verificam.
+func ignoreNotifications() *MockNotificationSender {
+	sender := new(MockNotificationSender)
+	sender.On("Notify", mock.Anything).Return(nil).Maybe()
+	return sender
+}
+
+// TestNotificationService_Notify_RespectsPreferences testa que tipos desativados não são registrados.
+func TestNotificationService_Notify_RespectsPreferences(t *testing.T) {
+	// Arrange
+	notificationRepo := new(MockNotificationRepository)
+	notificationService := service.NewNotificationService(notificationRepo)
+
+	userID := uuid.New()
+	notificationRepo.On("FindPreferences", userID).Return([]domain.NotificationPreference{
+		{UserID: userID, Type: domain.NotificationAppointmentReminder, Enabled: false},
+	}, nil)
+	notificationRepo.On("Create", mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.Type == domain.NotificationInvitationReceived
+	})).Return(nil).Once()
+
+	// Act
+	errReminder := notificationService.Notify(&domain.Notification{UserID: userID, Type: domain.NotificationAppointmentReminder})
+	errInvitation := notificationService.Notify(&domain.Notification{UserID: userID, Type: domain.NotificationInvitationReceived})
+
+	// Assert
+	assert.NoError(t, errReminder)
+	assert.NoError(t, errInvitation)
+	notificationRepo.AssertExpectations(t)
+	notificationRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+// TestNotificationService_GetPreferences_Defaults testa que tipos sem preferência ficam ativos.
+func TestNotificationService_GetPreferences_Defaults(t *testing.T) {
+	// Arrange
+	notificationRepo := new(MockNotificationRepository)
+	notificationService := service.NewNotificationService(notificationRepo)
+
+	userID := uuid.New()
+	notificationRepo.On("FindPreferences", userID).Return([]domain.NotificationPreference{
+		{UserID: userID, Type: domain.NotificationConnectionRequested, Enabled: false},
+	}, nil)
+
+	// Act
+	preferences, err := notificationService.GetPreferences(userID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, preferences, len(domain.NotificationTypes))
+	for _, preference := range preferences {
+		assert.Equal(t, preference.Type != domain.NotificationConnectionRequested, preference.Enabled, preference.Type)
+	}
+}
+
+// TestNotificationService_UpdatePreferences_InvalidType testa a rejeição de tipos desconhecidos.
+func TestNotificationService_UpdatePreferences_InvalidType(t *testing.T) {
+	// Arrange
+	notificationRepo := new(MockNotificationRepository)
+	notificationService := service.NewNotificationService(notificationRepo)
+
+	// Act
+	preferences, err := notificationService.UpdatePreferences(uuid.New(), service.UpdatePreferencesRequest{
+		Preferences: map[domain.NotificationType]bool{"PROMOTIONS": false},
+	})
+
+	// Assert
+	assert.Nil(t, preferences)
+	assert.Error(t, err)
+	notificationRepo.AssertNotCalled(t, "SavePreferences", mock.Anything)
+}
+
+// TestNotificationService_List_Pagination testa os limites da paginação.
+func TestNotificationService_List_Pagination(t *testing.T) {
+	// Arrange
+	notificationRepo := new(MockNotificationRepository)
+	notificationService := service.NewNotificationService(notificationRepo)
+
+	userID := uuid.New()
+	notificationRepo.On("FindByUser", userID, true, service.MaxNotificationPageSize, 2*service.MaxNotificationPageSize).
+		Return([]domain.Notification{}, int64(250), nil)
+
+	// Act
+	page, err := notificationService.List(userID, true, 3, 1000)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 3, page.Page)
+	assert.Equal(t, service.MaxNotificationPageSize, page.PerPage)
+	assert.Equal(t, int64(250), page.Total)
+}
+
+// TestNotificationService_GetUnreadCount testa a soma das não lidas por tipo.
+func TestNotificationService_GetUnreadCount(t *testing.T) {
+	// Arrange
+	notificationRepo := new(MockNotificationRepository)
+	notificationService := service.NewNotificationService(notificationRepo)
+
+	userID := uuid.New()
+	notificationRepo.On("CountUnread", userID).Return(map[domain.NotificationType]int64{
+		domain.NotificationInvitationReceived:  2,
+		domain.NotificationAppointmentReminder: 1,
+	}, nil)
+
+	// Act
+	count, err := notificationService.GetUnreadCount(userID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count.Total)
+	assert.Equal(t, int64(2), count.ByType[domain.NotificationInvitationReceived])
+}
+
+// TestAppointmentService_Cancel_NotifiesOtherParticipant testa que o outro participante
+// recebe a notificação de cancelamento.
+func TestAppointmentService_Cancel_NotifiesOtherParticipant(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	availabilityRepo := new(MockAvailabilityRepository)
+	notifications := new(MockNotificationSender)
+	appointmentService := service.NewAppointmentService(appointmentRepo, new(MockUserRepository), new(MockResidentRepository), availabilityRepo, notifications)
+
+	appointment := calendarAppointment(domain.AppointmentStatusConfirmed)
+	appointmentRepo.On("FindByID", appointment.ID).Return(&appointment, nil)
+	appointmentRepo.On("ChangeStatus", statusChange(appointment.ID, domain.AppointmentStatusCancelled)).Return(nil)
+	notifications.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.UserID == appointment.VolunteerID &&
+			n.Type == domain.NotificationAppointmentCancelled &&
+			*n.ActorID == appointment.TargetID &&
+			n.Body == "Seu José cancelou a conversa de 05/03/2030 às 14:00."
+	})).Return(nil).Once()
+
+	// Act
+	err := appointmentService.Cancel(appointment.ID, appointment.TargetID, "", service.SeriesScopeThis)
+
+	// Assert
+	assert.NoError(t, err)
+	notifications.AssertExpectations(t)
+}
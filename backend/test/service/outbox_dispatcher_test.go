@@ -0,0 +1,192 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+	"amigos-terceira-idade/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockOutboxRepository implementa repository.OutboxRepositoryInterface para testes.
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+var _ repository.OutboxRepositoryInterface = (*MockOutboxRepository)(nil)
+
+func (m *MockOutboxRepository) FindDue(now time.Time, limit int) ([]domain.OutboxMessage, error) {
+	args := m.Called(now, limit)
+	return args.Get(0).([]domain.OutboxMessage), args.Error(1)
+}
+
+func (m *MockOutboxRepository) Lease(message *domain.OutboxMessage, until time.Time) (bool, error) {
+	args := m.Called(message, until)
+	if args.Bool(0) {
+		message.NextAttemptAt = until
+	}
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOutboxRepository) SaveAttempt(message *domain.OutboxMessage) error {
+	args := m.Called(message)
+	return args.Error(0)
+}
+
+// outboxMessage cria uma mensagem pendente com o evento de aceite de uma conexão.
+func outboxMessage(t *testing.T, attempts int) domain.OutboxMessage {
+	t.Helper()
+	message, err := domain.NewOutboxMessage(domain.NewConnectionStatusEvent(&domain.Connection{
+		ID:          uuid.New(),
+		VolunteerID: uuid.New(),
+		TargetID:    uuid.New(),
+		Status:      domain.ConnectionStatusAccepted,
+	}))
+	require.NoError(t, err)
+	message.ID = uuid.New()
+	message.Attempts = attempts
+	return *message
+}
+
+// TestOutboxDispatcher_DispatchDue_Success testa a entrega aos handlers dos tipos registrados.
+func TestOutboxDispatcher_DispatchDue_Success(t *testing.T) {
+	// Arrange
+	outboxRepo := new(MockOutboxRepository)
+	dispatcher := service.NewOutboxDispatcher(outboxRepo, service.OutboxOptions{})
+
+	var received []domain.EventType
+	dispatcher.Register("todos", func(ctx context.Context, event domain.Event) error {
+		received = append(received, event.Type)
+		return nil
+	})
+	dispatcher.Register("agendamentos", func(ctx context.Context, event domain.Event) error {
+		t.Fatal("handler de outro tipo não deveria ser chamado")
+		return nil
+	}, domain.EventAppointmentCreated)
+
+	now := time.Now().UTC()
+	message := outboxMessage(t, 0)
+	outboxRepo.On("FindDue", now, 100).Return([]domain.OutboxMessage{message}, nil)
+	outboxRepo.On("Lease", mock.Anything, now.Add(time.Minute)).Return(true, nil)
+	outboxRepo.On("SaveAttempt", mock.MatchedBy(func(m *domain.OutboxMessage) bool {
+		return m.Status == domain.OutboxStatusDelivered && m.DeliveredTo == "todos" && m.ProcessedAt.Equal(now)
+	})).Return(nil).Once()
+
+	// Act
+	delivered, err := dispatcher.DispatchDue(context.Background(), now)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Equal(t, []domain.EventType{domain.EventConnectionAccepted}, received)
+	outboxRepo.AssertExpectations(t)
+}
+
+// TestOutboxDispatcher_DispatchDue_RetriesOnlyFailedHandler testa o reagendamento com backoff
+// e que o handler que já recebeu o evento não é chamado de novo.
+func TestOutboxDispatcher_DispatchDue_RetriesOnlyFailedHandler(t *testing.T) {
+	// Arrange
+	outboxRepo := new(MockOutboxRepository)
+	dispatcher := service.NewOutboxDispatcher(outboxRepo, service.OutboxOptions{BaseBackoff: 10 * time.Second})
+
+	calls := map[string]int{}
+	dispatcher.Register("sse", func(ctx context.Context, event domain.Event) error {
+		calls["sse"]++
+		return nil
+	})
+	dispatcher.Register("webhooks", func(ctx context.Context, event domain.Event) error {
+		calls["webhooks"]++
+		return errors.New("receptor indisponível")
+	})
+
+	now := time.Now().UTC()
+	message := outboxMessage(t, 2)
+	message.DeliveredTo = "sse"
+	outboxRepo.On("FindDue", now, 100).Return([]domain.OutboxMessage{message}, nil)
+	outboxRepo.On("Lease", mock.Anything, mock.Anything).Return(true, nil)
+	outboxRepo.On("SaveAttempt", mock.MatchedBy(func(m *domain.OutboxMessage) bool {
+		return m.Status == domain.OutboxStatusPending && m.Attempts == 3 &&
+			m.NextAttemptAt.Equal(now.Add(40*time.Second)) &&
+			m.LastError == "webhooks: receptor indisponível"
+	})).Return(nil).Once()
+
+	// Act
+	delivered, err := dispatcher.DispatchDue(context.Background(), now)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Zero(t, delivered)
+	assert.Equal(t, map[string]int{"webhooks": 1}, calls)
+	outboxRepo.AssertExpectations(t)
+}
+
+// TestOutboxDispatcher_DispatchDue_DeadLetter testa que a mensagem vai para a fila de mortos
+// ao esgotar as tentativas.
+func TestOutboxDispatcher_DispatchDue_DeadLetter(t *testing.T) {
+	// Arrange
+	outboxRepo := new(MockOutboxRepository)
+	dispatcher := service.NewOutboxDispatcher(outboxRepo, service.OutboxOptions{MaxAttempts: 3})
+	dispatcher.Register("webhooks", func(ctx context.Context, event domain.Event) error {
+		return errors.New("receptor indisponível")
+	})
+
+	now := time.Now().UTC()
+	outboxRepo.On("FindDue", now, 100).Return([]domain.OutboxMessage{outboxMessage(t, 2)}, nil)
+	outboxRepo.On("Lease", mock.Anything, mock.Anything).Return(true, nil)
+	outboxRepo.On("SaveAttempt", mock.MatchedBy(func(m *domain.OutboxMessage) bool {
+		return m.Status == domain.OutboxStatusDead && m.Attempts == 3 && m.ProcessedAt != nil
+	})).Return(nil).Once()
+
+	// Act
+	_, err := dispatcher.DispatchDue(context.Background(), now)
+
+	// Assert
+	assert.Error(t, err)
+	outboxRepo.AssertExpectations(t)
+}
+
+// TestOutboxDispatcher_DispatchDue_LeasedElsewhere testa que mensagens reservadas por outra
+// instância são ignoradas.
+func TestOutboxDispatcher_DispatchDue_LeasedElsewhere(t *testing.T) {
+	// Arrange
+	outboxRepo := new(MockOutboxRepository)
+	dispatcher := service.NewOutboxDispatcher(outboxRepo, service.OutboxOptions{})
+	dispatcher.Register("sse", func(ctx context.Context, event domain.Event) error {
+		t.Fatal("mensagem reservada não deveria ser entregue")
+		return nil
+	})
+
+	now := time.Now().UTC()
+	outboxRepo.On("FindDue", now, 100).Return([]domain.OutboxMessage{outboxMessage(t, 0)}, nil)
+	outboxRepo.On("Lease", mock.Anything, mock.Anything).Return(false, nil)
+
+	// Act
+	delivered, err := dispatcher.DispatchDue(context.Background(), now)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Zero(t, delivered)
+	outboxRepo.AssertNotCalled(t, "SaveAttempt", mock.Anything)
+}
+
+// TestOutboxDispatcher_Backoff testa o crescimento exponencial limitado ao máximo.
+func TestOutboxDispatcher_Backoff(t *testing.T) {
+	dispatcher := service.NewOutboxDispatcher(new(MockOutboxRepository), service.OutboxOptions{
+		BaseBackoff: 5 * time.Second,
+		MaxBackoff:  time.Minute,
+	})
+
+	assert.Equal(t, 5*time.Second, dispatcher.Backoff(1))
+	assert.Equal(t, 10*time.Second, dispatcher.Backoff(2))
+	assert.Equal(t, 40*time.Second, dispatcher.Backoff(4))
+	assert.Equal(t, time.Minute, dispatcher.Backoff(5))
+	assert.Equal(t, time.Minute, dispatcher.Backoff(30))
+}
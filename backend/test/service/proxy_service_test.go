@@ -0,0 +1,382 @@
+package service_test
+
+import (
+	"testing"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+	"amigos-terceira-idade/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockProxyRepository implementa repository.ProxyRepositoryInterface para testes.
+type MockProxyRepository struct {
+	mock.Mock
+}
+
+// Garante que implementa a interface
+var _ repository.ProxyRepositoryInterface = (*MockProxyRepository)(nil)
+
+func (m *MockProxyRepository) Create(grant *domain.ProxyGrant, entry *domain.ProxyActionLog) error {
+	args := m.Called(grant, entry)
+	return args.Error(0)
+}
+
+func (m *MockProxyRepository) Update(grant *domain.ProxyGrant, entry *domain.ProxyActionLog) error {
+	args := m.Called(grant, entry)
+	return args.Error(0)
+}
+
+func (m *MockProxyRepository) FindByID(id uuid.UUID) (*domain.ProxyGrant, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ProxyGrant), args.Error(1)
+}
+
+func (m *MockProxyRepository) FindActive(elderlyID, proxyID uuid.UUID) (*domain.ProxyGrant, error) {
+	args := m.Called(elderlyID, proxyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ProxyGrant), args.Error(1)
+}
+
+func (m *MockProxyRepository) FindByElderly(elderlyID uuid.UUID) ([]domain.ProxyGrant, error) {
+	args := m.Called(elderlyID)
+	return args.Get(0).([]domain.ProxyGrant), args.Error(1)
+}
+
+func (m *MockProxyRepository) FindActiveByProxy(proxyID uuid.UUID) ([]domain.ProxyGrant, error) {
+	args := m.Called(proxyID)
+	return args.Get(0).([]domain.ProxyGrant), args.Error(1)
+}
+
+func (m *MockProxyRepository) CreateAction(entry *domain.ProxyActionLog) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockProxyRepository) DeleteAction(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockProxyRepository) FindActions(elderlyID uuid.UUID, limit, offset int) ([]domain.ProxyActionLog, int64, error) {
+	args := m.Called(elderlyID, limit, offset)
+	return args.Get(0).([]domain.ProxyActionLog), args.Get(1).(int64), args.Error(2)
+}
+
+// proxyFixture reúne o serviço de delegações e os mocks usados nos testes.
+type proxyFixture struct {
+	service         *service.ProxyService
+	proxyRepo       *MockProxyRepository
+	userRepo        *MockUserRepository
+	appointmentRepo *MockAppointmentRepository
+	connectionRepo  *MockConnectionRepository
+	notifications   *MockNotificationSender
+}
+
+// newProxyService monta o serviço de delegações sobre os serviços reais de usuários,
+// agendamentos e pareamento, todos com repositórios simulados.
+func newProxyService() *proxyFixture {
+	f := &proxyFixture{
+		proxyRepo:       new(MockProxyRepository),
+		userRepo:        new(MockUserRepository),
+		appointmentRepo: new(MockAppointmentRepository),
+		connectionRepo:  new(MockConnectionRepository),
+		notifications:   new(MockNotificationSender),
+	}
+	users := service.NewUserService(f.userRepo, new(MockInterestRepository))
+	appointments := newAppointmentService(f.appointmentRepo, f.userRepo)
+	matching := service.NewMatchingService(f.userRepo, f.connectionRepo, noResidents(), new(MockVerificationRepository),
+		ignoreNotifications(), service.MatchingOptions{})
+	f.service = service.NewProxyService(f.proxyRepo, f.userRepo, f.connectionRepo, users, appointments, matching, f.notifications)
+	return f
+}
+
+// proxyAction casa com o registro de log de uma ação feita pelo ator esperado.
+func proxyAction(action domain.ProxyAction, actorID uuid.UUID) interface{} {
+	return mock.MatchedBy(func(entry *domain.ProxyActionLog) bool {
+		return entry.Action == action && entry.ActorID == actorID
+	})
+}
+
+// TestProxyService_Grant_Success testa a concessão de acesso a um familiar.
+func TestProxyService_Grant_Success(t *testing.T) {
+	// Arrange
+	f := newProxyService()
+	elderlyID := uuid.New()
+	proxyID := uuid.New()
+
+	f.userRepo.On("FindByID", elderlyID).Return(&domain.User{ID: elderlyID, Name: "Dona Maria", UserType: domain.UserTypeElderly}, nil)
+	f.userRepo.On("FindByEmail", "filha@email.com").Return(&domain.User{ID: proxyID, IsActive: true}, nil)
+	f.proxyRepo.On("FindActive", elderlyID, proxyID).Return(nil, domain.ErrProxyGrantNotFound)
+	f.proxyRepo.On("Create", mock.MatchedBy(func(grant *domain.ProxyGrant) bool {
+		// view é incluída e as permissões ficam na ordem canônica
+		return grant.ProxyID == proxyID && assert.ObjectsAreEqual([]domain.ProxyPermission{
+			domain.ProxyPermissionView,
+			domain.ProxyPermissionRespondInvitations,
+		}, grant.Permissions)
+	}), proxyAction(domain.ProxyActionGrantCreated, elderlyID)).Return(nil)
+	f.proxyRepo.On("FindByID", mock.AnythingOfType("uuid.UUID")).Return(&domain.ProxyGrant{ElderlyID: elderlyID, ProxyID: proxyID}, nil)
+	f.notifications.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.UserID == proxyID && n.Type == domain.NotificationProxyGranted
+	})).Return(nil)
+
+	// Act
+	grant, err := f.service.Grant(elderlyID, service.GrantProxyRequest{
+		Email:       "filha@email.com",
+		Permissions: []domain.ProxyPermission{domain.ProxyPermissionRespondInvitations, domain.ProxyPermissionRespondInvitations},
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, proxyID, grant.ProxyID)
+	f.proxyRepo.AssertExpectations(t)
+	f.notifications.AssertExpectations(t)
+}
+
+// TestProxyService_Grant_NotElderly testa que apenas idosos concedem acesso.
+func TestProxyService_Grant_NotElderly(t *testing.T) {
+	// Arrange
+	f := newProxyService()
+	volunteerID := uuid.New()
+	f.userRepo.On("FindByID", volunteerID).Return(&domain.User{ID: volunteerID, UserType: domain.UserTypeVolunteer}, nil)
+
+	// Act
+	grant, err := f.service.Grant(volunteerID, service.GrantProxyRequest{Email: "alguem@email.com"})
+
+	// Assert
+	assert.Nil(t, grant)
+	assert.ErrorIs(t, err, service.ErrElderlyOnly)
+	f.proxyRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// TestProxyService_Grant_AlreadyGranted testa delegação repetida para o mesmo familiar.
+func TestProxyService_Grant_AlreadyGranted(t *testing.T) {
+	// Arrange
+	f := newProxyService()
+	elderlyID := uuid.New()
+	proxyID := uuid.New()
+
+	f.userRepo.On("FindByID", elderlyID).Return(&domain.User{ID: elderlyID, UserType: domain.UserTypeElderly}, nil)
+	f.userRepo.On("FindByEmail", "filha@email.com").Return(&domain.User{ID: proxyID, IsActive: true}, nil)
+	f.proxyRepo.On("FindActive", elderlyID, proxyID).Return(&domain.ProxyGrant{ElderlyID: elderlyID, ProxyID: proxyID}, nil)
+
+	// Act
+	grant, err := f.service.Grant(elderlyID, service.GrantProxyRequest{Email: "filha@email.com"})
+
+	// Assert
+	assert.Nil(t, grant)
+	assert.ErrorIs(t, err, service.ErrProxyAlreadyGranted)
+}
+
+// TestProxyService_AcceptInvitation_RecordsActor testa que o aceite pelo familiar fica no log.
+func TestProxyService_AcceptInvitation_RecordsActor(t *testing.T) {
+	// Arrange
+	f := newProxyService()
+	elderlyID := uuid.New()
+	proxyID := uuid.New()
+	appointmentID := uuid.New()
+
+	f.proxyRepo.On("FindActive", elderlyID, proxyID).Return(&domain.ProxyGrant{
+		ID:          uuid.New(),
+		ElderlyID:   elderlyID,
+		ProxyID:     proxyID,
+		Permissions: []domain.ProxyPermission{domain.ProxyPermissionView, domain.ProxyPermissionRespondInvitations},
+	}, nil)
+	f.appointmentRepo.On("FindByID", appointmentID).Return(&domain.Appointment{
+		ID:          appointmentID,
+		VolunteerID: uuid.New(),
+		TargetID:    elderlyID,
+		Status:      domain.AppointmentStatusPending,
+	}, nil)
+	f.appointmentRepo.On("ChangeStatus", statusChange(appointmentID, domain.AppointmentStatusConfirmed)).Return(nil)
+	f.proxyRepo.On("CreateAction", mock.MatchedBy(func(entry *domain.ProxyActionLog) bool {
+		return entry.Action == domain.ProxyActionInvitationAccepted && entry.ActorID == proxyID &&
+			entry.ElderlyID == elderlyID && *entry.ResourceID == appointmentID
+	})).Return(nil)
+
+	// Act
+	err := f.service.AcceptInvitation(proxyID, elderlyID, appointmentID, service.SeriesScopeThis)
+
+	// Assert
+	assert.NoError(t, err)
+	f.appointmentRepo.AssertExpectations(t)
+	f.proxyRepo.AssertExpectations(t)
+}
+
+// TestProxyService_AcceptInvitation_WithoutPermission testa delegação apenas de visualização.
+func TestProxyService_AcceptInvitation_WithoutPermission(t *testing.T) {
+	// Arrange
+	f := newProxyService()
+	elderlyID := uuid.New()
+	proxyID := uuid.New()
+
+	f.proxyRepo.On("FindActive", elderlyID, proxyID).Return(&domain.ProxyGrant{
+		ElderlyID:   elderlyID,
+		ProxyID:     proxyID,
+		Permissions: []domain.ProxyPermission{domain.ProxyPermissionView},
+	}, nil)
+
+	// Act
+	err := f.service.AcceptInvitation(proxyID, elderlyID, uuid.New(), service.SeriesScopeThis)
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrProxyPermissionDenied)
+	f.appointmentRepo.AssertNotCalled(t, "ChangeStatus", mock.Anything)
+	f.proxyRepo.AssertNotCalled(t, "CreateAction", mock.Anything)
+}
+
+// TestProxyService_AcceptInvitation_OwnInvitation testa familiar voluntário aceitando o próprio convite.
+func TestProxyService_AcceptInvitation_OwnInvitation(t *testing.T) {
+	// Arrange
+	f := newProxyService()
+	elderlyID := uuid.New()
+	proxyID := uuid.New()
+	appointmentID := uuid.New()
+
+	f.proxyRepo.On("FindActive", elderlyID, proxyID).Return(&domain.ProxyGrant{
+		ElderlyID:   elderlyID,
+		ProxyID:     proxyID,
+		Permissions: []domain.ProxyPermission{domain.ProxyPermissionView, domain.ProxyPermissionRespondInvitations},
+	}, nil)
+	f.appointmentRepo.On("FindByID", appointmentID).Return(&domain.Appointment{
+		ID:          appointmentID,
+		VolunteerID: proxyID,
+		TargetID:    elderlyID,
+		Status:      domain.AppointmentStatusPending,
+	}, nil)
+
+	// Act
+	err := f.service.AcceptInvitation(proxyID, elderlyID, appointmentID, service.SeriesScopeThis)
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrProxyOwnInvitation)
+	f.appointmentRepo.AssertNotCalled(t, "ChangeStatus", mock.Anything)
+}
+
+// acceptInvitationFixture prepara um convite pendente para o idoso e um procurador que pode respondê-lo.
+func acceptInvitationFixture(f *proxyFixture) (elderlyID, proxyID, appointmentID uuid.UUID) {
+	elderlyID, proxyID, appointmentID = uuid.New(), uuid.New(), uuid.New()
+	f.proxyRepo.On("FindActive", elderlyID, proxyID).Return(&domain.ProxyGrant{
+		ID:          uuid.New(),
+		ElderlyID:   elderlyID,
+		ProxyID:     proxyID,
+		Permissions: []domain.ProxyPermission{domain.ProxyPermissionView, domain.ProxyPermissionRespondInvitations},
+	}, nil)
+	f.appointmentRepo.On("FindByID", appointmentID).Return(&domain.Appointment{
+		ID:          appointmentID,
+		VolunteerID: uuid.New(),
+		TargetID:    elderlyID,
+		Status:      domain.AppointmentStatusPending,
+	}, nil)
+	return elderlyID, proxyID, appointmentID
+}
+
+// TestProxyService_AcceptInvitation_LogFailure testa que a ação não é feita se o log não puder ser gravado.
+func TestProxyService_AcceptInvitation_LogFailure(t *testing.T) {
+	// Arrange
+	f := newProxyService()
+	elderlyID, proxyID, appointmentID := acceptInvitationFixture(f)
+	f.proxyRepo.On("CreateAction", proxyAction(domain.ProxyActionInvitationAccepted, proxyID)).Return(assert.AnError)
+
+	// Act
+	err := f.service.AcceptInvitation(proxyID, elderlyID, appointmentID, service.SeriesScopeThis)
+
+	// Assert
+	assert.ErrorIs(t, err, assert.AnError)
+	f.appointmentRepo.AssertNotCalled(t, "ChangeStatus", mock.Anything)
+}
+
+// TestProxyService_AcceptInvitation_ActionFailure testa que o registro é removido quando a ação falha.
+func TestProxyService_AcceptInvitation_ActionFailure(t *testing.T) {
+	// Arrange
+	f := newProxyService()
+	elderlyID, proxyID, appointmentID := acceptInvitationFixture(f)
+	var entry *domain.ProxyActionLog
+	f.proxyRepo.On("CreateAction", proxyAction(domain.ProxyActionInvitationAccepted, proxyID)).
+		Run(func(args mock.Arguments) { entry = args.Get(0).(*domain.ProxyActionLog) }).
+		Return(nil)
+	f.appointmentRepo.On("ChangeStatus", statusChange(appointmentID, domain.AppointmentStatusConfirmed)).Return(assert.AnError)
+	f.proxyRepo.On("DeleteAction", mock.AnythingOfType("uuid.UUID")).Return(nil)
+
+	// Act
+	err := f.service.AcceptInvitation(proxyID, elderlyID, appointmentID, service.SeriesScopeThis)
+
+	// Assert
+	assert.ErrorIs(t, err, assert.AnError)
+	f.proxyRepo.AssertCalled(t, "DeleteAction", entry.ID)
+}
+
+// TestProxyService_UpdateProfile_RequiresPermission testa edição do perfil sem profile:edit.
+func TestProxyService_UpdateProfile_RequiresPermission(t *testing.T) {
+	// Arrange
+	f := newProxyService()
+	elderlyID := uuid.New()
+	proxyID := uuid.New()
+
+	f.proxyRepo.On("FindActive", elderlyID, proxyID).Return(&domain.ProxyGrant{
+		ElderlyID:   elderlyID,
+		ProxyID:     proxyID,
+		Permissions: []domain.ProxyPermission{domain.ProxyPermissionView, domain.ProxyPermissionRespondInvitations},
+	}, nil)
+
+	// Act
+	user, err := f.service.UpdateProfile(proxyID, elderlyID, service.UpdateProfileRequest{Bio: "Nova bio"})
+
+	// Assert
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, service.ErrProxyPermissionDenied)
+	f.userRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+// TestProxyService_Revoke_ByElderly testa a revogação pelo idoso, que avisa o familiar.
+func TestProxyService_Revoke_ByElderly(t *testing.T) {
+	// Arrange
+	f := newProxyService()
+	elderlyID := uuid.New()
+	proxyID := uuid.New()
+	grantID := uuid.New()
+
+	f.proxyRepo.On("FindByID", grantID).Return(&domain.ProxyGrant{
+		ID:        grantID,
+		ElderlyID: elderlyID,
+		ProxyID:   proxyID,
+		Elderly:   domain.User{ID: elderlyID, Name: "Dona Maria"},
+	}, nil)
+	f.proxyRepo.On("Update", mock.MatchedBy(func(grant *domain.ProxyGrant) bool {
+		return !grant.IsActive() && *grant.RevokedBy == elderlyID
+	}), proxyAction(domain.ProxyActionGrantRevoked, elderlyID)).Return(nil)
+	f.notifications.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.UserID == proxyID && n.Type == domain.NotificationProxyRevoked
+	})).Return(nil)
+
+	// Act
+	err := f.service.Revoke(elderlyID, grantID)
+
+	// Assert
+	assert.NoError(t, err)
+	f.proxyRepo.AssertExpectations(t)
+	f.notifications.AssertExpectations(t)
+}
+
+// TestProxyService_Revoke_Stranger testa que terceiros não revogam delegações.
+func TestProxyService_Revoke_Stranger(t *testing.T) {
+	// Arrange
+	f := newProxyService()
+	grantID := uuid.New()
+	f.proxyRepo.On("FindByID", grantID).Return(&domain.ProxyGrant{ID: grantID, ElderlyID: uuid.New(), ProxyID: uuid.New()}, nil)
+
+	// Act
+	err := f.service.Revoke(uuid.New(), grantID)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrProxyGrantNotFound)
+	f.proxyRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,151 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+	"amigos-terceira-idade/internal/service"
+	"amigos-terceira-idade/pkg/notify"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReminderRepository implementa repository.ReminderRepositoryInterface para testes.
+type MockReminderRepository struct {
+	mock.Mock
+}
+
+var _ repository.ReminderRepositoryInterface = (*MockReminderRepository)(nil)
+
+func (m *MockReminderRepository) Claim(reminder *domain.AppointmentReminder) (bool, error) {
+	args := m.Called(reminder)
+	if reminder.ID == uuid.Nil {
+		reminder.ID = uuid.New()
+	}
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReminderRepository) MarkSent(id uuid.UUID, sentAt time.Time) error {
+	args := m.Called(id, sentAt)
+	return args.Error(0)
+}
+
+func (m *MockReminderRepository) Release(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// fakeNotifier guarda as mensagens enviadas; falha se err estiver preenchido.
+type fakeNotifier struct {
+	sent []notify.Message
+	err  error
+}
+
+func (n *fakeNotifier) Channel() string { return "fake" }
+
+func (n *fakeNotifier) Send(ctx context.Context, msg notify.Message) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.sent = append(n.sent, msg)
+	return nil
+}
+
+// reminderAppointment cria um agendamento confirmado entre participantes ativos.
+func reminderAppointment(date time.Time) domain.Appointment {
+	appointment := calendarAppointment(domain.AppointmentStatusConfirmed)
+	appointment.Date = date
+	appointment.Volunteer.IsActive = true
+	appointment.Target.IsActive = true
+	return appointment
+}
+
+// TestReminderService_SendDue_Success testa o envio do lembrete aos dois participantes.
+func TestReminderService_SendDue_Success(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	reminderRepo := new(MockReminderRepository)
+	notifier := &fakeNotifier{}
+	reminderService := service.NewReminderService(appointmentRepo, reminderRepo, notifier, ignoreNotifications(),
+		[]time.Duration{24 * time.Hour, 15 * time.Minute})
+
+	now := time.Date(2030, time.March, 5, 16, 50, 0, 0, time.UTC)
+	appointment := reminderAppointment(now.Add(10 * time.Minute)) // 14:00 em São Paulo
+
+	appointmentRepo.On("FindConfirmedBetween", now, now.Add(24*time.Hour)).Return([]domain.Appointment{appointment}, nil)
+	reminderRepo.On("Claim", mock.MatchedBy(func(reminder *domain.AppointmentReminder) bool {
+		return reminder.AppointmentID == appointment.ID && reminder.OffsetMinutes == 15 &&
+			reminder.AppointmentDate.Equal(appointment.Date) && reminder.Channel == "fake"
+	})).Return(true, nil).Twice()
+	reminderRepo.On("MarkSent", mock.AnythingOfType("uuid.UUID"), now).Return(nil).Twice()
+
+	// Act
+	sent, err := reminderService.SendDue(context.Background(), now)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	assert.Len(t, notifier.sent, 2)
+	assert.Equal(t, "ana@example.com", notifier.sent[0].To)
+	assert.Equal(t, "Lembrete: conversa com Seu José em 10 minutos", notifier.sent[0].Subject)
+	assert.Contains(t, notifier.sent[0].Body, "05/03/2030 às 14:00")
+	assert.Contains(t, notifier.sent[0].Body, "https://meet.google.com/abc-defg-hij")
+	reminderRepo.AssertExpectations(t)
+}
+
+// TestReminderService_SendDue_AlreadySent testa que um lembrete já reservado não é reenviado.
+func TestReminderService_SendDue_AlreadySent(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	reminderRepo := new(MockReminderRepository)
+	notifier := &fakeNotifier{}
+	reminderService := service.NewReminderService(appointmentRepo, reminderRepo, notifier, ignoreNotifications(), []time.Duration{24 * time.Hour})
+
+	now := time.Now().UTC()
+	appointment := reminderAppointment(now.Add(20 * time.Hour))
+
+	appointmentRepo.On("FindConfirmedBetween", now, now.Add(24*time.Hour)).Return([]domain.Appointment{appointment}, nil)
+	reminderRepo.On("Claim", mock.Anything).Return(false, nil)
+
+	// Act
+	sent, err := reminderService.SendDue(context.Background(), now)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Zero(t, sent)
+	assert.Empty(t, notifier.sent)
+	reminderRepo.AssertNotCalled(t, "MarkSent", mock.Anything, mock.Anything)
+}
+
+// TestReminderService_SendDue_FailureReleasesClaim testa que uma falha de envio
+// libera a reserva para nova tentativa.
+func TestReminderService_SendDue_FailureReleasesClaim(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	reminderRepo := new(MockReminderRepository)
+	notifier := &fakeNotifier{err: errors.New("servidor indisponível")}
+	reminderService := service.NewReminderService(appointmentRepo, reminderRepo, notifier, ignoreNotifications(), []time.Duration{15 * time.Minute})
+
+	now := time.Now().UTC()
+	appointment := reminderAppointment(now.Add(5 * time.Minute))
+	appointment.Target.IsActive = false // Conta desativada não recebe lembretes
+
+	appointmentRepo.On("FindConfirmedBetween", now, now.Add(15*time.Minute)).Return([]domain.Appointment{appointment}, nil)
+	reminderRepo.On("Claim", mock.Anything).Return(true, nil).Once()
+	reminderRepo.On("Release", mock.AnythingOfType("uuid.UUID")).Return(nil).Once()
+
+	// Act
+	sent, err := reminderService.SendDue(context.Background(), now)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Zero(t, sent)
+	reminderRepo.AssertExpectations(t)
+	reminderRepo.AssertNotCalled(t, "MarkSent", mock.Anything, mock.Anything)
+}
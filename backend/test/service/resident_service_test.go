@@ -0,0 +1,354 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+	"amigos-terceira-idade/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockResidentRepository implementa repository.ResidentRepositoryInterface para testes.
+type MockResidentRepository struct {
+	mock.Mock
+}
+
+// Garante que implementa a interface
+var _ repository.ResidentRepositoryInterface = (*MockResidentRepository)(nil)
+
+func (m *MockResidentRepository) Create(resident *domain.Resident) error {
+	args := m.Called(resident)
+	return args.Error(0)
+}
+
+func (m *MockResidentRepository) FindByID(id uuid.UUID) (*domain.Resident, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Resident), args.Error(1)
+}
+
+func (m *MockResidentRepository) FindByInstitution(institutionID uuid.UUID, includeInactive bool) ([]domain.Resident, error) {
+	args := m.Called(institutionID, includeInactive)
+	return args.Get(0).([]domain.Resident), args.Error(1)
+}
+
+func (m *MockResidentRepository) FindActive() ([]domain.Resident, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Resident), args.Error(1)
+}
+
+func (m *MockResidentRepository) Update(resident *domain.Resident) error {
+	args := m.Called(resident)
+	return args.Error(0)
+}
+
+// noResidents simula uma plataforma sem residentes, para testes de pareamento que não os usam.
+func noResidents() *MockResidentRepository {
+	residentRepo := new(MockResidentRepository)
+	residentRepo.On("FindActive").Return([]domain.Resident{}, nil).Maybe()
+	return residentRepo
+}
+
+// TestResidentService_Create_Success testa o cadastro de um residente pela instituição.
+func TestResidentService_Create_Success(t *testing.T) {
+	// Arrange
+	residentRepo := new(MockResidentRepository)
+	userRepo := new(MockUserRepository)
+	interestRepo := new(MockInterestRepository)
+	residentService := service.NewResidentService(residentRepo, userRepo, interestRepo)
+
+	institutionID := uuid.New()
+	interestIDs := []uuid.UUID{uuid.New()}
+	interests := []domain.Interest{{ID: interestIDs[0], Name: "Música"}}
+
+	userRepo.On("FindByID", institutionID).Return(&domain.User{ID: institutionID, UserType: domain.UserTypeInstitution}, nil)
+	interestRepo.On("FindByIDs", interestIDs).Return(interests, nil)
+	residentRepo.On("Create", mock.MatchedBy(func(r *domain.Resident) bool {
+		return r.InstitutionID == institutionID && r.Name == "Dona Cida" && r.IsActive && len(r.Interests) == 1
+	})).Return(nil)
+	residentRepo.On("FindByID", mock.AnythingOfType("uuid.UUID")).Return(&domain.Resident{
+		InstitutionID: institutionID,
+		Name:          "Dona Cida",
+		IsActive:      true,
+		Interests:     interests,
+	}, nil)
+
+	// Act
+	result, err := residentService.Create(institutionID, service.ResidentRequest{
+		Name:            "  Dona Cida ",
+		Age:             84,
+		AssistanceNeeds: "Baixa audição",
+		InterestIDs:     interestIDs,
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Dona Cida", result.Name)
+	residentRepo.AssertExpectations(t)
+}
+
+// TestResidentService_Create_NotInstitution testa que apenas instituições cadastram residentes.
+func TestResidentService_Create_NotInstitution(t *testing.T) {
+	// Arrange
+	residentRepo := new(MockResidentRepository)
+	userRepo := new(MockUserRepository)
+	residentService := service.NewResidentService(residentRepo, userRepo, new(MockInterestRepository))
+
+	volunteerID := uuid.New()
+	userRepo.On("FindByID", volunteerID).Return(&domain.User{ID: volunteerID, UserType: domain.UserTypeVolunteer}, nil)
+
+	// Act
+	result, err := residentService.Create(volunteerID, service.ResidentRequest{Name: "Seu Zé"})
+
+	// Assert
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, service.ErrInstitutionOnlyResidents)
+	residentRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestResidentService_Update_OtherInstitution testa que uma instituição não altera residentes de outra.
+func TestResidentService_Update_OtherInstitution(t *testing.T) {
+	// Arrange
+	residentRepo := new(MockResidentRepository)
+	residentService := service.NewResidentService(residentRepo, new(MockUserRepository), new(MockInterestRepository))
+
+	residentID := uuid.New()
+	residentRepo.On("FindByID", residentID).Return(&domain.Resident{ID: residentID, InstitutionID: uuid.New(), IsActive: true}, nil)
+
+	// Act
+	result, err := residentService.Update(uuid.New(), residentID, service.ResidentRequest{Name: "Seu Zé"})
+
+	// Assert
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, service.ErrNotResidentInstitution)
+	residentRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+// TestResidentService_GetByID_InactiveHidden testa que residentes desativados só aparecem para a instituição.
+func TestResidentService_GetByID_InactiveHidden(t *testing.T) {
+	// Arrange
+	residentRepo := new(MockResidentRepository)
+	residentService := service.NewResidentService(residentRepo, new(MockUserRepository), new(MockInterestRepository))
+
+	institutionID := uuid.New()
+	residentID := uuid.New()
+	residentRepo.On("FindByID", residentID).Return(&domain.Resident{ID: residentID, InstitutionID: institutionID, IsActive: false}, nil)
+
+	// Act
+	forVolunteer, errVolunteer := residentService.GetByID(residentID, uuid.New())
+	forInstitution, errInstitution := residentService.GetByID(residentID, institutionID)
+
+	// Assert
+	assert.Nil(t, forVolunteer)
+	assert.ErrorIs(t, errVolunteer, domain.ErrResidentNotFound)
+	assert.NoError(t, errInstitution)
+	assert.Equal(t, residentID, forInstitution.ID)
+}
+
+// TestMatchingService_GetSuggestions_Residents testa sugestões de residentes, com a instituição como usuário.
+func TestMatchingService_GetSuggestions_Residents(t *testing.T) {
+	// Arrange
+	userRepo := new(MockUserRepository)
+	connectionRepo := new(MockConnectionRepository)
+	residentRepo := new(MockResidentRepository)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, residentRepo, new(MockVerificationRepository), ignoreNotifications(), service.MatchingOptions{})
+
+	volunteerID := uuid.New()
+	music := domain.Interest{ID: uuid.New(), Name: "Música"}
+	institution := domain.User{ID: uuid.New(), Name: "Lar São Vicente", UserType: domain.UserTypeInstitution}
+	connected := domain.Resident{ID: uuid.New(), InstitutionID: institution.ID, Institution: institution}
+	resident := domain.Resident{
+		ID:            uuid.New(),
+		InstitutionID: institution.ID,
+		Name:          "Dona Cida",
+		Interests:     []domain.Interest{music},
+		Institution:   institution,
+	}
+
+	userRepo.On("FindByID", volunteerID).Return(&domain.User{
+		ID:        volunteerID,
+		UserType:  domain.UserTypeVolunteer,
+		Interests: []domain.Interest{music},
+	}, nil)
+	residentRepo.On("FindActive").Return([]domain.Resident{connected, resident}, nil)
+	connectionRepo.On("ExistsForResident", volunteerID, connected.ID).Return(true, nil)
+	connectionRepo.On("ExistsForResident", volunteerID, resident.ID).Return(false, nil)
+
+	// Act
+	suggestions, err := matchingService.GetSuggestions(volunteerID, "resident")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, suggestions, 1)
+	assert.Equal(t, resident.ID, suggestions[0].Resident.ID)
+	assert.Equal(t, institution.ID, suggestions[0].User.ID)
+	assert.Equal(t, 100.0, suggestions[0].MatchScore)
+	userRepo.AssertNotCalled(t, "FindByType", mock.Anything)
+}
+
+// TestMatchingService_ConnectResident testa que o pedido vai para a instituição do residente.
+func TestMatchingService_ConnectResident(t *testing.T) {
+	// Arrange
+	userRepo := new(MockUserRepository)
+	connectionRepo := new(MockConnectionRepository)
+	residentRepo := new(MockResidentRepository)
+	notifications := new(MockNotificationSender)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, residentRepo, new(MockVerificationRepository), notifications, service.MatchingOptions{})
+
+	volunteerID := uuid.New()
+	institutionID := uuid.New()
+	residentID := uuid.New()
+
+	connectionRepo.On("ExistsForResident", volunteerID, residentID).Return(false, nil)
+	userRepo.On("FindByID", volunteerID).Return(&domain.User{ID: volunteerID, Name: "Ana", UserType: domain.UserTypeVolunteer}, nil)
+	residentRepo.On("FindByID", residentID).Return(&domain.Resident{
+		ID:            residentID,
+		InstitutionID: institutionID,
+		Name:          "Dona Cida",
+		IsActive:      true,
+	}, nil)
+	connectionRepo.On("Create", mock.AnythingOfType("*domain.Connection")).Return(nil)
+	notifications.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.UserID == institutionID && n.Body == "Ana quer se conectar com o residente Dona Cida."
+	})).Return(nil)
+
+	// Act
+	connection, err := matchingService.ConnectResident(volunteerID, residentID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, institutionID, connection.TargetID)
+	assert.Equal(t, domain.UserTypeInstitution, connection.TargetType)
+	assert.Equal(t, residentID, *connection.ResidentID)
+	notifications.AssertExpectations(t)
+}
+
+// TestMatchingService_ConnectResident_Inactive testa pedido para residente desativado.
+func TestMatchingService_ConnectResident_Inactive(t *testing.T) {
+	// Arrange
+	userRepo := new(MockUserRepository)
+	connectionRepo := new(MockConnectionRepository)
+	residentRepo := new(MockResidentRepository)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, residentRepo, new(MockVerificationRepository), ignoreNotifications(), service.MatchingOptions{})
+
+	volunteerID := uuid.New()
+	residentID := uuid.New()
+
+	connectionRepo.On("ExistsForResident", volunteerID, residentID).Return(false, nil)
+	userRepo.On("FindByID", volunteerID).Return(&domain.User{ID: volunteerID, UserType: domain.UserTypeVolunteer}, nil)
+	residentRepo.On("FindByID", residentID).Return(&domain.Resident{ID: residentID, InstitutionID: uuid.New(), IsActive: false}, nil)
+
+	// Act
+	connection, err := matchingService.ConnectResident(volunteerID, residentID)
+
+	// Assert
+	assert.Nil(t, connection)
+	assert.ErrorIs(t, err, domain.ErrResidentNotFound)
+	connectionRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// newResidentVisit prepara um voluntário, uma instituição sem janelas de visita declaradas,
+// um residente ativo dela e o serviço de agendamentos.
+func newResidentVisit(t *testing.T) (*service.AppointmentService, *MockAppointmentRepository, uuid.UUID, *domain.Resident) {
+	t.Helper()
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	residentRepo := new(MockResidentRepository)
+	availabilityRepo := new(MockAvailabilityRepository)
+
+	volunteerID := uuid.New()
+	resident := &domain.Resident{ID: uuid.New(), InstitutionID: uuid.New(), Name: "Dona Cida", IsActive: true}
+	userRepo.On("FindByID", volunteerID).Return(&domain.User{ID: volunteerID, UserType: domain.UserTypeVolunteer}, nil)
+	userRepo.On("FindByID", resident.InstitutionID).Return(&domain.User{ID: resident.InstitutionID, UserType: domain.UserTypeInstitution}, nil)
+	residentRepo.On("FindByID", resident.ID).Return(resident, nil)
+	availabilityRepo.On("FindWeekly", volunteerID).Return([]domain.VolunteerAvailability{}, nil).Maybe()
+	availabilityRepo.On("FindVisitWindows", resident.InstitutionID).Return([]domain.VisitWindow{}, nil).Maybe()
+
+	return service.NewAppointmentService(appointmentRepo, userRepo, residentRepo, availabilityRepo, ignoreNotifications()), appointmentRepo, volunteerID, resident
+}
+
+// TestAppointmentService_Create_ForResident testa que o convite para um residente vai para a instituição.
+func TestAppointmentService_Create_ForResident(t *testing.T) {
+	// Arrange
+	appointmentService, appointmentRepo, volunteerID, resident := newResidentVisit(t)
+	date := time.Now().UTC().Add(48 * time.Hour)
+
+	appointmentRepo.On("FindOverlapping", []uuid.UUID{volunteerID, resident.InstitutionID}, date, date.Add(30*time.Minute)).
+		Return([]domain.Appointment{}, nil)
+	appointmentRepo.On("Create", mock.MatchedBy(func(a *domain.Appointment) bool {
+		return a.TargetID == resident.InstitutionID && a.ResidentID != nil && *a.ResidentID == resident.ID
+	})).Return(nil)
+	appointmentRepo.On("FindByID", mock.AnythingOfType("uuid.UUID")).Return(&domain.Appointment{
+		VolunteerID: volunteerID,
+		TargetID:    resident.InstitutionID,
+		ResidentID:  &resident.ID,
+		Date:        date,
+	}, nil)
+
+	// Act
+	result, err := appointmentService.Create(volunteerID, service.CreateAppointmentRequest{
+		ResidentID: &resident.ID,
+		Date:       date,
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, resident.InstitutionID, result.TargetID)
+	appointmentRepo.AssertExpectations(t)
+}
+
+// TestAppointmentService_Create_ResidentConflict testa que o residente não recebe duas visitas ao mesmo tempo.
+func TestAppointmentService_Create_ResidentConflict(t *testing.T) {
+	// Arrange
+	appointmentService, appointmentRepo, volunteerID, resident := newResidentVisit(t)
+	date := time.Now().UTC().Add(48 * time.Hour)
+	existing := domain.Appointment{
+		ID:              uuid.New(),
+		VolunteerID:     uuid.New(), // Outro voluntário
+		TargetID:        resident.InstitutionID,
+		ResidentID:      &resident.ID,
+		Date:            date,
+		DurationMinutes: 30,
+		Status:          domain.AppointmentStatusConfirmed,
+	}
+	appointmentRepo.On("FindOverlapping", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Appointment{existing}, nil)
+
+	// Act
+	result, err := appointmentService.Create(volunteerID, service.CreateAppointmentRequest{
+		ResidentID: &resident.ID,
+		Date:       date,
+	})
+
+	// Assert
+	assert.Nil(t, result)
+	var conflictErr *service.ConflictError
+	assert.True(t, errors.As(err, &conflictErr))
+	assert.Equal(t, resident.ID, conflictErr.Conflicts[0].UserID)
+	appointmentRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestAppointmentService_Create_ResidentWrongInstitution testa residente de outra instituição.
+func TestAppointmentService_Create_ResidentWrongInstitution(t *testing.T) {
+	// Arrange
+	appointmentService, appointmentRepo, volunteerID, resident := newResidentVisit(t)
+
+	// Act
+	result, err := appointmentService.Create(volunteerID, service.CreateAppointmentRequest{
+		TargetID:   uuid.New(),
+		ResidentID: &resident.ID,
+		Date:       time.Now().UTC().Add(48 * time.Hour),
+	})
+
+	// Assert
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "o residente não pertence à instituição informada")
+	appointmentRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
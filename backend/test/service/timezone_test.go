@@ -0,0 +1,126 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestValidateTimezone testa os fusos aceitos e rejeitados.
+func TestValidateTimezone(t *testing.T) {
+	assert.NoError(t, domain.ValidateTimezone("America/Manaus"))
+	assert.NoError(t, domain.ValidateTimezone("Europe/Lisbon"))
+	assert.Error(t, domain.ValidateTimezone(""))
+	assert.Error(t, domain.ValidateTimezone("Local"))
+	assert.Error(t, domain.ValidateTimezone("Brasil/Terceira"))
+
+	// Fusos desconhecidos caem no padrão
+	assert.Equal(t, domain.DefaultTimezone, domain.LoadLocation("Brasil/Terceira").String())
+}
+
+// newManausVolunteer prepara um voluntário em America/Manaus (UTC-4) com disponibilidade
+// às quartas das 14:00 às 16:00 no horário dele, e um idoso no fuso padrão.
+func newManausVolunteer(t *testing.T) (*service.AppointmentService, *MockAppointmentRepository, uuid.UUID, uuid.UUID) {
+	t.Helper()
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	availabilityRepo := new(MockAvailabilityRepository)
+
+	volunteerID := uuid.New()
+	elderlyID := uuid.New()
+	userRepo.On("FindByID", volunteerID).Return(&domain.User{
+		ID: volunteerID, UserType: domain.UserTypeVolunteer, Timezone: "America/Manaus",
+	}, nil)
+	userRepo.On("FindByID", elderlyID).Return(&domain.User{ID: elderlyID, UserType: domain.UserTypeElderly}, nil)
+
+	availabilityRepo.On("FindWeekly", volunteerID).Return([]domain.VolunteerAvailability{
+		{VolunteerID: volunteerID, Weekday: time.Wednesday, StartTime: "14:00", EndTime: "16:00"},
+	}, nil)
+	availabilityRepo.On("FindExceptions", volunteerID, mock.Anything, mock.Anything).
+		Return([]domain.AvailabilityException{}, nil)
+
+	return service.NewAppointmentService(appointmentRepo, userRepo, new(MockResidentRepository), availabilityRepo, ignoreNotifications()), appointmentRepo, volunteerID, elderlyID
+}
+
+// TestAppointmentService_Create_AvailabilityInVolunteerTimezone testa que a disponibilidade
+// é interpretada no fuso do voluntário, e não no do servidor ou do destinatário.
+func TestAppointmentService_Create_AvailabilityInVolunteerTimezone(t *testing.T) {
+	// Arrange
+	appointmentService, appointmentRepo, volunteerID, elderlyID := newManausVolunteer(t)
+	manaus := domain.LoadLocation("America/Manaus")
+	day := nextWeekday(time.Wednesday).In(domain.LoadLocation(domain.DefaultTimezone))
+	date := time.Date(day.Year(), day.Month(), day.Day(), 14, 0, 0, 0, manaus) // Quarta, 14:00 em Manaus
+
+	appointmentRepo.On("FindOverlapping", mock.Anything, date.UTC(), mock.Anything).Return([]domain.Appointment{}, nil)
+	appointmentRepo.On("Create", mock.MatchedBy(func(appointment *domain.Appointment) bool {
+		return appointment.Date.Location() == time.UTC && appointment.Date.Equal(date) &&
+			appointment.Timezone == "America/Manaus"
+	})).Return(nil)
+	appointmentRepo.On("FindByID", mock.AnythingOfType("uuid.UUID")).Return(&domain.Appointment{
+		VolunteerID: volunteerID,
+		TargetID:    elderlyID,
+		Date:        date.UTC(),
+		Timezone:    "America/Manaus",
+	}, nil)
+
+	// Act
+	result, err := appointmentService.Create(volunteerID, service.CreateAppointmentRequest{TargetID: elderlyID, Date: date})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "America/Manaus", result.ViewerTimezone)
+	assert.Equal(t, 14, result.LocalDate.Hour())
+	appointmentRepo.AssertExpectations(t)
+}
+
+// TestAppointmentService_Create_OutsideAvailabilityInVolunteerTimezone testa que 14:00 no fuso
+// padrão (13:00 em Manaus) fica fora da janela do voluntário.
+func TestAppointmentService_Create_OutsideAvailabilityInVolunteerTimezone(t *testing.T) {
+	// Arrange
+	appointmentService, appointmentRepo, volunteerID, elderlyID := newManausVolunteer(t)
+	date := nextWeekday(time.Wednesday).Add(14 * time.Hour)
+
+	// Act
+	result, err := appointmentService.Create(volunteerID, service.CreateAppointmentRequest{TargetID: elderlyID, Date: date})
+
+	// Assert
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, service.ErrOutsideAvailability)
+	appointmentRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestAppointmentService_GetUpcoming_LocalizedForViewer testa que cada participante
+// recebe o horário no próprio fuso.
+func TestAppointmentService_GetUpcoming_LocalizedForViewer(t *testing.T) {
+	// Arrange
+	appointmentRepo := new(MockAppointmentRepository)
+	appointmentService := newAppointmentService(appointmentRepo, new(MockUserRepository))
+
+	volunteerID := uuid.New()
+	elderlyID := uuid.New()
+	date := time.Date(2030, time.March, 6, 18, 0, 0, 0, time.UTC)
+	appointmentRepo.On("FindUpcoming", elderlyID).Return([]domain.Appointment{{
+		VolunteerID: volunteerID,
+		TargetID:    elderlyID,
+		Date:        date,
+		Timezone:    "America/Manaus",
+		Volunteer:   domain.User{ID: volunteerID, Timezone: "America/Manaus"},
+		Target:      domain.User{ID: elderlyID, Timezone: "Europe/Lisbon"},
+	}}, nil)
+
+	// Act
+	appointments, err := appointmentService.GetUpcoming(elderlyID)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, appointments, 1)
+	assert.Equal(t, "Europe/Lisbon", appointments[0].ViewerTimezone)
+	assert.Equal(t, 18, appointments[0].LocalDate.Hour())
+	assert.True(t, appointments[0].LocalDate.Equal(date))
+}
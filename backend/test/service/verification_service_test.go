@@ -0,0 +1,286 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+	"amigos-terceira-idade/internal/service"
+	"amigos-terceira-idade/pkg/storage"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockVerificationRepository implementa repository.VerificationRepositoryInterface para testes.
+type MockVerificationRepository struct {
+	mock.Mock
+}
+
+var _ repository.VerificationRepositoryInterface = (*MockVerificationRepository)(nil)
+
+func (m *MockVerificationRepository) FindVolunteer(userID uuid.UUID) (*domain.Volunteer, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Volunteer), args.Error(1)
+}
+
+func (m *MockVerificationRepository) FindByStatus(status domain.VerificationStatus, limit, offset int) ([]domain.Volunteer, int64, error) {
+	args := m.Called(status, limit, offset)
+	return args.Get(0).([]domain.Volunteer), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockVerificationRepository) CreateDocument(document *domain.VerificationDocument) error {
+	args := m.Called(document)
+	return args.Error(0)
+}
+
+func (m *MockVerificationRepository) FindDocuments(volunteerID uuid.UUID) ([]domain.VerificationDocument, error) {
+	args := m.Called(volunteerID)
+	return args.Get(0).([]domain.VerificationDocument), args.Error(1)
+}
+
+func (m *MockVerificationRepository) FindDocument(volunteerID, id uuid.UUID) (*domain.VerificationDocument, error) {
+	args := m.Called(volunteerID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.VerificationDocument), args.Error(1)
+}
+
+func (m *MockVerificationRepository) ChangeStatus(change *domain.VerificationStatusHistory) error {
+	args := m.Called(change)
+	return args.Error(0)
+}
+
+func (m *MockVerificationRepository) FindHistory(volunteerID uuid.UUID) ([]domain.VerificationStatusHistory, error) {
+	args := m.Called(volunteerID)
+	return args.Get(0).([]domain.VerificationStatusHistory), args.Error(1)
+}
+
+// memoryStorage implementa storage.Storage em memória.
+type memoryStorage struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{files: make(map[string][]byte)}
+}
+
+func (s *memoryStorage) Save(ctx context.Context, key string, content io.Reader) error {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[key] = data
+	return nil
+}
+
+func (s *memoryStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.files[key]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *memoryStorage) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.files, key)
+	return nil
+}
+
+// pdfContent é o início de um PDF, suficiente para a detecção de formato.
+const pdfContent = "%PDF-1.4\n% documento de teste\n"
+
+// verificationMocks agrupa as dependências do VerificationService.
+type verificationMocks struct {
+	verifications *MockVerificationRepository
+	users         *MockUserRepository
+	audit         *MockAuditRepository
+	storage       *memoryStorage
+	notifications *MockNotificationSender
+}
+
+// newVerificationService cria o serviço de verificação com dependências falsas
+// e um voluntário cadastrado no status informado.
+func newVerificationService(status domain.VerificationStatus) (*service.VerificationService, verificationMocks, *domain.Volunteer) {
+	mocks := verificationMocks{
+		verifications: new(MockVerificationRepository),
+		users:         new(MockUserRepository),
+		audit:         new(MockAuditRepository),
+		storage:       newMemoryStorage(),
+		notifications: ignoreNotifications(),
+	}
+	volunteer := &domain.Volunteer{UserID: uuid.New(), VerificationStatus: status, IsVerified: status == domain.VerificationApproved}
+	mocks.users.On("FindByID", volunteer.UserID).Return(&domain.User{ID: volunteer.UserID, UserType: domain.UserTypeVolunteer}, nil)
+	mocks.verifications.On("FindVolunteer", volunteer.UserID).Return(volunteer, nil)
+
+	verificationService := service.NewVerificationService(mocks.verifications, mocks.users, mocks.audit, mocks.storage,
+		mocks.notifications, service.VerificationOptions{MaxDocumentSize: 1 << 20})
+	return verificationService, mocks, volunteer
+}
+
+// TestVerificationService_UploadDocument testa que o documento é guardado com o formato detectado.
+func TestVerificationService_UploadDocument(t *testing.T) {
+	// Arrange
+	verificationService, mocks, volunteer := newVerificationService(domain.VerificationNotSubmitted)
+	mocks.verifications.On("CreateDocument", mock.AnythingOfType("*domain.VerificationDocument")).Return(nil)
+
+	// Act
+	document, err := verificationService.UploadDocument(volunteer.UserID, service.DocumentUpload{
+		Kind:     domain.DocumentCriminalRecord,
+		FileName: `C:\Users\ana\antecedentes.pdf`,
+		Size:     int64(len(pdfContent)),
+		Content:  strings.NewReader(pdfContent),
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "application/pdf", document.ContentType)
+	assert.Equal(t, "antecedentes.pdf", document.FileName)
+	assert.True(t, strings.HasSuffix(document.StorageKey, ".pdf"))
+	assert.Equal(t, pdfContent, string(mocks.storage.files[document.StorageKey]))
+}
+
+// TestVerificationService_UploadDocument_Invalid testa os formatos, tamanhos e tipos recusados.
+func TestVerificationService_UploadDocument_Invalid(t *testing.T) {
+	// Arrange
+	verificationService, mocks, volunteer := newVerificationService(domain.VerificationNotSubmitted)
+	upload := func(kind domain.DocumentKind, content string, size int64) error {
+		_, err := verificationService.UploadDocument(volunteer.UserID, service.DocumentUpload{
+			Kind: kind, FileName: "doc", Size: size, Content: strings.NewReader(content),
+		})
+		return err
+	}
+
+	// Act
+	errKind := upload("SELFIE", pdfContent, int64(len(pdfContent)))
+	errFormat := upload(domain.DocumentIdentity, "#!/bin/sh\nrm -rf /\n", 20)
+	errSize := upload(domain.DocumentIdentity, pdfContent, 2<<20)
+
+	// Assert
+	assert.Error(t, errKind)
+	assert.EqualError(t, errFormat, "formato não aceito: envie PDF, JPEG ou PNG")
+	assert.Error(t, errSize)
+	assert.Empty(t, mocks.storage.files)
+	mocks.verifications.AssertNotCalled(t, "CreateDocument", mock.Anything)
+}
+
+// TestVerificationService_Submit testa o pedido de análise e a exigência de documentos.
+func TestVerificationService_Submit(t *testing.T) {
+	// Arrange
+	verificationService, mocks, volunteer := newVerificationService(domain.VerificationRejected)
+	mocks.verifications.On("FindDocuments", volunteer.UserID).Return([]domain.VerificationDocument{}, nil).Once()
+	mocks.verifications.On("FindDocuments", volunteer.UserID).Return([]domain.VerificationDocument{{ID: uuid.New()}}, nil)
+	mocks.verifications.On("FindHistory", volunteer.UserID).Return([]domain.VerificationStatusHistory{}, nil)
+	mocks.verifications.On("ChangeStatus", mock.MatchedBy(func(change *domain.VerificationStatusHistory) bool {
+		return change.FromStatus == domain.VerificationRejected && change.ToStatus == domain.VerificationPending &&
+			change.ChangedBy == volunteer.UserID
+	})).Return(nil).Once()
+
+	// Act
+	_, errEmpty := verificationService.Submit(volunteer.UserID)
+	_, err := verificationService.Submit(volunteer.UserID)
+
+	// Assert
+	assert.EqualError(t, errEmpty, "envie ao menos um documento antes de pedir a verificação")
+	assert.NoError(t, err)
+	mocks.verifications.AssertExpectations(t)
+}
+
+// TestVerificationService_Approve testa a aprovação, a auditoria e o aviso ao voluntário.
+func TestVerificationService_Approve(t *testing.T) {
+	// Arrange
+	verificationService, mocks, volunteer := newVerificationService(domain.VerificationPending)
+	actorID := uuid.New()
+	mocks.verifications.On("ChangeStatus", mock.MatchedBy(func(change *domain.VerificationStatusHistory) bool {
+		return change.ToStatus == domain.VerificationApproved && change.ChangedBy == actorID
+	})).Return(nil)
+	mocks.verifications.On("FindDocuments", volunteer.UserID).Return([]domain.VerificationDocument{}, nil)
+	mocks.verifications.On("FindHistory", volunteer.UserID).Return([]domain.VerificationStatusHistory{}, nil)
+	expectAudit(mocks.audit, actorID, domain.AuditVolunteerApproved, volunteer.UserID)
+
+	// Act
+	_, err := verificationService.Approve(actorID, volunteer.UserID)
+
+	// Assert
+	assert.NoError(t, err)
+	mocks.audit.AssertExpectations(t)
+	mocks.notifications.AssertCalled(t, "Notify", mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.UserID == volunteer.UserID && n.Type == domain.NotificationVerificationApproved
+	}))
+}
+
+// TestVerificationService_Reject testa que a recusa exige motivo e um pedido pendente.
+func TestVerificationService_Reject(t *testing.T) {
+	// Arrange
+	verificationService, mocks, volunteer := newVerificationService(domain.VerificationNotSubmitted)
+
+	// Act
+	_, errReason := verificationService.Reject(uuid.New(), volunteer.UserID, service.RejectVerificationRequest{Reason: "  "})
+	_, errStatus := verificationService.Reject(uuid.New(), volunteer.UserID, service.RejectVerificationRequest{Reason: "Certidão ilegível"})
+	_, errSelf := verificationService.Approve(volunteer.UserID, volunteer.UserID)
+
+	// Assert
+	assert.EqualError(t, errReason, "informe o motivo da recusa")
+	assert.ErrorIs(t, errStatus, domain.ErrInvalidStatusTransition)
+	assert.ErrorIs(t, errSelf, service.ErrSelfAdministration)
+	mocks.verifications.AssertNotCalled(t, "ChangeStatus", mock.Anything)
+}
+
+// TestVerificationService_NotVolunteer testa que apenas voluntários passam pela verificação.
+func TestVerificationService_NotVolunteer(t *testing.T) {
+	// Arrange
+	verificationService, mocks, _ := newVerificationService(domain.VerificationNotSubmitted)
+	elderlyID := uuid.New()
+	mocks.users.On("FindByID", elderlyID).Return(&domain.User{ID: elderlyID, UserType: domain.UserTypeElderly}, nil)
+
+	// Act
+	_, err := verificationService.GetDetails(elderlyID)
+
+	// Assert
+	assert.ErrorIs(t, err, service.ErrVolunteerOnly)
+}
+
+// TestMatchingService_RequireVerifiedVolunteers testa que, com a exigência ativa,
+// voluntários não verificados não buscam sugestões nem pedem conexões.
+func TestMatchingService_RequireVerifiedVolunteers(t *testing.T) {
+	// Arrange
+	userRepo := new(MockUserRepository)
+	connectionRepo := new(MockConnectionRepository)
+	verificationRepo := new(MockVerificationRepository)
+	matchingService := service.NewMatchingService(userRepo, connectionRepo, noResidents(), verificationRepo, ignoreNotifications(),
+		service.MatchingOptions{RequireVerifiedVolunteers: true})
+
+	volunteerID, elderlyID := uuid.New(), uuid.New()
+	userRepo.On("FindByID", volunteerID).Return(&domain.User{ID: volunteerID, UserType: domain.UserTypeVolunteer}, nil)
+	userRepo.On("FindByID", elderlyID).Return(&domain.User{ID: elderlyID, UserType: domain.UserTypeElderly}, nil)
+	connectionRepo.On("Exists", volunteerID, elderlyID).Return(false, nil)
+	verificationRepo.On("FindVolunteer", volunteerID).Return(&domain.Volunteer{UserID: volunteerID}, nil)
+
+	// Act
+	_, errSuggestions := matchingService.GetSuggestions(volunteerID, "")
+	_, errConnect := matchingService.Connect(volunteerID, elderlyID)
+
+	// Assert
+	assert.ErrorIs(t, errSuggestions, service.ErrVolunteerNotVerified)
+	assert.ErrorIs(t, errConnect, service.ErrVolunteerNotVerified)
+	userRepo.AssertNotCalled(t, "FindByType", mock.Anything)
+	connectionRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
@@ -0,0 +1,153 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newInstitutionVisit prepara um voluntário, uma instituição com uma janela de visitas
+// às quartas das 14:00 às 16:00 (até 60 minutos, 2 visitantes) e o serviço de agendamentos.
+func newInstitutionVisit(t *testing.T) (*service.AppointmentService, *MockAppointmentRepository, uuid.UUID, uuid.UUID) {
+	t.Helper()
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	availabilityRepo := new(MockAvailabilityRepository)
+
+	volunteerID := uuid.New()
+	institutionID := uuid.New()
+	userRepo.On("FindByID", volunteerID).Return(&domain.User{ID: volunteerID, UserType: domain.UserTypeVolunteer}, nil)
+	userRepo.On("FindByID", institutionID).Return(&domain.User{ID: institutionID, UserType: domain.UserTypeInstitution}, nil)
+
+	availabilityRepo.On("FindWeekly", volunteerID).Return([]domain.VolunteerAvailability{}, nil).Maybe()
+	availabilityRepo.On("FindVisitWindows", institutionID).Return([]domain.VisitWindow{{
+		InstitutionID:      institutionID,
+		Weekday:            time.Wednesday,
+		StartTime:          "14:00",
+		EndTime:            "16:00",
+		MaxDurationMinutes: 60,
+		MaxVisitors:        2,
+	}}, nil)
+
+	return service.NewAppointmentService(appointmentRepo, userRepo, new(MockResidentRepository), availabilityRepo, ignoreNotifications()), appointmentRepo, volunteerID, institutionID
+}
+
+// TestAppointmentService_Create_InstitutionOutsideVisitWindow testa visita fora da janela da instituição.
+func TestAppointmentService_Create_InstitutionOutsideVisitWindow(t *testing.T) {
+	// Arrange
+	appointmentService, appointmentRepo, volunteerID, institutionID := newInstitutionVisit(t)
+	req := service.CreateAppointmentRequest{
+		TargetID:        institutionID,
+		Date:            nextWeekday(time.Wednesday).Add(15*time.Hour + 30*time.Minute),
+		DurationMinutes: 60, // Termina às 16:30
+	}
+
+	// Act
+	result, err := appointmentService.Create(volunteerID, req)
+
+	// Assert
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, service.ErrOutsideVisitWindow)
+	appointmentRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestAppointmentService_Create_InstitutionVisitTooLong testa o limite de duração da janela.
+func TestAppointmentService_Create_InstitutionVisitTooLong(t *testing.T) {
+	// Arrange
+	appointmentService, appointmentRepo, volunteerID, institutionID := newInstitutionVisit(t)
+	req := service.CreateAppointmentRequest{
+		TargetID:        institutionID,
+		Date:            nextWeekday(time.Wednesday).Add(14 * time.Hour),
+		DurationMinutes: 90,
+	}
+
+	// Act
+	result, err := appointmentService.Create(volunteerID, req)
+
+	// Assert
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "a duração máxima de uma visita neste horário é de 60 minutos")
+	appointmentRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestAppointmentService_Create_InstitutionCapacity testa o limite de visitantes simultâneos.
+func TestAppointmentService_Create_InstitutionCapacity(t *testing.T) {
+	// Arrange
+	appointmentService, appointmentRepo, volunteerID, institutionID := newInstitutionVisit(t)
+	date := nextWeekday(time.Wednesday).Add(14 * time.Hour)
+	req := service.CreateAppointmentRequest{
+		TargetID:        institutionID,
+		Date:            date,
+		DurationMinutes: 60,
+	}
+
+	// Dois outros voluntários já visitam a instituição no mesmo horário
+	visits := []domain.Appointment{
+		{ID: uuid.New(), VolunteerID: uuid.New(), TargetID: institutionID, Date: date, DurationMinutes: 60, Status: domain.AppointmentStatusConfirmed},
+		{ID: uuid.New(), VolunteerID: uuid.New(), TargetID: institutionID, Date: date.Add(30 * time.Minute), DurationMinutes: 30, Status: domain.AppointmentStatusPending},
+	}
+	appointmentRepo.On("FindOverlapping", []uuid.UUID{volunteerID, institutionID}, date, date.Add(time.Hour)).Return(visits, nil)
+
+	// Act
+	result, err := appointmentService.Create(volunteerID, req)
+
+	// Assert
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, service.ErrVisitCapacityReached)
+	appointmentRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestAppointmentService_Create_InstitutionWithinCapacity testa que visitas simultâneas são aceitas até o limite.
+func TestAppointmentService_Create_InstitutionWithinCapacity(t *testing.T) {
+	// Arrange
+	appointmentService, appointmentRepo, volunteerID, institutionID := newInstitutionVisit(t)
+	date := nextWeekday(time.Wednesday).Add(14 * time.Hour)
+	req := service.CreateAppointmentRequest{
+		TargetID:        institutionID,
+		Date:            date,
+		DurationMinutes: 60,
+	}
+
+	visits := []domain.Appointment{
+		{ID: uuid.New(), VolunteerID: uuid.New(), TargetID: institutionID, Date: date, DurationMinutes: 30, Status: domain.AppointmentStatusConfirmed},
+		{ID: uuid.New(), VolunteerID: uuid.New(), TargetID: institutionID, Date: date.Add(30 * time.Minute), DurationMinutes: 30, Status: domain.AppointmentStatusConfirmed},
+	}
+	appointmentRepo.On("FindOverlapping", []uuid.UUID{volunteerID, institutionID}, date, date.Add(time.Hour)).Return(visits, nil)
+	appointmentRepo.On("Create", mock.AnythingOfType("*domain.Appointment")).Return(nil)
+	appointmentRepo.On("FindByID", mock.AnythingOfType("uuid.UUID")).Return(&domain.Appointment{TargetID: institutionID}, nil)
+
+	// Act
+	result, err := appointmentService.Create(volunteerID, req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+}
+
+// TestAvailabilityService_SetVisitWindows_NotInstitution testa que apenas instituições definem janelas de visita.
+func TestAvailabilityService_SetVisitWindows_NotInstitution(t *testing.T) {
+	// Arrange
+	availabilityRepo := new(MockAvailabilityRepository)
+	appointmentRepo := new(MockAppointmentRepository)
+	userRepo := new(MockUserRepository)
+	availabilityService := service.NewAvailabilityService(availabilityRepo, appointmentRepo, userRepo)
+
+	userID := uuid.New()
+	userRepo.On("FindByID", userID).Return(&domain.User{ID: userID, UserType: domain.UserTypeElderly}, nil)
+
+	// Act
+	result, err := availabilityService.SetVisitWindows(userID, service.SetVisitWindowsRequest{
+		Windows: []service.VisitWindowRequest{{Weekday: time.Monday, StartTime: "09:00", EndTime: "11:00"}},
+	})
+
+	// Assert
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "apenas instituições possuem janelas de visita")
+	availabilityRepo.AssertNotCalled(t, "ReplaceVisitWindows", mock.Anything, mock.Anything)
+}
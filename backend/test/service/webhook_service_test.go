@@ -0,0 +1,346 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"amigos-terceira-idade/internal/domain"
+	"amigos-terceira-idade/internal/repository"
+	"amigos-terceira-idade/internal/service"
+	"amigos-terceira-idade/pkg/webhook"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockWebhookRepository implementa repository.WebhookRepositoryInterface para testes.
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+var _ repository.WebhookRepositoryInterface = (*MockWebhookRepository)(nil)
+
+func (m *MockWebhookRepository) CreateEndpoint(endpoint *domain.WebhookEndpoint) error {
+	args := m.Called(endpoint)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) FindEndpoint(institutionID, id uuid.UUID) (*domain.WebhookEndpoint, error) {
+	args := m.Called(institutionID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WebhookEndpoint), args.Error(1)
+}
+
+func (m *MockWebhookRepository) FindEndpointsByInstitution(institutionID uuid.UUID) ([]domain.WebhookEndpoint, error) {
+	args := m.Called(institutionID)
+	return args.Get(0).([]domain.WebhookEndpoint), args.Error(1)
+}
+
+func (m *MockWebhookRepository) FindActiveEndpoints(institutionIDs []uuid.UUID) ([]domain.WebhookEndpoint, error) {
+	args := m.Called(institutionIDs)
+	return args.Get(0).([]domain.WebhookEndpoint), args.Error(1)
+}
+
+func (m *MockWebhookRepository) UpdateEndpoint(endpoint *domain.WebhookEndpoint) error {
+	args := m.Called(endpoint)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) DeleteEndpoint(institutionID, id uuid.UUID) error {
+	args := m.Called(institutionID, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) EnqueueDelivery(delivery *domain.WebhookDelivery) (bool, error) {
+	args := m.Called(delivery)
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWebhookRepository) FindDueDeliveries(now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	args := m.Called(now, limit)
+	return args.Get(0).([]domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) LeaseDelivery(delivery *domain.WebhookDelivery, until time.Time) (bool, error) {
+	args := m.Called(delivery, until)
+	if args.Bool(0) {
+		delivery.NextAttemptAt = until
+	}
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWebhookRepository) SaveDeliveryAttempt(delivery *domain.WebhookDelivery) error {
+	args := m.Called(delivery)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) FindDeliveries(endpointID uuid.UUID, limit, offset int) ([]domain.WebhookDelivery, int64, error) {
+	args := m.Called(endpointID, limit, offset)
+	return args.Get(0).([]domain.WebhookDelivery), args.Get(1).(int64), args.Error(2)
+}
+
+// webhookReceiver é um endpoint httptest que confere a assinatura e responde com o status informado.
+type webhookReceiver struct {
+	server   *httptest.Server
+	status   int
+	received []domain.Event
+	verified bool
+}
+
+func newWebhookReceiver(t *testing.T, secret string, status int) *webhookReceiver {
+	t.Helper()
+	receiver := &webhookReceiver{status: status}
+	receiver.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receiver.verified = webhook.Verify(secret, r.Header.Get(webhook.HeaderSignature),
+			r.Header.Get(webhook.HeaderTimestamp), body, time.Minute)
+
+		var event domain.Event
+		if json.Unmarshal(body, &event) == nil {
+			receiver.received = append(receiver.received, event)
+		}
+		w.WriteHeader(receiver.status)
+	}))
+	t.Cleanup(receiver.server.Close)
+	return receiver
+}
+
+// newWebhookService cria o serviço com o cliente HTTP real.
+func newWebhookService(webhookRepo *MockWebhookRepository, userRepo *MockUserRepository) *service.WebhookService {
+	return service.NewWebhookService(webhookRepo, userRepo, webhook.NewUnrestrictedClient(time.Second, "teste/1.0"), service.WebhookOptions{
+		MaxAttempts: 3,
+		BaseBackoff: 30 * time.Second,
+	})
+}
+
+// pendingDelivery cria uma entrega pendente do evento de criação de um agendamento.
+func pendingDelivery(t *testing.T, endpoint domain.WebhookEndpoint, attempts int) domain.WebhookDelivery {
+	t.Helper()
+	appointment := calendarAppointment(domain.AppointmentStatusPending)
+	event := domain.NewAppointmentEvent(domain.EventAppointmentCreated, &appointment, appointment.VolunteerID)
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+	return domain.WebhookDelivery{
+		ID:         uuid.New(),
+		EndpointID: endpoint.ID,
+		EventID:    event.ID,
+		EventType:  event.Type,
+		Payload:    string(payload),
+		Status:     domain.WebhookDeliveryPending,
+		Attempts:   attempts,
+		Endpoint:   endpoint,
+	}
+}
+
+// TestWebhookService_CreateEndpoint_Success testa o cadastro com segredo gerado.
+func TestWebhookService_CreateEndpoint_Success(t *testing.T) {
+	// Arrange
+	webhookRepo := new(MockWebhookRepository)
+	userRepo := new(MockUserRepository)
+	webhookService := newWebhookService(webhookRepo, userRepo)
+
+	institutionID := uuid.New()
+	userRepo.On("FindByID", institutionID).Return(&domain.User{ID: institutionID, UserType: domain.UserTypeInstitution}, nil)
+	webhookRepo.On("CreateEndpoint", mock.AnythingOfType("*domain.WebhookEndpoint")).Return(nil)
+
+	// Act
+	endpoint, err := webhookService.CreateEndpoint(institutionID, service.CreateWebhookRequest{
+		URL:    "https://lar.example.com/hooks",
+		Events: []domain.EventType{domain.EventAppointmentCreated, domain.EventConnectionAccepted},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, endpoint.IsActive)
+	assert.Regexp(t, "^whsec_[0-9a-f]{64}$", endpoint.Secret)
+	assert.True(t, endpoint.Subscribes(domain.EventConnectionAccepted))
+	assert.False(t, endpoint.Subscribes(domain.EventAppointmentCancelled))
+}
+
+// TestWebhookService_CreateEndpoint_Validation testa as recusas de usuário, URL e evento.
+func TestWebhookService_CreateEndpoint_Validation(t *testing.T) {
+	// Arrange
+	webhookRepo := new(MockWebhookRepository)
+	userRepo := new(MockUserRepository)
+	webhookService := newWebhookService(webhookRepo, userRepo)
+
+	institutionID := uuid.New()
+	volunteerID := uuid.New()
+	userRepo.On("FindByID", institutionID).Return(&domain.User{ID: institutionID, UserType: domain.UserTypeInstitution}, nil)
+	userRepo.On("FindByID", volunteerID).Return(&domain.User{ID: volunteerID, UserType: domain.UserTypeVolunteer}, nil)
+	valid := []domain.EventType{domain.EventAppointmentCreated}
+
+	// Act
+	_, errVolunteer := webhookService.CreateEndpoint(volunteerID, service.CreateWebhookRequest{URL: "https://a.example.com", Events: valid})
+	_, errURL := webhookService.CreateEndpoint(institutionID, service.CreateWebhookRequest{URL: "ftp://a.example.com", Events: valid})
+	_, errEvent := webhookService.CreateEndpoint(institutionID, service.CreateWebhookRequest{
+		URL: "https://a.example.com", Events: []domain.EventType{"appointment.deleted"},
+	})
+
+	// Assert
+	assert.ErrorIs(t, errVolunteer, service.ErrInstitutionOnly)
+	assert.Error(t, errURL)
+	assert.Error(t, errEvent)
+	webhookRepo.AssertNotCalled(t, "CreateEndpoint", mock.Anything)
+}
+
+// TestWebhookService_CreateEndpoint_RejectsInternalURL testa que endpoints na rede interna são recusados.
+func TestWebhookService_CreateEndpoint_RejectsInternalURL(t *testing.T) {
+	// Arrange
+	webhookRepo := new(MockWebhookRepository)
+	userRepo := new(MockUserRepository)
+	webhookService := newWebhookService(webhookRepo, userRepo)
+
+	institutionID := uuid.New()
+	userRepo.On("FindByID", institutionID).Return(&domain.User{ID: institutionID, UserType: domain.UserTypeInstitution}, nil)
+	valid := []domain.EventType{domain.EventAppointmentCreated}
+
+	for _, rawURL := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.5/hook",
+		"https://192.168.1.10/hook",
+		"http://[::1]/hook",
+		"http://0.0.0.0/hook",
+		"http://100.64.0.1/hook",
+		"http://[64:ff9b::a9fe:a9fe]/hook",
+		"http://[::ffff:100.64.0.1]/hook",
+		"http://localhost/hook",
+	} {
+		// Act
+		_, err := webhookService.CreateEndpoint(institutionID, service.CreateWebhookRequest{URL: rawURL, Events: valid})
+
+		// Assert
+		assert.ErrorIs(t, err, webhook.ErrForbiddenAddress, rawURL)
+	}
+	webhookRepo.AssertNotCalled(t, "CreateEndpoint", mock.Anything)
+}
+
+// TestWebhookService_Handle_EnqueuesSubscribedEndpoints testa que só os endpoints que assinam
+// o tipo do evento recebem a entrega.
+func TestWebhookService_Handle_EnqueuesSubscribedEndpoints(t *testing.T) {
+	// Arrange
+	webhookRepo := new(MockWebhookRepository)
+	webhookService := newWebhookService(webhookRepo, new(MockUserRepository))
+
+	appointment := calendarAppointment(domain.AppointmentStatusConfirmed)
+	event := domain.NewAppointmentEvent(domain.EventAppointmentConfirmed, &appointment, appointment.TargetID)
+	subscribed := domain.WebhookEndpoint{ID: uuid.New(), Events: []domain.EventType{domain.EventAppointmentConfirmed}}
+	other := domain.WebhookEndpoint{ID: uuid.New(), Events: []domain.EventType{domain.EventAppointmentCancelled}}
+
+	webhookRepo.On("FindActiveEndpoints", event.UserIDs).Return([]domain.WebhookEndpoint{subscribed, other}, nil)
+	webhookRepo.On("EnqueueDelivery", mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+		return d.EndpointID == subscribed.ID && d.EventID == event.ID &&
+			d.Status == domain.WebhookDeliveryPending && d.NextAttemptAt.Equal(event.OccurredAt)
+	})).Return(true, nil).Once()
+
+	// Act
+	err := webhookService.Handle(context.Background(), event)
+
+	// Assert
+	assert.NoError(t, err)
+	webhookRepo.AssertExpectations(t)
+	webhookRepo.AssertNumberOfCalls(t, "EnqueueDelivery", 1)
+}
+
+// TestWebhookService_DeliverDue_Success testa a entrega assinada a um receptor que responde 2xx.
+func TestWebhookService_DeliverDue_Success(t *testing.T) {
+	// Arrange
+	webhookRepo := new(MockWebhookRepository)
+	webhookService := newWebhookService(webhookRepo, new(MockUserRepository))
+
+	receiver := newWebhookReceiver(t, "segredo", http.StatusNoContent)
+	endpoint := domain.WebhookEndpoint{ID: uuid.New(), URL: receiver.server.URL, Secret: "segredo", IsActive: true}
+	delivery := pendingDelivery(t, endpoint, 0)
+
+	now := time.Now().UTC()
+	webhookRepo.On("FindDueDeliveries", now, 50).Return([]domain.WebhookDelivery{delivery}, nil)
+	webhookRepo.On("LeaseDelivery", mock.Anything, mock.Anything).Return(true, nil)
+	webhookRepo.On("SaveDeliveryAttempt", mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+		return d.Status == domain.WebhookDeliverySucceeded && d.Attempts == 1 &&
+			d.ResponseStatus == http.StatusNoContent && d.DeliveredAt.Equal(now)
+	})).Return(nil).Once()
+
+	// Act
+	delivered, err := webhookService.DeliverDue(context.Background(), now)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.True(t, receiver.verified)
+	require.Len(t, receiver.received, 1)
+	assert.Equal(t, delivery.EventID, receiver.received[0].ID)
+	webhookRepo.AssertExpectations(t)
+}
+
+// TestWebhookService_DeliverDue_RetriesWithBackoff testa o reagendamento após uma resposta 5xx
+// e a falha definitiva ao esgotar as tentativas.
+func TestWebhookService_DeliverDue_RetriesWithBackoff(t *testing.T) {
+	// Arrange
+	webhookRepo := new(MockWebhookRepository)
+	webhookService := newWebhookService(webhookRepo, new(MockUserRepository))
+
+	receiver := newWebhookReceiver(t, "segredo", http.StatusServiceUnavailable)
+	endpoint := domain.WebhookEndpoint{ID: uuid.New(), URL: receiver.server.URL, Secret: "segredo", IsActive: true}
+	retried := pendingDelivery(t, endpoint, 1)
+	exhausted := pendingDelivery(t, endpoint, 2)
+
+	now := time.Now().UTC()
+	webhookRepo.On("FindDueDeliveries", now, 50).Return([]domain.WebhookDelivery{retried, exhausted}, nil)
+	webhookRepo.On("LeaseDelivery", mock.Anything, mock.Anything).Return(true, nil)
+	webhookRepo.On("SaveDeliveryAttempt", mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+		return d.ID == retried.ID && d.Status == domain.WebhookDeliveryPending && d.Attempts == 2 &&
+			d.NextAttemptAt.Equal(now.Add(time.Minute)) && d.ResponseStatus == http.StatusServiceUnavailable
+	})).Return(nil).Once()
+	webhookRepo.On("SaveDeliveryAttempt", mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+		return d.ID == exhausted.ID && d.Status == domain.WebhookDeliveryFailed && d.Attempts == 3 &&
+			d.Error == "o receptor respondeu HTTP 503"
+	})).Return(nil).Once()
+
+	// Act
+	delivered, err := webhookService.DeliverDue(context.Background(), now)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Zero(t, delivered)
+	assert.Len(t, receiver.received, 2)
+	webhookRepo.AssertExpectations(t)
+}
+
+// TestWebhookService_SendTest testa o envio imediato do evento de teste.
+func TestWebhookService_SendTest(t *testing.T) {
+	// Arrange
+	webhookRepo := new(MockWebhookRepository)
+	webhookService := newWebhookService(webhookRepo, new(MockUserRepository))
+
+	institutionID := uuid.New()
+	receiver := newWebhookReceiver(t, "segredo", http.StatusOK)
+	endpoint := &domain.WebhookEndpoint{
+		ID: uuid.New(), InstitutionID: institutionID, URL: receiver.server.URL, Secret: "segredo",
+	}
+	webhookRepo.On("FindEndpoint", institutionID, endpoint.ID).Return(endpoint, nil)
+	webhookRepo.On("EnqueueDelivery", mock.Anything).Return(true, nil)
+	webhookRepo.On("SaveDeliveryAttempt", mock.Anything).Return(nil)
+
+	// Act
+	delivery, err := webhookService.SendTest(context.Background(), institutionID, endpoint.ID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, domain.WebhookDeliverySucceeded, delivery.Status)
+	assert.True(t, receiver.verified)
+	require.Len(t, receiver.received, 1)
+	assert.Equal(t, domain.EventWebhookTest, receiver.received[0].Type)
+}
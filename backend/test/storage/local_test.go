@@ -0,0 +1,61 @@
+package storage_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"amigos-terceira-idade/pkg/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLocalStorage_SaveOpenDelete testa o ciclo completo de um arquivo.
+func TestLocalStorage_SaveOpenDelete(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := storage.NewLocalStorage(dir)
+	require.NoError(t, err)
+
+	// Act
+	errSave := store.Save(ctx, "verification/abc/doc.pdf", strings.NewReader("conteúdo"))
+	file, errOpen := store.Open(ctx, "verification/abc/doc.pdf")
+	require.NoError(t, errOpen)
+	content, _ := io.ReadAll(file)
+	file.Close()
+	errDelete := store.Delete(ctx, "verification/abc/doc.pdf")
+	_, errMissing := store.Open(ctx, "verification/abc/doc.pdf")
+
+	// Assert
+	assert.NoError(t, errSave)
+	assert.Equal(t, "conteúdo", string(content))
+	assert.NoError(t, errDelete)
+	assert.ErrorIs(t, errMissing, storage.ErrNotFound)
+	assert.NoError(t, store.Delete(ctx, "verification/abc/doc.pdf"), "remover de novo não é erro")
+
+	entries, _ := os.ReadDir(filepath.Join(dir, "verification", "abc"))
+	assert.Empty(t, entries, "nenhum arquivo temporário deve sobrar")
+}
+
+// TestLocalStorage_InvalidKeys testa que chaves não saem da raiz do armazenamento.
+func TestLocalStorage_InvalidKeys(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	store, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "/etc/passwd", "../fora.txt", "a/../../fora.txt", "a//b"} {
+		// Act
+		errSave := store.Save(ctx, key, strings.NewReader("x"))
+		_, errOpen := store.Open(ctx, key)
+
+		// Assert
+		assert.ErrorIs(t, errSave, storage.ErrInvalidKey, key)
+		assert.ErrorIs(t, errOpen, storage.ErrInvalidKey, key)
+	}
+}
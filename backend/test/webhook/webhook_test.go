@@ -0,0 +1,134 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"amigos-terceira-idade/pkg/webhook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_Send_SignsPayload testa os headers e a assinatura recebidos pelo endpoint.
+func TestClient_Send_SignsPayload(t *testing.T) {
+	// Arrange
+	var headers http.Header
+	var body []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("ok"))
+	}))
+	defer receiver.Close()
+	client := webhook.NewUnrestrictedClient(time.Second, "teste/1.0")
+
+	// Act
+	resp, err := client.Send(context.Background(), webhook.Request{
+		ID:     "entrega-1",
+		Event:  "appointment.created",
+		URL:    receiver.URL,
+		Secret: "segredo",
+		Body:   []byte(`{"type":"appointment.created"}`),
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, resp.Success())
+	assert.Equal(t, "entrega-1", headers.Get(webhook.HeaderID))
+	assert.Equal(t, "appointment.created", headers.Get(webhook.HeaderEvent))
+	assert.Equal(t, "application/json", headers.Get("Content-Type"))
+	assert.True(t, webhook.Verify("segredo", headers.Get(webhook.HeaderSignature),
+		headers.Get(webhook.HeaderTimestamp), body, time.Minute))
+}
+
+// TestClient_Send_DoesNotFollowRedirects testa que um redirecionamento conta como falha.
+func TestClient_Send_DoesNotFollowRedirects(t *testing.T) {
+	// Arrange
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/", http.StatusFound)
+	}))
+	defer receiver.Close()
+	client := webhook.NewUnrestrictedClient(time.Second, "teste/1.0")
+
+	// Act
+	resp, err := client.Send(context.Background(), webhook.Request{URL: receiver.URL, Body: []byte(`{}`)})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+	assert.False(t, resp.Success())
+}
+
+// TestClient_Send_RejectsInternalAddress testa que o cliente padrão não conecta em endereços internos.
+func TestClient_Send_RejectsInternalAddress(t *testing.T) {
+	// Arrange
+	called := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer receiver.Close()
+	client := webhook.NewClient(time.Second, "teste/1.0")
+
+	// Act
+	resp, err := client.Send(context.Background(), webhook.Request{URL: receiver.URL, Body: []byte(`{}`)})
+
+	// Assert
+	assert.ErrorIs(t, err, webhook.ErrForbiddenAddress)
+	assert.Nil(t, resp)
+	assert.False(t, called)
+}
+
+// TestVerify_Rejections testa assinaturas adulteradas, segredos errados e timestamps antigos.
+func TestVerify_Rejections(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	now := time.Now().Unix()
+	signature := webhook.Sign("segredo", now, body)
+	timestamp := strconv.FormatInt(now, 10)
+
+	assert.True(t, webhook.Verify("segredo", signature, timestamp, body, time.Minute))
+	assert.False(t, webhook.Verify("outro", signature, timestamp, body, time.Minute))
+	assert.False(t, webhook.Verify("segredo", signature, timestamp, []byte(`{"id":"2"}`), time.Minute))
+
+	old := now - 600
+	assert.False(t, webhook.Verify("segredo", webhook.Sign("segredo", old, body),
+		strconv.FormatInt(old, 10), body, time.Minute))
+	assert.False(t, webhook.Verify("segredo", "md5=abc", timestamp, body, time.Minute))
+}
+
+// TestIsForbiddenIP testa os endereços internos recusados, inclusive nas formas mapeadas em IPv6.
+func TestIsForbiddenIP(t *testing.T) {
+	forbidden := []string{
+		"127.0.0.1",
+		"10.0.0.1",
+		"192.168.1.10",
+		"169.254.169.254",
+		"0.0.0.0",
+		"0.1.2.3",
+		"100.64.0.1",
+		"100.127.255.254",
+		"::1",
+		"fd00::1",
+		"64:ff9b::a9fe:a9fe",
+		"64:ff9b:1::1",
+		"::ffff:127.0.0.1",
+		"::ffff:100.64.0.1",
+		"::ffff:0.1.2.3",
+		"::ffff:169.254.169.254",
+	}
+	for _, address := range forbidden {
+		assert.True(t, webhook.IsForbiddenIP(net.ParseIP(address)), address)
+	}
+
+	allowed := []string{"8.8.8.8", "100.63.255.255", "100.128.0.1", "2001:4860:4860::8888", "::ffff:8.8.8.8"}
+	for _, address := range allowed {
+		assert.False(t, webhook.IsForbiddenIP(net.ParseIP(address)), address)
+	}
+}